@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/EspenTeigen/lazylab/internal/app"
+	"github.com/EspenTeigen/lazylab/internal/gitlab"
+	"gopkg.in/yaml.v3"
+)
+
+// exporter fetches one kind of data; project is empty for those that take none
+type exporter struct {
+	needsProject bool
+	fetch        func(client gitlab.API, project string) (any, error)
+}
+
+// exporters are the kinds of data -export can print
+var exporters = map[string]exporter{
+	"projects": {fetch: func(client gitlab.API, _ string) (any, error) {
+		return client.ListAllProjects()
+	}},
+	// Pipelines go back to the project's start, so only the most recent page is printed
+	"pipelines": {needsProject: true, fetch: func(client gitlab.API, project string) (any, error) {
+		return client.ListPipelines(project)
+	}},
+	"mrs": {needsProject: true, fetch: func(client gitlab.API, project string) (any, error) {
+		return allPages(func(page int) ([]gitlab.MergeRequest, int, error) {
+			return client.ListMergeRequestsPage(project, page)
+		})
+	}},
+}
+
+// allPages follows a paged list call from the first page to its last
+func allPages[T any](fetch func(page int) ([]T, int, error)) ([]T, error) {
+	var all []T
+	for page := 1; page != 0; {
+		items, next, err := fetch(page)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+		page = next
+	}
+	return all, nil
+}
+
+// exportNames lists the kinds of data -export accepts, for usage messages
+func exportNames() string {
+	return "projects, pipelines <project> (most recent), mrs <project>"
+}
+
+// runExport prints the requested data to stdout and returns the exit code
+func runExport(what, format string, args []string) int {
+	newClient := func() gitlab.API { return app.NewClientFromCredentials() }
+	return export(newClient, os.Stdout, os.Stderr, what, format, args)
+}
+
+// export writes the requested data to stdout, and errors to stderr, and
+// returns the exit code. The client is only created once the usage is valid.
+func export(newClient func() gitlab.API, stdout, stderr io.Writer, what, format string, args []string) int {
+	exp, ok := exporters[what]
+	if !ok {
+		fmt.Fprintf(stderr, "Error: unknown export %q (want %s)\n", what, exportNames())
+		return exitUsage
+	}
+	if format != "json" && format != "yaml" {
+		fmt.Fprintf(stderr, "Error: unknown output format %q (want json or yaml)\n", format)
+		return exitUsage
+	}
+	var project string
+	switch {
+	case exp.needsProject && len(args) != 1:
+		fmt.Fprintf(stderr, "Error: -export %s needs a project, e.g. group/project\n", what)
+		return exitUsage
+	case exp.needsProject:
+		project = args[0]
+	case len(args) != 0:
+		fmt.Fprintf(stderr, "Error: -export %s takes no arguments\n", what)
+		return exitUsage
+	}
+
+	data, err := exp.fetch(newClient(), project)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return exitCode(err)
+	}
+	if err := writeExport(stdout, data, format); err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return exitError
+	}
+	return 0
+}
+
+// writeExport encodes data as indented JSON, or as YAML with the same keys
+func writeExport(w io.Writer, data any, format string) error {
+	out, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	if format == "yaml" {
+		// Round-trip through JSON so YAML uses the API's field names
+		var generic any
+		if err := json.Unmarshal(out, &generic); err != nil {
+			return err
+		}
+		if out, err = yaml.Marshal(generic); err != nil {
+			return err
+		}
+	}
+	_, err = io.WriteString(w, strings.TrimRight(string(out), "\n")+"\n")
+	return err
+}
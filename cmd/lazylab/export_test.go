@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/EspenTeigen/lazylab/internal/gitlab"
+)
+
+// exportAPI serves the lists -export reads, failing with err when set
+type exportAPI struct {
+	gitlab.API
+	projects []gitlab.Project
+	mrPages  [][]gitlab.MergeRequest
+	err      error
+}
+
+func (a *exportAPI) ListAllProjects() ([]gitlab.Project, error) {
+	return a.projects, a.err
+}
+
+func (a *exportAPI) ListMergeRequestsPage(projectID string, page int) ([]gitlab.MergeRequest, int, error) {
+	if a.err != nil {
+		return nil, 0, a.err
+	}
+	next := page + 1
+	if next > len(a.mrPages) {
+		next = 0
+	}
+	return a.mrPages[page-1], next, nil
+}
+
+func runTestExport(api *exportAPI, what, format string, args ...string) (code int, stdout, stderr string) {
+	var out, errOut bytes.Buffer
+	code = export(func() gitlab.API { return api }, &out, &errOut, what, format, args)
+	return code, out.String(), errOut.String()
+}
+
+func TestExport_Formats(t *testing.T) {
+	api := &exportAPI{projects: []gitlab.Project{{ID: 1, Name: "api", PathWithNamespace: "acme/api"}}}
+
+	code, out, _ := runTestExport(api, "projects", "json")
+	if code != 0 || !strings.Contains(out, `"path_with_namespace": "acme/api"`) {
+		t.Errorf("json export: code %d, output %q", code, out)
+	}
+	code, out, _ = runTestExport(api, "projects", "yaml")
+	if code != 0 || !strings.Contains(out, "path_with_namespace: acme/api") {
+		t.Errorf("yaml export should use the API's field names: code %d, output %q", code, out)
+	}
+}
+
+func TestExport_FollowsMRPages(t *testing.T) {
+	api := &exportAPI{mrPages: [][]gitlab.MergeRequest{{{IID: 1}, {IID: 2}}, {{IID: 3}}}}
+	code, out, _ := runTestExport(api, "mrs", "json", "acme/api")
+	if code != 0 || !strings.Contains(out, `"iid": 3`) {
+		t.Errorf("expected MRs from every page: code %d, output %q", code, out)
+	}
+}
+
+func TestExport_ExitCodes(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		what   string
+		format string
+		args   []string
+		want   int
+	}{
+		{"unknown export", nil, "issues", "json", nil, exitUsage},
+		{"unknown format", nil, "projects", "xml", nil, exitUsage},
+		{"missing project", nil, "mrs", "json", nil, exitUsage},
+		{"unexpected argument", nil, "projects", "json", []string{"acme/api"}, exitUsage},
+		{"not found", fmt.Errorf("get project: %w", gitlab.ErrNotFound), "mrs", "json", []string{"acme/gone"}, exitNotFound},
+		{"unauthorized", gitlab.ErrUnauthorized, "projects", "json", nil, exitUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, out, errOut := runTestExport(&exportAPI{err: tt.err}, tt.what, tt.format, tt.args...)
+			if code != tt.want {
+				t.Errorf("exit code %d, want %d (stderr %q)", code, tt.want, errOut)
+			}
+			if out != "" || !strings.HasPrefix(errOut, "Error: ") {
+				t.Errorf("errors belong on stderr: stdout %q, stderr %q", out, errOut)
+			}
+		})
+	}
+}
+
+func TestParseArgs_FlagsAfterArguments(t *testing.T) {
+	tests := []struct {
+		args       []string
+		format     string
+		positional []string
+	}{
+		{[]string{"-export", "pipelines", "g/p", "-o", "yaml"}, "yaml", []string{"g/p"}},
+		{[]string{"-o", "yaml", "-export", "pipelines", "g/p"}, "yaml", []string{"g/p"}},
+		{[]string{"-export", "pipelines", "--", "-odd/name"}, "json", []string{"-odd/name"}},
+	}
+
+	for _, tt := range tests {
+		fs := flag.NewFlagSet("lazylab", flag.ContinueOnError)
+		fs.String("export", "", "")
+		format := fs.String("o", "json", "")
+		positional := parseArgs(fs, tt.args)
+		if *format != tt.format || !slices.Equal(positional, tt.positional) {
+			t.Errorf("parseArgs(%q) = %q with -o %s, want %q with -o %s", tt.args, positional, *format, tt.positional, tt.format)
+		}
+	}
+}
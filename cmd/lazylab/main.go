@@ -1,25 +1,95 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/EspenTeigen/lazylab/internal/app"
+	"github.com/EspenTeigen/lazylab/internal/config"
+	"github.com/EspenTeigen/lazylab/internal/gitlab"
+)
+
+// Exit codes for non-interactive commands
+const (
+	exitError        = 1
+	exitUsage        = 2
+	exitNotFound     = 3
+	exitUnauthorized = 4
 )
 
 func main() {
 	setup := flag.Bool("setup", false, "Configure GitLab connection (add/change host and token)")
 	demo := flag.Bool("demo", false, "Run with mock data (for screenshots/demos)")
-	flag.Parse()
+	snapshot := flag.String("snapshot", "", "Browse GitLab data captured as JSON files in `dir` instead of a GitLab instance")
+	printSSH := flag.Bool("print-ssh", false, "Print the SSH clone URL of the given project and exit")
+	printHTTPS := flag.Bool("print-https", false, "Print the HTTPS clone URL of the given project and exit")
+	export := flag.String("export", "", "Print `data` as JSON, or YAML with -o yaml, and exit: "+exportNames())
+	format := flag.String("o", "json", "Output `format` for -export: json or yaml")
+	debug := flag.Bool("debug", false, "Log HTTP requests to debug.log in the config directory (also LAZYLAB_DEBUG=1)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: lazylab [flags] [lazylab://host/group/project?tab=files&ref=main&path=src]\n       lazylab -print-ssh|-print-https group/project\n       lazylab [-o yaml] -export projects|pipelines|mrs [group/project]\n\n")
+		flag.PrintDefaults()
+	}
+	args := parseArgs(flag.CommandLine, os.Args[1:])
+
+	// The token is redacted, so the log is safe to attach to bug reports
+	if *debug || os.Getenv(config.EnvDebug) != "" {
+		if _, err := app.EnableDebugLog(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: enabling debug log: %v\n", err)
+			os.Exit(exitError)
+		}
+	}
+
+	// Non-interactive mode: print clone URLs without starting the TUI
+	if *printSSH || *printHTTPS {
+		if len(args) != 1 {
+			flag.Usage()
+			os.Exit(exitUsage)
+		}
+		os.Exit(printCloneURLs(args[0], *printSSH, *printHTTPS))
+	}
+	if *export != "" {
+		os.Exit(runExport(*export, *format, args))
+	}
+
+	// A deep link copied with ctrl+l opens straight to that view
+	var link *app.DeepLink
+	if len(args) > 0 {
+		if len(args) > 1 {
+			flag.Usage()
+			os.Exit(exitUsage)
+		}
+		parsed, err := app.ParseDeepLink(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitUsage)
+		}
+		link = &parsed
+	}
 
 	// Check for credentials and show appropriate screen
 	var screen tea.Model
 	if *demo {
 		screen = app.NewDemoScreen()
+	} else if *snapshot != "" {
+		snapshotScreen, err := app.NewSnapshotScreen(*snapshot)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitUsage)
+		}
+		screen = snapshotScreen
 	} else if *setup || !app.HasCredentials() {
-		screen = app.NewLauncher()
+		// Log in first, then open the link
+		if link != nil {
+			screen = app.NewLauncherAt(*link)
+		} else {
+			screen = app.NewLauncher()
+		}
+	} else if link != nil {
+		screen = app.NewMainScreenAt(*link)
 	} else {
 		screen = app.NewMainScreen()
 	}
@@ -36,3 +106,52 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// parseArgs parses flags given before or after the positional arguments, as
+// in "-export pipelines group/project -o yaml", and returns the positional
+// ones. Everything after "--" is positional.
+func parseArgs(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		// fs exits on errors, like flag.Parse
+		_ = fs.Parse(args)
+		rest := fs.Args()
+		if len(rest) == 0 {
+			return positional
+		}
+		if len(rest) < len(args) && args[len(args)-len(rest)-1] == "--" {
+			return append(positional, rest...)
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// printCloneURLs prints the requested clone URLs for a project and returns the exit code
+func printCloneURLs(projectPath string, ssh, https bool) int {
+	client := app.NewClientFromCredentials()
+	project, err := client.GetProject(projectPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitCode(err)
+	}
+
+	if ssh {
+		fmt.Println(project.SSHURLToRepo)
+	}
+	if https {
+		fmt.Println(project.HTTPURLToRepo)
+	}
+	return 0
+}
+
+// exitCode maps an API error to the exit code of a non-interactive command
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, gitlab.ErrNotFound):
+		return exitNotFound
+	case errors.Is(err, gitlab.ErrUnauthorized):
+		return exitUnauthorized
+	}
+	return exitError
+}
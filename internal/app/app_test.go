@@ -3,6 +3,7 @@ package app
 import (
 	"testing"
 
+	"github.com/EspenTeigen/lazylab/internal/gitlab"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -62,6 +63,32 @@ func TestViewStack(t *testing.T) {
 	}
 }
 
+func TestContextStack(t *testing.T) {
+	stack := contextStack{max: 2}
+	if _, ok := stack.Pop(); ok {
+		t.Fatal("expected nothing to pop from an empty stack")
+	}
+
+	for _, name := range []string{"a", "b", "c"} {
+		stack.Push(viewContext{project: &gitlab.Project{Name: name}, tab: TabPipelines, ref: name + "-ref"})
+	}
+	if stack.Len() != 2 {
+		t.Fatalf("expected the stack bounded to 2, got %d", stack.Len())
+	}
+
+	top, _ := stack.Peek()
+	popped, ok := stack.Pop()
+	if !ok || popped.project.Name != "c" || top.project != popped.project || popped.tab != TabPipelines || popped.ref != "c-ref" {
+		t.Errorf("expected to pop c as pushed, got %+v", popped)
+	}
+	if popped, _ = stack.Pop(); popped.project.Name != "b" {
+		t.Errorf("expected b next, the oldest to be dropped, got %q", popped.project.Name)
+	}
+	if stack.Len() != 0 {
+		t.Errorf("expected empty stack, got %d", stack.Len())
+	}
+}
+
 func TestBreadcrumbs(t *testing.T) {
 	stack := NewViewStack()
 	stack.Push(mockView{title: "Groups"})
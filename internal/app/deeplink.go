@@ -0,0 +1,70 @@
+package app
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// deepLinkScheme prefixes links to a lazylab view
+const deepLinkScheme = "lazylab://"
+
+// DeepLink points at a view of a project for a teammate to open with
+// `lazylab <link>`, e.g. lazylab://gitlab.com/group/project?tab=files&ref=main&path=src
+type DeepLink struct {
+	Host    string // GitLab host the project lives on, e.g. gitlab.com
+	Project string // path with namespace, e.g. group/project
+	Tab     string // content tab in lower case, e.g. files or pipelines
+	Ref     string // browsed branch, tag or SHA; empty for the default branch
+	Path    string // browsed directory in the files tab
+}
+
+// String formats the link with its query in a stable, readable order
+func (l DeepLink) String() string {
+	// Slashes are fine in a query and keep refs and paths legible
+	escape := func(s string) string { return strings.ReplaceAll(url.QueryEscape(s), "%2F", "/") }
+	query := []string{"tab=" + escape(cmp.Or(l.Tab, "files"))}
+	if l.Ref != "" {
+		query = append(query, "ref="+escape(l.Ref))
+	}
+	if l.Path != "" {
+		query = append(query, "path="+escape(l.Path))
+	}
+	return deepLinkScheme + l.Host + "/" + l.Project + "?" + strings.Join(query, "&")
+}
+
+// ParseDeepLink parses a link made by DeepLink.String. The lazylab:// prefix
+// may be left out.
+func ParseDeepLink(s string) (DeepLink, error) {
+	s = strings.TrimSpace(s)
+	if !strings.Contains(s, "://") {
+		s = deepLinkScheme + s
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return DeepLink{}, fmt.Errorf("invalid link: %w", err)
+	}
+	if u.Scheme+"://" != deepLinkScheme {
+		return DeepLink{}, fmt.Errorf("invalid link: expected %s, got %s://", deepLinkScheme, u.Scheme)
+	}
+	link := DeepLink{
+		Host:    u.Host,
+		Project: strings.Trim(u.Path, "/"),
+		Tab:     strings.ToLower(u.Query().Get("tab")),
+		Ref:     u.Query().Get("ref"),
+		Path:    strings.Trim(u.Query().Get("path"), "/"),
+	}
+	if link.Host == "" || !strings.Contains(link.Project, "/") {
+		return DeepLink{}, fmt.Errorf("invalid link: expected %shost/group/project", deepLinkScheme)
+	}
+	if link.Tab != "" && deepLinkTab(link.Tab) < 0 {
+		return DeepLink{}, fmt.Errorf("invalid link: unknown tab %q", link.Tab)
+	}
+	return link, nil
+}
+
+// deepLinkTab returns the content tab named by a link, -1 if there's none
+func deepLinkTab(name string) ContentTab {
+	return tabByName(cmp.Or(name, "files"))
+}
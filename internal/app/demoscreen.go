@@ -1,10 +1,13 @@
 package app
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/EspenTeigen/lazylab/internal/gitlab"
 	"github.com/EspenTeigen/lazylab/internal/keymap"
+	"github.com/charmbracelet/bubbles/spinner"
 )
 
 // NewDemoScreen creates a MainScreen with mock data for demos/screenshots
@@ -12,11 +15,15 @@ func NewDemoScreen() *MainScreen {
 	m := &MainScreen{
 		groups:         mockGroups(),
 		groupProjects:  mockGroupProjects(),
-		expandedGroups: map[int]bool{1: true}, // Expand first group
+		expandedGroups: map[int]bool{1: true, favoritesGroupID: true}, // Expand first group and favorites
 		focusedPanel:   PanelNavigator,
 		contentTab:     TabFiles,
 		keymap:         keymap.DefaultKeyMap(),
+		spinner:        spinner.New(spinner.WithSpinner(spinner.Dot)),
 		pipelineJobs:   mockPipelineJobs(),
+		projectCounts:  mockProjectCounts(),
+		currentUser:    &gitlab.User{Username: "achen", Name: "Alice Chen"},
+		todosCount:     3,
 		isDemo:         true,
 	}
 
@@ -26,10 +33,13 @@ func NewDemoScreen() *MainScreen {
 		m.selectedProject = &projects[0]
 		m.files = mockFiles()
 		m.readmeContent = mockReadme()
-		m.readmeRendered = renderMarkdown(mockReadme(), 80)
 		m.pipelines = mockPipelines()
 		m.mergeRequests = mockMergeRequests()
+		m.mrApprovals = mockMRApprovals()
+		m.projectLanguages = map[string]float64{"Go": 91.4, "Shell": 5.2, "Dockerfile": 3.4}
+		m.projectStats = &gitlab.ProjectStatistics{CommitCount: 1203, RepositorySize: 13002342}
 		m.branches = mockBranches()
+		m.tags = mockTags()
 		m.currentBranch = "main"
 	}
 
@@ -66,6 +76,12 @@ func mockGroupProjects() map[int][]gitlab.Project {
 				Description:       "Central API gateway service",
 				DefaultBranch:     "main",
 				WebURL:            "https://gitlab.com/acme-corp/api-gateway",
+				Topics:            []string{"go", "api", "gateway"},
+				Visibility:        "internal",
+				StarCount:         42,
+				ForksCount:        7,
+				SSHURLToRepo:      "git@gitlab.com:acme-corp/api-gateway.git",
+				HTTPURLToRepo:     "https://gitlab.com/acme-corp/api-gateway.git",
 			},
 			{
 				ID:                2,
@@ -97,6 +113,14 @@ func mockGroupProjects() map[int][]gitlab.Project {
 	}
 }
 
+func mockProjectCounts() map[int]gitlab.ProjectCounts {
+	return map[int]gitlab.ProjectCounts{
+		1: {OpenMergeRequests: 4, OpenIssues: 2},
+		2: {OpenMergeRequests: 1},
+		3: {OpenIssues: 5},
+	}
+}
+
 func mockFiles() []gitlab.TreeEntry {
 	now := time.Now()
 	return []gitlab.TreeEntry{
@@ -111,6 +135,80 @@ func mockFiles() []gitlab.TreeEntry {
 	}
 }
 
+// mockCodeFiles are the demo files code search looks through
+var mockCodeFiles = map[string]string{
+	"main.go": `package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os/signal"
+	"syscall"
+
+	"example.com/api-gateway/src/middleware"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM)
+	defer stop()
+
+	mux := http.NewServeMux()
+	handler := middleware.RateLimit(middleware.Auth(mux))
+	srv := &http.Server{Addr: ":8080", Handler: handler}
+
+	go func() {
+		<-ctx.Done()
+		srv.Shutdown(context.Background())
+	}()
+	log.Fatal(srv.ListenAndServe())
+}
+`,
+	"src/middleware/ratelimit.go": `package middleware
+
+import (
+	"net/http"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimit rejects requests over 100 per second
+func RateLimit(next http.Handler) http.Handler {
+	limiter := rate.NewLimiter(100, 200)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !limiter.Allow() {
+			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+`,
+	".gitlab-ci.yml": `stages: [test, build, deploy]
+
+test:
+  stage: test
+  script: go test ./...
+
+deploy:
+  stage: deploy
+  script: ./deploy.sh production
+`,
+}
+
+// demoSearchBlobs searches mockCodeFiles, one match per file like GitLab's
+// grouped results
+func demoSearchBlobs(query string) []gitlab.SearchBlob {
+	var blobs []gitlab.SearchBlob
+	for _, path := range []string{".gitlab-ci.yml", "main.go", "src/middleware/ratelimit.go"} {
+		content := mockCodeFiles[path]
+		if strings.Contains(strings.ToLower(content), strings.ToLower(query)) {
+			blobs = append(blobs, gitlab.SearchBlob{Path: path, Ref: "main", Startline: 1, Data: content})
+		}
+	}
+	return blobs
+}
+
 func mockReadme() string {
 	return `# API Gateway
 
@@ -160,6 +258,7 @@ func mockPipelines() []gitlab.Pipeline {
 			CreatedAt: now.Add(-2 * time.Hour),
 			UpdatedAt: now.Add(-1 * time.Hour),
 			WebURL:    "https://gitlab.com/acme-corp/api-gateway/-/pipelines/1001",
+			Coverage:  "86.40",
 			User:      gitlab.User{Username: "achen", Name: "Alice Chen"},
 		},
 		{
@@ -172,6 +271,7 @@ func mockPipelines() []gitlab.Pipeline {
 			CreatedAt: now.Add(-6 * time.Hour),
 			UpdatedAt: now.Add(-5 * time.Hour),
 			WebURL:    "https://gitlab.com/acme-corp/api-gateway/-/pipelines/1000",
+			Coverage:  "71.20",
 			User:      gitlab.User{Username: "bsmith", Name: "Bob Smith"},
 		},
 		{
@@ -204,6 +304,7 @@ func mockMergeRequests() []gitlab.MergeRequest {
 			Reviewers:    []gitlab.User{},
 			CreatedAt:    now.Add(-30 * time.Minute),
 			WebURL:       "https://gitlab.com/acme-corp/api-gateway/-/merge_requests/24",
+			HeadPipeline: &gitlab.Pipeline{ID: 1004, Status: "running", WebURL: "https://gitlab.com/acme-corp/api-gateway/-/pipelines/1004"},
 		},
 		{
 			IID:          23,
@@ -216,6 +317,7 @@ func mockMergeRequests() []gitlab.MergeRequest {
 			Reviewers:    []gitlab.User{{Username: "bsmith", Name: "Bob Smith"}},
 			CreatedAt:    now.Add(-3 * time.Hour),
 			WebURL:       "https://gitlab.com/acme-corp/api-gateway/-/merge_requests/23",
+			HeadPipeline: &gitlab.Pipeline{ID: 1003, Status: "success"},
 		},
 		{
 			IID:          22,
@@ -229,6 +331,7 @@ func mockMergeRequests() []gitlab.MergeRequest {
 			HasConflicts: true,
 			CreatedAt:    now.Add(-2 * 24 * time.Hour),
 			WebURL:       "https://gitlab.com/acme-corp/api-gateway/-/merge_requests/22",
+			HeadPipeline: &gitlab.Pipeline{ID: 1002, Status: "failed"},
 		},
 		{
 			IID:          21,
@@ -241,7 +344,280 @@ func mockMergeRequests() []gitlab.MergeRequest {
 			Reviewers:    []gitlab.User{{Username: "cjones", Name: "Carol Jones"}},
 			CreatedAt:    now.Add(-5 * 24 * time.Hour),
 			WebURL:       "https://gitlab.com/acme-corp/api-gateway/-/merge_requests/21",
+			HeadPipeline: &gitlab.Pipeline{ID: 1001, Status: "success"},
+		},
+	}
+}
+
+func mockMRApprovals() map[int]*gitlab.MRApprovals {
+	approved := func(n, required int) *gitlab.MRApprovals {
+		return &gitlab.MRApprovals{
+			ApprovalsRequired: required,
+			ApprovalsLeft:     max(required-n, 0),
+			ApprovedBy:        make([]gitlab.MRApprover, n),
+		}
+	}
+	return map[int]*gitlab.MRApprovals{
+		23: approved(1, 2),
+		22: approved(0, 2),
+		21: approved(2, 2),
+	}
+}
+
+func mockMyMergeRequests() (assigned, review []gitlab.MergeRequest) {
+	mrs := mockMergeRequests()
+	for i := range mrs {
+		mrs[i].ProjectID = 1
+		mrs[i].References.Full = fmt.Sprintf("acme-corp/api-gateway!%d", mrs[i].IID)
+	}
+	return []gitlab.MergeRequest{mrs[0], mrs[3]}, []gitlab.MergeRequest{mrs[1], mrs[2]}
+}
+
+func mockComparison() *gitlab.Comparison {
+	now := time.Now()
+	return &gitlab.Comparison{
+		Commits: []gitlab.Commit{
+			{ID: "c1d2e3f4a5b6", Title: "Add token bucket rate limiter", AuthorName: "Bob Smith", AuthoredDate: now.Add(-26 * time.Hour)},
+			{ID: "d2e3f4a5b6c7", Title: "Wire rate limiter into middleware chain", AuthorName: "Bob Smith", AuthoredDate: now.Add(-5 * time.Hour)},
+		},
+		Diffs: []gitlab.Diff{
+			{
+				OldPath: "internal/ratelimit/bucket.go",
+				NewPath: "internal/ratelimit/bucket.go",
+				NewFile: true,
+				Diff:    "@@ -0,0 +1,6 @@\n+package ratelimit\n+\n+// Bucket is a token bucket rate limiter\n+type Bucket struct {\n+\ttokens int\n+}\n",
+			},
+			{
+				OldPath: "cmd/server/main.go",
+				NewPath: "cmd/server/main.go",
+				Diff:    "@@ -12,7 +12,8 @@ func main() {\n \tmux := http.NewServeMux()\n-\thandler := logging(mux)\n+\tlimiter := ratelimit.New(100)\n+\thandler := logging(limiter.Wrap(mux))\n \tlog.Fatal(http.ListenAndServe(\":8080\", handler))\n }\n",
+			},
+		},
+	}
+}
+
+// mockCommitDiff returns the demo diff of an entry's last commit
+func mockCommitDiff(entry gitlab.TreeEntry) []gitlab.Diff {
+	if entry.Type == "tree" {
+		return mockComparison().Diffs
+	}
+	title := "Update " + entry.Name
+	if entry.LastCommit != nil {
+		title = entry.LastCommit.Title
+	}
+	first, _, _ := strings.Cut(MockFileContent[entry.Name], "\n")
+	return []gitlab.Diff{{
+		OldPath: entry.Path,
+		NewPath: entry.Path,
+		Diff:    "@@ -1,2 +1,3 @@\n " + first + "\n+# " + title + "\n \n",
+	}}
+}
+
+func mockVariables() []gitlab.CIVariable {
+	return []gitlab.CIVariable{
+		{Key: "DATABASE_URL", Value: "postgres://api:secret@db:5432/api", VariableType: "env_var", Protected: true, Masked: true, EnvironmentScope: "production"},
+		{Key: "DOCKER_REGISTRY", Value: "registry.acme-corp.example", VariableType: "env_var", EnvironmentScope: "*"},
+		{Key: "LOG_LEVEL", Value: "debug", VariableType: "env_var", EnvironmentScope: "staging"},
+		{Key: "SENTRY_DSN", Value: "https://key@sentry.example/42", VariableType: "env_var", Masked: true, EnvironmentScope: "*"},
+		{Key: "KUBECONFIG", Value: "apiVersion: v1\nkind: Config", VariableType: "file", Protected: true, EnvironmentScope: "production"},
+	}
+}
+
+func mockSnippets() []gitlab.Snippet {
+	now := time.Now()
+	return []gitlab.Snippet{
+		{ID: 101, Title: "Rotate staging credentials", FileName: "rotate-creds.sh", Visibility: "private",
+			Author: gitlab.User{Username: "achen", Name: "Alice Chen"}, UpdatedAt: now.Add(-2 * time.Hour)},
+		{ID: 102, Title: "Seed local database", FileName: "seed.sql", Visibility: "internal",
+			Author: gitlab.User{Username: "bmiller", Name: "Bob Miller"}, UpdatedAt: now.Add(-72 * time.Hour)},
+		{ID: 103, Title: "Load test plan", FileName: "loadtest.md", Visibility: "internal",
+			Author: gitlab.User{Username: "cwong", Name: "Carol Wong"}, UpdatedAt: now.Add(-240 * time.Hour)},
+	}
+}
+
+// mockSnippetContent holds the demo content of each mock snippet by ID
+var mockSnippetContent = map[int]string{
+	101: `#!/usr/bin/env bash
+set -euo pipefail
+
+# Rotate the staging database password and restart the API
+NEW_PASSWORD=$(openssl rand -base64 24)
+kubectl -n staging create secret generic api-db \
+  --from-literal=password="$NEW_PASSWORD" --dry-run=client -o yaml | kubectl apply -f -
+kubectl -n staging rollout restart deployment/api-gateway
+`,
+	102: `-- Minimal data set for local development
+INSERT INTO users (id, email, role) VALUES
+  (1, 'admin@acme-corp.example', 'admin'),
+  (2, 'dev@acme-corp.example', 'developer');
+
+INSERT INTO api_keys (user_id, name) VALUES (2, 'local');
+`,
+	103: `# Load test plan
+
+1. Ramp to 500 rps over 5 minutes
+2. Hold for 15 minutes
+3. Watch p99 latency and error rate in Grafana
+`,
+}
+
+func mockContributors() []gitlab.Contributor {
+	return []gitlab.Contributor{
+		{Name: "Alice Chen", Email: "achen@acme-corp.example", Commits: 412, Additions: 58210, Deletions: 21877},
+		{Name: "Bob Smith", Email: "bsmith@acme-corp.example", Commits: 187, Additions: 19544, Deletions: 8012},
+		{Name: "Carol Wong", Email: "cwong@acme-corp.example", Commits: 96, Additions: 7330, Deletions: 2954},
+		{Name: "Dependency Bot", Email: "bot@acme-corp.example", Commits: 41, Additions: 1208, Deletions: 1156},
+		{Name: "Dave Patel", Email: "dpatel@acme-corp.example", Commits: 3, Additions: 88, Deletions: 12},
+	}
+}
+
+func mockEvents() []gitlab.Event {
+	now := time.Now()
+	alice := gitlab.User{Username: "achen", Name: "Alice Chen"}
+	bob := gitlab.User{Username: "bsmith", Name: "Bob Smith"}
+	carol := gitlab.User{Username: "cwong", Name: "Carol Wong"}
+	return []gitlab.Event{
+		{ID: 906, ActionName: "pushed to", Author: alice, CreatedAt: now.Add(-2 * time.Hour),
+			PushData: &gitlab.PushData{CommitCount: 3, RefType: "branch", Ref: "main", CommitTitle: "Add rate limiting middleware"}},
+		{ID: 905, ActionName: "commented on", TargetType: "Note", TargetTitle: "Add OAuth2 support", Author: carol, CreatedAt: now.Add(-4 * time.Hour)},
+		{ID: 904, ActionName: "opened", TargetType: "MergeRequest", TargetIID: 23, TargetTitle: "Add OAuth2 support", Author: bob, CreatedAt: now.Add(-5 * time.Hour)},
+		{ID: 903, ActionName: "pushed new", Author: bob, CreatedAt: now.Add(-6 * time.Hour),
+			PushData: &gitlab.PushData{CommitCount: 1, RefType: "branch", Ref: "feature/oauth2", CommitTitle: "Add OAuth2 provider config"}},
+		{ID: 902, ActionName: "accepted", TargetType: "MergeRequest", TargetIID: 21, TargetTitle: "Fix token refresh race", Author: alice, CreatedAt: now.Add(-26 * time.Hour)},
+		{ID: 901, ActionName: "closed", TargetType: "Issue", TargetIID: 58, TargetTitle: "Login fails behind proxy", Author: carol, CreatedAt: now.Add(-3 * 24 * time.Hour)},
+	}
+}
+
+func mockLabels() []gitlab.Label {
+	return []gitlab.Label{
+		{ID: 1, Name: "bug", Color: "#dc143c", Description: "Something isn't working"},
+		{ID: 2, Name: "documentation", Color: "#428bca", Description: "Docs and examples"},
+		{ID: 3, Name: "feature", Color: "#69d100", Description: "New functionality"},
+		{ID: 4, Name: "needs-review", Color: "#ed9121"},
+		{ID: 5, Name: "security", Color: "#d10069", Description: "Handled by the security team"},
+		{ID: 6, Name: "wontfix", Color: "#7f8c8d"},
+	}
+}
+
+func mockMilestones() []gitlab.Milestone {
+	dueIn := func(days int) string {
+		return time.Now().AddDate(0, 0, days).Format("2006-01-02")
+	}
+	return []gitlab.Milestone{
+		{ID: 31, IID: 4, Title: "v2.5.0", State: "active", DueDate: dueIn(12)},
+		{ID: 30, IID: 3, Title: "Q3 hardening", State: "active", DueDate: dueIn(-5)},
+		{ID: 32, IID: 5, Title: "Backlog", State: "active"},
+		{ID: 29, IID: 2, Title: "v2.4.0", State: "closed", DueDate: dueIn(-40)},
+	}
+}
+
+func mockWikiPages() []gitlab.WikiPage {
+	return []gitlab.WikiPage{
+		{Slug: "home", Title: "home", Format: "markdown"},
+		{Slug: "runbooks", Title: "runbooks", Format: "markdown"},
+		{Slug: "runbooks/rate-limit-alerts", Title: "rate-limit-alerts", Format: "markdown"},
+		{Slug: "architecture", Title: "architecture", Format: "markdown"},
+	}
+}
+
+// mockWikiContent holds the demo content of each mock wiki page by slug
+var mockWikiContent = map[string]string{
+	"home": `# API Gateway wiki
+
+Start with the [architecture](architecture) overview. On-call engineers
+should keep the [runbooks](runbooks) handy.
+`,
+	"runbooks": `# Runbooks
+
+- [Rate limit alerts](runbooks/rate-limit-alerts)
+`,
+	"runbooks/rate-limit-alerts": `# Rate limit alerts
+
+1. Check the **429 rate** panel in Grafana
+2. Find the noisy client in the access logs
+3. Raise its quota in ` + "`config/limits.yaml`" + ` if the traffic is expected
+`,
+	"architecture": `# Architecture
+
+Requests pass through the gateway, which authenticates them, applies rate
+limits and routes them to the backing services.
+`,
+}
+
+func mockEnvironments() []gitlab.Environment {
+	now := time.Now()
+	return []gitlab.Environment{
+		{
+			ID:          1,
+			Name:        "production",
+			State:       "available",
+			Tier:        "production",
+			ExternalURL: "https://api.acme-corp.example",
+			LastDeployment: &gitlab.Deployment{
+				IID: 42, Ref: "main", SHA: "a1b2c3d4e5f6", Status: "success",
+				CreatedAt: now.Add(-3 * time.Hour),
+				User:      gitlab.User{Username: "achen", Name: "Alice Chen"},
+			},
+		},
+		{
+			ID:          2,
+			Name:        "staging",
+			State:       "available",
+			Tier:        "staging",
+			ExternalURL: "https://staging.acme-corp.example",
+			LastDeployment: &gitlab.Deployment{
+				IID: 57, Ref: "feature/rate-limiting", SHA: "f6e5d4c3b2a1", Status: "running",
+				CreatedAt: now.Add(-5 * time.Minute),
+				User:      gitlab.User{Username: "bsmith", Name: "Bob Smith"},
+			},
+		},
+		{
+			ID:    3,
+			Name:  "review/fix-auth",
+			State: "stopped",
+			Tier:  "development",
 		},
+		{
+			ID:          4,
+			Name:        "review/feature-oauth2",
+			State:       "available",
+			Tier:        "development",
+			ExternalURL: "https://feature-oauth2.review.acme-corp.example",
+			LastDeployment: &gitlab.Deployment{
+				IID: 58, Ref: "feature/oauth2", SHA: "0a1b2c3d4e5f", Status: "success",
+				CreatedAt: now.Add(-25 * time.Minute),
+				User:      gitlab.User{Username: "cjones", Name: "Carol Jones"},
+			},
+		},
+	}
+}
+
+func mockDeployments(environment string) []gitlab.Deployment {
+	now := time.Now()
+	var deployments []gitlab.Deployment
+	for _, env := range mockEnvironments() {
+		if env.Name != environment || env.LastDeployment == nil {
+			continue
+		}
+		deployments = append(deployments, *env.LastDeployment)
+		for i := 1; i <= 4; i++ {
+			d := *env.LastDeployment
+			d.IID -= i
+			d.Status = "success"
+			d.SHA = fmt.Sprintf("%07x", 0x1a2b3c4*i)
+			d.CreatedAt = now.Add(-time.Duration(i) * 26 * time.Hour)
+			deployments = append(deployments, d)
+		}
+	}
+	return deployments
+}
+
+func mockTags() []gitlab.Tag {
+	now := time.Now()
+	return []gitlab.Tag{
+		{Name: "v2.4.0", Message: "Rate limiting and metrics", Commit: gitlab.Commit{ID: "a1b2c3d4e5f6", Title: "Release 2.4.0", CommittedDate: now.Add(-48 * time.Hour)}},
+		{Name: "v2.3.1", Commit: gitlab.Commit{ID: "b2c3d4e5f6a1", Title: "Fix token refresh race", CommittedDate: now.Add(-10 * 24 * time.Hour)}},
+		{Name: "v2.3.0", Commit: gitlab.Commit{ID: "c3d4e5f6a1b2", Title: "Release 2.3.0", CommittedDate: now.Add(-21 * 24 * time.Hour)}},
 	}
 }
 
@@ -250,7 +626,7 @@ func mockBranches() []gitlab.Branch {
 		{Name: "main", Default: true, Protected: true, Commit: gitlab.Commit{Title: "Merge branch 'feature/logging' into main", AuthorName: "Alice Chen"}},
 		{Name: "develop", Default: false, Protected: true, Commit: gitlab.Commit{Title: "Add prometheus metrics endpoint", AuthorName: "Bob Smith"}},
 		{Name: "feature/rate-limit", Default: false, Protected: false, Commit: gitlab.Commit{Title: "Implement token bucket algorithm", AuthorName: "Alice Chen"}},
-		{Name: "feature/auth", Default: false, Protected: false, Commit: gitlab.Commit{Title: "Fix JWT validation for expired tokens", AuthorName: "Bob Smith"}},
+		{Name: "feature/auth", Default: false, Protected: false, Merged: true, Commit: gitlab.Commit{Title: "Fix JWT validation for expired tokens", AuthorName: "Bob Smith"}},
 		{Name: "fix/auth-timeout", Default: false, Protected: false, Commit: gitlab.Commit{Title: "Increase timeout to 30 seconds", AuthorName: "Bob Smith"}},
 	}
 }
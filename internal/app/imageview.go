@@ -0,0 +1,91 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // registers the GIF decoder
+	_ "image/jpeg"
+	_ "image/png"
+	"path/filepath"
+	"strings"
+
+	"github.com/EspenTeigen/lazylab/internal/config"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// isImageFile reports whether path has an extension the image preview can decode
+func isImageFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png", ".jpg", ".jpeg", ".gif":
+		return true
+	}
+	return false
+}
+
+// decodeImagePreview decodes an image for the file viewer, refusing ones too
+// large to be worth rendering at terminal resolution
+func decodeImagePreview(data []byte) (image.Image, error) {
+	if len(data) > config.MaxFileViewBytes {
+		return nil, fmt.Errorf("image is %d bytes", len(data))
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width*cfg.Height > config.MaxImagePreviewPixels {
+		return nil, fmt.Errorf("image is %dx%d", cfg.Width, cfg.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
+
+// renderImageBlocks draws img at most maxWidth cells wide using half blocks:
+// each cell shows two pixels, the top one in the foreground color and the
+// bottom one in the background, which keeps pixels roughly square.
+// Transparent pixels are left blank.
+func renderImageBlocks(img image.Image, maxWidth int) string {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w == 0 || h == 0 || maxWidth <= 0 {
+		return ""
+	}
+	cols := min(w, maxWidth)
+	rows := max(h*cols/w, 1)
+
+	// Nearest-neighbor sample of the scaled image, nil when transparent
+	pixel := func(x, y int) *lipgloss.Color {
+		c := color.NRGBAModel.Convert(img.At(bounds.Min.X+x*w/cols, bounds.Min.Y+y*h/rows)).(color.NRGBA)
+		if c.A < 128 {
+			return nil
+		}
+		hex := lipgloss.Color(fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B))
+		return &hex
+	}
+
+	var b strings.Builder
+	for y := 0; y < rows; y += 2 {
+		for x := 0; x < cols; x++ {
+			top := pixel(x, y)
+			var bottom *lipgloss.Color
+			if y+1 < rows {
+				bottom = pixel(x, y+1)
+			}
+			switch {
+			case top == nil && bottom == nil:
+				b.WriteString(" ")
+			case top == nil:
+				b.WriteString(lipgloss.NewStyle().Foreground(*bottom).Render("▄"))
+			case bottom == nil:
+				b.WriteString(lipgloss.NewStyle().Foreground(*top).Render("▀"))
+			default:
+				b.WriteString(lipgloss.NewStyle().Foreground(*top).Background(*bottom).Render("▀"))
+			}
+		}
+		if y+2 < rows {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
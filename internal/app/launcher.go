@@ -9,6 +9,7 @@ type Launcher struct {
 	loginScreen *LoginScreen
 	mainScreen  *MainScreen
 	loggedIn    bool
+	deepLink    *DeepLink // opened once logged in
 	width       int
 	height      int
 }
@@ -21,6 +22,13 @@ func NewLauncher() *Launcher {
 	}
 }
 
+// NewLauncherAt creates a Launcher that opens the view link points at after login
+func NewLauncherAt(link DeepLink) *Launcher {
+	l := NewLauncher()
+	l.deepLink = &link
+	return l
+}
+
 // Init initializes the launcher
 func (l *Launcher) Init() tea.Cmd {
 	return l.loginScreen.Init()
@@ -44,7 +52,11 @@ func (l *Launcher) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Check for successful login
 	if _, ok := msg.(loginSuccessMsg); ok {
 		l.loggedIn = true
-		l.mainScreen = NewMainScreen()
+		if l.deepLink != nil {
+			l.mainScreen = NewMainScreenAt(*l.deepLink)
+		} else {
+			l.mainScreen = NewMainScreen()
+		}
 		l.loginScreen = nil
 
 		// Send window size and init to main screen
@@ -0,0 +1,126 @@
+package app
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/EspenTeigen/lazylab/internal/config"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// sgrRegex matches SGR (color and style) escape sequences
+var sgrRegex = regexp.MustCompile(`\x1b\[([0-9;]*)m`)
+
+// basicColors are the RGB values of the 16 basic colors in xterm
+var basicColors = [16][3]int{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// cubeLevels are the channel values of the 6x6x6 color cube in the 256 palette
+var cubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+// resolveLogColorMode turns auto into the job log colors the terminal can show
+func resolveLogColorMode(mode string) string {
+	if mode != config.LogColorAuto {
+		return mode
+	}
+	switch lipgloss.ColorProfile() {
+	case termenv.Ascii:
+		return config.LogColorNone
+	case termenv.ANSI:
+		return config.LogColor16
+	}
+	return config.LogColor256
+}
+
+// downsampleLogColors limits the colors of a job log: none strips escape
+// codes, 16 maps 256-color and truecolor codes to the nearest basic color
+func downsampleLogColors(log, mode string) string {
+	switch mode {
+	case config.LogColorNone:
+		return stripANSI(log)
+	case config.LogColor16:
+		return sgrRegex.ReplaceAllStringFunc(log, downsampleSGR)
+	}
+	return log
+}
+
+// downsampleSGR rewrites the extended colors of one SGR sequence as basic
+// ones, keeping its other attributes
+func downsampleSGR(seq string) string {
+	params := strings.Split(seq[2:len(seq)-1], ";")
+	out := make([]string, 0, len(params))
+	for i := 0; i < len(params); i++ {
+		p := params[i]
+		if (p != "38" && p != "48") || i+1 >= len(params) {
+			out = append(out, p)
+			continue
+		}
+		base := 30
+		if p == "48" {
+			base = 40
+		}
+		switch {
+		case params[i+1] == "5" && i+2 < len(params):
+			n, _ := strconv.Atoi(params[i+2])
+			out = append(out, strconv.Itoa(basicColorCode(ansi256To16(n), base)))
+			i += 2
+		case params[i+1] == "2" && i+4 < len(params):
+			r, _ := strconv.Atoi(params[i+2])
+			g, _ := strconv.Atoi(params[i+3])
+			b, _ := strconv.Atoi(params[i+4])
+			out = append(out, strconv.Itoa(basicColorCode(nearestBasicColor(r, g, b), base)))
+			i += 4
+		default:
+			out = append(out, p)
+		}
+	}
+	return "\x1b[" + strings.Join(out, ";") + "m"
+}
+
+// ansi256To16 returns the basic color closest to a color of the 256 palette
+func ansi256To16(n int) int {
+	switch {
+	case n < 0 || n > 255:
+		return 7
+	case n < 16:
+		return n
+	case n < 232:
+		n -= 16
+		return nearestBasicColor(cubeLevels[n/36], cubeLevels[n/6%6], cubeLevels[n%6])
+	}
+	gray := 8 + 10*(n-232)
+	return nearestBasicColor(gray, gray, gray)
+}
+
+// nearestBasicColor returns the basic color closest to an RGB value
+func nearestBasicColor(r, g, b int) int {
+	return nearestColor([3]int{r, g, b}, basicColors[:])
+}
+
+// nearestColor returns the index of the palette color closest to an RGB
+// value, by squared distance
+func nearestColor(rgb [3]int, palette [][3]int) int {
+	best, bestDist := 0, -1
+	for i, c := range palette {
+		dr, dg, db := rgb[0]-c[0], rgb[1]-c[1], rgb[2]-c[2]
+		if dist := dr*dr + dg*dg + db*db; bestDist < 0 || dist < bestDist {
+			best, bestDist = i, dist
+		}
+	}
+	return best
+}
+
+// basicColorCode returns the SGR parameter of basic color c, with base 30
+// for foreground and 40 for background
+func basicColorCode(c, base int) int {
+	if c < 8 {
+		return base + c
+	}
+	return base + 60 + c - 8
+}
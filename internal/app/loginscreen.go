@@ -1,13 +1,16 @@
 package app
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/EspenTeigen/lazylab/internal/config"
+	"github.com/EspenTeigen/lazylab/internal/gitlab"
 	"github.com/EspenTeigen/lazylab/internal/ui/styles"
 )
 
@@ -27,6 +30,8 @@ type LoginScreen struct {
 	width      int
 	height     int
 	errMsg     string
+	validating bool // Token check in flight
+	spinner    spinner.Model
 }
 
 // NewLoginScreen creates a new login screen
@@ -49,6 +54,7 @@ func NewLoginScreen() *LoginScreen {
 		hostInput:  hostInput,
 		tokenInput: tokenInput,
 		focused:    FieldToken,
+		spinner:    spinner.New(spinner.WithSpinner(spinner.Dot)),
 	}
 }
 
@@ -60,6 +66,13 @@ func (m *LoginScreen) Init() tea.Cmd {
 // loginSuccessMsg signals successful login
 type loginSuccessMsg struct{}
 
+// tokenValidatedMsg carries the result of checking a token against the API
+type tokenValidatedMsg struct {
+	host  string
+	token string
+	err   error
+}
+
 // Update handles messages
 func (m *LoginScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
@@ -68,10 +81,38 @@ func (m *LoginScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		return m, nil
 
+	case tokenValidatedMsg:
+		m.validating = false
+		if msg.err != nil {
+			if errors.Is(msg.err, gitlab.ErrUnauthorized) {
+				m.errMsg = "Invalid token"
+			} else {
+				m.errMsg = fmt.Sprintf("Could not reach %s: %v", msg.host, msg.err)
+			}
+			return m, nil
+		}
+		return m.saveCredentials(msg.host, msg.token)
+
+	case spinner.TickMsg:
+		if m.validating {
+			var cmd tea.Cmd
+			m.spinner, cmd = m.spinner.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c", "esc":
 			return m, tea.Quit
+		}
+
+		// Keep the form as is while the token is being checked
+		if m.validating {
+			return m, nil
+		}
+
+		switch msg.String() {
 
 		case "tab", "shift+tab", "up", "down":
 			// Toggle between fields
@@ -111,14 +152,33 @@ func (m *LoginScreen) handleSubmit() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	if host == "" {
-		host = config.DefaultHost
+	// Hosts are keyed without the scheme unless it isn't the default https
+	host = config.HostKey(host)
+
+	// Check the token works before saving it
+	m.errMsg = ""
+	m.validating = true
+	return m, tea.Batch(m.spinner.Tick, validateToken(host, token))
+}
+
+// validateToken fetches the current user to confirm the token is accepted
+func validateToken(host, token string) tea.Cmd {
+	return func() tea.Msg {
+		_, err := gitlab.NewClient(config.NormalizeHost(host), token, debugOptions()...).GetCurrentUser()
+		return tokenValidatedMsg{host: host, token: token, err: err}
 	}
+}
 
-	// Save to config
-	cfg := &config.LazyLabConfig{
-		DefaultHost: host,
+// saveCredentials stores a validated token and signals the login is done
+func (m *LoginScreen) saveCredentials(host, token string) (tea.Model, tea.Cmd) {
+	// Save to config, keeping other settings such as keys and favorites. A
+	// config that doesn't parse is left for the user to fix, not overwritten.
+	cfg, err := config.LoadLazyLabConfigForUpdate()
+	if err != nil {
+		m.errMsg = fmt.Sprintf("Not saving the token, config didn't load: %v", err)
+		return m, nil
 	}
+	cfg.DefaultHost = host
 	cfg.SetHostToken(host, token)
 
 	if err := config.SaveLazyLabConfig(cfg); err != nil {
@@ -174,7 +234,9 @@ func (m *LoginScreen) View() string {
 
 	// Error message
 	errView := ""
-	if m.errMsg != "" {
+	if m.validating {
+		errView = "\n\n" + m.spinner.View() + " Checking token..."
+	} else if m.errMsg != "" {
 		errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
 		errView = "\n\n" + errStyle.Render("Error: "+m.errMsg)
 	}
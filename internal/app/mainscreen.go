@@ -2,26 +2,45 @@ package app
 
 import (
 	"bytes"
+	"cmp"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"image"
+	"io"
+	"maps"
+	"math"
+	"math/rand/v2"
+	"net/url"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf16"
+	"unicode/utf8"
 
 	"github.com/alecthomas/chroma/v2"
 	"github.com/alecthomas/chroma/v2/formatters"
 	"github.com/alecthomas/chroma/v2/lexers"
 	chromaStyles "github.com/alecthomas/chroma/v2/styles"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/EspenTeigen/lazylab/internal/config"
 	"github.com/EspenTeigen/lazylab/internal/gitlab"
 	"github.com/EspenTeigen/lazylab/internal/keymap"
@@ -29,6 +48,51 @@ import (
 	"github.com/EspenTeigen/lazylab/internal/ui/styles"
 )
 
+// editorCommand returns the command used to open files externally:
+// $EDITOR, then $PAGER, falling back to vi. Arguments in the variable are kept.
+func editorCommand() []string {
+	for _, env := range []string{"EDITOR", "PAGER"} {
+		if parts := strings.Fields(os.Getenv(env)); len(parts) > 0 {
+			return parts
+		}
+	}
+	return []string{"vi"}
+}
+
+// openInEditor writes content to a temp file named after path, off the update
+// loop as the file can be large, and reports it with editorFileReadyMsg
+func openInEditor(path, content string) tea.Cmd {
+	return func() tea.Msg {
+		// Keep the real extension so the editor picks the right syntax highlighting
+		f, err := os.CreateTemp("", "lazylab-*-"+filepath.Base(path))
+		if err != nil {
+			return editorFinishedMsg{err: err}
+		}
+		tmpPath := f.Name()
+		if _, err := f.WriteString(content); err != nil {
+			f.Close()
+			os.Remove(tmpPath)
+			return editorFinishedMsg{err: err}
+		}
+		if err := f.Close(); err != nil {
+			os.Remove(tmpPath)
+			return editorFinishedMsg{err: err}
+		}
+		return editorFileReadyMsg{path: tmpPath}
+	}
+}
+
+// editTempFile opens tmpPath with editorCommand, suspending the TUI until the
+// editor exits, and removes it afterwards
+func editTempFile(tmpPath string) tea.Cmd {
+	args := editorCommand()
+	cmd := exec.Command(args[0], append(args[1:], tmpPath)...)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		os.Remove(tmpPath)
+		return editorFinishedMsg{err: err}
+	})
+}
+
 // copyToClipboard copies text to the system clipboard
 func copyToClipboard(text string) error {
 	var cmd *exec.Cmd
@@ -69,6 +133,12 @@ func copyToClipboard(text string) error {
 	return cmd.Wait()
 }
 
+// markdownLink formats a markdown link, escaping brackets in the text
+func markdownLink(text, url string) string {
+	text = strings.NewReplacer("[", "\\[", "]", "\\]").Replace(text)
+	return "[" + text + "](" + url + ")"
+}
+
 // ansiRegex matches ANSI escape sequences
 var ansiRegex = regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]`)
 
@@ -84,6 +154,92 @@ func truncateString(s string, maxLen int) string {
 	return s[:maxLen-3] + "..."
 }
 
+// formatDuration returns a compact human duration like "45s", "4m12s" or "1h3m"
+func formatDuration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	d = d.Round(time.Second)
+	h := int(d.Hours())
+	m := int(d.Minutes()) % 60
+	sec := int(d.Seconds()) % 60
+	switch {
+	case h > 0:
+		return fmt.Sprintf("%dh%dm", h, m)
+	case m > 0:
+		return fmt.Sprintf("%dm%02ds", m, sec)
+	default:
+		return fmt.Sprintf("%ds", sec)
+	}
+}
+
+// formatElapsed formats a duration as a stopwatch, "MM:SS" or "H:MM:SS" past an hour
+func formatElapsed(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	total := int(d.Round(time.Second).Seconds())
+	h := total / 3600
+	m := (total % 3600) / 60
+	sec := total % 60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, sec)
+	}
+	return fmt.Sprintf("%02d:%02d", m, sec)
+}
+
+// pipelineDuration returns how long a pipeline ran (or has been running).
+// The list endpoint doesn't return timings, so they're derived from the
+// pipeline's jobs when available, falling back to created/updated times.
+// Returns false for pipelines that haven't started.
+func pipelineDuration(p gitlab.Pipeline, jobs []gitlab.Job) (time.Duration, bool) {
+	running := p.Status == "running"
+	switch p.Status {
+	case "created", "pending", "waiting_for_resource", "preparing", "scheduled", "manual", "skipped":
+		return 0, false
+	}
+
+	if p.Duration > 0 && !running {
+		return time.Duration(p.Duration) * time.Second, true
+	}
+
+	start := p.StartedAt
+	end := p.FinishedAt
+	for _, job := range jobs {
+		if job.StartedAt != nil && (start == nil || job.StartedAt.Before(*start)) {
+			start = job.StartedAt
+		}
+		if job.FinishedAt != nil && (end == nil || job.FinishedAt.After(*end)) {
+			end = job.FinishedAt
+		}
+	}
+	if start == nil {
+		start = &p.CreatedAt
+	}
+
+	if running {
+		return time.Since(*start), true
+	}
+	if end == nil {
+		end = &p.UpdatedAt
+	}
+	return end.Sub(*start), true
+}
+
+// coverageBadge renders a pipeline's coverage percentage, green at or above
+// threshold and red below. Returns "" when the pipeline reports no coverage.
+func coverageBadge(coverage string, threshold float64) string {
+	pct, err := strconv.ParseFloat(coverage, 64)
+	if err != nil {
+		return ""
+	}
+	style := styles.PipelineStatus("failed")
+	if pct >= threshold {
+		style = styles.PipelineStatus("success")
+	}
+	return style.Render(fmt.Sprintf("%.1f%%", pct))
+}
+
 // timeAgo formats a time as a human-readable relative time
 func timeAgo(t time.Time) string {
 	d := time.Since(t)
@@ -165,6 +321,55 @@ func stripANSI(s string) string {
 	return ansiRegex.ReplaceAllString(s, "")
 }
 
+// runnerEpilogue starts the lines a runner prints after the job's script,
+// such as "ERROR: Job failed: exit code 1", which would otherwise always be
+// the last marker
+var runnerEpilogue = []string{"ERROR: Job failed", "Cleaning up", "section_start:", "section_end:"}
+
+// isRunnerEpilogue reports whether line is blank or printed by the runner
+// after the script. Only the text after a carriage return is shown, so
+// that's what is checked.
+func isRunnerEpilogue(line string) bool {
+	line = strings.TrimSpace(line[strings.LastIndex(line, "\r")+1:])
+	if line == "" {
+		return true
+	}
+	for _, prefix := range runnerEpilogue {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// errorSummary returns the failing tail of a job log: the lines from the last
+// one containing any of markers to the end, or the last fallback lines when
+// none matches. ANSI codes and the runner's epilogue after the script are
+// removed first.
+func errorSummary(log string, markers []string, fallback int) []string {
+	lines := strings.Split(stripANSI(log), "\n")
+	end := len(lines)
+	for end > 0 && isRunnerEpilogue(lines[end-1]) {
+		end--
+	}
+	// A log of nothing but epilogue keeps it, less the blank lines
+	if end == 0 {
+		for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+			lines = lines[:len(lines)-1]
+		}
+		end = len(lines)
+	}
+	lines = lines[:end]
+	for i := len(lines) - 1; i >= 0; i-- {
+		for _, marker := range markers {
+			if marker != "" && strings.Contains(lines[i], marker) {
+				return lines[i:]
+			}
+		}
+	}
+	return lines[max(len(lines)-fallback, 0):]
+}
+
 // Custom markdown style based on dark theme
 var markdownStyle = []byte(`{
 	"document": {
@@ -310,17 +515,74 @@ func renderMarkdown(content string, width int) string {
 	return strings.TrimSpace(rendered)
 }
 
-// isBinaryContent checks if content appears to be binary
+// isBinaryContent checks if content appears to be binary: the first 8KB
+// has a null byte or is more than a tenth control characters
 func isBinaryContent(content string) bool {
-	// Check first 8KB for null bytes (strong indicator of binary)
-	checkLen := 8192
-	if len(content) < checkLen {
-		checkLen = len(content)
-	}
+	checkLen := min(len(content), 8192)
+	control := 0
 	for i := 0; i < checkLen; i++ {
-		if content[i] == 0 {
-			return true
+		switch c := content[i]; {
+		case c == 0:
+			return true // Strong indicator of binary
+		case c == '\t', c == '\n', c == '\r', c == '\f', c == 0x1b:
+			// Whitespace, and escapes from colored output
+		case c < 0x20, c == 0x7f:
+			control++
+		}
+	}
+	return control*10 > checkLen
+}
+
+// decodeBOM strips a byte order mark, converting UTF-16 to UTF-8 so files
+// saved by Windows tools display instead of being flagged as binary
+func decodeBOM(content string) string {
+	switch {
+	case strings.HasPrefix(content, "\xef\xbb\xbf"):
+		return content[3:]
+	case strings.HasPrefix(content, "\xff\xfe"):
+		return decodeUTF16([]byte(content[2:]), binary.LittleEndian)
+	case strings.HasPrefix(content, "\xfe\xff"):
+		return decodeUTF16([]byte(content[2:]), binary.BigEndian)
+	}
+	return content
+}
+
+// decodeUTF16 converts UTF-16 text to UTF-8. A trailing odd byte, left by
+// truncating the download, is dropped.
+func decodeUTF16(data []byte, order binary.ByteOrder) string {
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = order.Uint16(data[2*i:])
+	}
+	return string(utf16.Decode(units))
+}
+
+// Placeholders shown instead of content the viewer can't display
+const (
+	binaryFileNote = "[Binary file - cannot display]"
+	nonUTF8Note    = "[non-UTF-8 content]"
+	largeFileNote  = "[large file - highlighting disabled]"
+	truncatedNote  = "[truncated - file too large to show in full]"
+)
+
+// trimPartialRune drops a multi-byte character cut in half at the end of
+// truncated content, so it isn't mistaken for non-UTF-8 text
+func trimPartialRune(content string) string {
+	for i := 0; i < utf8.UTFMax-1 && content != ""; i++ {
+		r, size := utf8.DecodeLastRuneInString(content)
+		if r != utf8.RuneError || size != 1 {
+			break
 		}
+		content = content[:len(content)-1]
+	}
+	return content
+}
+
+// isMarkdownFile checks if a file should be rendered as markdown
+func isMarkdownFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown":
+		return true
 	}
 	return false
 }
@@ -347,6 +609,19 @@ func isBinaryExtension(path string) bool {
 	return false
 }
 
+// projectCountsBadge formats open MR and issue counts as a compact badge.
+// Zero counts are omitted; returns "" when there is nothing to show.
+func projectCountsBadge(c gitlab.ProjectCounts) string {
+	var parts []string
+	if c.OpenMergeRequests > 0 {
+		parts = append(parts, fmt.Sprintf("!%d", c.OpenMergeRequests))
+	}
+	if c.OpenIssues > 0 {
+		parts = append(parts, fmt.Sprintf("⊙%d", c.OpenIssues))
+	}
+	return strings.Join(parts, " ")
+}
+
 // hardTruncate cuts a string to fit within maxWidth visual characters
 func hardTruncate(s string, maxWidth int) string {
 	if maxWidth <= 0 {
@@ -367,6 +642,95 @@ func hardTruncate(s string, maxWidth int) string {
 	return ""
 }
 
+// topLanguage returns the language with the largest share, or "" if none
+func topLanguage(languages map[string]float64) string {
+	top := ""
+	for name, pct := range languages {
+		if top == "" || pct > languages[top] || (pct == languages[top] && name < top) {
+			top = name
+		}
+	}
+	return top
+}
+
+// formatCount formats n with thousands separators, e.g. "1,203"
+func formatCount(n int) string {
+	s := strconv.Itoa(n)
+	for i := len(s) - 3; i > 0 && s[i-1] != '-'; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
+
+// projectStatsBadge builds the " · 12.4 MB · 1,203 commits" suffix of the
+// content header, or "" when no statistics were returned
+func projectStatsBadge(stats *gitlab.ProjectStatistics) string {
+	if stats == nil || (stats.RepositorySize == 0 && stats.CommitCount == 0) {
+		return ""
+	}
+	commits := "commits"
+	if stats.CommitCount == 1 {
+		commits = "commit"
+	}
+	return fmt.Sprintf(" · %s · %s %s", formatBytes(stats.RepositorySize), formatCount(stats.CommitCount), commits)
+}
+
+// projectBadges builds the " · Go · #cli #tui +2" suffix of the content
+// header, dropping topics that don't fit within maxWidth
+func projectBadges(languages map[string]float64, topics []string, maxWidth int) string {
+	badges := ""
+	if lang := topLanguage(languages); lang != "" {
+		badges = " · " + lang
+		if lipgloss.Width(badges) > maxWidth {
+			return ""
+		}
+	}
+	if len(topics) == 0 {
+		return badges
+	}
+
+	sep := " · "
+	for i, topic := range topics {
+		next := badges + sep + "#" + topic
+		rest := ""
+		if remaining := len(topics) - i - 1; remaining > 0 {
+			rest = fmt.Sprintf(" +%d", remaining)
+		}
+		if lipgloss.Width(next+rest) > maxWidth {
+			// Summarize this topic and everything after it
+			if more := fmt.Sprintf(" +%d", len(topics)-i); lipgloss.Width(badges+more) <= maxWidth {
+				badges += more
+			}
+			return badges
+		}
+		badges = next
+		sep = " "
+	}
+	return badges
+}
+
+// truncateWidth cuts a string to fit within maxWidth visual characters,
+// marking the cut with an ellipsis
+func truncateWidth(s string, maxWidth int) string {
+	if lipgloss.Width(s) <= maxWidth {
+		return s
+	}
+	return hardTruncate(s, maxWidth-1) + "…"
+}
+
+// truncateWidthLeft keeps the end of a string, e.g. the deepest part of a
+// path, marking the cut with a leading ellipsis
+func truncateWidthLeft(s string, maxWidth int) string {
+	if lipgloss.Width(s) <= maxWidth {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && lipgloss.Width(string(runes)) > maxWidth-3 {
+		runes = runes[1:]
+	}
+	return "..." + string(runes)
+}
+
 // sliceByWidth returns a substring starting at visual offset and fitting within maxWidth
 func sliceByWidth(s string, offset, maxWidth int) string {
 	if maxWidth <= 0 {
@@ -427,32 +791,74 @@ func wrapText(text string, maxWidth int) string {
 			continue
 		}
 
-		// Wrap long line using rune-based approach for proper width handling
-		var currentLine []rune
-		currentWidth := 0
-
-		for _, r := range line {
-			runeWidth := lipgloss.Width(string(r))
-			if currentWidth+runeWidth > maxWidth {
-				// Line would exceed maxWidth, wrap here
-				if len(currentLine) > 0 {
-					result = append(result, string(currentLine))
-				}
-				currentLine = []rune{r}
-				currentWidth = runeWidth
-			} else {
-				currentLine = append(currentLine, r)
-				currentWidth += runeWidth
+		// Hardwrap skips escape codes when measuring and never splits one;
+		// each row then reopens the colors still active where the last ended
+		var active string
+		for _, row := range strings.Split(ansi.Hardwrap(line, maxWidth, true), "\n") {
+			styled := active + row
+			if active = activeSGR(styled); active != "" {
+				styled += "\x1b[0m"
 			}
-		}
-		if len(currentLine) > 0 {
-			result = append(result, string(currentLine))
+			result = append(result, styled)
 		}
 	}
 
 	return strings.Join(result, "\n")
 }
 
+// activeSGR returns the SGR sequences still in effect at the end of s, since
+// its last reset
+func activeSGR(s string) string {
+	var active string
+	for _, m := range sgrRegex.FindAllStringSubmatch(s, -1) {
+		if params := m[1]; params == "" || params == "0" {
+			active = ""
+		} else if strings.HasPrefix(params, "0;") {
+			active = m[0]
+		} else {
+			active += m[0]
+		}
+	}
+	return active
+}
+
+// wrapLogLines wraps each log line to maxWidth and returns the display rows
+// along with the first display row of every logical line
+func wrapLogLines(lines []string, maxWidth int) ([]string, []int) {
+	var rows []string
+	starts := make([]int, len(lines))
+	for i, line := range lines {
+		starts[i] = len(rows)
+		rows = append(rows, strings.Split(wrapText(line, maxWidth), "\n")...)
+	}
+	return rows, starts
+}
+
+// logicalLineAt maps a display row back to the logical line it belongs to
+func logicalLineAt(starts []int, row int) int {
+	idx := sort.SearchInts(starts, row)
+	if idx < len(starts) && starts[idx] == row {
+		return idx
+	}
+	return idx - 1
+}
+
+// findLogMatches returns the indexes of log lines containing query,
+// comparing against the text without ANSI codes and ignoring case
+func findLogMatches(log, query string) []int {
+	if query == "" {
+		return nil
+	}
+	query = strings.ToLower(query)
+	var matches []int
+	for i, line := range strings.Split(log, "\n") {
+		if strings.Contains(strings.ToLower(stripANSI(line)), query) {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
 // PanelID identifies panels in the UI
 type PanelID int
 
@@ -470,10 +876,14 @@ type TreeNode struct {
 	ID       int
 	Depth    int
 	Expanded bool
+	Favorite bool // node lives in the Favorites group
 	Group    *gitlab.Group
-	Project  *gitlab.Project
+	Project  *gitlab.Project // nil for favorites whose group hasn't been loaded
 }
 
+// favoritesGroupID is the ID of the synthetic Favorites group in the navigator
+const favoritesGroupID = -1
+
 // ContentTab identifies tabs in the content panel
 type ContentTab int
 
@@ -487,16 +897,56 @@ const (
 
 var contentTabNames = []string{"Files", "MRs", "Pipelines", "Releases"}
 
+// tabByName returns the content tab with a case-insensitive name, -1 if there's none
+func tabByName(name string) ContentTab {
+	return ContentTab(slices.IndexFunc(contentTabNames, func(tab string) bool {
+		return strings.EqualFold(tab, name)
+	}))
+}
+
+// parseTabs returns the content tabs listed in the config, in order. Unset
+// shows them all. Files is always shown since projects open on it, first
+// unless listed elsewhere.
+func parseTabs(names []string) ([]ContentTab, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	var tabs []ContentTab
+	for _, name := range names {
+		tab := tabByName(strings.TrimSpace(name))
+		if tab < 0 {
+			return nil, fmt.Errorf("unknown tab %q, expected files, mrs, pipelines or releases", name)
+		}
+		if slices.Contains(tabs, tab) {
+			return nil, fmt.Errorf("tab %q listed twice", name)
+		}
+		tabs = append(tabs, tab)
+	}
+	if !slices.Contains(tabs, TabFiles) {
+		tabs = slices.Insert(tabs, 0, TabFiles)
+	}
+	return tabs, nil
+}
+
 // MainScreen is the lazygit-style multi-panel interface
 type MainScreen struct {
-	// GitLab client
-	client *gitlab.Client
+	// GitLab client, or a snapshot served from disk
+	client gitlab.API
 
 	// Navigator tree
 	treeNodes       []TreeNode
 	selectedNodeIdx int
-	expandedGroups  map[int]bool             // group ID -> expanded
-	groupProjects   map[int][]gitlab.Project // group ID -> projects (cache)
+	expandedGroups  map[int]bool                 // group ID -> expanded
+	groupProjects   map[int][]gitlab.Project     // group ID -> projects (cache)
+	projectCounts   map[int]gitlab.ProjectCounts // project ID -> open MR/issue counts (session cache)
+	favorites       []config.Favorite            // pinned projects, persisted in the lazylab config
+	recentProjects  []config.Favorite            // last opened projects, most recent first
+	projectRefs     map[int]string               // project ID -> ref last browsed, restored on reselect (session only)
+
+	// Authenticated user, loaded once at startup
+	currentUser *gitlab.User
+	todosCount  int  // -1 when unknown
+	anonymous   bool // no token configured
 
 	// Raw data
 	groups        []gitlab.Group
@@ -511,16 +961,39 @@ type MainScreen struct {
 	// Jobs per pipeline (for showing stages in list)
 	pipelineJobs map[int][]gitlab.Job
 
+	// Single-pipeline details by ID, fetched when a pipeline is selected since
+	// the list endpoint omits coverage
+	pipelineDetails map[int]pipelineDetail
+
+	// Approvals per MR IID (loaded in the background after the MR list)
+	mrApprovals map[int]*gitlab.MRApprovals
+
 	// Selected project
 	selectedProject *gitlab.Project
 
 	// File browser state
 	currentPath     []string
+	expandedDirs    map[string]bool // directories expanded inline with space, by path
+	repoEmpty       bool            // selected project has no commits
 	fileContent     string
+	lineJumpActive  bool // ':' prompt open in the file viewer
+	lineJumpInput   textinput.Model
+	refJumpActive   bool // 'g' prompt open in the file list
+	refJumpInput    textinput.Model
+	fileFlashLine   int // 1-based line highlighted after a jump, 0 for none
+	fileFlashSeq    int // invalidates stale flash timers
+	fileJumpPending bool
+	fileIsMarkdown  bool // viewed file is markdown, rendered unless fileShowRaw
+	fileShowRaw     bool
+	fileTruncated   bool        // viewed file was cut off at config.MaxFileViewBytes
+	fileSaveRaw     bool        // fileContent differs from the stored bytes; saving re-fetches them
+	fileImage       image.Image // decoded image drawn instead of fileContent, with image_preview on
 	readmeContent   string
 	readmeRendered  string
+	readmeWidth     int // width readmeRendered was rendered for; 0 forces a re-render
 	viewingFile     bool
 	viewingFilePath string
+	viewingFileRef  string // commit the viewed file was opened at from a diff, empty for the browsed ref
 
 	// Selection indices
 	selectedContent int
@@ -530,6 +1003,7 @@ type MainScreen struct {
 
 	// Content tab
 	contentTab ContentTab
+	tabs       []ContentTab // Tabs in the order shown, from the tabs config; empty shows all
 
 	// Dimensions
 	width  int
@@ -542,6 +1016,8 @@ type MainScreen struct {
 	loading    bool
 	loadingMsg string
 	errMsg     string
+	spinner    spinner.Model // Animates loadingMsg while loading
+	spinning   bool          // A spinner tick is in flight
 
 	// Viewports for scrolling
 	readmeViewport viewport.Model
@@ -557,6 +1033,8 @@ type MainScreen struct {
 	readmeVisualMode   bool
 	readmeVisualStart  int
 	readmeVisualEnd    int
+	readmeHScroll     int // Horizontal scroll offset
+	readmeMaxWidth    int // Widest rendered line, bounds readmeHScroll
 
 	// Job selection for pipelines
 	selectedJobIdx int
@@ -566,38 +1044,200 @@ type MainScreen struct {
 
 	// Job log popup
 	showJobLogPopup   bool
-	currentPipelineID int // Pipeline ID for job refresh
+	currentPipelineID int    // Pipeline ID for job refresh
+	jobStageFilter    string // Stage the job list is narrowed to ('f'); empty shows all
 
 	// Branch selector popup
 	showBranchPopup   bool
 	selectedBranchIdx int
 	currentBranch     string
+	compareMark       string // branch marked with space as the comparison base
+	// Commits ahead of / behind the default branch, by branch name, fetched
+	// with 'a' for the selected branch only
+	branchDivergence  map[string]aheadBehind
+	divergenceLoading string // branch whose counts are being fetched
+
+	// Branch comparison popup
+	showComparePopup bool
+	compareFrom      string
+	compareTo        string
+	comparison       *gitlab.Comparison
+	compareLoading   bool
+	compareErr       string
+	compareViewport  viewport.Model
+	compareReady     bool
+
+	// Project info popup
+	showProjectInfo bool
+	infoProject     *gitlab.Project
+
+	// Last commit diff popup for a file or directory
+	showCommitDiffPopup bool
+	commitDiffPath      string
+	commitDiffDir       bool // directories show the commit's whole diff
+	commitDiffCommit    *gitlab.Commit
+	commitDiffs         []gitlab.Diff
+	commitDiffLoading   bool
+	commitDiffErr       string
+	commitDiffViewport  viewport.Model
+	commitDiffReady     bool
+	commitDiffFile      int // changed file 'o' opens at the commit, picked with n/p
 
 	// Status message (for clipboard feedback etc)
 	statusMsg string
 
+	// Vim-style count prefix: digits typed so far, and the count for the
+	// key being handled (0 when none was typed)
+	countPrefix int
+	count       int
+
 	// Error handling
 	lastError string
 	retryCmd  tea.Cmd // Command to retry on 'r' key
 
 	// Job log popup focus (true = log panel, false = job list)
-	jobLogFocused    bool
-	jobLogCursor     int    // Current cursor line in log
-	jobLogHScroll    int    // Horizontal scroll offset
-	jobLogLastKey    string // Last key pressed (for sequences like yy, gg)
-	visualLineMode   bool   // Visual line selection active
-	visualStartLine  int    // Start of visual selection
-	visualEndLine    int    // End of visual selection (follows cursor)
+	jobLogFocused   bool
+	jobLogCursor    int    // Current cursor line in log
+	jobLogHScroll   int    // Horizontal scroll offset
+	jobLogMaxWidth  int    // Widest log line, bounds jobLogHScroll
+	jobLogLastKey   string // Last key pressed (for sequences like yy, gg)
+	visualLineMode  bool   // Visual line selection active
+	visualStartLine int    // Start of visual selection
+	visualEndLine   int    // End of visual selection (follows cursor)
+	jobLogWrap      bool   // Wrap long lines instead of scrolling horizontally
+	jobLogRowStarts []int  // First display row of each log line when wrapped
+	logColorMode    string // Resolved job log colors: 256, 16 or none; empty keeps them as is
+
+	// Job log search ('/' prompt, n/N to cycle matches)
+	jobLogSearchActive bool
+	jobLogSearchInput  textinput.Model
+	jobLogSearch       string // Active query, empty when not searching
+	jobLogMatches      []int  // Log lines containing the query
+	jobLogMatchIdx     int    // Current match in jobLogMatches
 
 	// Runners popup (shows all running/pending jobs across projects)
 	showRunnersPopup bool
 	runningJobs      []gitlab.Job
 	pendingJobs      []gitlab.Job
 	runnersLoading   bool
+	runnersNote      string // why the jobs are limited, e.g. to the selected project
+	runnersErr       string
 	runnersLastKey   string
 	runnersCursor    int
 	runnersTab       int // 0 = running, 1 = pending
 
+	// Pipeline list sort/filter (applied on top of m.pipelines)
+	pipelineSort   int    // pipelineSortCreated or pipelineSortStatus
+	pipelineFilter string // status to show, empty for all
+
+	// MR and pipeline lists load a page at a time, fetching the next as the
+	// cursor nears the end; 0 once the last page is in
+	mrNextPage       int
+	pipelineNextPage int
+	loadingMore      bool
+
+	// Merge request list filter (applied on top of m.mergeRequests)
+	mrFilterInput  textinput.Model
+	mrFilterActive bool   // true while typing the filter query
+	mrFilter       string // title/author/label substring
+	mrHideDrafts   bool
+
+	// Tags view of the releases tab
+	tags     []gitlab.Tag
+	showTags bool // releases tab lists tags instead of releases
+
+	// Environments popup (environments list, then deployments of the selected one)
+	showEnvPopup    bool
+	environments    []gitlab.Environment
+	envCursor       int
+	envLoading      bool
+	envErr          string
+	envSelected     string // environment whose deployments are shown, empty for the list
+	envDeployments  []gitlab.Deployment
+	envDeployCursor int
+
+	// CI/CD variables popup (read-only, values hidden unless toggled)
+	showVarsPopup  bool
+	variables      []gitlab.CIVariable
+	varsCursor     int
+	varsLoading    bool
+	varsErr        string
+	varsShowValues bool // reveal unmasked values
+
+	// Snippets popup; opening one shows it in the file viewer
+	showSnippetsPopup bool
+	snippets          []gitlab.Snippet
+	snippetsCursor    int
+	snippetsLoading   bool
+	snippetsErr       string
+	viewingSnippet    bool // Esc in the file viewer returns to the popup
+
+	// Wiki popup ('W' in the files tab); opening a page shows it in the file viewer
+	showWikiPopup bool
+	wikiPages     []gitlab.WikiPage
+	wikiCursor    int
+	wikiLoading   bool
+	wikiErr       string
+	viewingWiki   bool // Esc in the file viewer returns to the popup
+
+	// Recent projects switcher ('ctrl+r')
+	showRecentPopup bool
+	recentCursor    int
+
+	// Contributors popup ('C'), most commits first
+	showContributorsPopup bool
+	contributors          []gitlab.Contributor
+	contributorsCursor    int
+	contributorsLoading   bool
+	contributorsErr       string
+
+	// Labels and milestones popup ('T'), Tab switches between the two
+	showLabelsPopup      bool
+	labelsShowMilestones bool
+	labels               []gitlab.Label
+	milestones           []gitlab.Milestone
+	labelsCursor         int
+	labelsLoading        bool
+	labelsErr            string
+
+	// Activity popup ('a'), the project's events newest first
+	showActivityPopup bool
+	events            []gitlab.Event
+	activityCursor    int
+	activityLoading   bool
+	activityErr       string
+
+	// Code search popup ('/' in the files tab)
+	showCodeSearch    bool
+	codeSearchInput   textinput.Model
+	codeSearchTyping  bool   // keys edit the query until Enter runs it
+	codeSearchQuery   string // query the results are for
+	codeSearchResults []gitlab.SearchBlob
+	codeSearchCursor  int
+	codeSearchLoading bool
+	codeSearchErr     string
+
+	// Help overlay
+	showHelpPopup bool
+	helpScroll    int
+
+	// My merge requests popup (assigned to me / awaiting my review across projects)
+	showMyMRsPopup bool
+	myAssignedMRs  []gitlab.MergeRequest
+	myReviewMRs    []gitlab.MergeRequest
+	myMRsLoading   bool
+	myMRsErr       string
+	myMRsLastKey   string
+	myMRsCursor    int
+	myMRsTab       int // 0 = assigned, 1 = to review
+	myMRsGen       int // Bumped by each load so only the latest refresh chain keeps ticking
+
+	// Merge request detail popup
+	showMRDetailPopup bool
+	mrDetail          *gitlab.MergeRequest
+	mrDetailViewport  viewport.Model
+	mrDetailReady     bool
+
 	// Release assets popup
 	showReleasePopup    bool
 	selectedReleaseIdx  int // Index of selected release for popup
@@ -610,8 +1250,82 @@ type MainScreen struct {
 	folderBrowserEntries []string // Directory entries (folders only)
 	folderBrowserCursor  int      // Selected entry index
 	folderBrowserScroll  int      // Scroll offset
+	folderBrowserHidden  bool     // '.' lists hidden directories too
 	downloadURL          string   // URL to download after folder selection
 	downloadFilename     string   // Filename for the download
+	downloadChecksumURL  string   // URL of the asset's .sha256 sibling, if the release has one
+	pendingDownload      string   // Destination awaiting overwrite confirmation
+	saveViewedFile       bool     // Write the viewed file instead of fetching downloadURL
+
+	// Yes/no prompt shown over everything else; answers arrive as components.ConfirmedMsg
+	confirm components.Confirm
+
+	// In-flight download: Esc calls downloadCancel; progress and the final
+	// result arrive on downloadUpdates
+	downloadCancel  context.CancelFunc
+	downloadUpdates chan tea.Msg
+
+	// Pipelines expanded in place to show their stages and jobs, by pipeline ID
+	expandedPipelines map[int]bool
+	pipelineRowOffset int // first visible row of the pipelines list
+
+	// Navigator limited to a group's subtree; defaultGroup is what 'A' toggles back to
+	defaultGroup string
+	groupFilter  string
+
+	// Navigator filter ('/' in the navigator), narrowing the tree to matching names
+	navFilterInput  textinput.Model
+	navFilterActive bool   // true while typing the filter query
+	navFilter       string // case-insensitive name substring
+	navFoldPending  bool   // 'z' was pressed, waiting for o/c/a
+
+	// Repository languages of the selected project, loaded after its content
+	projectLanguages map[string]float64
+	// Repository size and commit count; empty when the token can't see them
+	projectStats *gitlab.ProjectStatistics
+
+	// Last viewed project, persisted to the config and reopened on startup
+	restoreLast bool
+	lastProject *config.LastProject
+	saveRecent  bool // persist recentProjects to the config
+
+	saveFavorites bool // persist favorites to the config
+
+	// GitLab URL from the credentials, empty for demo and snapshots
+	host string
+	// View to open on startup instead of the last project
+	deepLink *DeepLink
+	// Project contexts left by switching projects, for ctrl+o
+	backStack contextStack
+
+	// Tunables from the config: highlight limit, fetch concurrency, layout
+	// ratios (adjusted with '<'/'>'), error summary, scroll step and coverage
+	// goal. Its accessors supply the defaults for unset values.
+	settings config.LazyLabConfig
+
+	// One slot per parallel per-item request, shared by all parallel fetches
+	fetchLimiter chan struct{}
+
+	// Last commit fetches of the listed files; cancelled when the list is replaced
+	lastCommitsCtx    context.Context
+	lastCommitsCancel context.CancelFunc
+
+	// Draw images in the file viewer, from the config
+	imagePreview bool
+
+	// Auto-refresh; zero intervals fall back to the config defaults
+	pipelineRefresh time.Duration
+	jobLogRefresh   time.Duration
+	refreshPaused   bool // Tickers keep running but skip network calls
+	// Bumped whenever a refresh chain starts so ticks from older chains drop out
+	pipelineGen int
+	jobLogGen   int
+	runnersGen  int
+	// Idle pause: tickers arriving while idle stop and are re-armed on input
+	idleTimeout time.Duration // zero never pauses
+	lastInput   time.Time
+	idle        bool
+	idleStopped []tea.Cmd
 
 	// Demo mode (no API calls)
 	isDemo bool
@@ -620,31 +1334,138 @@ type MainScreen struct {
 // NewMainScreen creates a new main screen
 func NewMainScreen() *MainScreen {
 	token, host := loadCredentials()
-	client := createClient(host, token)
+	return newMainScreen(createClient(host, token), host)
+}
+
+// NewMainScreenAt creates a main screen that opens the view link points at
+func NewMainScreenAt(link DeepLink) *MainScreen {
+	m := NewMainScreen()
+	m.deepLink = &link
+	return m
+}
+
+// NewSnapshotScreen creates a main screen that browses the JSON snapshot in
+// dir instead of a GitLab instance. See gitlab.SnapshotClient for the layout.
+func NewSnapshotScreen(dir string) (*MainScreen, error) {
+	client, err := gitlab.NewSnapshotClient(dir)
+	if err != nil {
+		return nil, err
+	}
+	m := newMainScreen(client, "")
+	// Start at the snapshot's groups and leave the last project alone.
+	// Favorites and recent projects are IDs on the real instance, so they
+	// neither resolve here nor take snapshot IDs back to the config.
+	m.defaultGroup, m.groupFilter = "", ""
+	m.restoreLast, m.lastProject = false, nil
+	m.recentProjects, m.saveRecent = nil, false
+	m.favorites, m.saveFavorites = nil, false
+	return m, nil
+}
+
+// newMainScreen creates a main screen for client, configured from the
+// lazylab config file
+func newMainScreen(client gitlab.API, host string) *MainScreen {
+	// Apply keybinding overrides from the config file, falling back to defaults
+	var statusMsg string
+	lazylabConfig, _ := config.LoadLazyLabConfig()
+	km, err := keymap.LoadKeyMap(lazylabConfig)
+	if err != nil {
+		statusMsg = "Ignoring keys config: " + err.Error()
+	}
+	if lazylabConfig != nil && lazylabConfig.ColorScheme != "" && !styles.Apply(lazylabConfig.ColorScheme) && statusMsg == "" {
+		statusMsg = fmt.Sprintf("Unknown colorscheme %q, using %s", lazylabConfig.ColorScheme, styles.DefaultScheme)
+	}
+	logColorMode, ok := lazylabConfig.LogColors()
+	if !ok && statusMsg == "" {
+		statusMsg = fmt.Sprintf("Unknown log_color_mode %q, using %s", lazylabConfig.LogColorMode, config.LogColorAuto)
+	}
+	var tabs []ContentTab
+	if lazylabConfig != nil {
+		if tabs, err = parseTabs(lazylabConfig.Tabs); err != nil && statusMsg == "" {
+			statusMsg = "Ignoring tabs config: " + err.Error()
+		}
+	}
+	var favorites, recentProjects []config.Favorite
+	if lazylabConfig != nil {
+		favorites = lazylabConfig.Favorites
+		recentProjects = lazylabConfig.RecentProjects
+	}
+	pipelineRefresh, jobLogRefresh := lazylabConfig.RefreshIntervals()
+	idleTimeout := lazylabConfig.IdleTimeout()
+	group := defaultGroup(host, lazylabConfig)
+	restoreLast := lazylabConfig.ShouldRestoreLastProject()
+	imagePreview := lazylabConfig != nil && lazylabConfig.ImagePreview
+	var settings config.LazyLabConfig
+	if lazylabConfig != nil {
+		settings = *lazylabConfig
+	}
+	var lastProject *config.LastProject
+	if restoreLast && lazylabConfig != nil {
+		lastProject = lazylabConfig.LastProject
+	}
 
 	return &MainScreen{
 		client:         client,
+		spinner:        spinner.New(spinner.WithSpinner(spinner.Dot)),
 		focusedPanel:   PanelNavigator,
 		contentTab:     TabFiles,
-		keymap:         keymap.DefaultKeyMap(),
-		expandedGroups: make(map[int]bool),
+		keymap:         km,
+		statusMsg:      statusMsg,
+		expandedGroups: map[int]bool{favoritesGroupID: true},
 		groupProjects:  make(map[int][]gitlab.Project),
+		projectCounts:  make(map[int]gitlab.ProjectCounts),
+		projectRefs:    make(map[int]string),
+		favorites:      favorites,
+		recentProjects: recentProjects,
+
+		pipelineRefresh: pipelineRefresh,
+		jobLogRefresh:   jobLogRefresh,
+		restoreLast:     restoreLast,
+		defaultGroup:    group,
+		groupFilter:     group,
+		settings:        settings,
+		lastProject:     lastProject,
+		saveRecent:      true,
+		saveFavorites:   true,
+
+		imagePreview: imagePreview,
+		idleTimeout:  idleTimeout,
+		lastInput:    time.Now(),
+		host:         host,
+		logColorMode: resolveLogColorMode(logColorMode),
+		backStack:    contextStack{max: config.MaxBackStack},
+		tabs:         tabs,
 	}
 }
 
-// loadCredentials loads GitLab credentials from env vars, lazylab config, or glab config
+// loadCredentials loads GitLab credentials from env vars, token files, lazylab config, or glab config
 func loadCredentials() (token, host string) {
 	// 1. Check environment variables (highest priority)
 	token = os.Getenv(config.EnvGitLabToken)
 	host = os.Getenv(config.EnvGitLabHost)
 
-	// 2. Fall back to lazylab config
+	// 2. Token file from the environment (unreadable files fall through)
+	if token == "" {
+		if path := os.Getenv(config.EnvGitLabTokenFile); path != "" {
+			if fileToken, err := config.ReadTokenFile(path); err == nil {
+				token = fileToken
+			}
+		}
+	}
+
+	// 3. Fall back to lazylab config (token_file, then inline token)
 	if token == "" || host == "" {
 		if lazylabConfig, err := config.LoadLazyLabConfig(); err == nil {
 			if host == "" {
 				host = lazylabConfig.GetDefaultHost()
 			}
-			if hostConfig := lazylabConfig.GetHostConfig(host); hostConfig != nil {
+			// Hosts are keyed normalized, so "https://gitlab.com/" finds "gitlab.com"
+			if hostConfig := lazylabConfig.GetHostConfig(config.HostKey(host)); hostConfig != nil {
+				if token == "" && hostConfig.TokenFile != "" {
+					if fileToken, err := config.ReadTokenFile(hostConfig.TokenFile); err == nil {
+						token = fileToken
+					}
+				}
 				if token == "" {
 					token = hostConfig.Token
 				}
@@ -652,31 +1473,27 @@ func loadCredentials() (token, host string) {
 		}
 	}
 
-	// 3. Fall back to glab config
+	// 4. Fall back to glab config
 	if token == "" || host == "" {
 		if glabConfig, err := config.LoadGlabConfig(); err == nil {
-			if host == "" {
+			hostFromGlab := host == ""
+			if hostFromGlab {
 				host = glabConfig.GetDefaultHost()
 			}
-			if hostConfig := glabConfig.GetHostConfig(host); hostConfig != nil {
+			if hostConfig := glabConfig.GetHostConfig(config.HostKey(host)); hostConfig != nil {
 				if token == "" {
 					token = hostConfig.Token
 				}
+				// Use glab's api_host/api_protocol for the host it picked
+				if hostFromGlab {
+					host = hostConfig.BaseURL(host)
+				}
 			}
 		}
 	}
 
-	// Apply defaults
-	if host == "" {
-		host = config.DefaultHost
-	}
-
-	// Ensure host has protocol
-	if !strings.HasPrefix(host, "http") {
-		host = "https://" + host
-	}
-
-	return token, host
+	// Apply defaults and ensure host has protocol
+	return token, config.NormalizeHost(host)
 }
 
 // HasCredentials checks if valid credentials are available
@@ -685,21 +1502,95 @@ func HasCredentials() bool {
 	return token != ""
 }
 
-// createClient creates a GitLab client with the given credentials
+// NewClientFromCredentials creates a GitLab client using the same credential
+// resolution as the TUI (env vars, token files, lazylab config, glab config)
+func NewClientFromCredentials() *gitlab.Client {
+	token, host := loadCredentials()
+	return createClient(host, token)
+}
+
+// createClient creates a GitLab client with the given credentials, using the
+// page size from the lazylab config
 func createClient(host, token string) *gitlab.Client {
+	lazylabConfig, _ := config.LoadLazyLabConfig()
+	opts := append(debugOptions(), gitlab.WithPerPage(lazylabConfig.PageSize()))
 	if token != "" {
-		return gitlab.NewClient(host, token)
+		return gitlab.NewClient(host, token, opts...)
+	}
+	return gitlab.NewPublicClient(opts...)
+}
+
+// debugLog receives a line per HTTP request once EnableDebugLog is called
+var debugLog io.Writer
+
+// EnableDebugLog appends a line per HTTP request to the debug log in the
+// config directory and returns its path. The TUI owns stdout, hence the file.
+func EnableDebugLog() (string, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(configDir, 0700); err != nil {
+		return "", err
+	}
+	path := filepath.Join(configDir, config.DebugLogFile)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return "", err
+	}
+	debugLog = f
+	return path, nil
+}
+
+// debugOptions returns the client options that enable the debug log, if on
+func debugOptions() []gitlab.ClientOption {
+	if debugLog == nil {
+		return nil
 	}
-	return gitlab.NewPublicClient()
+	return []gitlab.ClientOption{gitlab.WithDebugLog(debugLog)}
 }
 
 // rebuildNavTree rebuilds the flat tree representation from groups and their projects
 func (m *MainScreen) rebuildNavTree() {
 	m.treeNodes = nil
 
+	// Favorites group at the top, regardless of the projects' real groups
+	if len(m.favorites) > 0 {
+		var projects []TreeNode
+		for _, f := range m.favorites {
+			projects = append(projects, TreeNode{
+				Type:     "project",
+				Name:     path.Base(f.Path),
+				FullPath: f.Path,
+				ID:       f.ID,
+				Depth:    1,
+				Favorite: true,
+				Project:  m.cachedProject(f.ID),
+			})
+		}
+		m.appendNavGroup(TreeNode{
+			Type:     "group",
+			Name:     "⭐ Favorites",
+			ID:       favoritesGroupID,
+			Depth:    0,
+			Expanded: m.expandedGroups[favoritesGroupID],
+		}, projects)
+	}
+
 	for _, g := range m.groups {
-		// Add group node
-		groupNode := TreeNode{
+		// Projects are only known once the group has been expanded
+		var projects []TreeNode
+		for _, p := range m.groupProjects[g.ID] {
+			projects = append(projects, TreeNode{
+				Type:     "project",
+				Name:     p.Name,
+				FullPath: p.PathWithNamespace,
+				ID:       p.ID,
+				Depth:    1,
+				Project:  &p,
+			})
+		}
+		m.appendNavGroup(TreeNode{
 			Type:     "group",
 			Name:     g.Name,
 			FullPath: g.FullPath,
@@ -707,2357 +1598,10308 @@ func (m *MainScreen) rebuildNavTree() {
 			Depth:    0,
 			Expanded: m.expandedGroups[g.ID],
 			Group:    &g,
+		}, projects)
+	}
+}
+
+// appendNavGroup adds a group and its visible projects to the tree. Without a
+// navigator filter the projects show when the group is expanded. With one, a
+// matching group shows as usual, and any other group shows only its matching
+// projects, expanded or not, so they stay in context; groups with neither are
+// left out.
+func (m *MainScreen) appendNavGroup(group TreeNode, projects []TreeNode) {
+	if m.navFilter == "" || navNodeMatches(group, m.navFilter) {
+		m.treeNodes = append(m.treeNodes, group)
+		if group.Expanded {
+			m.treeNodes = append(m.treeNodes, projects...)
 		}
-		m.treeNodes = append(m.treeNodes, groupNode)
-
-		// If expanded, add projects
-		if m.expandedGroups[g.ID] {
-			if projects, ok := m.groupProjects[g.ID]; ok {
-				for _, p := range projects {
-					projectNode := TreeNode{
-						Type:     "project",
-						Name:     p.Name,
-						FullPath: p.PathWithNamespace,
-						ID:       p.ID,
-						Depth:    1,
-						Project:  &p,
-					}
-					m.treeNodes = append(m.treeNodes, projectNode)
-				}
-			}
+		return
+	}
+
+	var matching []TreeNode
+	for _, p := range projects {
+		if navNodeMatches(p, m.navFilter) {
+			matching = append(matching, p)
 		}
 	}
+	if len(matching) > 0 {
+		m.treeNodes = append(m.treeNodes, group)
+		m.treeNodes = append(m.treeNodes, matching...)
+	}
 }
 
-// Init initializes the screen
-func (m *MainScreen) Init() tea.Cmd {
-	// Demo mode has pre-loaded data, no API calls needed
-	if m.isDemo {
-		return nil
-	}
-	m.loading = true
-	m.loadingMsg = "Loading groups..."
-	cmd := m.loadGroups()
-	m.retryCmd = cmd
-	return cmd
+// navNodeMatches reports whether a node's name contains query, ignoring case
+func navNodeMatches(node TreeNode, query string) bool {
+	return strings.Contains(strings.ToLower(node.Name), strings.ToLower(query))
 }
 
-func (m *MainScreen) loadGroups() tea.Cmd {
-	if m.isDemo {
-		return nil
+// setNavFilter narrows the navigator to query and moves the cursor to the
+// first matching node, or keeps it on the same node when it still matches or
+// the filter is cleared, so the selection is never out of range
+func (m *MainScreen) setNavFilter(query string) {
+	var prev *TreeNode
+	if m.selectedNodeIdx < len(m.treeNodes) && (query == "" || navNodeMatches(m.treeNodes[m.selectedNodeIdx], query)) {
+		node := m.treeNodes[m.selectedNodeIdx]
+		prev = &node
 	}
-	return func() tea.Msg {
-		groups, err := m.client.ListGroups()
-		if err != nil {
-			return errMsg{err: err}
+
+	// Clearing the filter jumps to the match: open the group it was found in
+	if query == "" && prev != nil && prev.Type == "project" {
+		m.expandGroupOf(*prev)
+	}
+
+	m.navFilter = query
+	m.rebuildNavTree()
+	m.selectedNodeIdx = 0
+	for i, n := range m.treeNodes {
+		if prev != nil && n.Type == prev.Type && n.ID == prev.ID && n.Favorite == prev.Favorite {
+			m.selectedNodeIdx = i
+			return
+		}
+		if prev == nil && query != "" && navNodeMatches(n, query) {
+			m.selectedNodeIdx = i
+			return
 		}
-		return groupsLoadedMsg{groups: groups}
 	}
 }
 
-func (m *MainScreen) loadGroupProjects(groupID int, groupPath string) tea.Cmd {
-	if m.isDemo {
-		return nil
+// expandGroupOf expands the navigator group that holds a project node
+func (m *MainScreen) expandGroupOf(node TreeNode) {
+	if node.Favorite {
+		m.expandedGroups[favoritesGroupID] = true
+		return
 	}
-	return func() tea.Msg {
-		projects, err := m.client.ListGroupProjects(groupPath)
-		if err != nil {
-			return errMsg{err: err}
+	for groupID, projects := range m.groupProjects {
+		for _, p := range projects {
+			if p.ID == node.ID {
+				m.expandedGroups[groupID] = true
+				return
+			}
 		}
-		return groupProjectsLoadedMsg{groupID: groupID, projects: projects}
 	}
 }
 
-func (m *MainScreen) loadAllProjects() tea.Cmd {
-	if m.isDemo {
-		return nil
+// handleNavFold finishes a z sequence in the navigator
+func (m *MainScreen) handleNavFold(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.navFoldPending = false
+	switch msg.String() {
+	case "o":
+		m.expandAllGroups()
+	case "c":
+		m.collapseAllGroups()
+	case "a":
+		for _, expanded := range m.expandedGroups {
+			if expanded {
+				m.collapseAllGroups()
+				return m, nil
+			}
+		}
+		m.expandAllGroups()
 	}
-	return func() tea.Msg {
-		projects, err := m.client.ListProjects()
-		if err != nil {
-			return errMsg{err: err}
+	return m, nil
+}
+
+// collapseAllGroups collapses every navigator group, leaving the cursor on
+// the group it was in
+func (m *MainScreen) collapseAllGroups() {
+	var group TreeNode
+	for i := min(m.selectedNodeIdx, len(m.treeNodes)-1); i >= 0; i-- {
+		if m.treeNodes[i].Type == "group" {
+			group = m.treeNodes[i]
+			break
 		}
-		return allProjectsLoadedMsg{projects: projects}
 	}
+	clear(m.expandedGroups)
+	m.rebuildNavTree()
+	m.selectNavNode(group)
 }
 
-func (m *MainScreen) loadProjectContent() tea.Cmd {
-	if m.selectedProject == nil {
-		return nil
+// expandAllGroups expands every group whose projects are already loaded.
+// Loading the rest costs a request per group, so that waits for a yes.
+func (m *MainScreen) expandAllGroups() {
+	var node TreeNode
+	if m.selectedNodeIdx < len(m.treeNodes) {
+		node = m.treeNodes[m.selectedNodeIdx]
 	}
-	ref := m.selectedProject.DefaultBranch
-	if ref == "" {
-		ref = "main"
+	if len(m.favorites) > 0 {
+		m.expandedGroups[favoritesGroupID] = true
+	}
+	uncached := 0
+	for _, g := range m.groups {
+		if _, ok := m.groupProjects[g.ID]; ok {
+			m.expandedGroups[g.ID] = true
+		} else {
+			uncached++
+		}
+	}
+	m.rebuildNavTree()
+	m.selectNavNode(node)
+
+	if uncached > 0 && !m.isDemo {
+		m.confirm.Ask(confirmExpandAllGroups, fmt.Sprintf("Load projects for %d more groups?", uncached))
 	}
-	return m.loadProjectContentForBranch(ref)
 }
 
-func (m *MainScreen) loadProjectContentForBranch(branch string) tea.Cmd {
-	if m.selectedProject == nil || m.isDemo {
+// confirmExpandAllGroups identifies the prompt before loading every group's projects
+const confirmExpandAllGroups = "expand-all-groups"
+
+// loadAllGroupProjects expands the groups whose projects haven't been loaded
+// yet and fetches them
+func (m *MainScreen) loadAllGroupProjects() tea.Cmd {
+	// Shares the fetch limiter so expanding many groups doesn't fire every request at once
+	sem := m.limiter()
+	var cmds []tea.Cmd
+	for _, g := range m.groups {
+		if _, ok := m.groupProjects[g.ID]; !ok {
+			m.expandedGroups[g.ID] = true
+			load := m.loadGroupProjects(g.ID, g.FullPath)
+			if load == nil {
+				continue
+			}
+			cmds = append(cmds, func() tea.Msg {
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				return load()
+			})
+		}
+	}
+	if len(cmds) == 0 {
 		return nil
 	}
-	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
+	m.loading = true
+	m.loadingMsg = fmt.Sprintf("Loading projects for %d groups...", len(cmds))
+	return tea.Batch(cmds...)
+}
 
-	return func() tea.Msg {
-		entries, err := m.client.GetTree(projectID, branch, "")
-		if err != nil {
-			return errMsg{err: err}
+// selectNavNode moves the cursor to node after the tree was rebuilt, or
+// keeps it in range when the node is no longer shown
+func (m *MainScreen) selectNavNode(node TreeNode) {
+	for i, n := range m.treeNodes {
+		if n.Type == node.Type && n.ID == node.ID && n.Favorite == node.Favorite {
+			m.selectedNodeIdx = i
+			return
 		}
+	}
+	m.selectedNodeIdx = max(min(m.selectedNodeIdx, len(m.treeNodes)-1), 0)
+}
 
-		// Fetch last commit for each entry in parallel
-		m.fetchLastCommits(projectID, branch, entries)
-
-		// Try to load README
-		var readme string
-		for _, e := range entries {
-			lower := strings.ToLower(e.Name)
-			if strings.HasPrefix(lower, "readme") {
-				content, err := m.client.GetFileContent(projectID, e.Path, branch)
-				if err == nil {
-					readme = content
-				}
-				break
-			}
-		}
+// handleNavFilterInput handles keyboard input while typing the navigator filter
+func (m *MainScreen) handleNavFilterInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "escape":
+		// Cancel and clear the filter
+		m.navFilterActive = false
+		m.setNavFilter("")
+		return m, nil
+	case "enter":
+		// Keep the filter and return to tree navigation
+		m.navFilterActive = false
+		return m, nil
+	}
 
-		return projectContentMsg{entries: entries, readme: readme}
+	var cmd tea.Cmd
+	m.navFilterInput, cmd = m.navFilterInput.Update(msg)
+	if m.navFilterInput.Value() != m.navFilter {
+		m.setNavFilter(m.navFilterInput.Value())
 	}
+	return m, cmd
 }
 
-func (m *MainScreen) loadDirectory(path string) tea.Cmd {
-	if m.selectedProject == nil || m.isDemo {
-		return nil
+// navHeaderLines returns the number of lines the filter takes above the tree
+func (m *MainScreen) navHeaderLines() int {
+	if m.navFilterActive || m.navFilter != "" {
+		return 1
 	}
-	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
-	ref := m.currentBranch
-	if ref == "" {
-		ref = m.selectedProject.DefaultBranch
+	return 0
+}
+
+// cachedProject looks up a project in the loaded group projects
+func (m *MainScreen) cachedProject(id int) *gitlab.Project {
+	for _, projects := range m.groupProjects {
+		for i := range projects {
+			if projects[i].ID == id {
+				return &projects[i]
+			}
+		}
 	}
-	if ref == "" {
-		ref = "main"
+	return nil
+}
+
+// isFavorite reports whether a project is in the favorites
+func (m *MainScreen) isFavorite(id int) bool {
+	for _, f := range m.favorites {
+		if f.ID == id {
+			return true
+		}
 	}
+	return false
+}
 
-	return func() tea.Msg {
-		entries, err := m.client.GetTree(projectID, ref, path)
+// toggleFavorite adds or removes the project under the cursor from favorites,
+// saving the change to the lazylab config and keeping the cursor on the node.
+// Demo and snapshot screens keep favorites in memory only.
+func (m *MainScreen) toggleFavorite(node TreeNode) {
+	if !m.saveFavorites {
+		cfg := &config.LazyLabConfig{Favorites: m.favorites}
+		cfg.ToggleFavorite(node.ID, node.FullPath)
+		m.favorites = cfg.Favorites
+	} else {
+		cfg, err := config.LoadLazyLabConfigForUpdate()
 		if err != nil {
-			return errMsg{err: err}
+			m.statusMsg = "Failed to save favorites, config didn't load: " + err.Error()
+			return
 		}
+		cfg.ToggleFavorite(node.ID, node.FullPath)
+		if err := config.SaveLazyLabConfig(cfg); err != nil {
+			m.statusMsg = "Failed to save favorites: " + err.Error()
+			return
+		}
+		m.favorites = cfg.Favorites
+	}
 
-		// Fetch last commit for each entry in parallel
-		m.fetchLastCommits(projectID, ref, entries)
+	if m.isFavorite(node.ID) {
+		m.statusMsg = "★ Added " + node.FullPath + " to favorites"
+	} else {
+		m.statusMsg = "Removed " + node.FullPath + " from favorites"
+	}
 
-		return treeLoadedMsg{entries: entries, path: path}
+	m.rebuildNavTree()
+	for i, n := range m.treeNodes {
+		if n.Type == node.Type && n.ID == node.ID && n.Favorite == node.Favorite {
+			m.selectedNodeIdx = i
+			return
+		}
+	}
+	if m.selectedNodeIdx >= len(m.treeNodes) {
+		m.selectedNodeIdx = max(len(m.treeNodes)-1, 0)
 	}
 }
 
-// fetchLastCommits fetches the last commit for each entry in parallel
-func (m *MainScreen) fetchLastCommits(projectID, ref string, entries []gitlab.TreeEntry) {
-	if m.client == nil || len(entries) == 0 {
-		return
+// Init initializes the screen
+func (m *MainScreen) Init() tea.Cmd {
+	// Demo mode has pre-loaded data, no API calls needed
+	if m.isDemo {
+		return uiTickCmd()
+	}
+	m.loading = true
+	m.loadingMsg = "Loading groups..."
+	cmd := m.loadGroups()
+	m.retryCmd = cmd
+	cmds := []tea.Cmd{cmd, uiTickCmd()}
+	if m.deepLink != nil {
+		cmds = append(cmds, m.loadDeepLink())
+	} else {
+		cmds = append(cmds, m.loadLastProject())
+	}
+	if !m.client.HasToken() {
+		m.anonymous = true
+	} else {
+		cmds = append(cmds, m.loadCurrentUser())
 	}
+	return tea.Batch(cmds...)
+}
 
-	var wg sync.WaitGroup
-	// Limit concurrent requests
-	sem := make(chan struct{}, 10)
+// lastProjectLoadedMsg carries the project reopened from the previous session
+type lastProjectLoadedMsg struct {
+	project *gitlab.Project
+	tab     ContentTab
+	err     error
+}
 
-	for i := range entries {
-		wg.Add(1)
-		go func(idx int) {
-			defer wg.Done()
-			sem <- struct{}{}
-			defer func() { <-sem }()
+// deepLinkLoadedMsg carries the project of the link lazylab was started with
+type deepLinkLoadedMsg struct {
+	link    DeepLink
+	project *gitlab.Project
+	err     error
+}
 
-			commit, err := m.client.GetLastCommitForPath(projectID, ref, entries[idx].Path)
-			if err == nil && commit != nil {
-				entries[idx].LastCommit = commit
-			}
-		}(i)
+// loadDeepLink fetches the project of the startup link
+func (m *MainScreen) loadDeepLink() tea.Cmd {
+	if m.deepLink == nil || m.isDemo {
+		return nil
+	}
+	link := *m.deepLink
+	if host := linkHost(m.host); m.host != "" && !strings.EqualFold(host, link.Host) {
+		return func() tea.Msg {
+			return deepLinkLoadedMsg{link: link, err: fmt.Errorf("link is for %s, not %s", link.Host, host)}
+		}
+	}
+	return func() tea.Msg {
+		project, err := m.client.GetProject(link.Project)
+		return deepLinkLoadedMsg{link: link, project: project, err: err}
 	}
-	wg.Wait()
 }
 
-func (m *MainScreen) loadFile(filePath string) tea.Cmd {
-	if m.selectedProject == nil || m.isDemo {
+// openDeepLink selects the project of a link and shows its tab, ref and directory
+func (m *MainScreen) openDeepLink(project *gitlab.Project, link DeepLink) tea.Cmd {
+	ctx := viewContext{project: project, tab: max(deepLinkTab(link.Tab), TabFiles), ref: link.Ref}
+	if link.Path != "" {
+		ctx.path = strings.Split(link.Path, "/")
+	}
+	return m.openContext(ctx)
+}
+
+// currentContext captures the selected project and what it shows
+func (m *MainScreen) currentContext() viewContext {
+	return viewContext{
+		project: m.selectedProject,
+		tab:     m.contentTab,
+		path:    slices.Clone(m.currentPath),
+		ref:     m.currentBranch,
+	}
+}
+
+// goBack returns to the project context before the last project switch
+func (m *MainScreen) goBack() tea.Cmd {
+	ctx, ok := m.backStack.Pop()
+	if !ok {
+		m.statusMsg = "No previous project"
 		return nil
 	}
-	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
-	ref := m.currentBranch
-	if ref == "" {
-		ref = m.selectedProject.DefaultBranch
+	// Going back isn't itself recorded
+	m.selectedProject = nil
+	cmd := m.openContext(ctx)
+	m.statusMsg = "Back to " + cmp.Or(ctx.project.PathWithNamespace, ctx.project.Name)
+	return cmd
+}
+
+// openContext selects a project and shows its tab, ref and directory
+func (m *MainScreen) openContext(ctx viewContext) tea.Cmd {
+	m.selectProject(ctx.project)
+	if ctx.ref != "" {
+		m.rememberRef(ctx.ref)
+		m.currentBranch = ctx.ref
 	}
-	if ref == "" {
-		ref = "main"
+	if ctx.tab > TabFiles {
+		return m.switchTab(ctx.tab)
+	}
+	var cmd tea.Cmd
+	if len(ctx.path) > 0 {
+		m.currentPath = ctx.path
+		cmd = m.loadDirectory(strings.Join(ctx.path, "/"))
+	} else {
+		cmd = m.loadProjectContent()
+	}
+	if cmd != nil {
+		m.loading = true
+		m.loadingMsg = "Loading repository..."
+		m.retryCmd = cmd
 	}
+	return cmd
+}
 
-	return func() tea.Msg {
-		content, err := m.client.GetFileContent(projectID, filePath, ref)
-		if err != nil {
-			return errMsg{err: err}
+// currentDeepLink describes the view in the content panel, false when no
+// project is open
+func (m *MainScreen) currentDeepLink() (DeepLink, bool) {
+	if m.selectedProject == nil || m.selectedProject.PathWithNamespace == "" {
+		return DeepLink{}, false
+	}
+	host := linkHost(m.host)
+	if u, err := url.Parse(m.selectedProject.WebURL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	link := DeepLink{
+		Host:    host,
+		Project: m.selectedProject.PathWithNamespace,
+		Tab:     strings.ToLower(contentTabNames[m.contentTab]),
+	}
+	if m.contentTab == TabFiles {
+		if m.currentBranch != m.selectedProject.DefaultBranch {
+			link.Ref = m.currentBranch
 		}
-		return fileContentMsg{content: content, path: filePath}
+		link.Path = strings.Join(m.currentPath, "/")
 	}
+	return link, true
 }
 
-func (m *MainScreen) loadMRs() tea.Cmd {
-	if m.selectedProject == nil || m.isDemo {
+// linkHost returns the host name of a GitLab URL as used in deep links
+func linkHost(host string) string {
+	u, err := url.Parse(config.NormalizeHost(host))
+	if err != nil {
+		return host
+	}
+	return u.Host
+}
+
+// loadLastProject fetches the project recorded in the config, if any
+func (m *MainScreen) loadLastProject() tea.Cmd {
+	if m.lastProject == nil || m.isDemo {
 		return nil
 	}
-	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
+	last := *m.lastProject
+	tab := TabFiles
+	if i := slices.Index(contentTabNames, last.Tab); i >= 0 {
+		tab = ContentTab(i)
+	}
 	return func() tea.Msg {
-		mrs, err := m.client.ListMergeRequests(projectID)
-		if err != nil {
-			return errMsg{err: err}
+		// The ID survives renames and transfers, the path is only for display
+		project, err := m.client.GetProject(fmt.Sprintf("%d", last.ID))
+		return lastProjectLoadedMsg{project: project, tab: tab, err: err}
+	}
+}
+
+// rememberProject records the selected project and tab in the config so the
+// next session can reopen them
+func (m *MainScreen) rememberProject() {
+	if !m.restoreLast || m.isDemo || m.selectedProject == nil {
+		return
+	}
+	last := &config.LastProject{
+		ID:   m.selectedProject.ID,
+		Path: m.selectedProject.PathWithNamespace,
+		Tab:  contentTabNames[m.contentTab],
+	}
+	if m.lastProject != nil && *m.lastProject == *last {
+		return
+	}
+	m.lastProject = last
+
+	// Best effort - failing to save is not worth an error, but a config that
+	// doesn't load is left alone rather than overwritten
+	cfg, err := config.LoadLazyLabConfigForUpdate()
+	if err != nil {
+		m.statusMsg = "Not remembering the project, config didn't load: " + err.Error()
+		return
+	}
+	cfg.LastProject = last
+	_ = config.SaveLazyLabConfig(cfg)
+}
+
+// addRecentProject moves the selected project to the front of the recent
+// projects. Demo and snapshot screens keep them in memory only.
+func (m *MainScreen) addRecentProject() {
+	if m.selectedProject == nil {
+		return
+	}
+	cfg := &config.LazyLabConfig{RecentProjects: m.recentProjects}
+	save := m.saveRecent
+	if save {
+		// Best effort, like the last project: a config that doesn't load is
+		// left alone and the list is only kept in memory
+		loaded, err := config.LoadLazyLabConfigForUpdate()
+		if err == nil {
+			cfg = loaded
 		}
-		return mrsLoadedMsg{mrs: mrs}
+		save = err == nil
+	}
+	cfg.AddRecentProject(m.selectedProject.ID, m.selectedProject.PathWithNamespace)
+	m.recentProjects = cfg.RecentProjects
+	if save {
+		_ = config.SaveLazyLabConfig(cfg)
 	}
 }
 
-func (m *MainScreen) loadPipelines() tea.Cmd {
-	if m.selectedProject == nil || m.isDemo {
+// recentProjectLoadedMsg carries a recent project fetched by ID
+type recentProjectLoadedMsg struct{ project *gitlab.Project }
+
+// openRecentProject switches to a project from the recent list, fetching it
+// by ID when its group hasn't been loaded
+func (m *MainScreen) openRecentProject(recent config.Favorite) tea.Cmd {
+	if m.selectedProject != nil && m.selectedProject.ID == recent.ID {
 		return nil
 	}
-	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
-	return func() tea.Msg {
-		pipelines, err := m.client.ListPipelines(projectID)
+	if project := m.cachedProject(recent.ID); project != nil {
+		m.selectProject(project)
+		if m.isDemo {
+			return nil
+		}
+		m.loading = true
+		m.loadingMsg = "Loading repository..."
+		cmd := m.loadProjectContent()
+		m.retryCmd = cmd
+		return cmd
+	}
+	if m.isDemo {
+		return nil
+	}
+	m.loading = true
+	m.loadingMsg = "Loading project..."
+	cmd := func() tea.Msg {
+		// The ID survives renames and transfers
+		project, err := m.client.GetProject(fmt.Sprintf("%d", recent.ID))
 		if err != nil {
 			return errMsg{err: err}
 		}
-		return pipelinesLoadedMsg{pipelines: pipelines}
+		return recentProjectLoadedMsg{project: project}
+	}
+	m.retryCmd = cmd
+	return cmd
+}
+
+// forgetLastProject drops a remembered project that no longer exists
+func (m *MainScreen) forgetLastProject() {
+	m.lastProject = nil
+	if cfg, err := config.LoadLazyLabConfig(); err == nil && cfg.LastProject != nil {
+		cfg.LastProject = nil
+		_ = config.SaveLazyLabConfig(cfg)
 	}
 }
 
-func (m *MainScreen) loadReleases() tea.Cmd {
-	if m.selectedProject == nil || m.isDemo {
+// defaultGroup returns the group the navigator starts filtered to:
+// GITLAB_GROUP, then the host's default_group in the config
+func defaultGroup(host string, cfg *config.LazyLabConfig) string {
+	if group := os.Getenv(config.EnvGitLabGroup); group != "" {
+		return strings.Trim(group, "/")
+	}
+	if cfg == nil {
+		return ""
+	}
+	if hostConfig := cfg.GetHostConfig(config.HostKey(host)); hostConfig != nil {
+		return strings.Trim(hostConfig.DefaultGroup, "/")
+	}
+	return ""
+}
+
+// setGroupFilter switches the navigator to a group's subtree, or to all
+// groups when filter is empty, and reloads it
+func (m *MainScreen) setGroupFilter(filter string) tea.Cmd {
+	m.groupFilter = filter
+	m.groups = nil
+	m.selectedNodeIdx = 0
+	m.rebuildNavTree()
+	m.loading = true
+	m.loadingMsg = "Loading groups..."
+	cmd := m.loadGroups()
+	m.retryCmd = cmd
+	return cmd
+}
+
+func (m *MainScreen) loadGroups() tea.Cmd {
+	if m.isDemo {
 		return nil
 	}
-	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
+	if filter := m.groupFilter; filter != "" {
+		// Only the group's subtree; skips listing every group on the instance
+		return func() tea.Msg {
+			root, err := m.client.GetGroup(filter)
+			if errors.Is(err, gitlab.ErrNotFound) {
+				return groupFilterNotFoundMsg{filter: filter}
+			}
+			if err != nil {
+				return errMsg{err: err}
+			}
+			subgroups, err := m.client.ListSubgroups(filter)
+			if err != nil {
+				return errMsg{err: err}
+			}
+			return groupsLoadedMsg{groups: append([]gitlab.Group{*root}, subgroups...), filter: filter}
+		}
+	}
 	return func() tea.Msg {
-		releases, err := m.client.ListReleases(projectID)
+		groups, err := m.client.ListGroups()
 		if err != nil {
 			return errMsg{err: err}
 		}
-		return releasesLoadedMsg{releases: releases}
+		return groupsLoadedMsg{groups: groups}
 	}
 }
 
-func (m *MainScreen) loadBranches() tea.Cmd {
-	if m.selectedProject == nil || m.isDemo {
+func (m *MainScreen) loadGroupProjects(groupID int, groupPath string) tea.Cmd {
+	if m.isDemo {
 		return nil
 	}
-	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
 	return func() tea.Msg {
-		branches, err := m.client.ListBranches(projectID)
+		projects, err := m.client.ListGroupProjects(groupPath)
 		if err != nil {
 			return errMsg{err: err}
 		}
-		return branchesLoadedMsg{branches: branches}
+		return groupProjectsLoadedMsg{groupID: groupID, projects: projects}
 	}
 }
 
-func (m *MainScreen) loadPipelineJobs(pipelineID int) tea.Cmd {
-	if m.selectedProject == nil || m.isDemo {
+func (m *MainScreen) loadAllProjects() tea.Cmd {
+	if m.isDemo {
 		return nil
 	}
-	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
 	return func() tea.Msg {
-		jobs, err := m.client.ListPipelineJobs(projectID, pipelineID)
+		projects, err := m.client.ListProjects()
 		if err != nil {
 			return errMsg{err: err}
 		}
-		return jobsLoadedMsg{jobs: jobs}
+		return allProjectsLoadedMsg{projects: projects}
 	}
 }
 
-func (m *MainScreen) loadPipelineJobsForList(pipelineID int) tea.Cmd {
-	if m.selectedProject == nil || m.isDemo {
+// fetchProjectCounts loads open MR/issue counts for projects not yet cached.
+// Runs in the background so the navigator renders immediately.
+func (m *MainScreen) fetchProjectCounts(projects []gitlab.Project) tea.Cmd {
+	if m.isDemo || m.client == nil {
 		return nil
 	}
-	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
-	return func() tea.Msg {
-		jobs, err := m.client.ListPipelineJobs(projectID, pipelineID)
-		if err != nil {
-			// Silently ignore errors for list view
-			return pipelineJobsLoadedMsg{pipelineID: pipelineID, jobs: nil}
+
+	var pending []gitlab.Project
+	for _, p := range projects {
+		if _, ok := m.projectCounts[p.ID]; !ok {
+			pending = append(pending, p)
 		}
-		return pipelineJobsLoadedMsg{pipelineID: pipelineID, jobs: jobs}
 	}
-}
-
-func (m *MainScreen) loadJobLog(jobID int) tea.Cmd {
-	if m.selectedProject == nil || m.isDemo {
+	if len(pending) == 0 {
 		return nil
 	}
-	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
+	sem := m.limiter()
+
 	return func() tea.Msg {
-		log, err := m.client.GetJobLog(projectID, jobID)
-		if err != nil {
-			return errMsg{err: err}
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		counts := make(map[int]gitlab.ProjectCounts)
+
+		for _, project := range pending {
+			wg.Add(1)
+			go func(project gitlab.Project) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				c, err := m.client.GetProjectCounts(project)
+				if err == nil && c != nil {
+					mu.Lock()
+					counts[project.ID] = *c
+					mu.Unlock()
+				}
+			}(project)
 		}
-		return jobLogLoadedMsg{log: log}
+		wg.Wait()
+
+		return projectCountsLoadedMsg{counts: counts}
 	}
 }
 
-// Messages
-type errMsg struct{ err error }
-type groupsLoadedMsg struct{ groups []gitlab.Group }
-type groupProjectsLoadedMsg struct {
-	groupID  int
-	projects []gitlab.Project
+// languagesLoadedMsg carries the repository languages of a project
+type languagesLoadedMsg struct {
+	projectID int
+	languages map[string]float64
+	err       error
 }
-type allProjectsLoadedMsg struct{ projects []gitlab.Project }
-type projectContentMsg struct {
-	entries []gitlab.TreeEntry
-	readme  string
+
+// statisticsLoadedMsg carries the repository statistics of a project
+type statisticsLoadedMsg struct {
+	projectID int
+	stats     *gitlab.ProjectStatistics
+	err       error
 }
-type treeLoadedMsg struct {
-	entries []gitlab.TreeEntry
-	path    string
+
+func (m *MainScreen) loadStatistics() tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
+		return nil
+	}
+	id := m.selectedProject.ID
+	return func() tea.Msg {
+		stats, err := m.client.GetProjectStatistics(fmt.Sprintf("%d", id))
+		if stats == nil && err == nil {
+			// Not visible with this token's access; don't fetch again
+			stats = &gitlab.ProjectStatistics{}
+		}
+		return statisticsLoadedMsg{projectID: id, stats: stats, err: err}
+	}
 }
-type fileContentMsg struct {
-	content string
-	path    string
+
+func (m *MainScreen) loadLanguages() tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
+		return nil
+	}
+	id := m.selectedProject.ID
+	return func() tea.Msg {
+		languages, err := m.client.GetProjectLanguages(fmt.Sprintf("%d", id))
+		if languages == nil && err == nil {
+			// Empty repositories have no languages; don't fetch again
+			languages = map[string]float64{}
+		}
+		return languagesLoadedMsg{projectID: id, languages: languages, err: err}
+	}
 }
-type mrsLoadedMsg struct{ mrs []gitlab.MergeRequest }
-type pipelinesLoadedMsg struct{ pipelines []gitlab.Pipeline }
-type releasesLoadedMsg struct{ releases []gitlab.Release }
-type downloadCompleteMsg struct {
-	filename string
-	bytes    int64
-	err      error
+
+func (m *MainScreen) loadProjectContent() tea.Cmd {
+	if m.selectedProject == nil {
+		return nil
+	}
+	ref := m.selectedProject.DefaultBranch
+	if ref == "" && !m.isDemo && !m.selectedProject.EmptyRepo {
+		// Lists don't always include the default branch, and guessing "main"
+		// fails for master and custom defaults
+		return m.loadDefaultBranch()
+	}
+	if ref == "" {
+		ref = "main"
+	}
+	if saved := m.projectRefs[m.selectedProject.ID]; saved != "" && saved != ref {
+		return m.loadSavedRef(saved)
+	}
+	return m.loadProjectContentForBranch(ref)
 }
-type branchesLoadedMsg struct{ branches []gitlab.Branch }
-type jobsLoadedMsg struct{ jobs []gitlab.Job }
-type jobLogLoadedMsg struct{ log string }
-type pipelineJobsLoadedMsg struct {
-	pipelineID int
-	jobs       []gitlab.Job
+
+// savedRefMissingMsg reports that the ref remembered for a project is gone
+type savedRefMissingMsg struct {
+	projectID int
+	ref       string
 }
 
-// pipelineTickMsg triggers auto-refresh of pipelines
-type pipelineTickMsg time.Time
+// loadSavedRef loads the files of the ref last browsed in the selected
+// project, reporting savedRefMissingMsg if it has since been deleted
+func (m *MainScreen) loadSavedRef(ref string) tea.Cmd {
+	id := m.selectedProject.ID
+	cmd := m.loadProjectContentForBranch(ref)
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		msg := cmd()
+		if e, ok := msg.(errMsg); ok && errors.Is(e.err, gitlab.ErrNotFound) {
+			return savedRefMissingMsg{projectID: id, ref: ref}
+		}
+		return msg
+	}
+}
 
-// pipelinesRefreshedMsg is like pipelinesLoadedMsg but preserves selection
-type pipelinesRefreshedMsg struct{ pipelines []gitlab.Pipeline }
+// defaultBranchMsg carries the selected project as fetched on its own, nil
+// when that failed
+type defaultBranchMsg struct {
+	projectID int
+	project   *gitlab.Project
+}
 
-// pipelineTickCmd returns a command that sends a tick after the configured interval
-func pipelineTickCmd() tea.Cmd {
-	return tea.Tick(config.PipelineRefreshInterval, func(t time.Time) tea.Msg {
-		return pipelineTickMsg(t)
-	})
+// loadDefaultBranch fetches the selected project for its default branch
+func (m *MainScreen) loadDefaultBranch() tea.Cmd {
+	id := m.selectedProject.ID
+	return func() tea.Msg {
+		project, err := m.client.GetProject(fmt.Sprintf("%d", id))
+		if err != nil {
+			return defaultBranchMsg{projectID: id}
+		}
+		return defaultBranchMsg{projectID: id, project: project}
+	}
 }
 
-// refreshPipelines fetches pipelines without resetting selection
-func (m *MainScreen) refreshPipelines() tea.Cmd {
+// currentRef returns the ref being browsed: the selected branch, else the
+// project's default branch, with "main" as a last resort
+func (m *MainScreen) currentRef() string {
+	if m.currentBranch != "" {
+		return m.currentBranch
+	}
+	if m.selectedProject != nil && m.selectedProject.DefaultBranch != "" {
+		return m.selectedProject.DefaultBranch
+	}
+	return "main"
+}
+
+func (m *MainScreen) loadProjectContentForBranch(branch string) tea.Cmd {
 	if m.selectedProject == nil || m.isDemo {
 		return nil
 	}
 	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
+
+	// Repositories without commits have no tree to list
+	if m.selectedProject.EmptyRepo {
+		return func() tea.Msg { return projectContentMsg{empty: true} }
+	}
+	isDefault := m.selectedProject.DefaultBranch == "" || branch == m.selectedProject.DefaultBranch
+
 	return func() tea.Msg {
-		pipelines, err := m.client.ListPipelines(projectID)
+		entries, err := m.client.GetTree(projectID, branch, "")
+		if errors.Is(err, gitlab.ErrNotFound) && !isDefault {
+			// A typed ref that doesn't exist
+			return errMsg{err: fmt.Errorf("ref %q: %w", branch, err)}
+		}
+		if errors.Is(err, gitlab.ErrNotFound) {
+			// GitLab answers 404 for the tree of an empty repository
+			return projectContentMsg{empty: true}
+		}
 		if err != nil {
-			// Silently ignore errors on auto-refresh
-			return nil
+			return errMsg{err: err}
+		}
+		if len(entries) == 0 {
+			return projectContentMsg{empty: true}
+		}
+
+		// Try to load README
+		var readme string
+		for _, e := range entries {
+			lower := strings.ToLower(e.Name)
+			if strings.HasPrefix(lower, "readme") {
+				content, err := m.client.GetFileContent(projectID, e.Path, branch)
+				if err == nil {
+					readme = content
+				}
+				break
+			}
 		}
-		return pipelinesRefreshedMsg{pipelines: pipelines}
+
+		return projectContentMsg{entries: entries, readme: readme, ref: branch}
 	}
 }
 
-// jobLogTickMsg triggers auto-refresh of job log
-type jobLogTickMsg time.Time
+func (m *MainScreen) loadDirectory(path string) tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
+		return nil
+	}
+	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
+	ref := m.currentRef()
 
-// jobLogRefreshedMsg carries refreshed log content
-type jobLogRefreshedMsg struct{ log string }
+	return func() tea.Msg {
+		entries, err := m.client.GetTree(projectID, ref, path)
+		if err != nil {
+			return errMsg{err: err}
+		}
+		return treeLoadedMsg{entries: entries, path: path, ref: ref}
+	}
+}
 
-// jobsRefreshedMsg carries refreshed job statuses
-type jobsRefreshedMsg struct{ jobs []gitlab.Job }
+// dirExpandedMsg carries the entries of a directory expanded inline
+type dirExpandedMsg struct {
+	projectID int
+	ref       string
+	path      string
+	entries   []gitlab.TreeEntry
+}
 
-// runnersLoadedMsg carries all running and pending jobs
-type runnersLoadedMsg struct {
-	running []gitlab.Job
-	pending []gitlab.Job
+// loadDirChildren fetches the entries of a directory to show beneath it
+func (m *MainScreen) loadDirChildren(dirPath string) tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
+		return nil
+	}
+	id := m.selectedProject.ID
+	projectID := fmt.Sprintf("%d", id)
+	ref := m.currentRef()
+
+	return func() tea.Msg {
+		entries, err := m.client.GetTree(projectID, ref, dirPath)
+		if err != nil {
+			return errMsg{err: err}
+		}
+		return dirExpandedMsg{projectID: id, ref: ref, path: dirPath, entries: entries}
+	}
+}
+
+// toggleDir expands the directory at index i of the file list beneath it,
+// or collapses it along with everything expanded inside it
+func (m *MainScreen) toggleDir(i int) tea.Cmd {
+	dir := m.files[i]
+	if dir.Type != "tree" {
+		return nil
+	}
+	if !m.expandedDirs[dir.Path] {
+		return m.loadDirChildren(dir.Path)
+	}
+	end := i + 1
+	for end < len(m.files) && strings.HasPrefix(m.files[end].Path, dir.Path+"/") {
+		delete(m.expandedDirs, m.files[end].Path)
+		end++
+	}
+	delete(m.expandedDirs, dir.Path)
+	m.files = slices.Delete(m.files, i+1, end)
+	if m.selectedContent >= end {
+		m.selectedContent -= end - i - 1
+	} else if m.selectedContent > i {
+		m.selectedContent = i
+	}
+	m.adjustScrollOffset()
+	return nil
 }
 
-// runnersTickMsg triggers auto-refresh of runners popup
-type runnersTickMsg time.Time
+// fileDepth returns how deep an entry of the file list is nested below
+// the browsed directory
+func (m *MainScreen) fileDepth(entry gitlab.TreeEntry) int {
+	return max(strings.Count(entry.Path, "/")-len(m.currentPath), 0)
+}
 
-// runnersTickCmd returns a command that sends a tick for runners refresh
-func runnersTickCmd() tea.Cmd {
-	return tea.Tick(config.PipelineRefreshInterval, func(t time.Time) tea.Msg {
-		return runnersTickMsg(t)
-	})
+// concurrency returns how many parallel per-item requests may be in flight
+func (m *MainScreen) concurrency() int {
+	return m.settings.Concurrency()
 }
 
-// loadAllJobs fetches all running and pending jobs across projects
-func (m *MainScreen) loadAllJobs() tea.Cmd {
-	if m.isDemo {
+// limiter returns the semaphore shared by the parallel per-item fetches, so
+// overlapping loads stay within concurrency() requests together
+func (m *MainScreen) limiter() chan struct{} {
+	if m.fetchLimiter == nil {
+		m.fetchLimiter = make(chan struct{}, m.concurrency())
+	}
+	return m.fetchLimiter
+}
+
+// restartLastCommits cancels the last commit fetches of the list being
+// replaced; those still queued return without a request
+func (m *MainScreen) restartLastCommits() {
+	if m.lastCommitsCancel != nil {
+		m.lastCommitsCancel()
+	}
+	m.lastCommitsCtx, m.lastCommitsCancel = context.WithCancel(context.Background())
+}
+
+// lastCommitMsg carries the last commit touching one file list entry
+type lastCommitMsg struct {
+	projectID int
+	ref       string
+	path      string
+	commit    *gitlab.Commit
+}
+
+// loadLastCommits fetches the last commit for each entry in parallel. Each
+// arrives as its own lastCommitMsg, so the list shows before they're all in.
+func (m *MainScreen) loadLastCommits(ref string, entries []gitlab.TreeEntry) tea.Cmd {
+	if m.selectedProject == nil || m.isDemo || m.client == nil || len(entries) == 0 {
+		return nil
+	}
+	id := m.selectedProject.ID
+	projectID := fmt.Sprintf("%d", id)
+	client := m.client
+	sem := m.limiter()
+	if m.lastCommitsCtx == nil {
+		m.restartLastCommits()
+	}
+	ctx := m.lastCommitsCtx
+
+	cmds := make([]tea.Cmd, len(entries))
+	for i, entry := range entries {
+		path := entry.Path
+		cmds[i] = func() tea.Msg {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return nil
+			}
+			defer func() { <-sem }()
+			// Stagger requests so a large directory doesn't burst the API
+			time.Sleep(rand.N(config.FetchStagger))
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			commit, err := client.GetLastCommitForPath(projectID, ref, path)
+			if err != nil || commit == nil {
+				return nil
+			}
+			return lastCommitMsg{projectID: id, ref: ref, path: path, commit: commit}
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// mrStatusesLoadedMsg carries head pipelines and approvals of the listed MRs, keyed by IID
+type mrStatusesLoadedMsg struct {
+	projectID int
+	pipelines map[int]*gitlab.Pipeline
+	approvals map[int]*gitlab.MRApprovals
+}
+
+// loadMRStatuses fetches the head pipeline and approvals of each MR in parallel.
+// The list endpoint doesn't include head pipelines, so each MR is fetched individually.
+func (m *MainScreen) loadMRStatuses(mrs []gitlab.MergeRequest) tea.Cmd {
+	if m.selectedProject == nil || m.isDemo || len(mrs) == 0 {
 		return nil
 	}
+	project := m.selectedProject.ID
+	projectID := fmt.Sprintf("%d", project)
+	sem := m.limiter()
+
 	return func() tea.Msg {
-		running, _ := m.client.ListRunningJobs()
-		pending, _ := m.client.ListPendingJobs()
-		return runnersLoadedMsg{running: running, pending: pending}
+		result := mrStatusesLoadedMsg{
+			projectID: project,
+			pipelines: make(map[int]*gitlab.Pipeline),
+			approvals: make(map[int]*gitlab.MRApprovals),
+		}
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+
+		for _, mr := range mrs {
+			wg.Add(1)
+			go func(iid int) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				detail, _ := m.client.GetMergeRequest(projectID, iid)
+				approvals, _ := m.client.GetMergeRequestApprovals(projectID, iid)
+
+				mu.Lock()
+				defer mu.Unlock()
+				if detail != nil && detail.HeadPipeline != nil {
+					result.pipelines[iid] = detail.HeadPipeline
+				}
+				if approvals != nil {
+					result.approvals[iid] = approvals
+				}
+			}(mr.IID)
+		}
+		wg.Wait()
+
+		return result
 	}
 }
 
-// jobLogTickCmd returns a command that sends a tick after the configured interval
-func jobLogTickCmd() tea.Cmd {
-	return tea.Tick(config.JobLogRefreshInterval, func(t time.Time) tea.Msg {
-		return jobLogTickMsg(t)
-	})
+// approvalBadge formats MR approvals like "✔1/2", or "" when there is nothing to show
+func approvalBadge(a *gitlab.MRApprovals) string {
+	if a == nil {
+		return ""
+	}
+	approved := len(a.ApprovedBy)
+	if a.ApprovalsRequired > 0 {
+		return fmt.Sprintf("✔%d/%d", approved, a.ApprovalsRequired)
+	}
+	if approved > 0 {
+		return fmt.Sprintf("✔%d", approved)
+	}
+	return ""
 }
 
-// refreshJobLog fetches job log without resetting viewport position
-func (m *MainScreen) refreshJobLog() tea.Cmd {
-	if m.selectedProject == nil || m.isDemo || m.selectedJobIdx < 0 || m.selectedJobIdx >= len(m.jobs) {
+func (m *MainScreen) loadFile(filePath string) tea.Cmd {
+	return m.loadFileAt(filePath, "")
+}
+
+// fileAtCommitMissingMsg reports a file that didn't exist at the commit it
+// was opened at
+type fileAtCommitMissingMsg struct {
+	path string
+	sha  string
+}
+
+// loadFileAt loads a file as of pinnedRef, e.g. an older commit, or of the
+// browsed ref when pinnedRef is empty
+func (m *MainScreen) loadFileAt(filePath, pinnedRef string) tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
 		return nil
 	}
-	job := m.jobs[m.selectedJobIdx]
 	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
+	ref := cmp.Or(pinnedRef, m.currentRef())
+
+	// Links like "main.go#L42" carry a line to jump to
+	filePath, line := splitLineAnchor(filePath)
+
+	loadErr := func(err error) tea.Msg {
+		if pinnedRef != "" && errors.Is(err, gitlab.ErrNotFound) {
+			return fileAtCommitMissingMsg{path: filePath, sha: pinnedRef}
+		}
+		return errMsg{err: err}
+	}
+
+	if m.imagePreview && isImageFile(filePath) {
+		return func() tea.Msg {
+			// Huge images aren't fetched; they and undecodable ones fall back
+			// to the binary file note
+			data, err := m.client.GetFileRawLimited(projectID, filePath, ref, config.MaxFileViewBytes)
+			if errors.Is(err, gitlab.ErrFileTooLarge) {
+				return fileContentMsg{path: filePath, ref: pinnedRef}
+			}
+			if err != nil {
+				return loadErr(err)
+			}
+			img, _ := decodeImagePreview(data)
+			return fileContentMsg{path: filePath, ref: pinnedRef, image: img}
+		}
+	}
+
 	return func() tea.Msg {
-		log, err := m.client.GetJobLog(projectID, job.ID)
+		content, truncated, err := m.client.GetFileContentLimited(projectID, filePath, ref, config.MaxFileViewBytes)
 		if err != nil {
-			// Silently ignore errors on auto-refresh
-			return nil
+			return loadErr(err)
+		}
+		if truncated {
+			content = trimPartialRune(content)
 		}
-		return jobLogRefreshedMsg{log: log}
+		return fileContentMsg{content: content, path: filePath, ref: pinnedRef, line: line, truncated: truncated}
 	}
 }
 
-// refreshJobs fetches updated job statuses for the current pipeline
-func (m *MainScreen) refreshJobs() tea.Cmd {
-	if m.selectedProject == nil || m.isDemo || m.currentPipelineID == 0 {
+func (m *MainScreen) loadMRs() tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
 		return nil
 	}
 	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
-	pipelineID := m.currentPipelineID
 	return func() tea.Msg {
-		jobs, err := m.client.ListPipelineJobs(projectID, pipelineID)
+		mrs, nextPage, err := m.client.ListMergeRequestsPage(projectID, 1)
 		if err != nil {
-			return nil
+			return errMsg{err: err}
 		}
-		return jobsRefreshedMsg{jobs: jobs}
+		return mrsLoadedMsg{mrs: mrs, nextPage: nextPage}
 	}
 }
 
-// Update handles messages
-func (m *MainScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
-		m.height = msg.Height
-		return m, nil
+func (m *MainScreen) loadPipelines() tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
+		return nil
+	}
+	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
+	return func() tea.Msg {
+		pipelines, nextPage, err := m.client.ListPipelinesPage(projectID, 1)
+		if err != nil {
+			return errMsg{err: err}
+		}
+		return pipelinesLoadedMsg{pipelines: pipelines, nextPage: nextPage}
+	}
+}
 
-	case errMsg:
-		m.loading = false
-		m.lastError = msg.err.Error()
-		// Don't set m.errMsg - that would crash the UI
-		// Instead show error in status bar and allow retry
-		return m, nil
+// moreMRsLoadedMsg carries a later page of the MR list
+type moreMRsLoadedMsg struct {
+	projectID int
+	page      int
+	mrs       []gitlab.MergeRequest
+	nextPage  int
+	err       error
+}
 
-	case groupsLoadedMsg:
-		m.groups = msg.groups
-		m.loading = false
-		m.lastError = ""
-		m.rebuildNavTree()
-		// If no groups, load all projects directly
-		if len(m.groups) == 0 {
-			m.loading = true
-			m.loadingMsg = "Loading projects..."
-			cmd := m.loadAllProjects()
-			m.retryCmd = cmd
-			return m, cmd
-		}
-		return m, nil
+// morePipelinesLoadedMsg carries a later page of the pipeline list
+type morePipelinesLoadedMsg struct {
+	projectID int
+	page      int
+	pipelines []gitlab.Pipeline
+	nextPage  int
+	err       error
+}
 
-	case groupProjectsLoadedMsg:
-		m.groupProjects[msg.groupID] = msg.projects
-		m.loading = false
-		m.lastError = ""
-		m.rebuildNavTree()
-		return m, nil
+// loadMoreThreshold is how close to the end of a list the cursor gets, in
+// screens, before the next page is fetched
+const loadMoreThreshold = 1
 
-	case allProjectsLoadedMsg:
-		// When no groups exist, show all projects as root nodes
-		for _, p := range msg.projects {
-			projectNode := TreeNode{
-				Type:     "project",
-				Name:     p.Name,
-				FullPath: p.PathWithNamespace,
-				ID:       p.ID,
-				Depth:    0,
-				Project:  &p,
-			}
-			m.treeNodes = append(m.treeNodes, projectNode)
-		}
-		m.loading = false
-		m.lastError = ""
-		return m, nil
+// maybeLoadMore fetches the next page of the MR or pipeline list once the
+// cursor is within a screen of its end
+func (m *MainScreen) maybeLoadMore() tea.Cmd {
+	if m.selectedProject == nil || m.isDemo || m.loading || m.loadingMore {
+		return nil
+	}
+	if m.selectedContent < m.getContentCount()-loadMoreThreshold*m.listVisibleLines() {
+		return nil
+	}
+	switch {
+	case m.contentTab == TabMRs && m.mrNextPage > 0:
+		m.loadingMore = true
+		return m.loadMoreMRs(m.mrNextPage)
+	case m.contentTab == TabPipelines && m.pipelineNextPage > 0:
+		m.loadingMore = true
+		return m.loadMorePipelines(m.pipelineNextPage)
+	}
+	return nil
+}
 
-	case projectContentMsg:
-		m.files = msg.entries
-		m.readmeContent = msg.readme
-		// Calculate content width for markdown rendering
-		contentWidth := int(float64(m.width) * (1 - config.NavigatorWidthRatio)) - 4
-		if contentWidth < 40 {
-			contentWidth = 80
-		}
-		m.readmeRendered = renderMarkdown(msg.readme, contentWidth)
-		m.fileContent = ""
-		m.selectedContent = 0
-		m.fileScrollOffset = 0
-		m.readmeReady = false // Reset to reinitialize viewport with new content
-		m.loading = false
-		m.lastError = ""
-		// Set current branch if not set
-		if m.currentBranch == "" && m.selectedProject != nil {
-			m.currentBranch = m.selectedProject.DefaultBranch
-			if m.currentBranch == "" {
-				m.currentBranch = "main"
-			}
-		}
-		return m, nil
+// loadMoreMRs fetches a later page of open merge requests
+func (m *MainScreen) loadMoreMRs(page int) tea.Cmd {
+	id := m.selectedProject.ID
+	projectID := fmt.Sprintf("%d", id)
+	return func() tea.Msg {
+		mrs, nextPage, err := m.client.ListMergeRequestsPage(projectID, page)
+		return moreMRsLoadedMsg{projectID: id, page: page, mrs: mrs, nextPage: nextPage, err: err}
+	}
+}
 
-	case treeLoadedMsg:
-		m.files = msg.entries
-		m.selectedContent = 0
-		m.fileScrollOffset = 0
-		m.fileContent = ""
-		m.loading = false
-		m.lastError = ""
-		return m, nil
+// loadMorePipelines fetches a later page of pipelines
+func (m *MainScreen) loadMorePipelines(page int) tea.Cmd {
+	id := m.selectedProject.ID
+	projectID := fmt.Sprintf("%d", id)
+	return func() tea.Msg {
+		pipelines, nextPage, err := m.client.ListPipelinesPage(projectID, page)
+		return morePipelinesLoadedMsg{projectID: id, page: page, pipelines: pipelines, nextPage: nextPage, err: err}
+	}
+}
 
-	case fileContentMsg:
-		// Check for binary content
-		if isBinaryExtension(msg.path) || isBinaryContent(msg.content) {
-			m.fileContent = "[Binary file - cannot display]"
-		} else {
-			m.fileContent = msg.content
+// appendNewMRs appends the MRs of more that aren't listed yet; lists shift
+// between page requests when MRs are opened or merged
+func appendNewMRs(mrs, more []gitlab.MergeRequest) []gitlab.MergeRequest {
+	seen := make(map[int]bool, len(mrs))
+	for _, mr := range mrs {
+		seen[mr.IID] = true
+	}
+	for _, mr := range more {
+		if !seen[mr.IID] {
+			mrs = append(mrs, mr)
 		}
-		m.viewingFile = true
-		m.viewingFilePath = msg.path
-		m.fileViewReady = false // Reset to reinitialize viewport with new content
-		m.loading = false
-		m.lastError = ""
-		return m, nil
+	}
+	return mrs
+}
 
-	case mrsLoadedMsg:
-		m.mergeRequests = msg.mrs
-		m.selectedContent = 0
-		m.fileScrollOffset = 0
-		m.loading = false
-		m.lastError = ""
-		return m, nil
+// appendNewPipelines appends the pipelines of more that aren't listed yet
+func appendNewPipelines(pipelines, more []gitlab.Pipeline) []gitlab.Pipeline {
+	seen := make(map[int]bool, len(pipelines))
+	for _, p := range pipelines {
+		seen[p.ID] = true
+	}
+	for _, p := range more {
+		if !seen[p.ID] {
+			pipelines = append(pipelines, p)
+		}
+	}
+	return pipelines
+}
 
-	case pipelinesLoadedMsg:
-		m.pipelines = msg.pipelines
-		m.selectedContent = 0
-		m.fileScrollOffset = 0
-		m.pipelineJobs = make(map[int][]gitlab.Job)
-		m.loading = false
-		m.lastError = ""
-		// Load jobs for each pipeline to show stages
-		var cmds []tea.Cmd
-		for _, p := range m.pipelines {
-			cmds = append(cmds, m.loadPipelineJobsForList(p.ID))
+func (m *MainScreen) loadReleases() tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
+		return nil
+	}
+	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
+	return func() tea.Msg {
+		releases, err := m.client.ListReleases(projectID)
+		if err != nil {
+			return errMsg{err: err}
 		}
-		// Start auto-refresh ticker
-		cmds = append(cmds, pipelineTickCmd())
-		return m, tea.Batch(cmds...)
+		return releasesLoadedMsg{releases: releases}
+	}
+}
 
-	case releasesLoadedMsg:
-		m.releases = msg.releases
-		m.selectedContent = 0
-		m.fileScrollOffset = 0
-		m.loading = false
-		m.lastError = ""
-		return m, nil
+// loadRelease fetches the release of a tag that isn't in the loaded page of releases
+func (m *MainScreen) loadRelease(tagName string) tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
+		return nil
+	}
+	projectID := m.selectedProject.ID
+	client := m.client
+	return func() tea.Msg {
+		release, err := client.GetRelease(fmt.Sprintf("%d", projectID), tagName)
+		return releaseLoadedMsg{projectID: projectID, tagName: tagName, release: release, err: err}
+	}
+}
 
-	case downloadCompleteMsg:
-		m.loading = false
-		if msg.err != nil {
-			m.statusMsg = "Download failed: " + msg.err.Error()
-		} else {
-			m.statusMsg = fmt.Sprintf("Downloaded %s (%d bytes)", msg.filename, msg.bytes)
-		}
-		return m, nil
+func (m *MainScreen) loadTags() tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
+		return nil
+	}
+	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
 
-	case pipelinesRefreshedMsg:
-		// Preserve selection when auto-refreshing
-		selectedPipelineID := 0
-		if m.selectedContent < len(m.pipelines) {
-			selectedPipelineID = m.pipelines[m.selectedContent].ID
-		}
-		m.pipelines = msg.pipelines
-		// Restore selection by finding the same pipeline ID
-		if selectedPipelineID != 0 {
-			for i, p := range m.pipelines {
-				if p.ID == selectedPipelineID {
-					m.selectedContent = i
-					break
-				}
-			}
-		}
-		// Clamp selection to valid range
-		if m.selectedContent >= len(m.pipelines) && len(m.pipelines) > 0 {
-			m.selectedContent = len(m.pipelines) - 1
-		}
-		// Refresh jobs for pipelines
-		var cmds []tea.Cmd
-		for _, p := range m.pipelines {
-			cmds = append(cmds, m.loadPipelineJobsForList(p.ID))
+	return func() tea.Msg {
+		tags, err := m.client.ListTags(projectID)
+		if err != nil {
+			return errMsg{err: err}
 		}
-		// Continue ticker
-		cmds = append(cmds, pipelineTickCmd())
-		return m, tea.Batch(cmds...)
+		return tagsLoadedMsg{tags: tags}
+	}
+}
 
-	case pipelineTickMsg:
-		// Only refresh if we're viewing pipelines tab and have a project
-		if m.contentTab == TabPipelines && m.selectedProject != nil && !m.loading {
-			return m, m.refreshPipelines()
-		}
-		// Keep ticker running even if we're not on pipelines tab
-		if m.selectedProject != nil {
-			return m, pipelineTickCmd()
+func (m *MainScreen) loadBranches() tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
+		return nil
+	}
+	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
+	return func() tea.Msg {
+		branches, err := m.client.ListBranches(projectID)
+		if err != nil {
+			return errMsg{err: err}
 		}
-		return m, nil
+		return branchesLoadedMsg{branches: branches}
+	}
+}
 
-	case pipelineJobsLoadedMsg:
-		if m.pipelineJobs == nil {
-			m.pipelineJobs = make(map[int][]gitlab.Job)
+func (m *MainScreen) loadPipelineJobs(pipelineID int) tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
+		return nil
+	}
+	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
+	return func() tea.Msg {
+		jobs, err := m.client.ListPipelineJobs(projectID, pipelineID)
+		if err != nil {
+			return errMsg{err: err}
 		}
-		m.pipelineJobs[msg.pipelineID] = msg.jobs
-		return m, nil
+		return jobsLoadedMsg{jobs: jobs}
+	}
+}
 
-	case branchesLoadedMsg:
-		m.branches = msg.branches
-		m.selectedContent = 0
-		m.fileScrollOffset = 0
-		m.loading = false
-		m.lastError = ""
-		// If branch popup is open, keep it open
-		if m.showBranchPopup {
-			// Find current branch in list
-			for i, br := range m.branches {
-				if br.Name == m.currentBranch {
-					m.selectedBranchIdx = i
-					break
-				}
-			}
+func (m *MainScreen) loadPipelineJobsForList(pipelineID int) tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
+		return nil
+	}
+	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
+	return func() tea.Msg {
+		jobs, err := m.client.ListPipelineJobs(projectID, pipelineID)
+		if err != nil {
+			// Silently ignore errors for list view
+			return pipelineJobsLoadedMsg{pipelineID: pipelineID, jobs: nil}
 		}
-		return m, nil
+		return pipelineJobsLoadedMsg{pipelineID: pipelineID, jobs: jobs}
+	}
+}
 
-	case jobsLoadedMsg:
-		m.jobs = msg.jobs
-		m.selectedJobIdx = 0
-		m.jobLog = ""
-		m.jobLogReady = false
-		m.loading = false
-		m.lastError = ""
-		// Auto-load first job's log if available
-		if len(m.jobs) > 0 {
-			m.loading = true
-			m.loadingMsg = "Loading job log..."
-			cmd := m.loadJobLog(m.jobs[0].ID)
-			m.retryCmd = cmd
-			return m, cmd
+// loadSelectedPipelineDetail fetches the selected pipeline on its own for the
+// coverage the list omits. A pipeline is fetched again once its status moves on.
+func (m *MainScreen) loadSelectedPipelineDetail() tea.Cmd {
+	if m.selectedProject == nil || m.isDemo || m.contentTab != TabPipelines {
+		return nil
+	}
+	visible := m.visiblePipelines()
+	if m.selectedContent >= len(visible) {
+		return nil
+	}
+	p := visible[m.selectedContent]
+	if d, ok := m.pipelineDetails[p.ID]; ok && d.listStatus == p.Status {
+		return nil
+	}
+	if m.pipelineDetails == nil {
+		m.pipelineDetails = make(map[int]pipelineDetail)
+	}
+	// Record the request up front so it's only made once per status
+	m.pipelineDetails[p.ID] = pipelineDetail{listStatus: p.Status}
+	projectID := m.selectedProject.ID
+	return func() tea.Msg {
+		detail, err := m.client.GetPipeline(fmt.Sprintf("%d", projectID), p.ID)
+		if err != nil {
+			// Silently ignore errors; the row just goes without coverage
+			return nil
 		}
-		return m, nil
+		return pipelineDetailLoadedMsg{projectID: projectID, listStatus: p.Status, pipeline: detail}
+	}
+}
 
-	case jobLogLoadedMsg:
-		m.jobLog = msg.log
-		m.jobLogReady = false
-		m.loading = false
-		m.lastError = ""
-		// Start at bottom where errors usually are
-		m.jobLogCursor = strings.Count(msg.log, "\n")
-		// Start auto-refresh for live log viewing
-		return m, jobLogTickCmd()
+// pipelineCoverage returns the coverage of p from the list or its fetched detail
+func (m *MainScreen) pipelineCoverage(p gitlab.Pipeline) string {
+	if p.Coverage != "" {
+		return p.Coverage
+	}
+	if d, ok := m.pipelineDetails[p.ID]; ok && d.pipeline != nil {
+		return d.pipeline.Coverage
+	}
+	return ""
+}
 
-	case jobLogTickMsg:
-		// Only refresh if job popup is still open
-		if m.showJobLogPopup {
-			// Refresh both jobs (for status updates) and log, then schedule next tick
-			return m, tea.Batch(m.refreshJobs(), m.refreshJobLog(), jobLogTickCmd())
-		}
-		return m, nil
+// coverageGoal returns the coverage threshold, falling back to the default
+func (m *MainScreen) coverageGoal() float64 {
+	return m.settings.CoverageGoal()
+}
 
-	case jobsRefreshedMsg:
-		if m.showJobLogPopup && msg.jobs != nil {
-			// Preserve selection by job ID
-			selectedJobID := 0
-			if m.selectedJobIdx >= 0 && m.selectedJobIdx < len(m.jobs) {
-				selectedJobID = m.jobs[m.selectedJobIdx].ID
-			}
-			m.jobs = msg.jobs
-			// Restore selection
-			if selectedJobID != 0 {
-				for i, job := range m.jobs {
-					if job.ID == selectedJobID {
-						m.selectedJobIdx = i
-						break
-					}
-				}
-			}
+func (m *MainScreen) loadJobLog(jobID int) tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
+		return nil
+	}
+	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
+	return func() tea.Msg {
+		log, err := m.client.GetJobLog(projectID, jobID)
+		if err != nil {
+			return errMsg{err: err}
 		}
-		return m, nil
+		return jobLogLoadedMsg{log: log}
+	}
+}
 
-	case jobLogRefreshedMsg:
-		if msg.log != "" && m.showJobLogPopup {
-			// Save current scroll position
-			currentLine := m.jobLogViewport.YOffset
-			wasAtBottom := m.jobLogViewport.ScrollPercent() >= 0.99
+// Messages
+type errMsg struct{ err error }
+type groupsLoadedMsg struct {
+	groups []gitlab.Group
+	filter string // group filter the groups were loaded for
+}
 
-			// Update log content
-			m.jobLog = msg.log
+// groupFilterNotFoundMsg reports that the default group doesn't exist
+type groupFilterNotFoundMsg struct{ filter string }
+type groupProjectsLoadedMsg struct {
+	groupID  int
+	projects []gitlab.Project
+}
+type allProjectsLoadedMsg struct{ projects []gitlab.Project }
+type projectCountsLoadedMsg struct{ counts map[int]gitlab.ProjectCounts }
+type projectContentMsg struct {
+	entries []gitlab.TreeEntry
+	readme  string
+	ref     string
+	empty   bool // repository has no commits
+}
+type treeLoadedMsg struct {
+	entries []gitlab.TreeEntry
+	path    string
+	ref     string
+}
+type fileContentMsg struct {
+	content string
+	path    string
+	ref     string // commit the file was opened at, empty for the browsed ref
+	line    int    // line to jump to from a "#L42" anchor, 0 for none
+	snippet bool   // content is a snippet rather than a repository file
+	wiki    bool   // content is a wiki page rather than a repository file
 
-			// Update viewport content directly without recreating it
-			cleanLog := msg.log
-			cleanLog = strings.ReplaceAll(cleanLog, "\t", "    ")
-			cleanLog = strings.ReplaceAll(cleanLog, "\r", "")
-			// Don't wrap - preserve line numbers for visual selection
-			m.jobLogViewport.SetContent(cleanLog)
+	// projectID is the project a wiki page was fetched for; pages for a
+	// project that's no longer selected are dropped. Zero skips the check.
+	projectID int
 
-			// Auto-scroll to bottom when not focused on log panel, or was already at bottom
-			if !m.jobLogFocused || wasAtBottom {
-				m.jobLogViewport.GotoBottom()
-			} else {
-				m.jobLogViewport.SetYOffset(currentLine)
-			}
-		}
-		return m, nil
+	truncated bool        // content was cut off at config.MaxFileViewBytes
+	image     image.Image // decoded image to draw instead of content
+}
 
-	case runnersLoadedMsg:
-		m.runningJobs = msg.running
-		m.pendingJobs = msg.pending
-		m.runnersLoading = false
-		if m.showRunnersPopup {
-			return m, runnersTickCmd()
-		}
-		return m, nil
+// fileFlashDoneMsg clears the jump-to-line highlight
+type fileFlashDoneMsg struct{ seq int }
 
-	case runnersTickMsg:
-		if m.showRunnersPopup {
-			return m, m.loadAllJobs()
-		}
-		return m, nil
+// fileFlashCmd clears the jump-to-line highlight after a short delay
+func fileFlashCmd(seq int) tea.Cmd {
+	return tea.Tick(config.LineFlashDuration, func(time.Time) tea.Msg {
+		return fileFlashDoneMsg{seq: seq}
+	})
+}
 
-	case tea.KeyMsg:
-		return m.handleKey(msg)
-	}
+type mrsLoadedMsg struct {
+	mrs      []gitlab.MergeRequest
+	nextPage int
+}
+type pipelinesLoadedMsg struct {
+	pipelines []gitlab.Pipeline
+	nextPage  int
+}
+type releasesLoadedMsg struct{ releases []gitlab.Release }
+type tagsLoadedMsg struct{ tags []gitlab.Tag }
+
+// releaseLoadedMsg carries the release of a single tag, fetched on demand
+type releaseLoadedMsg struct {
+	projectID int
+	tagName   string
+	release   *gitlab.Release
+	err       error
+}
+type downloadCompleteMsg struct {
+	filename    string
+	bytes       int64
+	digest      string // hex SHA-256 of the saved file
+	checksum    string // expected SHA-256 from a .sha256 sibling asset, if any
+	checksumErr error  // why the expected SHA-256 couldn't be fetched
+	err         error
+}
 
-	return m, nil
+// downloadProgressMsg reports how far a download has got; total is -1 when unknown
+type downloadProgressMsg struct {
+	filename   string
+	bytesSoFar int64
+	total      int64
+}
+type editorFileReadyMsg struct{ path string } // temp file written for the editor
+type editorFinishedMsg struct{ err error }
+type branchesLoadedMsg struct{ branches []gitlab.Branch }
+type jobsLoadedMsg struct{ jobs []gitlab.Job }
+type jobLogLoadedMsg struct{ log string }
+type pipelineJobsLoadedMsg struct {
+	pipelineID int
+	jobs       []gitlab.Job
+}
+type pipelineDetailLoadedMsg struct {
+	projectID  int
+	listStatus string // Status in the list when the detail was requested
+	pipeline   *gitlab.Pipeline
 }
 
-func (m *MainScreen) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	// Clear status message on any keypress
-	m.statusMsg = ""
+// pipelineDetail is a fetched single pipeline; pipeline is nil while in flight
+type pipelineDetail struct {
+	listStatus string
+	pipeline   *gitlab.Pipeline
+}
 
-	// Handle popups first
-	if m.showJobLogPopup {
-		return m.handleJobLogPopup(msg)
+// pipelineTickMsg triggers auto-refresh of pipelines; ticks from an older
+// refresh chain are dropped
+type pipelineTickMsg struct{ gen int }
+
+// visibleMRs returns m.mergeRequests narrowed by the active filter query and draft toggle.
+// The query matches title, author username, or label substrings (case-insensitive).
+func (m *MainScreen) visibleMRs() []gitlab.MergeRequest {
+	if m.mrFilter == "" && !m.mrHideDrafts {
+		return m.mergeRequests
 	}
-	if m.showBranchPopup {
-		return m.handleBranchPopup(msg)
+	query := strings.ToLower(m.mrFilter)
+	visible := make([]gitlab.MergeRequest, 0, len(m.mergeRequests))
+	for _, mr := range m.mergeRequests {
+		if m.mrHideDrafts && mr.Draft {
+			continue
+		}
+		if query != "" && !mrMatchesFilter(mr, query) {
+			continue
+		}
+		visible = append(visible, mr)
 	}
-	if m.showRunnersPopup {
-		return m.handleRunnersPopup(msg)
+	return visible
+}
+
+// mrMatchesFilter reports whether a lowercase query matches an MR's title, author, or labels
+func mrMatchesFilter(mr gitlab.MergeRequest, query string) bool {
+	if strings.Contains(strings.ToLower(mr.Title), query) ||
+		strings.Contains(strings.ToLower(mr.Author.Username), query) {
+		return true
 	}
-	if m.showReleasePopup {
-		return m.handleReleasePopup(msg)
+	for _, label := range mr.Labels {
+		if strings.Contains(strings.ToLower(label), query) {
+			return true
+		}
 	}
-	if m.showFolderBrowser {
-		return m.handleFolderBrowser(msg)
+	return false
+}
+
+// clearMRFilter resets the merge request filter query and draft toggle
+func (m *MainScreen) clearMRFilter() {
+	m.mrFilter = ""
+	m.mrFilterActive = false
+	m.mrHideDrafts = false
+}
+
+// handleMRFilterInput handles keyboard input while typing the merge request filter
+func (m *MainScreen) handleMRFilterInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "escape":
+		// Cancel and clear the filter
+		m.mrFilterActive = false
+		m.mrFilter = ""
+		m.selectedContent = 0
+		m.fileScrollOffset = 0
+		return m, nil
+	case "enter":
+		// Keep the filter and return to list navigation
+		m.mrFilterActive = false
+		return m, nil
 	}
 
-	if key.Matches(msg, m.keymap.Quit) {
-		return m, tea.Quit
+	var cmd tea.Cmd
+	m.mrFilterInput, cmd = m.mrFilterInput.Update(msg)
+	if m.mrFilterInput.Value() != m.mrFilter {
+		m.mrFilter = m.mrFilterInput.Value()
+		m.selectedContent = 0
+		m.fileScrollOffset = 0
 	}
+	return m, cmd
+}
 
-	// Clear error on Escape
-	if msg.String() == "esc" || msg.String() == "escape" {
-		if m.lastError != "" {
-			m.lastError = ""
-			return m, nil
+// Pipeline list sort orders, toggled with 's'
+const (
+	pipelineSortCreated = iota // newest first
+	pipelineSortStatus         // failed, running, pending, then the rest
+)
+
+// pipelineStatusFilters is the cycle of status filters toggled with 'f'
+var pipelineStatusFilters = []string{"", "failed", "running", "success"}
+
+// pipelineStatusRank orders pipeline statuses for the status sort
+func pipelineStatusRank(status string) int {
+	switch status {
+	case "failed":
+		return 0
+	case "running":
+		return 1
+	case "pending", "created", "waiting_for_resource", "preparing", "scheduled", "manual":
+		return 2
+	case "success":
+		return 3
+	}
+	return 4
+}
+
+// visiblePipelines returns m.pipelines with the active filter and sort applied.
+// The underlying slice is left untouched.
+func (m *MainScreen) visiblePipelines() []gitlab.Pipeline {
+	visible := make([]gitlab.Pipeline, 0, len(m.pipelines))
+	for _, p := range m.pipelines {
+		if m.pipelineFilter == "" || p.Status == m.pipelineFilter {
+			visible = append(visible, p)
 		}
 	}
-
-	// Retry on 'r' key if there's an error
-	if msg.String() == "r" && m.lastError != "" && m.retryCmd != nil {
-		m.lastError = ""
-		m.loading = true
-		m.loadingMsg = "Retrying..."
-		cmd := m.retryCmd
-		return m, cmd
+	switch m.pipelineSort {
+	case pipelineSortStatus:
+		sort.SliceStable(visible, func(i, j int) bool {
+			return pipelineStatusRank(visible[i].Status) < pipelineStatusRank(visible[j].Status)
+		})
+	default:
+		sort.SliceStable(visible, func(i, j int) bool {
+			return visible[i].CreatedAt.After(visible[j].CreatedAt)
+		})
 	}
+	return visible
+}
 
-	// Yank clone URLs when project is selected
-	if m.selectedProject != nil {
-		switch msg.String() {
-		case "S":
-			// Yank SSH URL
-			if m.selectedProject.SSHURLToRepo != "" {
-				if err := copyToClipboard(m.selectedProject.SSHURLToRepo); err != nil {
-					m.statusMsg = "Copy failed: " + err.Error()
-				} else {
-					m.statusMsg = "SSH: " + m.selectedProject.SSHURLToRepo
-				}
-				return m, nil
-			}
-		case "U":
-			// Yank HTTPS URL
-			if m.selectedProject.HTTPURLToRepo != "" {
-				if err := copyToClipboard(m.selectedProject.HTTPURLToRepo); err != nil {
-					m.statusMsg = "Copy failed: " + err.Error()
-				} else {
-					m.statusMsg = "HTTPS: " + m.selectedProject.HTTPURLToRepo
-				}
-				return m, nil
-			}
-		}
+// selectedPipelineID returns the ID of the selected pipeline in the visible list, or 0
+func (m *MainScreen) selectedPipelineID() int {
+	visible := m.visiblePipelines()
+	if m.selectedContent < len(visible) {
+		return visible[m.selectedContent].ID
 	}
+	return 0
+}
 
-	// 'b' to open branch selector (when viewing files)
-	if msg.String() == "b" && m.selectedProject != nil && m.contentTab == TabFiles {
-		m.showBranchPopup = true
-		m.selectedBranchIdx = 0
-		// Find current branch in list
-		for i, br := range m.branches {
-			if br.Name == m.currentBranch {
-				m.selectedBranchIdx = i
+// restorePipelineSelection moves the selection back to the pipeline with the given ID
+// and clamps it to the visible list
+func (m *MainScreen) restorePipelineSelection(id int) {
+	visible := m.visiblePipelines()
+	if id != 0 {
+		for i, p := range visible {
+			if p.ID == id {
+				m.selectedContent = i
 				break
 			}
 		}
-		if len(m.branches) == 0 && !m.isDemo {
-			m.loading = true
-			m.loadingMsg = "Loading branches..."
-			cmd := m.loadBranches()
-			m.retryCmd = cmd
-			return m, cmd
-		}
-		return m, nil
 	}
-
-	// 'R' to open runners/jobs popup (shows all running/pending jobs)
-	if msg.String() == "R" {
-		m.showRunnersPopup = true
-		m.runnersCursor = 0
-		m.runnersTab = 0
-		m.runnersLoading = true
-		return m, m.loadAllJobs()
+	// Clamp selection to valid range
+	if m.selectedContent >= len(visible) {
+		m.selectedContent = max(len(visible)-1, 0)
 	}
+	m.adjustScrollOffset()
+}
 
-	// Panel navigation with Shift+HJKL
-	// Layout:
-	// [1 Navigator] [2 Content ]
-	//               [3 README  ]
-	switch msg.String() {
-	case "H", "shift+left":
-		switch m.focusedPanel {
-		case PanelContent, PanelReadme:
-			m.focusedPanel = PanelNavigator
-		}
-		return m, nil
-	case "L", "shift+right":
-		switch m.focusedPanel {
-		case PanelNavigator:
-			m.focusedPanel = PanelContent
-		}
-		return m, nil
-	case "K", "shift+up":
-		switch m.focusedPanel {
-		case PanelReadme:
-			m.focusedPanel = PanelContent
-		}
-		return m, nil
-	case "J", "shift+down":
-		switch m.focusedPanel {
-		case PanelContent:
-			m.focusedPanel = PanelReadme
-		}
-		return m, nil
-	case "1":
-		m.focusedPanel = PanelNavigator
-		return m, nil
-	case "2":
-		m.focusedPanel = PanelContent
-		return m, nil
-	case "3":
-		m.focusedPanel = PanelReadme
-		return m, nil
+// pipelinesTabName returns the tab label with the active filter/sort, e.g. "Pipelines (failed)"
+func (m *MainScreen) pipelinesTabName() string {
+	var parts []string
+	if m.pipelineFilter != "" {
+		parts = append(parts, m.pipelineFilter)
 	}
-
-	switch m.focusedPanel {
-	case PanelNavigator:
-		return m.handleNavigatorNav(msg)
-	case PanelContent:
-		return m.handleContentNav(msg)
-	case PanelReadme:
-		return m.handleReadmeNav(msg)
+	if m.pipelineSort == pipelineSortStatus {
+		parts = append(parts, "by status")
 	}
+	if len(parts) == 0 {
+		return contentTabNames[TabPipelines]
+	}
+	return contentTabNames[TabPipelines] + " (" + strings.Join(parts, ", ") + ")"
+}
 
-	return m, nil
+// pipelinesRefreshedMsg is like pipelinesLoadedMsg but preserves selection
+type pipelinesRefreshedMsg struct {
+	pipelines []gitlab.Pipeline
+	gen       int
 }
 
-func (m *MainScreen) handleNavigatorNav(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	if len(m.treeNodes) == 0 {
-		return m, nil
+// refreshInterval returns d, or def when d is unset
+func refreshInterval(d, def time.Duration) time.Duration {
+	if d <= 0 {
+		return def
 	}
+	return d
+}
 
-	switch {
-	case key.Matches(msg, m.keymap.Down):
-		if m.selectedNodeIdx < len(m.treeNodes)-1 {
-			m.selectedNodeIdx++
-		}
-	case key.Matches(msg, m.keymap.Up):
-		if m.selectedNodeIdx > 0 {
-			m.selectedNodeIdx--
-		}
-	case key.Matches(msg, m.keymap.Right), key.Matches(msg, m.keymap.Select):
-		if m.selectedNodeIdx >= len(m.treeNodes) {
-			return m, nil
-		}
-
-		node := m.treeNodes[m.selectedNodeIdx]
+// pipelineTickCmd returns a command that sends a tick after the configured interval
+func (m *MainScreen) pipelineTickCmd() tea.Cmd {
+	gen := m.pipelineGen
+	return tea.Tick(refreshInterval(m.pipelineRefresh, config.PipelineRefreshInterval), func(time.Time) tea.Msg {
+		return pipelineTickMsg{gen: gen}
+	})
+}
 
-		if node.Type == "group" {
-			// Toggle group expansion
-			if m.expandedGroups[node.ID] {
-				// Collapse
-				m.expandedGroups[node.ID] = false
-				m.rebuildNavTree()
-			} else {
-				// Expand - check if we have projects cached
-				m.expandedGroups[node.ID] = true
-				if _, ok := m.groupProjects[node.ID]; !ok {
-					// Need to load projects
-					m.loading = true
-					m.loadingMsg = "Loading projects..."
-					cmd := m.loadGroupProjects(node.ID, node.FullPath)
-					m.retryCmd = cmd
-					return m, cmd
-				}
-				m.rebuildNavTree()
-			}
-		} else if node.Type == "project" && node.Project != nil {
-			// Select project and load its content
-			m.selectedProject = node.Project
-			m.currentPath = nil
-			m.currentBranch = ""
-			m.contentTab = TabFiles
-			m.focusedPanel = PanelContent
+// refreshPipelines fetches pipelines without resetting selection
+func (m *MainScreen) refreshPipelines() tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
+		return nil
+	}
+	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
+	gen := m.pipelineGen
+	return func() tea.Msg {
+		pipelines, err := m.client.ListPipelines(projectID)
+		if err != nil {
+			// Silently ignore errors on auto-refresh
+			return nil
+		}
+		return pipelinesRefreshedMsg{pipelines: pipelines, gen: gen}
+	}
+}
 
-			// In demo mode, data is pre-populated - don't clear or reload
-			if m.isDemo {
-				return m, nil
-			}
+// jobLogTickMsg triggers auto-refresh of job log; ticks from an older refresh
+// chain are dropped
+type jobLogTickMsg struct{ gen int }
+
+// jobLogRefreshedMsg carries refreshed log content. When partial is set, log
+// holds only the bytes past offset and is appended to the current log.
+type jobLogRefreshedMsg struct {
+	jobID   int
+	log     string
+	offset  int
+	partial bool
+}
 
-			m.files = nil
-			m.mergeRequests = nil
-			m.pipelines = nil
-			m.releases = nil
-			m.branches = nil
-			m.fileContent = ""
-			m.readmeContent = ""
-			m.loading = true
-			m.loadingMsg = "Loading repository..."
-			cmd := m.loadProjectContent()
-			m.retryCmd = cmd
-			return m, cmd
-		}
-	case key.Matches(msg, m.keymap.Left):
-		if m.selectedNodeIdx >= len(m.treeNodes) {
-			return m, nil
-		}
+// jobsRefreshedMsg carries refreshed job statuses
+type jobsRefreshedMsg struct{ jobs []gitlab.Job }
 
-		node := m.treeNodes[m.selectedNodeIdx]
-		if node.Type == "group" && m.expandedGroups[node.ID] {
-			// Collapse the group
-			m.expandedGroups[node.ID] = false
-			m.rebuildNavTree()
-		}
-	}
-	return m, nil
+// runnersLoadedMsg carries all running and pending jobs
+type runnersLoadedMsg struct {
+	running []gitlab.Job
+	pending []gitlab.Job
+	note    string // shown above the jobs, e.g. when they fell back to one project
+	err     error
+	gen     int
 }
 
-func (m *MainScreen) handleContentNav(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	// Handle escape for going back
-	if msg.String() == "esc" || msg.String() == "escape" {
-		// If viewing a file, go back to file list
-		if m.viewingFile {
-			m.viewingFile = false
-			m.fileContent = ""
-			m.viewingFilePath = ""
-			return m, nil
-		}
-		// If in a directory, go up
-		if m.contentTab == TabFiles && len(m.currentPath) > 0 {
-			m.currentPath = m.currentPath[:len(m.currentPath)-1]
-			// Demo mode doesn't support directory navigation
-			if m.isDemo {
-				return m, nil
-			}
-			m.loading = true
-			m.loadingMsg = "Loading..."
-			path := strings.Join(m.currentPath, "/")
-			cmd := m.loadDirectory(path)
-			m.retryCmd = cmd
-			return m, cmd
-		}
-		// If at root, go back to navigator
-		m.focusedPanel = PanelNavigator
-		return m, nil
-	}
+// runnersFallbackNote explains jobs listed for the selected project only
+const runnersFallbackNote = "Admin access required for instance-wide jobs; showing current project"
 
-	switch {
-	case key.Matches(msg, m.keymap.Left):
-		// h - switch to previous tab
-		if m.contentTab > TabFiles {
-			return m, m.switchTab(m.contentTab - 1)
-		}
-		// At first tab, go to navigator panel
-		m.focusedPanel = PanelNavigator
+// runnersTickMsg triggers auto-refresh of runners popup; ticks from an older
+// refresh chain are dropped
+type runnersTickMsg struct{ gen int }
 
-	case key.Matches(msg, m.keymap.Right):
-		// l - switch to next tab
-		if m.contentTab < TabReleases {
-			return m, m.switchTab(m.contentTab + 1)
-		}
+// runnersTickCmd returns a command that sends a tick for runners refresh
+func (m *MainScreen) runnersTickCmd() tea.Cmd {
+	gen := m.runnersGen
+	return tea.Tick(refreshInterval(m.pipelineRefresh, config.PipelineRefreshInterval), func(time.Time) tea.Msg {
+		return runnersTickMsg{gen: gen}
+	})
+}
 
-	case key.Matches(msg, m.keymap.Select):
-		// Enter - drill into directory or view file
-		if m.contentTab == TabFiles && m.selectedContent < len(m.files) {
-			entry := m.files[m.selectedContent]
-			if entry.Type == "tree" {
-				// Demo mode doesn't support directory navigation
-				if m.isDemo {
-					return m, nil
-				}
-				m.currentPath = append(m.currentPath, entry.Name)
-				m.loading = true
-				m.loadingMsg = "Loading..."
-				cmd := m.loadDirectory(entry.Path)
-				m.retryCmd = cmd
-				return m, cmd
-			} else {
-				// Demo mode uses mock file content
-				if m.isDemo {
-					if content, ok := MockFileContent[entry.Name]; ok {
-						m.fileContent = content
-						m.viewingFile = true
-						m.viewingFilePath = entry.Path
-					}
-					return m, nil
-				}
-				m.loading = true
-				m.loadingMsg = "Loading file..."
-				cmd := m.loadFile(entry.Path)
-				m.retryCmd = cmd
-				return m, cmd
-			}
-		}
-		// Load jobs for selected pipeline and show popup
-		if m.contentTab == TabPipelines && m.selectedContent < len(m.pipelines) {
-			// Demo mode doesn't support job log viewing
-			if m.isDemo {
-				return m, nil
+// loadAllJobs fetches all running and pending jobs across projects. Without
+// access to them it falls back to the selected project's active pipelines.
+// Each load starts a new refresh chain, retiring any tick still pending.
+func (m *MainScreen) loadAllJobs() tea.Cmd {
+	if m.isDemo {
+		return nil
+	}
+	m.runnersGen++
+	gen := m.runnersGen
+	project := m.selectedProject
+	return func() tea.Msg {
+		running, err := m.client.ListRunningJobs()
+		if err == nil {
+			var pending []gitlab.Job
+			pending, err = m.client.ListPendingJobs()
+			if err == nil {
+				return runnersLoadedMsg{running: running, pending: pending, gen: gen}
 			}
-			pipeline := m.pipelines[m.selectedContent]
-			m.jobs = nil
-			m.jobLog = ""
-			m.showJobLogPopup = true
-			m.jobLogFocused = false // Start focused on job list
-			m.jobLogCursor = 0
-			m.jobLogHScroll = 0
-			m.currentPipelineID = pipeline.ID
-			m.loading = true
-			m.loadingMsg = "Loading jobs..."
-			cmd := m.loadPipelineJobs(pipeline.ID)
-			m.retryCmd = cmd
-			return m, cmd
 		}
-		// Show release assets popup
-		if m.contentTab == TabReleases && m.selectedContent < len(m.releases) {
-			m.selectedReleaseIdx = m.selectedContent
-			m.releaseAssetCursor = 0
-			m.releaseScrollOffset = 0
-			m.showReleasePopup = true
-			return m, nil
+		if !errors.Is(err, gitlab.ErrUnauthorized) || project == nil {
+			return runnersLoadedMsg{err: err, gen: gen}
 		}
+		running, pending, err := m.projectActiveJobs(project)
+		return runnersLoadedMsg{running: running, pending: pending, note: runnersFallbackNote, err: err, gen: gen}
+	}
+}
 
-	case key.Matches(msg, m.keymap.Down):
-		// If viewing file, scroll down
-		if m.viewingFile {
-			m.fileViewport.ScrollDown(1)
-			return m, nil
-		}
-		maxItems := m.getContentCount()
-		if m.selectedContent < maxItems-1 {
-			m.selectedContent++
-			if m.contentTab == TabFiles {
-				m.fileContent = ""
-				m.viewingFile = false
-			}
-			m.adjustScrollOffset()
-		}
-	case key.Matches(msg, m.keymap.Up):
-		// If viewing file, scroll up
-		if m.viewingFile {
-			m.fileViewport.ScrollUp(1)
-			return m, nil
+// projectActiveJobs collects the running and pending jobs of a project's
+// active pipelines
+func (m *MainScreen) projectActiveJobs(project *gitlab.Project) (running, pending []gitlab.Job, err error) {
+	projectID := fmt.Sprintf("%d", project.ID)
+	pipelines, err := m.client.ListPipelines(projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, p := range pipelines {
+		// Finished pipelines have no running or pending jobs left
+		if p.Status != "running" && p.Status != "pending" {
+			continue
 		}
-		if m.selectedContent > 0 {
-			m.selectedContent--
-			if m.contentTab == TabFiles {
-				m.fileContent = ""
-				m.viewingFile = false
+		jobs, err := m.client.ListPipelineJobs(projectID, p.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, job := range jobs {
+			job.Project.ID = project.ID
+			job.Project.Name = project.Name
+			job.Project.PathWithNamespace = project.PathWithNamespace
+			switch job.Status {
+			case "running":
+				running = append(running, job)
+			case "pending":
+				pending = append(pending, job)
 			}
-			m.adjustScrollOffset()
 		}
 	}
+	return running, pending, nil
+}
 
-	// Additional scroll keys when viewing file
-	if m.viewingFile {
-		switch msg.String() {
-		case "ctrl+d":
-			m.fileViewport.HalfPageDown()
-		case "ctrl+u":
-			m.fileViewport.HalfPageUp()
-		case "g":
-			m.fileViewport.GotoTop()
-		case "G":
-			m.fileViewport.GotoBottom()
+// currentUserLoadedMsg carries the authenticated user and their pending todo count
+type currentUserLoadedMsg struct {
+	user  *gitlab.User
+	todos int
+	err   error
+}
+
+// loadCurrentUser fetches the authenticated user and their todo count
+func (m *MainScreen) loadCurrentUser() tea.Cmd {
+	if m.isDemo {
+		return nil
+	}
+	return func() tea.Msg {
+		user, err := m.client.GetCurrentUser()
+		if err != nil {
+			return currentUserLoadedMsg{err: err}
+		}
+		todos, err := m.client.GetTodosCount()
+		if err != nil {
+			todos = -1
 		}
+		return currentUserLoadedMsg{user: user, todos: todos}
 	}
+}
 
-	return m, nil
+// myMRsLoadedMsg carries merge requests assigned to / awaiting review by the current user
+type myMRsLoadedMsg struct {
+	assigned []gitlab.MergeRequest
+	review   []gitlab.MergeRequest
+	err      error
+	gen      int
 }
 
-func (m *MainScreen) adjustScrollOffset() {
-	// Calculate visible area matching renderContentPanel calculation
-	// contentHeight = m.height - StatusBarHeight (1)
-	// visibleLines = height - 6 (in renderContentPanel)
-	contentHeight := m.height - config.StatusBarHeight
-	visibleLines := contentHeight - 6
-	if visibleLines < 1 {
-		visibleLines = 1
-	}
+// myMRsTickMsg triggers auto-refresh of the my merge requests popup; ticks
+// from an older refresh chain are dropped
+type myMRsTickMsg struct{ gen int }
 
-	// Adjust offset to keep selected item visible
-	if m.selectedContent < m.fileScrollOffset {
-		m.fileScrollOffset = m.selectedContent
-	} else if m.selectedContent >= m.fileScrollOffset+visibleLines {
-		m.fileScrollOffset = m.selectedContent - visibleLines + 1
-	}
+// mrProjectLoadedMsg carries the project of an MR selected from the my merge requests popup
+type mrProjectLoadedMsg struct {
+	project *gitlab.Project
+	mr      gitlab.MergeRequest
 }
 
-func (m *MainScreen) handleReadmeNav(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	key := msg.String()
+// environmentsLoadedMsg carries the environments of the selected project
+type environmentsLoadedMsg struct {
+	environments []gitlab.Environment
+	err          error
+}
 
-	// Clear key sequence unless it's a sequence key (g, y)
-	if key != "g" && key != "y" {
-		m.readmeLastKey = ""
+// deploymentsLoadedMsg carries recent deployments to an environment
+type deploymentsLoadedMsg struct {
+	environment string
+	deployments []gitlab.Deployment
+	err         error
+}
+
+// loadEnvironments fetches environments for the selected project
+func (m *MainScreen) loadEnvironments() tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
+		return nil
 	}
+	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
+	client := m.client
+	sem := m.limiter()
 
-	// Get line count from raw content
-	maxLine := strings.Count(m.readmeContent, "\n")
+	return func() tea.Msg {
+		environments, err := client.ListEnvironments(projectID)
+		if err == nil {
+			fillLastDeployments(client, sem, projectID, environments)
+		}
+		return environmentsLoadedMsg{environments: environments, err: err}
+	}
+}
 
-	switch key {
-	case "H", "shift+left":
-		m.focusedPanel = PanelNavigator
-		return m, nil
-	case "L", "shift+right":
-		m.focusedPanel = PanelContent
-		return m, nil
-	case "h", "left":
-		m.focusedPanel = PanelNavigator
-		return m, nil
-	case "l", "right":
-		m.focusedPanel = PanelContent
-		return m, nil
-	case "j", "down":
-		if m.readmeCursor < maxLine {
-			m.readmeCursor++
-			if m.readmeVisualMode {
-				m.readmeVisualEnd = m.readmeCursor
-			}
-		}
-		// Keep cursor in view
-		viewportBottom := m.readmeViewport.YOffset + m.readmeViewport.Height - 1
-		if m.readmeCursor > viewportBottom {
-			m.readmeViewport.ScrollDown(1)
-		}
-	case "k", "up":
-		if m.readmeCursor > 0 {
-			m.readmeCursor--
-			if m.readmeVisualMode {
-				m.readmeVisualEnd = m.readmeCursor
-			}
-		}
-		// Keep cursor in view
-		if m.readmeCursor < m.readmeViewport.YOffset {
-			m.readmeViewport.ScrollUp(1)
-		}
-	case "ctrl+d":
-		m.readmeViewport.HalfPageDown()
-		m.readmeCursor += m.readmeViewport.Height / 2
-		if m.readmeCursor > maxLine {
-			m.readmeCursor = maxLine
-		}
-		if m.readmeVisualMode {
-			m.readmeVisualEnd = m.readmeCursor
-		}
-	case "ctrl+u":
-		m.readmeViewport.HalfPageUp()
-		m.readmeCursor -= m.readmeViewport.Height / 2
-		if m.readmeCursor < 0 {
-			m.readmeCursor = 0
-		}
-		if m.readmeVisualMode {
-			m.readmeVisualEnd = m.readmeCursor
+// fillLastDeployments fetches the environments the list endpoint returned
+// without a last deployment, in parallel within sem. Failures leave the
+// environment without one.
+func fillLastDeployments(client gitlab.API, sem chan struct{}, projectID string, environments []gitlab.Environment) {
+	var wg sync.WaitGroup
+	for i := range environments {
+		if environments[i].LastDeployment != nil {
+			continue
 		}
-	case "g":
-		if m.readmeLastKey == "g" {
-			// gg - go to top
-			m.readmeViewport.GotoTop()
-			m.readmeCursor = 0
-			if m.readmeVisualMode {
-				m.readmeVisualEnd = m.readmeCursor
+		wg.Add(1)
+		go func(env *gitlab.Environment) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if full, err := client.GetEnvironment(projectID, env.ID); err == nil {
+				env.LastDeployment = full.LastDeployment
 			}
-			m.readmeLastKey = "gg"
-			return m, nil
+		}(&environments[i])
+	}
+	wg.Wait()
+}
+
+// aheadBehind counts the commits a branch has that the default branch
+// doesn't, and the other way around
+type aheadBehind struct {
+	ahead, behind int
+}
+
+// divergenceLoadedMsg carries the ahead/behind counts of a branch
+type divergenceLoadedMsg struct {
+	projectID int
+	branch    string
+	counts    aheadBehind
+	err       error
+}
+
+// loadDivergence compares a branch with the default branch in both directions
+func (m *MainScreen) loadDivergence(branch, defaultBranch string) tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
+		return nil
+	}
+	project := m.selectedProject.ID
+	projectID := fmt.Sprintf("%d", project)
+
+	return func() tea.Msg {
+		ahead, err := m.client.CompareBranches(projectID, defaultBranch, branch)
+		if err != nil {
+			return divergenceLoadedMsg{projectID: project, branch: branch, err: err}
 		}
-		m.readmeLastKey = "g"
-		return m, nil
-	case "G":
-		m.readmeViewport.GotoBottom()
-		m.readmeCursor = maxLine
-		if m.readmeVisualMode {
-			m.readmeVisualEnd = m.readmeCursor
+		behind, err := m.client.CompareBranches(projectID, branch, defaultBranch)
+		if err != nil {
+			return divergenceLoadedMsg{projectID: project, branch: branch, err: err}
 		}
-	case "V":
-		// Toggle visual line mode
-		if m.readmeVisualMode {
-			m.readmeVisualMode = false
-		} else {
-			m.readmeVisualMode = true
-			m.readmeVisualStart = m.readmeCursor
-			m.readmeVisualEnd = m.readmeCursor
+		return divergenceLoadedMsg{
+			projectID: project,
+			branch:    branch,
+			counts:    aheadBehind{ahead: len(ahead.Commits), behind: len(behind.Commits)},
 		}
-	case "y":
-		if m.readmeContent == "" {
-			m.readmeLastKey = ""
-			return m, nil
+	}
+}
+
+// defaultBranchName returns the branch flagged as default, falling back to the project's
+func (m *MainScreen) defaultBranchName() string {
+	for _, b := range m.branches {
+		if b.Default {
+			return b.Name
 		}
-		lines := strings.Split(m.readmeContent, "\n")
-		if m.readmeVisualMode {
-			// Copy selected lines
-			startLine := m.readmeVisualStart
-			endLine := m.readmeVisualEnd
-			if startLine > endLine {
-				startLine, endLine = endLine, startLine
-			}
-			if startLine < 0 {
-				startLine = 0
-			}
-			if endLine >= len(lines) {
-				endLine = len(lines) - 1
-			}
-			selected := strings.Join(lines[startLine:endLine+1], "\n")
-			if err := copyToClipboard(selected); err != nil {
-				m.statusMsg = "Copy failed: " + err.Error()
-			} else {
-				m.statusMsg = fmt.Sprintf("Copied %d lines!", endLine-startLine+1)
-			}
-			m.readmeVisualMode = false
-		} else if m.readmeLastKey == "gg" {
-			// ggy - yank entire readme
-			if err := copyToClipboard(m.readmeContent); err != nil {
-				m.statusMsg = "Copy failed: " + err.Error()
-			} else {
-				m.statusMsg = fmt.Sprintf("Yanked all %d lines!", len(lines))
+	}
+	if m.selectedProject != nil && m.selectedProject.DefaultBranch != "" {
+		return m.selectedProject.DefaultBranch
+	}
+	return config.DefaultBranch
+}
+
+// comparisonLoadedMsg carries the diff between two branches
+type comparisonLoadedMsg struct {
+	from       string
+	to         string
+	comparison *gitlab.Comparison
+	err        error
+}
+
+// loadComparison fetches the comparison between two branches of the selected project
+func (m *MainScreen) loadComparison(from, to string) tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
+		return nil
+	}
+	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
+
+	return func() tea.Msg {
+		comparison, err := m.client.CompareBranches(projectID, from, to)
+		return comparisonLoadedMsg{from: from, to: to, comparison: comparison, err: err}
+	}
+}
+
+// commitDiffLoadedMsg carries the last commit of a path and its diff
+type commitDiffLoadedMsg struct {
+	path   string
+	commit *gitlab.Commit
+	diffs  []gitlab.Diff
+	err    error
+}
+
+// loadCommitDiff fetches the diff of the entry's last commit. The commit is
+// looked up first when fetching it with the tree failed.
+func (m *MainScreen) loadCommitDiff(entry gitlab.TreeEntry) tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
+		return nil
+	}
+	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
+	ref := m.currentRef()
+
+	return func() tea.Msg {
+		commit := entry.LastCommit
+		if commit == nil {
+			var err error
+			commit, err = m.client.GetLastCommitForPath(projectID, ref, entry.Path)
+			if err != nil {
+				return commitDiffLoadedMsg{path: entry.Path, err: err}
 			}
-		} else if m.readmeLastKey == "y" {
-			// yy - yank current line
-			if m.readmeCursor >= 0 && m.readmeCursor < len(lines) {
-				if err := copyToClipboard(lines[m.readmeCursor]); err != nil {
-					m.statusMsg = "Copy failed: " + err.Error()
-				} else {
-					m.statusMsg = "Yanked line!"
-				}
+			if commit == nil {
+				return commitDiffLoadedMsg{path: entry.Path, err: fmt.Errorf("no commits found for %s", entry.Path)}
 			}
-		} else {
-			m.readmeLastKey = "y"
-			return m, nil
 		}
-		m.readmeLastKey = ""
-	case "esc", "escape":
-		if m.readmeVisualMode {
-			m.readmeVisualMode = false
-			return m, nil
+		diffs, err := m.client.GetCommitDiff(projectID, commit.ID)
+		if err == nil && entry.Type != "tree" {
+			diffs = diffsForPath(diffs, entry.Path)
 		}
+		return commitDiffLoadedMsg{path: entry.Path, commit: commit, diffs: diffs, err: err}
 	}
-	return m, nil
 }
 
-func (m *MainScreen) handleBranchPopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "q", "esc", "escape":
-		m.showBranchPopup = false
-		return m, nil
-	case "j", "down":
-		if m.selectedBranchIdx < len(m.branches)-1 {
-			m.selectedBranchIdx++
-		}
-	case "k", "up":
-		if m.selectedBranchIdx > 0 {
-			m.selectedBranchIdx--
-		}
-	case "enter":
-		if m.selectedBranchIdx < len(m.branches) {
-			m.currentBranch = m.branches[m.selectedBranchIdx].Name
-			m.showBranchPopup = false
-			// Demo mode doesn't support branch switching
-			if m.isDemo {
-				return m, nil
-			}
-			// Reload files for new branch
-			m.files = nil
-			m.currentPath = nil
-			m.fileContent = ""
-			m.viewingFile = false
-			m.readmeContent = ""
-			m.loading = true
-			m.loadingMsg = "Loading files..."
-			cmd := m.loadProjectContentForBranch(m.currentBranch)
-			m.retryCmd = cmd
-			return m, cmd
+// diffsForPath keeps the diffs touching filePath, including renames from it
+func diffsForPath(diffs []gitlab.Diff, filePath string) []gitlab.Diff {
+	var result []gitlab.Diff
+	for _, d := range diffs {
+		if d.NewPath == filePath || d.OldPath == filePath {
+			result = append(result, d)
 		}
 	}
-	return m, nil
+	return result
 }
 
-func (m *MainScreen) handleRunnersPopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	// Get current job list based on tab
-	jobs := m.runningJobs
-	if m.runnersTab == 1 {
-		jobs = m.pendingJobs
-	}
+// variablesLoadedMsg carries the CI/CD variables of the selected project
+type variablesLoadedMsg struct {
+	variables []gitlab.CIVariable
+	err       error
+}
 
-	switch msg.String() {
-	case "q", "esc", "escape":
-		m.showRunnersPopup = false
-		return m, nil
-	case "j", "down":
-		if m.runnersCursor < len(jobs)-1 {
-			m.runnersCursor++
-		}
-	case "k", "up":
-		if m.runnersCursor > 0 {
-			m.runnersCursor--
-		}
-	case "tab", "l", "right":
-		// Switch between running/pending tabs
-		m.runnersTab = (m.runnersTab + 1) % 2
-		m.runnersCursor = 0
-	case "shift+tab", "h", "left":
-		m.runnersTab = (m.runnersTab + 1) % 2
-		m.runnersCursor = 0
-	case "r":
-		// Manual refresh
-		m.runnersLoading = true
-		return m, m.loadAllJobs()
-	case "g":
-		if m.runnersLastKey == "g" {
-			m.runnersCursor = 0
-			m.runnersLastKey = ""
-			return m, nil
-		}
-		m.runnersLastKey = "g"
-		return m, nil
-	case "G":
-		m.runnersCursor = len(jobs) - 1
-		if m.runnersCursor < 0 {
-			m.runnersCursor = 0
-		}
+// loadVariables fetches CI/CD variables for the selected project
+func (m *MainScreen) loadVariables() tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
+		return nil
 	}
-	// Clear key sequence for non-sequence keys
-	if msg.String() != "g" {
-		m.runnersLastKey = ""
+	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
+
+	return func() tea.Msg {
+		variables, err := m.client.ListProjectVariables(projectID)
+		return variablesLoadedMsg{variables: variables, err: err}
 	}
-	return m, nil
 }
 
-func (m *MainScreen) handleJobLogPopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	key := msg.String()
+// snippetsLoadedMsg carries the snippets of the selected project
+type snippetsLoadedMsg struct {
+	snippets []gitlab.Snippet
+	err      error
+}
 
-	// Clear key sequence unless it's a sequence key (g, y)
-	if key != "g" && key != "y" {
-		m.jobLogLastKey = ""
+// loadSnippets fetches the snippets of the selected project
+func (m *MainScreen) loadSnippets() tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
+		return nil
 	}
+	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
 
-	switch key {
-	case "q":
-		m.showJobLogPopup = false
-		m.jobs = nil
-		m.jobLog = ""
-		m.statusMsg = ""
-		m.lastError = ""
-		m.jobLogFocused = false
-		return m, nil
-	case "esc", "escape":
-		// Cancel visual mode first
-		if m.visualLineMode {
-			m.visualLineMode = false
-			return m, nil
-		}
-		// Switch to job list first, then close
-		if m.jobLogFocused {
-			m.jobLogFocused = false
-			return m, nil
-		}
-		m.showJobLogPopup = false
-		m.jobs = nil
-		m.jobLog = ""
-		m.statusMsg = ""
-		m.lastError = ""
-		m.visualLineMode = false
-		return m, nil
-	case "H", "shift+left":
-		// Switch to job list panel
-		if m.jobLogFocused {
-			m.jobLogFocused = false
+	return func() tea.Msg {
+		snippets, err := m.client.ListProjectSnippets(projectID)
+		return snippetsLoadedMsg{snippets: snippets, err: err}
+	}
+}
+
+// snippetPath is the path shown in the file viewer for a snippet; its base
+// name drives syntax highlighting
+func snippetPath(s gitlab.Snippet) string {
+	name := s.FileName
+	if name == "" {
+		name = "snippet.txt"
+	}
+	return fmt.Sprintf("$%d/%s", s.ID, name)
+}
+
+// loadSnippetContent fetches a snippet for the file viewer
+func (m *MainScreen) loadSnippetContent(snippet gitlab.Snippet) tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
+		return nil
+	}
+	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
+
+	return func() tea.Msg {
+		content, err := m.client.GetSnippetContent(projectID, snippet.ID)
+		if err != nil {
+			return errMsg{err: err}
 		}
-		return m, nil
-	case "L", "shift+right", "enter":
-		// Switch to log panel
-		if !m.jobLogFocused {
-			m.jobLogFocused = true
+		return fileContentMsg{content: content, path: snippetPath(snippet), snippet: true}
+	}
+}
+
+// contributorsLoadedMsg carries the contributors of the selected project
+type contributorsLoadedMsg struct {
+	contributors []gitlab.Contributor
+	err          error
+}
+
+// loadContributors fetches the contributors of the selected project
+func (m *MainScreen) loadContributors() tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
+		return nil
+	}
+	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
+
+	return func() tea.Msg {
+		contributors, err := m.client.ListContributors(projectID)
+		return contributorsLoadedMsg{contributors: contributors, err: err}
+	}
+}
+
+// sortContributors orders contributors by commit count, most first
+func sortContributors(contributors []gitlab.Contributor) []gitlab.Contributor {
+	slices.SortStableFunc(contributors, func(a, b gitlab.Contributor) int {
+		return b.Commits - a.Commits
+	})
+	return contributors
+}
+
+// activityLoadedMsg carries the activity feed of the selected project
+type activityLoadedMsg struct {
+	events []gitlab.Event
+	err    error
+}
+
+// loadActivity fetches the activity feed of the selected project
+func (m *MainScreen) loadActivity() tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
+		return nil
+	}
+	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
+
+	return func() tea.Msg {
+		events, err := m.client.ListProjectEvents(projectID)
+		return activityLoadedMsg{events: events, err: err}
+	}
+}
+
+// sortEvents orders events newest first
+func sortEvents(events []gitlab.Event) []gitlab.Event {
+	slices.SortStableFunc(events, func(a, b gitlab.Event) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return events
+}
+
+// eventSummary describes an event the way GitLab's activity page does, e.g.
+// "Alice pushed to main" or "Bob opened !23 Add OAuth2"
+func eventSummary(e gitlab.Event) string {
+	who := e.Author.Name
+	if who == "" {
+		who = e.Author.Username
+	}
+	action := e.ActionName
+	if action == "accepted" {
+		action = "merged" // GitLab's name for merging an MR
+	}
+
+	if p := e.PushData; p != nil {
+		// "pushed new branch x" and "deleted tag y" read better with the ref type
+		if action == "pushed to" {
+			return fmt.Sprintf("%s %s %s", who, action, p.Ref)
 		}
-		return m, nil
-	case "h", "left":
-		// Scroll left
-		if m.jobLogFocused && m.jobLogHScroll > 0 {
-			m.jobLogHScroll -= 20
-			if m.jobLogHScroll < 0 {
-				m.jobLogHScroll = 0
-			}
+		return fmt.Sprintf("%s %s %s %s", who, action, p.RefType, p.Ref)
+	}
+
+	target := e.TargetTitle
+	switch e.TargetType {
+	case "MergeRequest":
+		target = fmt.Sprintf("!%d %s", e.TargetIID, target)
+	case "Issue", "WorkItem":
+		target = fmt.Sprintf("#%d %s", e.TargetIID, target)
+	}
+	return strings.TrimSpace(fmt.Sprintf("%s %s %s", who, action, target))
+}
+
+// codeSearchMsg carries the matches of a code search
+type codeSearchMsg struct {
+	projectID int
+	query     string
+	blobs     []gitlab.SearchBlob
+	err       error
+}
+
+// loadCodeSearch searches the selected project's code at the current ref
+func (m *MainScreen) loadCodeSearch(query string) tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
+		return nil
+	}
+	project := m.selectedProject.ID
+	projectID := fmt.Sprintf("%d", project)
+	ref := m.currentRef()
+
+	return func() tea.Msg {
+		blobs, err := m.client.SearchBlobs(projectID, query, ref)
+		return codeSearchMsg{projectID: project, query: query, blobs: blobs, err: err}
+	}
+}
+
+// codeSearchError explains search failures; instances without advanced
+// search only answer with a terse API error
+func codeSearchError(err error) string {
+	lower := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(lower, "elasticsearch"), strings.Contains(lower, "scope not supported"):
+		return "Code search is not enabled on this GitLab instance"
+	case errors.Is(err, gitlab.ErrUnauthorized):
+		return "Code search is not allowed for this token or project"
+	}
+	return "Error: " + err.Error()
+}
+
+// blobMatch returns the line number and text of the first line of a search
+// match containing query, or its first line when the query used search
+// syntax that doesn't appear literally
+func blobMatch(b gitlab.SearchBlob, query string) (int, string) {
+	lines := strings.Split(strings.TrimRight(b.Data, "\n"), "\n")
+	for i, line := range lines {
+		if strings.Contains(strings.ToLower(line), strings.ToLower(query)) {
+			return b.Startline + i, strings.TrimSpace(line)
 		}
-		return m, nil
-	case "l", "right":
-		// Scroll right
-		if m.jobLogFocused {
-			m.jobLogHScroll += 20
+	}
+	return b.Startline, strings.TrimSpace(lines[0])
+}
+
+// blobPath returns the repository path of a search match
+func blobPath(b gitlab.SearchBlob) string {
+	if b.Path != "" {
+		return b.Path
+	}
+	return b.Filename
+}
+
+// labelsLoadedMsg carries the labels and milestones of the selected project
+type labelsLoadedMsg struct {
+	labels     []gitlab.Label
+	milestones []gitlab.Milestone
+	err        error
+}
+
+// loadLabels fetches the labels and milestones of the selected project
+func (m *MainScreen) loadLabels() tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
+		return nil
+	}
+	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
+
+	return func() tea.Msg {
+		labels, err := m.client.ListLabels(projectID)
+		if err != nil {
+			return labelsLoadedMsg{err: err}
 		}
-		return m, nil
-	case "j", "down":
-		if m.jobLogFocused {
-			maxLine := strings.Count(m.jobLog, "\n")
-			if m.jobLogCursor < maxLine {
-				m.jobLogCursor++
-				if m.visualLineMode {
-					m.visualEndLine = m.jobLogCursor
-				}
-			}
-			// Keep cursor in view
-			viewportBottom := m.jobLogViewport.YOffset + m.jobLogViewport.Height - 1
-			if m.jobLogCursor > viewportBottom {
-				m.jobLogViewport.ScrollDown(1)
-			}
-		} else {
-			// Next job in list
-			if m.selectedJobIdx < len(m.jobs)-1 {
-				m.selectedJobIdx++
-				if !m.isDemo {
-					m.jobLog = ""
-					m.jobLogReady = false
-					m.jobLogHScroll = 0
-					m.visualLineMode = false
-					m.loading = true
-					m.loadingMsg = "Loading job log..."
-					m.statusMsg = ""
-					cmd := m.loadJobLog(m.jobs[m.selectedJobIdx].ID)
-					m.retryCmd = cmd
-					return m, cmd
-				}
-			}
+		milestones, err := m.client.ListMilestones(projectID)
+		if err != nil {
+			return labelsLoadedMsg{err: err}
 		}
-	case "k", "up":
-		if m.jobLogFocused {
-			if m.jobLogCursor > 0 {
-				m.jobLogCursor--
-				if m.visualLineMode {
-					m.visualEndLine = m.jobLogCursor
-				}
-			}
-			// Keep cursor in view
-			if m.jobLogCursor < m.jobLogViewport.YOffset {
-				m.jobLogViewport.ScrollUp(1)
-			}
-		} else {
-			// Previous job in list
-			if m.selectedJobIdx > 0 {
-				m.selectedJobIdx--
-				if !m.isDemo {
-					m.jobLog = ""
-					m.jobLogReady = false
-					m.jobLogHScroll = 0
-					m.visualLineMode = false
-					m.loading = true
-					m.loadingMsg = "Loading job log..."
-					m.statusMsg = ""
-					cmd := m.loadJobLog(m.jobs[m.selectedJobIdx].ID)
-					m.retryCmd = cmd
-					return m, cmd
-				}
+		return labelsLoadedMsg{labels: labels, milestones: milestones}
+	}
+}
+
+// sortLabels orders labels by name, ignoring case
+func sortLabels(labels []gitlab.Label) []gitlab.Label {
+	slices.SortStableFunc(labels, func(a, b gitlab.Label) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	return labels
+}
+
+// sortMilestones puts active milestones first, soonest due date first, with
+// undated ones after dated ones
+func sortMilestones(milestones []gitlab.Milestone) []gitlab.Milestone {
+	slices.SortStableFunc(milestones, func(a, b gitlab.Milestone) int {
+		if (a.State == "active") != (b.State == "active") {
+			if a.State == "active" {
+				return -1
 			}
+			return 1
 		}
-	case "ctrl+d":
-		if m.jobLogFocused {
-			m.jobLogViewport.HalfPageDown()
-			maxLine := strings.Count(m.jobLog, "\n")
-			m.jobLogCursor += m.jobLogViewport.Height / 2
-			if m.jobLogCursor > maxLine {
-				m.jobLogCursor = maxLine
-			}
-			if m.visualLineMode {
-				m.visualEndLine = m.jobLogCursor
+		if (a.DueDate == "") != (b.DueDate == "") {
+			if a.DueDate == "" {
+				return 1
 			}
+			return -1
 		}
-	case "ctrl+u":
-		if m.jobLogFocused {
-			m.jobLogViewport.HalfPageUp()
-			m.jobLogCursor -= m.jobLogViewport.Height / 2
-			if m.jobLogCursor < 0 {
-				m.jobLogCursor = 0
-			}
-			if m.visualLineMode {
-				m.visualEndLine = m.jobLogCursor
-			}
+		// ISO dates sort lexically
+		return strings.Compare(a.DueDate, b.DueDate)
+	})
+	return milestones
+}
+
+// wikiPagesLoadedMsg carries the wiki pages of a project
+type wikiPagesLoadedMsg struct {
+	projectID int
+	pages     []gitlab.WikiPage
+	err       error
+}
+
+// loadWikiPages fetches the wiki pages of the selected project
+func (m *MainScreen) loadWikiPages() tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
+		return nil
+	}
+	projectID := m.selectedProject.ID
+
+	return func() tea.Msg {
+		pages, err := m.client.ListWikiPages(fmt.Sprintf("%d", projectID))
+		return wikiPagesLoadedMsg{projectID: projectID, pages: pages, err: err}
+	}
+}
+
+// wikiPath is the path shown in the file viewer for a wiki page; its
+// extension decides whether it's rendered as markdown
+func wikiPath(page gitlab.WikiPage) string {
+	ext := page.Format
+	if ext == "" || ext == "markdown" {
+		ext = "md"
+	}
+	return "wiki/" + page.Slug + "." + ext
+}
+
+// loadWikiPage fetches a wiki page for the file viewer
+func (m *MainScreen) loadWikiPage(page gitlab.WikiPage) tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
+		return nil
+	}
+	projectID := m.selectedProject.ID
+
+	return func() tea.Msg {
+		full, err := m.client.GetWikiPage(fmt.Sprintf("%d", projectID), page.Slug)
+		if err != nil {
+			return errMsg{err: err}
 		}
-	case "g":
-		if m.jobLogFocused {
-			if m.jobLogLastKey == "g" {
-				// gg - go to top
-				m.jobLogViewport.GotoTop()
-				m.jobLogCursor = 0
-				if m.visualLineMode {
-					m.visualEndLine = m.jobLogCursor
-				}
-				m.jobLogLastKey = "gg" // Mark that we did gg
-				return m, nil
-			}
-			m.jobLogLastKey = "g"
-			return m, nil
+		return fileContentMsg{content: full.Content, path: wikiPath(*full), wiki: true, projectID: projectID}
+	}
+}
+
+// loadDeployments fetches recent deployments to an environment of the selected project
+func (m *MainScreen) loadDeployments(environment string) tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
+		return nil
+	}
+	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
+
+	return func() tea.Msg {
+		deployments, err := m.client.ListDeployments(projectID, environment)
+		return deploymentsLoadedMsg{environment: environment, deployments: deployments, err: err}
+	}
+}
+
+// myMRsTickCmd returns a command that sends a tick for my merge requests refresh
+func (m *MainScreen) myMRsTickCmd() tea.Cmd {
+	gen := m.myMRsGen
+	return tea.Tick(refreshInterval(m.pipelineRefresh, config.PipelineRefreshInterval), func(time.Time) tea.Msg {
+		return myMRsTickMsg{gen: gen}
+	})
+}
+
+// loadMyMRs fetches merge requests assigned to me and awaiting my review.
+// Each load starts a new refresh chain, retiring any tick still pending.
+func (m *MainScreen) loadMyMRs() tea.Cmd {
+	if m.isDemo {
+		return nil
+	}
+	m.myMRsGen++
+	gen := m.myMRsGen
+	return func() tea.Msg {
+		assigned, err := m.client.ListMyMergeRequests()
+		if err != nil {
+			return myMRsLoadedMsg{err: err, gen: gen}
 		}
-	case "G":
-		if m.jobLogFocused {
-			m.jobLogViewport.GotoBottom()
-			m.jobLogCursor = strings.Count(m.jobLog, "\n")
-			if m.visualLineMode {
-				m.visualEndLine = m.jobLogCursor
-			}
+		review, err := m.client.ListMyReviewRequests()
+		if err != nil {
+			return myMRsLoadedMsg{err: err, gen: gen}
 		}
-	case "V":
-		// Toggle visual line mode
-		if m.jobLogFocused {
-			if m.visualLineMode {
-				m.visualLineMode = false
-			} else {
-				m.visualLineMode = true
-				m.visualStartLine = m.jobLogCursor
-				m.visualEndLine = m.jobLogCursor
-			}
+		return myMRsLoadedMsg{assigned: assigned, review: review, gen: gen}
+	}
+}
+
+// loadMRProject fetches the project an MR belongs to so it can be opened
+func (m *MainScreen) loadMRProject(mr gitlab.MergeRequest) tea.Cmd {
+	if m.isDemo {
+		return nil
+	}
+	return func() tea.Msg {
+		project, err := m.client.GetProject(fmt.Sprintf("%d", mr.ProjectID))
+		if err != nil {
+			return errMsg{err: err}
 		}
-	case "y":
-		if m.jobLog == "" {
-			m.jobLogLastKey = ""
-			return m, nil
+		return mrProjectLoadedMsg{project: project, mr: mr}
+	}
+}
+
+// favoriteProjectLoadedMsg carries a favorited project fetched by path
+type favoriteProjectLoadedMsg struct{ project *gitlab.Project }
+
+// loadFavoriteProject fetches a favorited project that isn't in the group cache
+func (m *MainScreen) loadFavoriteProject(projectPath string) tea.Cmd {
+	if m.isDemo {
+		return nil
+	}
+	return func() tea.Msg {
+		project, err := m.client.GetProject(projectPath)
+		if err != nil {
+			return errMsg{err: err}
 		}
-		lines := strings.Split(m.jobLog, "\n")
-		if m.visualLineMode {
-			// Copy selected lines
-			startLine := m.visualStartLine
-			endLine := m.visualEndLine
-			if startLine > endLine {
-				startLine, endLine = endLine, startLine
+		return favoriteProjectLoadedMsg{project: project}
+	}
+}
+
+// projectInfoLoadedMsg carries a project fetched for the info popup
+type projectInfoLoadedMsg struct {
+	project *gitlab.Project
+	err     error
+}
+
+// loadProjectInfo fetches a project for the info popup without opening it
+func (m *MainScreen) loadProjectInfo(projectPath string) tea.Cmd {
+	if m.isDemo {
+		return nil
+	}
+	client := m.client
+	return func() tea.Msg {
+		project, err := client.GetProject(projectPath)
+		return projectInfoLoadedMsg{project: project, err: err}
+	}
+}
+
+// jobLogTickCmd returns a command that sends a tick after the configured interval
+func (m *MainScreen) jobLogTickCmd() tea.Cmd {
+	gen := m.jobLogGen
+	return tea.Tick(refreshInterval(m.jobLogRefresh, config.JobLogRefreshInterval), func(time.Time) tea.Msg {
+		return jobLogTickMsg{gen: gen}
+	})
+}
+
+// refreshJobLog fetches job log without resetting viewport position
+func (m *MainScreen) refreshJobLog() tea.Cmd {
+	if m.selectedProject == nil || m.isDemo || m.selectedJobIdx < 0 || m.selectedJobIdx >= len(m.jobs) {
+		return nil
+	}
+	job := m.jobs[m.selectedJobIdx]
+	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
+	// Running jobs only grow, so fetch just the bytes we don't have yet
+	offset := 0
+	if job.Status == "running" {
+		offset = len(m.jobLog)
+	}
+	return func() tea.Msg {
+		log, partial, err := m.client.GetJobLogFrom(projectID, job.ID, offset)
+		if err != nil {
+			// Silently ignore errors on auto-refresh
+			return nil
+		}
+		return jobLogRefreshedMsg{jobID: job.ID, log: log, offset: offset, partial: partial}
+	}
+}
+
+// refreshJobs fetches updated job statuses for the current pipeline
+func (m *MainScreen) refreshJobs() tea.Cmd {
+	if m.selectedProject == nil || m.isDemo || m.currentPipelineID == 0 {
+		return nil
+	}
+	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
+	pipelineID := m.currentPipelineID
+	return func() tea.Msg {
+		jobs, err := m.client.ListPipelineJobs(projectID, pipelineID)
+		if err != nil {
+			return nil
+		}
+		return jobsRefreshedMsg{jobs: jobs}
+	}
+}
+
+// Update handles messages, keeping the spinner ticking while loading
+func (m *MainScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	resume := m.noteInput(msg)
+	model, cmd := m.update(msg)
+	if resume != nil {
+		cmd = tea.Batch(cmd, resume)
+	}
+	if (m.loading || m.loadingMore) && !m.spinning {
+		m.spinning = true
+		cmd = tea.Batch(cmd, m.spinner.Tick)
+	}
+	if detail := m.loadSelectedPipelineDetail(); detail != nil {
+		cmd = tea.Batch(cmd, detail)
+	}
+	return model, cmd
+}
+
+// noteInput records key and mouse input, re-arming the tickers that stopped
+// while lazylab was idle
+func (m *MainScreen) noteInput(msg tea.Msg) tea.Cmd {
+	switch msg.(type) {
+	case tea.KeyMsg, tea.MouseMsg:
+	default:
+		return nil
+	}
+	m.lastInput = time.Now()
+	if !m.idle {
+		return nil
+	}
+	m.idle = false
+	resume := tea.Batch(m.idleStopped...)
+	m.idleStopped = nil
+	return resume
+}
+
+// idleStop drops a tick that arrived while idle, keeping its ticker to
+// re-arm once input arrives. The tick keeps its chain's generation, so one
+// superseded in the meantime is dropped rather than run alongside the new one.
+func (m *MainScreen) idleStop(tick tea.Cmd) (tea.Model, tea.Cmd) {
+	m.idleStopped = append(m.idleStopped, tick)
+	return m, nil
+}
+
+func (m *MainScreen) update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case components.ConfirmedMsg:
+		if msg.ID == confirmOverwriteDownload {
+			destPath := m.pendingDownload
+			m.pendingDownload = ""
+			if !msg.Yes {
+				m.statusMsg = "Download cancelled"
+				return m, nil
 			}
-			// Clamp to valid range
-			if startLine < 0 {
-				startLine = 0
+			return m, m.downloadTo(destPath)
+		}
+		if msg.ID == confirmExpandAllGroups {
+			if !msg.Yes {
+				m.statusMsg = "Expanded the loaded groups only"
+				return m, nil
 			}
-			if endLine >= len(lines) {
-				endLine = len(lines) - 1
+			return m, m.loadAllGroupProjects()
+		}
+		return m, nil
+
+	case spinner.TickMsg:
+		// Let the tick chain end once loading is done
+		if !m.loading && !m.loadingMore {
+			m.spinning = false
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case errMsg:
+		m.loading = false
+		m.lastError = msg.err.Error()
+		// Don't set m.errMsg - that would crash the UI
+		// Instead show error in status bar and allow retry
+		return m, nil
+
+	case groupsLoadedMsg:
+		// Ignore groups loaded before the filter was toggled
+		if msg.filter != m.groupFilter {
+			return m, nil
+		}
+		m.groups = msg.groups
+		m.loading = false
+		m.lastError = ""
+		m.rebuildNavTree()
+		// If no groups, load all projects directly
+		if len(m.groups) == 0 {
+			m.loading = true
+			m.loadingMsg = "Loading projects..."
+			cmd := m.loadAllProjects()
+			m.retryCmd = cmd
+			return m, cmd
+		}
+		return m, nil
+
+	case groupFilterNotFoundMsg:
+		if msg.filter != m.groupFilter {
+			return m, nil
+		}
+		m.statusMsg = "Group " + msg.filter + " not found, showing all groups"
+		return m, m.setGroupFilter("")
+
+	case groupProjectsLoadedMsg:
+		m.groupProjects[msg.groupID] = msg.projects
+		m.loading = false
+		m.lastError = ""
+		m.rebuildNavTree()
+		return m, m.fetchProjectCounts(msg.projects)
+
+	case projectCountsLoadedMsg:
+		if m.projectCounts == nil {
+			m.projectCounts = make(map[int]gitlab.ProjectCounts)
+		}
+		for id, c := range msg.counts {
+			m.projectCounts[id] = c
+		}
+		return m, nil
+
+	case allProjectsLoadedMsg:
+		// When no groups exist, show all projects as root nodes
+		for _, p := range msg.projects {
+			projectNode := TreeNode{
+				Type:     "project",
+				Name:     p.Name,
+				FullPath: p.PathWithNamespace,
+				ID:       p.ID,
+				Depth:    0,
+				Project:  &p,
 			}
-			selected := strings.Join(lines[startLine:endLine+1], "\n")
-			cleanLog := stripANSI(selected)
-			if err := copyToClipboard(cleanLog); err != nil {
-				m.statusMsg = "Copy failed: " + err.Error()
-			} else {
-				m.statusMsg = fmt.Sprintf("Copied %d lines!", endLine-startLine+1)
+			m.treeNodes = append(m.treeNodes, projectNode)
+		}
+		m.loading = false
+		m.lastError = ""
+		return m, m.fetchProjectCounts(msg.projects)
+
+	case defaultBranchMsg:
+		if m.selectedProject == nil || m.selectedProject.ID != msg.projectID {
+			return m, nil
+		}
+		// Cache it on the project so the next load goes straight to the tree
+		branch := "main"
+		if msg.project != nil {
+			branch = cmp.Or(msg.project.DefaultBranch, branch)
+			m.selectedProject.EmptyRepo = msg.project.EmptyRepo
+		}
+		m.selectedProject.DefaultBranch = branch
+		return m, m.loadProjectContent()
+
+	case savedRefMissingMsg:
+		if m.selectedProject == nil || m.selectedProject.ID != msg.projectID {
+			return m, nil
+		}
+		// Fall back to the default branch
+		delete(m.projectRefs, msg.projectID)
+		m.currentBranch = ""
+		m.statusMsg = fmt.Sprintf("Ref %s no longer exists, showing the default branch", msg.ref)
+		cmd := m.loadProjectContent()
+		m.retryCmd = cmd
+		return m, cmd
+
+	case projectContentMsg:
+		m.files = msg.entries
+		m.expandedDirs = nil
+		m.repoEmpty = msg.empty
+		m.readmeContent = msg.readme
+		m.readmeWidth = 0 // Rendered for the panel's width on the next draw
+		m.readmeHScroll = 0
+		m.fileContent = ""
+		m.selectedContent = 0
+		m.fileScrollOffset = 0
+		m.readmeReady = false // Reset to reinitialize viewport with new content
+		m.loading = false
+		m.lastError = ""
+		// Set current branch if not set
+		if m.currentBranch == "" && m.selectedProject != nil {
+			m.currentBranch = m.currentRef()
+		}
+		// Languages and statistics don't change per branch, so only fetch them once
+		m.restartLastCommits()
+		cmds := []tea.Cmd{m.loadLastCommits(msg.ref, msg.entries)}
+		if m.projectLanguages == nil {
+			cmds = append(cmds, m.loadLanguages())
+		}
+		if m.projectStats == nil {
+			cmds = append(cmds, m.loadStatistics())
+		}
+		return m, tea.Batch(cmds...)
+
+	case statisticsLoadedMsg:
+		// Best effort - the header simply omits the size on failure
+		if msg.err == nil && m.selectedProject != nil && msg.projectID == m.selectedProject.ID {
+			m.projectStats = msg.stats
+		}
+		return m, nil
+
+	case languagesLoadedMsg:
+		// Best effort - the header simply omits the language on failure
+		if msg.err == nil && m.selectedProject != nil && msg.projectID == m.selectedProject.ID {
+			m.projectLanguages = msg.languages
+		}
+		return m, nil
+
+	case treeLoadedMsg:
+		m.files = msg.entries
+		m.expandedDirs = nil
+		m.selectedContent = 0
+		m.fileScrollOffset = 0
+		m.fileContent = ""
+		m.loading = false
+		m.lastError = ""
+		m.restartLastCommits()
+		return m, m.loadLastCommits(msg.ref, msg.entries)
+
+	case dirExpandedMsg:
+		// Ignore directories of another project or branch, or no longer listed
+		if m.selectedProject == nil || msg.projectID != m.selectedProject.ID || msg.ref != m.currentRef() || m.expandedDirs[msg.path] {
+			return m, nil
+		}
+		i := slices.IndexFunc(m.files, func(e gitlab.TreeEntry) bool { return e.Type == "tree" && e.Path == msg.path })
+		if i < 0 {
+			return m, nil
+		}
+		m.files = slices.Insert(m.files, i+1, msg.entries...)
+		if m.expandedDirs == nil {
+			m.expandedDirs = make(map[string]bool)
+		}
+		m.expandedDirs[msg.path] = true
+		if m.selectedContent > i {
+			m.selectedContent += len(msg.entries)
+		}
+		m.adjustScrollOffset()
+		return m, m.loadLastCommits(msg.ref, msg.entries)
+
+	case lastCommitMsg:
+		// Ignore commits for another project or branch; entries of a
+		// directory that's no longer listed simply don't match
+		if m.selectedProject == nil || msg.projectID != m.selectedProject.ID || msg.ref != m.currentRef() {
+			return m, nil
+		}
+		for i := range m.files {
+			if m.files[i].Path == msg.path {
+				m.files[i].LastCommit = msg.commit
 			}
-			m.visualLineMode = false
-		} else if m.jobLogLastKey == "gg" {
-			// ggy - yank entire log
-			cleanLog := stripANSI(m.jobLog)
-			if err := copyToClipboard(cleanLog); err != nil {
-				m.statusMsg = "Copy failed: " + err.Error()
-			} else {
-				m.statusMsg = fmt.Sprintf("Yanked all %d lines!", len(lines))
+		}
+		return m, nil
+
+	case fileAtCommitMissingMsg:
+		m.statusMsg = fmt.Sprintf("%s didn't exist at %s", msg.path, shortSHA(msg.sha))
+		return m, nil
+
+	case fileContentMsg:
+		if msg.projectID != 0 && (m.selectedProject == nil || m.selectedProject.ID != msg.projectID) {
+			return m, nil
+		}
+		m.fileImage = msg.image
+		content := decodeBOM(msg.content)
+		m.fileSaveRaw = true
+		// Check for binary content
+		if msg.image != nil {
+			size := msg.image.Bounds().Size()
+			m.fileContent = fmt.Sprintf("[image %dx%d]", size.X, size.Y)
+			m.fileIsMarkdown = false
+		} else if isBinaryExtension(msg.path) || isBinaryContent(content) {
+			m.fileContent = binaryFileNote
+			m.fileIsMarkdown = false
+		} else if !utf8.ValidString(content) {
+			// Latin-1 and friends would render as mojibake
+			m.fileContent = nonUTF8Note
+			m.fileIsMarkdown = false
+		} else {
+			m.fileContent = content
+			m.fileIsMarkdown = isMarkdownFile(msg.path)
+			m.fileSaveRaw = msg.truncated || content != msg.content
+		}
+		m.fileShowRaw = false
+		m.viewingFile = true
+		m.viewingFilePath = msg.path
+		m.viewingFileRef = msg.ref
+		if msg.ref != "" {
+			// Opened from the last commit diff
+			m.showCommitDiffPopup = false
+			m.statusMsg = ""
+		}
+		m.viewingSnippet = msg.snippet
+		m.viewingWiki = msg.wiki
+		m.fileTruncated = msg.truncated
+		m.fileViewReady = false // Reset to reinitialize viewport with new content
+		m.fileFlashLine = 0
+		m.fileJumpPending = false
+		m.loading = false
+		m.lastError = ""
+		if msg.line > 0 {
+			// Jump is applied once the viewport is created in View, in the
+			// source of a markdown file as the anchor counts source lines
+			m.fileShowRaw = m.fileIsMarkdown
+			m.fileFlashLine = msg.line
+			m.fileJumpPending = true
+			m.fileFlashSeq++
+			return m, fileFlashCmd(m.fileFlashSeq)
+		}
+		return m, nil
+
+	case uiTickMsg:
+		// Returning is enough to re-render; the tick doubles as the idle watchdog
+		if m.idleTimeout > 0 && !m.idle && time.Since(m.lastInput) >= m.idleTimeout {
+			m.idle = true
+		}
+		return m, uiTickCmd()
+
+	case fileFlashDoneMsg:
+		if msg.seq == m.fileFlashSeq && m.fileFlashLine > 0 {
+			m.fileFlashLine = 0
+			if m.fileViewReady {
+				m.fileViewport.SetContent(m.fileViewContent())
 			}
-		} else if m.jobLogLastKey == "y" {
-			// yy - yank current line
-			if m.jobLogCursor >= 0 && m.jobLogCursor < len(lines) {
-				cleanLine := stripANSI(lines[m.jobLogCursor])
-				if err := copyToClipboard(cleanLine); err != nil {
-					m.statusMsg = "Copy failed: " + err.Error()
-				} else {
-					m.statusMsg = "Yanked line!"
+		}
+		return m, nil
+
+	case mrsLoadedMsg:
+		m.mergeRequests = msg.mrs
+		m.mrNextPage = msg.nextPage
+		m.loadingMore = false
+		m.selectedContent = 0
+		m.fileScrollOffset = 0
+		m.loading = false
+		m.lastError = ""
+		return m, m.loadMRStatuses(msg.mrs)
+
+	case moreMRsLoadedMsg:
+		// Ignore pages of another project or an earlier load
+		if m.selectedProject == nil || m.selectedProject.ID != msg.projectID || msg.page != m.mrNextPage {
+			return m, nil
+		}
+		m.loadingMore = false
+		if msg.err != nil {
+			m.statusMsg = "Loading more merge requests failed: " + msg.err.Error()
+			return m, nil
+		}
+		m.mergeRequests = appendNewMRs(m.mergeRequests, msg.mrs)
+		m.mrNextPage = msg.nextPage
+		return m, m.loadMRStatuses(msg.mrs)
+
+	case failureJobsLoadedMsg:
+		// Ignore jobs from a project that's no longer selected
+		if m.selectedProject == nil || m.selectedProject.ID != msg.projectID {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.statusMsg = "Loading jobs failed: " + msg.err.Error()
+			return m, nil
+		}
+		if m.pipelineJobs == nil {
+			m.pipelineJobs = make(map[int][]gitlab.Job)
+		}
+		m.pipelineJobs[msg.pipeline.ID] = msg.jobs
+		m.copyFailureSummary(pipelineFailureSummary(msg.pipeline, msg.jobs))
+		return m, nil
+
+	case mrBranchCheckedMsg:
+		// Ignore lookups for an MR that's no longer open
+		if !m.showMRDetailPopup || m.mrDetail == nil || m.mrDetail.IID != msg.iid ||
+			m.selectedProject == nil || m.selectedProject.ID != msg.projectID {
+			return m, nil
+		}
+		if errors.Is(msg.err, gitlab.ErrNotFound) {
+			m.statusMsg = "Source branch " + msg.branch + " no longer exists"
+			return m, nil
+		}
+		if msg.err != nil {
+			m.statusMsg = "Branch lookup failed: " + msg.err.Error()
+			return m, nil
+		}
+		return m, m.browseMRBranch(msg.branch)
+
+	case reviewAppLoadedMsg:
+		// Ignore lookups for an MR that's no longer open
+		if !m.showMRDetailPopup || m.mrDetail == nil || m.mrDetail.IID != msg.iid ||
+			m.selectedProject == nil || m.selectedProject.ID != msg.projectID {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.statusMsg = "Review app lookup failed: " + msg.err.Error()
+			return m, nil
+		}
+		m.copyReviewApp(msg.branch, msg.url)
+		return m, nil
+
+	case mrStatusesLoadedMsg:
+		// Ignore results for a project that's no longer selected
+		if m.selectedProject == nil || m.selectedProject.ID != msg.projectID {
+			return m, nil
+		}
+		for i := range m.mergeRequests {
+			if p, ok := msg.pipelines[m.mergeRequests[i].IID]; ok {
+				m.mergeRequests[i].HeadPipeline = p
+			}
+		}
+		// Later pages add to the approvals already loaded
+		if m.mrApprovals == nil {
+			m.mrApprovals = make(map[int]*gitlab.MRApprovals)
+		}
+		maps.Copy(m.mrApprovals, msg.approvals)
+		return m, nil
+
+	case pipelinesLoadedMsg:
+		m.pipelines = msg.pipelines
+		m.pipelineNextPage = msg.nextPage
+		m.loadingMore = false
+		m.selectedContent = 0
+		m.fileScrollOffset = 0
+		m.pipelineJobs = make(map[int][]gitlab.Job)
+		m.pipelineDetails = make(map[int]pipelineDetail)
+		m.loading = false
+		m.lastError = ""
+		// Load jobs for each pipeline to show stages
+		var cmds []tea.Cmd
+		for _, p := range m.pipelines {
+			cmds = append(cmds, m.loadPipelineJobsForList(p.ID))
+		}
+		// Start auto-refresh ticker, retiring any chain already running
+		m.pipelineGen++
+		cmds = append(cmds, m.pipelineTickCmd())
+		return m, tea.Batch(cmds...)
+
+	case morePipelinesLoadedMsg:
+		// Ignore pages of another project or an earlier load
+		if m.selectedProject == nil || m.selectedProject.ID != msg.projectID || msg.page != m.pipelineNextPage {
+			return m, nil
+		}
+		m.loadingMore = false
+		if msg.err != nil {
+			m.statusMsg = "Loading more pipelines failed: " + msg.err.Error()
+			return m, nil
+		}
+		selectedPipelineID := m.selectedPipelineID()
+		m.pipelines = appendNewPipelines(m.pipelines, msg.pipelines)
+		m.pipelineNextPage = msg.nextPage
+		m.restorePipelineSelection(selectedPipelineID)
+		var cmds []tea.Cmd
+		for _, p := range msg.pipelines {
+			cmds = append(cmds, m.loadPipelineJobsForList(p.ID))
+		}
+		return m, tea.Batch(cmds...)
+
+	case releasesLoadedMsg:
+		m.releases = msg.releases
+		m.selectedContent = 0
+		m.fileScrollOffset = 0
+		m.loading = false
+		m.lastError = ""
+		return m, nil
+
+	case releaseLoadedMsg:
+		if m.selectedProject == nil || m.selectedProject.ID != msg.projectID {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.statusMsg = "Couldn't load the release for tag " + msg.tagName + ": " + msg.err.Error()
+			return m, nil
+		}
+		m.statusMsg = ""
+		m.releases = append(m.releases, *msg.release)
+		m.selectedReleaseIdx = len(m.releases) - 1
+		m.releaseAssetCursor = 0
+		m.releaseScrollOffset = 0
+		m.showReleasePopup = true
+		return m, nil
+
+	case tagsLoadedMsg:
+		m.tags = msg.tags
+		m.selectedContent = 0
+		m.fileScrollOffset = 0
+		m.loading = false
+		m.lastError = ""
+		return m, nil
+
+	case editorFileReadyMsg:
+		return m, editTempFile(msg.path)
+
+	case editorFinishedMsg:
+		if msg.err != nil {
+			m.statusMsg = "Editor failed: " + msg.err.Error()
+		}
+		return m, nil
+
+	case downloadProgressMsg:
+		if m.downloadUpdates == nil {
+			return m, nil
+		}
+		m.loadingMsg = downloadProgressText(msg.filename, msg.bytesSoFar, msg.total)
+		return m, waitForDownload(m.downloadUpdates)
+
+	case downloadCompleteMsg:
+		m.loading = false
+		m.downloadCancel = nil
+		m.downloadUpdates = nil
+		if errors.Is(msg.err, context.Canceled) {
+			m.statusMsg = "Download of " + msg.filename + " cancelled"
+		} else if msg.err != nil {
+			m.statusMsg = "Download failed: " + msg.err.Error()
+		} else {
+			m.statusMsg = fmt.Sprintf("Downloaded %s (%d bytes)", msg.filename, msg.bytes)
+			switch {
+			case msg.checksumErr != nil:
+				m.statusMsg += ", checksum not verified: " + msg.checksumErr.Error()
+			case msg.checksum == "":
+			case strings.EqualFold(msg.checksum, msg.digest):
+				m.statusMsg += ", SHA-256 verified"
+			default:
+				m.statusMsg += fmt.Sprintf(", SHA-256 MISMATCH: got %.12s, expected %.12s", msg.digest, msg.checksum)
+			}
+		}
+		return m, nil
+
+	case pipelinesRefreshedMsg:
+		// A refresh from a retired chain, e.g. of another project
+		if msg.gen != m.pipelineGen {
+			return m, nil
+		}
+		// Preserve selection when auto-refreshing
+		selectedPipelineID := m.selectedPipelineID()
+		// The refresh only covers the first page; keep older pages loaded since
+		m.pipelines = appendNewPipelines(msg.pipelines, m.pipelines)
+		// Restore selection by finding the same pipeline ID in the filtered/sorted list
+		m.restorePipelineSelection(selectedPipelineID)
+		// Refresh jobs for the refreshed pipelines
+		var cmds []tea.Cmd
+		for _, p := range msg.pipelines {
+			cmds = append(cmds, m.loadPipelineJobsForList(p.ID))
+		}
+		// Continue ticker
+		cmds = append(cmds, m.pipelineTickCmd())
+		return m, tea.Batch(cmds...)
+
+	case pipelineTickMsg:
+		if msg.gen != m.pipelineGen {
+			return m, nil
+		}
+		if m.idle && m.selectedProject != nil {
+			return m.idleStop(m.pipelineTickCmd())
+		}
+		// Only refresh if we're viewing pipelines tab and have a project
+		if m.contentTab == TabPipelines && m.selectedProject != nil && !m.loading && !m.refreshPaused {
+			return m, m.refreshPipelines()
+		}
+		// Keep ticker running even if we're not on pipelines tab
+		if m.selectedProject != nil {
+			return m, m.pipelineTickCmd()
+		}
+		return m, nil
+
+	case pipelineJobsLoadedMsg:
+		if m.pipelineJobs == nil {
+			m.pipelineJobs = make(map[int][]gitlab.Job)
+		}
+		m.pipelineJobs[msg.pipelineID] = msg.jobs
+		return m, nil
+
+	case pipelineDetailLoadedMsg:
+		// Ignore details from a project that's no longer selected
+		if m.selectedProject == nil || m.selectedProject.ID != msg.projectID || m.pipelineDetails == nil {
+			return m, nil
+		}
+		m.pipelineDetails[msg.pipeline.ID] = pipelineDetail{listStatus: msg.listStatus, pipeline: msg.pipeline}
+		return m, nil
+
+	case branchesLoadedMsg:
+		m.branches = msg.branches
+		m.branchDivergence = nil
+		m.selectedContent = 0
+		m.fileScrollOffset = 0
+		m.loading = false
+		m.lastError = ""
+		// If branch popup is open, keep it open
+		if m.showBranchPopup {
+			// Find current branch in list
+			for i, br := range m.branches {
+				if br.Name == m.currentBranch {
+					m.selectedBranchIdx = i
+					break
+				}
+			}
+		}
+		return m, nil
+
+	case jobsLoadedMsg:
+		m.jobs = msg.jobs
+		m.selectedJobIdx = 0
+		m.jobStageFilter = ""
+		m.jobLog = ""
+		m.jobLogReady = false
+		m.loading = false
+		m.lastError = ""
+		// Auto-load first job's log if available
+		if len(m.jobs) > 0 {
+			m.loading = true
+			m.loadingMsg = "Loading job log..."
+			cmd := m.loadJobLog(m.jobs[0].ID)
+			m.retryCmd = cmd
+			return m, cmd
+		}
+		return m, nil
+
+	case jobLogLoadedMsg:
+		m.jobLog = msg.log
+		m.jobLogReady = false
+		m.loading = false
+		m.lastError = ""
+		// Start at bottom where errors usually are
+		m.jobLogCursor = strings.Count(msg.log, "\n")
+		m.updateJobLogMatches()
+		// Start auto-refresh for live log viewing, retiring any chain already running
+		m.jobLogGen++
+		return m, m.jobLogTickCmd()
+
+	case jobLogTickMsg:
+		if msg.gen != m.jobLogGen {
+			return m, nil
+		}
+		if m.idle && m.showJobLogPopup {
+			return m.idleStop(m.jobLogTickCmd())
+		}
+		// Only refresh if job popup is still open
+		if m.showJobLogPopup && m.refreshPaused {
+			return m, m.jobLogTickCmd()
+		}
+		if m.showJobLogPopup {
+			// Refresh both jobs (for status updates) and log, then schedule next tick
+			return m, tea.Batch(m.refreshJobs(), m.refreshJobLog(), m.jobLogTickCmd())
+		}
+		return m, nil
+
+	case jobsRefreshedMsg:
+		if m.showJobLogPopup && msg.jobs != nil {
+			// Preserve selection by job ID
+			selectedJobID := 0
+			if m.selectedJobIdx >= 0 && m.selectedJobIdx < len(m.jobs) {
+				selectedJobID = m.jobs[m.selectedJobIdx].ID
+			}
+			m.jobs = msg.jobs
+			// Restore selection
+			if selectedJobID != 0 {
+				for i, job := range m.jobs {
+					if job.ID == selectedJobID {
+						m.selectedJobIdx = i
+						break
+					}
 				}
 			}
-		} else {
-			// First y - wait for second key
-			m.jobLogLastKey = "y"
-			return m, nil
 		}
-		m.jobLogLastKey = ""
-	case "0":
-		// Go to start of line
-		if m.jobLogFocused {
-			m.jobLogHScroll = 0
+		return m, nil
+
+	case jobLogRefreshedMsg:
+		// Ignore refreshes for a job that is no longer selected
+		if m.selectedJobIdx >= len(m.jobs) || m.jobs[m.selectedJobIdx].ID != msg.jobID {
+			return m, nil
+		}
+		// Appends only apply to the log they were requested against
+		if msg.partial && msg.offset != len(m.jobLog) {
+			return m, nil
+		}
+		if msg.log != "" && m.showJobLogPopup {
+			// Save current scroll position
+			currentLine := m.jobLogViewport.YOffset
+			wasAtBottom := m.jobLogViewport.ScrollPercent() >= 0.99
+			cursorAtEnd := m.jobLogCursor == strings.Count(m.jobLog, "\n")
+
+			// Update log content
+			if msg.partial {
+				m.jobLog += msg.log
+			} else {
+				m.jobLog = msg.log
+			}
+			m.updateJobLogMatches()
+			// Keep following the tail with the cursor
+			if cursorAtEnd {
+				m.jobLogCursor = strings.Count(m.jobLog, "\n")
+			}
+
+			// Update viewport content directly without recreating it
+			m.setJobLogContent()
+
+			// Auto-scroll to bottom when not focused on log panel, or was already at bottom
+			if !m.jobLogFocused || wasAtBottom {
+				m.jobLogViewport.GotoBottom()
+			} else {
+				m.jobLogViewport.SetYOffset(currentLine)
+			}
+		}
+		return m, nil
+
+	case runnersLoadedMsg:
+		// A newer load is on its way and will keep the chain going
+		if msg.gen != m.runnersGen {
+			return m, nil
+		}
+		m.runnersLoading = false
+		m.runnersNote = msg.note
+		if msg.err != nil {
+			// Keep the last jobs shown; a refresh may just have failed
+			m.runnersErr = msg.err.Error()
+			if errors.Is(msg.err, gitlab.ErrUnauthorized) {
+				m.runnersErr = "Listing jobs across projects needs more access; select a project to see its jobs"
+			}
+		} else {
+			m.runnersErr = ""
+			m.runningJobs = msg.running
+			m.pendingJobs = msg.pending
+		}
+		if m.showRunnersPopup {
+			return m, m.runnersTickCmd()
+		}
+		return m, nil
+
+	case runnersTickMsg:
+		if msg.gen != m.runnersGen {
+			return m, nil
+		}
+		if m.idle && m.showRunnersPopup {
+			return m.idleStop(m.runnersTickCmd())
+		}
+		if m.showRunnersPopup && m.refreshPaused {
+			return m, m.runnersTickCmd()
+		}
+		if m.showRunnersPopup {
+			return m, m.loadAllJobs()
+		}
+		return m, nil
+
+	case currentUserLoadedMsg:
+		// Best effort - the status bar simply omits the user on failure
+		if msg.err == nil {
+			m.currentUser = msg.user
+			m.todosCount = msg.todos
+		}
+		return m, nil
+
+	case myMRsLoadedMsg:
+		// A newer load is on its way and will keep the chain going
+		if msg.gen != m.myMRsGen {
+			return m, nil
+		}
+		m.myMRsLoading = false
+		if msg.err != nil {
+			m.myMRsErr = msg.err.Error()
+		} else {
+			m.myMRsErr = ""
+			m.myAssignedMRs = msg.assigned
+			m.myReviewMRs = msg.review
+		}
+		// Clamp cursor in case the list shrank
+		if n := len(m.currentMyMRs()); m.myMRsCursor >= n {
+			m.myMRsCursor = max(n-1, 0)
+		}
+		if m.showMyMRsPopup {
+			return m, m.myMRsTickCmd()
+		}
+		return m, nil
+
+	case environmentsLoadedMsg:
+		m.envLoading = false
+		if msg.err != nil {
+			m.envErr = msg.err.Error()
+			return m, nil
+		}
+		m.envErr = ""
+		m.environments = msg.environments
+		return m, nil
+
+	case divergenceLoadedMsg:
+		// Ignore results for a project that's no longer selected
+		if m.selectedProject == nil || m.selectedProject.ID != msg.projectID {
+			return m, nil
+		}
+		if m.divergenceLoading == msg.branch {
+			m.divergenceLoading = ""
+		}
+		if msg.err != nil {
+			m.statusMsg = "Ahead/behind failed: " + msg.err.Error()
+			return m, nil
+		}
+		if m.branchDivergence == nil {
+			m.branchDivergence = make(map[string]aheadBehind)
+		}
+		m.branchDivergence[msg.branch] = msg.counts
+		return m, nil
+
+	case comparisonLoadedMsg:
+		// Ignore results for a comparison that's no longer shown
+		if msg.from != m.compareFrom || msg.to != m.compareTo {
+			return m, nil
+		}
+		m.compareLoading = false
+		m.compareReady = false
+		if msg.err != nil {
+			m.compareErr = msg.err.Error()
+			return m, nil
+		}
+		m.compareErr = ""
+		m.comparison = msg.comparison
+		return m, nil
+
+	case commitDiffLoadedMsg:
+		// Ignore results for a path that's no longer shown
+		if msg.path != m.commitDiffPath {
+			return m, nil
+		}
+		m.commitDiffLoading = false
+		m.commitDiffReady = false
+		if msg.err != nil {
+			m.commitDiffErr = msg.err.Error()
+			return m, nil
+		}
+		m.commitDiffErr = ""
+		m.commitDiffCommit = msg.commit
+		m.commitDiffs = msg.diffs
+		// Keep a commit fetched on demand for the file list
+		for i := range m.files {
+			if m.files[i].Path == msg.path && m.files[i].LastCommit == nil {
+				m.files[i].LastCommit = msg.commit
+			}
+		}
+		return m, nil
+
+	case snippetsLoadedMsg:
+		m.snippetsLoading = false
+		if msg.err != nil {
+			m.snippetsErr = "Error: " + msg.err.Error()
+			return m, nil
+		}
+		m.snippetsErr = ""
+		m.snippets = msg.snippets
+		if m.snippetsCursor >= len(m.snippets) {
+			m.snippetsCursor = max(len(m.snippets)-1, 0)
+		}
+		return m, nil
+
+	case permalinkResolvedMsg:
+		if m.selectedProject == nil {
+			return m, nil
+		}
+		if msg.err != nil || msg.sha == "" {
+			m.copyPermalink(blobPermalink(m.selectedProject.WebURL, msg.ref, msg.filePath), false)
+			return m, nil
+		}
+		m.copyPermalink(blobPermalink(m.selectedProject.WebURL, msg.sha, msg.filePath), true)
+		return m, nil
+
+	case contributorsLoadedMsg:
+		m.contributorsLoading = false
+		if msg.err != nil {
+			m.contributorsErr = "Error: " + msg.err.Error()
+			return m, nil
+		}
+		m.contributorsErr = ""
+		m.contributors = sortContributors(msg.contributors)
+		if m.contributorsCursor >= len(m.contributors) {
+			m.contributorsCursor = max(len(m.contributors)-1, 0)
+		}
+		return m, nil
+
+	case codeSearchMsg:
+		// Ignore results for an older query or another project
+		if m.selectedProject == nil || m.selectedProject.ID != msg.projectID || msg.query != m.codeSearchQuery {
+			return m, nil
+		}
+		m.codeSearchLoading = false
+		if msg.err != nil {
+			m.codeSearchErr = codeSearchError(msg.err)
+			return m, nil
+		}
+		m.codeSearchErr = ""
+		m.codeSearchResults = msg.blobs
+		m.codeSearchCursor = 0
+		return m, nil
+
+	case activityLoadedMsg:
+		m.activityLoading = false
+		if msg.err != nil {
+			m.activityErr = "Error: " + msg.err.Error()
+			return m, nil
+		}
+		m.activityErr = ""
+		m.events = sortEvents(msg.events)
+		if m.activityCursor >= len(m.events) {
+			m.activityCursor = max(len(m.events)-1, 0)
+		}
+		return m, nil
+
+	case labelsLoadedMsg:
+		m.labelsLoading = false
+		if msg.err != nil {
+			m.labelsErr = "Error: " + msg.err.Error()
+			return m, nil
+		}
+		m.labelsErr = ""
+		m.labels = sortLabels(msg.labels)
+		m.milestones = sortMilestones(msg.milestones)
+		if m.labelsCursor >= m.labelsCount() {
+			m.labelsCursor = max(m.labelsCount()-1, 0)
+		}
+		return m, nil
+
+	case wikiPagesLoadedMsg:
+		// Ignore pages of a project that's no longer selected
+		if m.selectedProject == nil || m.selectedProject.ID != msg.projectID {
+			return m, nil
+		}
+		m.wikiLoading = false
+		if errors.Is(msg.err, gitlab.ErrNotFound) {
+			m.wikiErr = "The wiki is disabled for this project"
+			return m, nil
+		}
+		if msg.err != nil {
+			m.wikiErr = "Error: " + msg.err.Error()
+			return m, nil
+		}
+		m.wikiErr = ""
+		m.wikiPages = sortWikiPages(msg.pages)
+		if m.wikiCursor >= len(m.wikiPages) {
+			m.wikiCursor = max(len(m.wikiPages)-1, 0)
+		}
+		return m, nil
+
+	case variablesLoadedMsg:
+		m.varsLoading = false
+		if errors.Is(msg.err, gitlab.ErrUnauthorized) {
+			// Listing variables needs maintainer access
+			m.varsErr = "Insufficient permissions: maintainer access is required to view CI/CD variables"
+			return m, nil
+		}
+		if msg.err != nil {
+			m.varsErr = "Error: " + msg.err.Error()
+			return m, nil
+		}
+		m.varsErr = ""
+		m.variables = msg.variables
+		if m.varsCursor >= len(m.variables) {
+			m.varsCursor = max(len(m.variables)-1, 0)
+		}
+		return m, nil
+
+	case deploymentsLoadedMsg:
+		m.envLoading = false
+		// Ignore results for an environment that's no longer shown
+		if msg.environment != m.envSelected {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.envErr = msg.err.Error()
+			return m, nil
+		}
+		m.envErr = ""
+		m.envDeployments = msg.deployments
+		return m, nil
+
+	case myMRsTickMsg:
+		if msg.gen != m.myMRsGen {
+			return m, nil
+		}
+		if m.idle && m.showMyMRsPopup {
+			return m.idleStop(m.myMRsTickCmd())
+		}
+		if m.showMyMRsPopup && m.refreshPaused {
+			return m, m.myMRsTickCmd()
+		}
+		if m.showMyMRsPopup {
+			return m, m.loadMyMRs()
+		}
+		return m, nil
+
+	case deepLinkLoadedMsg:
+		if msg.err != nil {
+			m.statusMsg = "Couldn't open " + msg.link.String() + ": " + msg.err.Error()
+			return m, nil
+		}
+		// Don't override a project picked while this was loading
+		if m.selectedProject != nil {
+			return m, nil
+		}
+		return m, m.openDeepLink(msg.project, msg.link)
+
+	case lastProjectLoadedMsg:
+		if msg.err != nil {
+			// Stay on the group list; a deleted project is forgotten for good
+			if errors.Is(msg.err, gitlab.ErrNotFound) {
+				m.forgetLastProject()
+			}
+			return m, nil
+		}
+		// Don't override a project picked while this was loading
+		if m.selectedProject != nil {
+			return m, nil
+		}
+		m.selectProject(msg.project)
+		if msg.tab != TabFiles {
+			return m, m.switchTab(msg.tab)
+		}
+		m.loading = true
+		m.loadingMsg = "Loading repository..."
+		cmd := m.loadProjectContent()
+		m.retryCmd = cmd
+		return m, cmd
+
+	case favoriteProjectLoadedMsg:
+		m.selectProject(msg.project)
+		m.loadingMsg = "Loading repository..."
+		cmd := m.loadProjectContent()
+		m.retryCmd = cmd
+		return m, cmd
+
+	case projectInfoLoadedMsg:
+		if msg.err != nil {
+			m.statusMsg = "Couldn't load the project: " + msg.err.Error()
+			return m, nil
+		}
+		m.statusMsg = ""
+		// Something else took over the screen while it loaded
+		if m.popupOpen() {
+			return m, nil
+		}
+		m.infoProject = msg.project
+		m.showProjectInfo = true
+		return m, nil
+
+	case recentProjectLoadedMsg:
+		m.selectProject(msg.project)
+		m.loadingMsg = "Loading repository..."
+		cmd := m.loadProjectContent()
+		m.retryCmd = cmd
+		return m, cmd
+
+	case mrProjectLoadedMsg:
+		m.selectProject(msg.project)
+		m.openMRDetail(msg.mr)
+		if !m.tabVisible(TabMRs) {
+			// Show the MR over the files when its tab is hidden
+			m.loading = true
+			m.loadingMsg = "Loading repository..."
+			cmd := m.loadProjectContent()
+			m.retryCmd = cmd
+			return m, cmd
+		}
+		m.contentTab = TabMRs
+		m.loading = true
+		m.loadingMsg = "Loading merge requests..."
+		cmd := m.loadMRs()
+		m.retryCmd = cmd
+		return m, cmd
+
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
+	}
+
+	return m, nil
+}
+
+// focusedMarkdownLink returns a markdown link to the project, merge request,
+// pipeline or release under the cursor, or "" if there is none
+func (m *MainScreen) focusedMarkdownLink() string {
+	mrLink := func(mr gitlab.MergeRequest) string {
+		return markdownLink(fmt.Sprintf("!%d %s", mr.IID, mr.Title), mr.WebURL)
+	}
+	releaseLink := func(rel gitlab.Release) string {
+		url := rel.Links.Self
+		if url == "" && m.selectedProject != nil {
+			url = m.selectedProject.WebURL + "/-/releases/" + rel.TagName
+		}
+		return markdownLink(rel.TagName, url)
+	}
+	projectLink := func(p *gitlab.Project) string {
+		if p == nil || p.WebURL == "" {
+			return ""
+		}
+		return markdownLink(p.Name, p.WebURL)
+	}
+
+	switch {
+	case m.showMRDetailPopup:
+		if m.mrDetail != nil {
+			return mrLink(*m.mrDetail)
+		}
+		return ""
+	case m.showMyMRsPopup:
+		if mrs := m.currentMyMRs(); m.myMRsCursor < len(mrs) {
+			return mrLink(mrs[m.myMRsCursor])
+		}
+		return ""
+	case m.showReleasePopup:
+		if m.selectedReleaseIdx < len(m.releases) {
+			return releaseLink(m.releases[m.selectedReleaseIdx])
+		}
+		return ""
+	case m.popupOpen():
+		return ""
+	}
+
+	if m.focusedPanel == PanelNavigator {
+		if m.selectedNodeIdx < len(m.treeNodes) {
+			return projectLink(m.treeNodes[m.selectedNodeIdx].Project)
+		}
+		return ""
+	}
+	if m.focusedPanel == PanelContent && m.selectedProject != nil {
+		switch m.contentTab {
+		case TabMRs:
+			if mrs := m.visibleMRs(); m.selectedContent < len(mrs) {
+				return mrLink(mrs[m.selectedContent])
+			}
+		case TabPipelines:
+			if pipelines := m.visiblePipelines(); m.selectedContent < len(pipelines) {
+				p := pipelines[m.selectedContent]
+				return markdownLink(fmt.Sprintf("#%d %s", p.IID, p.Ref), p.WebURL)
+			}
+		case TabReleases:
+			if !m.showTags && m.selectedContent < len(m.releases) {
+				return releaseLink(m.releases[m.selectedContent])
+			}
+		}
+	}
+	// Files, README and empty lists link the project itself
+	return projectLink(m.selectedProject)
+}
+
+// popupOpen reports whether a popup is covering the panels.
+func (m *MainScreen) popupOpen() bool {
+	return m.showJobLogPopup || m.showBranchPopup || m.showRunnersPopup ||
+		m.showHelpPopup || m.showMRDetailPopup || m.showEnvPopup ||
+		m.showVarsPopup || m.showSnippetsPopup || m.showWikiPopup || m.showContributorsPopup || m.showLabelsPopup || m.showActivityPopup || m.showCodeSearch || m.showRecentPopup || m.showComparePopup || m.showCommitDiffPopup || m.showProjectInfo || m.showMyMRsPopup ||
+		m.showReleasePopup || m.showFolderBrowser || m.confirm.Active
+}
+
+// promptOpen reports whether a text prompt is taking the typed keys.
+func (m *MainScreen) promptOpen() bool {
+	return m.mrFilterActive || m.navFilterActive || m.lineJumpActive || m.refJumpActive
+}
+
+// handleMouse maps clicks and wheel events onto the main layout. Popups and
+// text prompts stay keyboard only.
+func (m *MainScreen) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.width == 0 || m.height == 0 || m.popupOpen() || m.promptOpen() {
+		return m, nil
+	}
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		m.scrollFocused(-1)
+	case tea.MouseButtonWheelDown:
+		m.scrollFocused(1)
+	case tea.MouseButtonLeft:
+		if msg.Action == tea.MouseActionPress {
+			m.handleClick(msg.X, msg.Y)
+		}
+	}
+	return m, nil
+}
+
+// handleClick focuses the panel under (x, y) and selects the row there, using
+// the same dimensions as View.
+func (m *MainScreen) handleClick(x, y int) {
+	contentHeight := m.height - config.StatusBarHeight
+	navWidth := m.navigatorWidth()
+	if y >= contentHeight {
+		return
+	}
+
+	// Row inside the panel, below the top border
+	row := y - 1
+
+	if x < navWidth {
+		m.focusedPanel = PanelNavigator
+		row -= m.navHeaderLines()
+		visibleLines, scrollOffset := m.navScrollWindow(contentHeight)
+		if idx := scrollOffset + row; row >= 0 && row < visibleLines && idx < len(m.treeNodes) {
+			m.selectedNodeIdx = idx
+		}
+		return
+	}
+
+	// Same split as renderContentPanel
+	listHeight := m.contentListHeight(contentHeight)
+	if listHeight < contentHeight && y >= listHeight {
+		m.focusedPanel = PanelReadme
+		return
+	}
+
+	m.focusedPanel = PanelContent
+	if m.selectedProject == nil || m.loading || m.viewingFile {
+		return
+	}
+	row -= m.listHeaderLines()
+	visibleLines := listHeight - 6 - m.mrFilterLines() // as in renderListSection
+	if row < 0 || row >= visibleLines {
+		return
+	}
+	if m.contentTab == TabPipelines {
+		// Expanded pipelines take several rows
+		if rows := m.pipelineRows(m.visiblePipelines()); m.pipelineRowOffset+row < len(rows) {
+			m.selectedContent = rows[m.pipelineRowOffset+row].pipeline
+		}
+		return
+	}
+	if idx := m.fileScrollOffset + row; idx < m.getContentCount() {
+		m.selectedContent = idx
+	}
+}
+
+// listHeaderLines counts the lines renderListSection draws above the first
+// list row.
+func (m *MainScreen) listHeaderLines() int {
+	lines := 2 // tab bar and the blank line below it
+	if m.selectedProject != nil {
+		lines++
+		for _, b := range m.branches {
+			if b.Name == m.currentBranch && b.Commit.Title != "" {
+				lines++
+				break
+			}
+		}
+	}
+	if m.contentTab == TabFiles && len(m.currentPath) > 0 {
+		lines++
+	}
+	if m.contentTab == TabFiles && m.refJumpActive {
+		lines++
+	}
+	return lines + m.mrFilterLines()
+}
+
+// mrFilterLines is 1 while the MR tab shows its filter line, which takes a
+// row from the list.
+func (m *MainScreen) mrFilterLines() int {
+	if m.contentTab == TabMRs && (m.mrFilterActive || m.mrFilter != "" || m.mrHideDrafts) {
+		return 1
+	}
+	return 0
+}
+
+// scrollFocused moves the selection of the focused list, or scrolls its
+// viewport, by delta rows.
+func (m *MainScreen) scrollFocused(delta int) {
+	const wheelLines = 3
+
+	switch m.focusedPanel {
+	case PanelNavigator:
+		m.selectedNodeIdx = max(0, min(m.selectedNodeIdx+delta, len(m.treeNodes)-1))
+	case PanelContent:
+		if m.viewingFile {
+			if delta < 0 {
+				m.fileViewport.ScrollUp(wheelLines)
+			} else {
+				m.fileViewport.ScrollDown(wheelLines)
+			}
+			return
+		}
+		m.selectedContent = max(0, min(m.selectedContent+delta, m.getContentCount()-1))
+		m.adjustScrollOffset()
+	case PanelReadme:
+		if delta < 0 {
+			m.readmeViewport.ScrollUp(wheelLines)
+		} else {
+			m.readmeViewport.ScrollDown(wheelLines)
+		}
+	}
+}
+
+func (m *MainScreen) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Clear status message on any keypress
+	m.statusMsg = ""
+
+	// The typed count applies to this key only; digits extend it again
+	m.count, m.countPrefix = m.countPrefix, 0
+
+	// An open confirmation takes every key until it is answered
+	if m.confirm.Active {
+		return m, m.confirm.HandleKey(msg)
+	}
+
+	// ctrl+p pauses auto-refresh everywhere, including popups
+	if msg.String() == "ctrl+p" {
+		m.refreshPaused = !m.refreshPaused
+		if m.refreshPaused {
+			m.statusMsg = "Auto-refresh paused"
+		} else {
+			m.statusMsg = "Auto-refresh resumed"
+		}
+		return m, nil
+	}
+
+	// ctrl+r switches between recently opened projects
+	if msg.String() == "ctrl+r" && !m.popupOpen() && !m.promptOpen() {
+		if len(m.recentProjects) == 0 {
+			m.statusMsg = "No recent projects"
+			return m, nil
+		}
+		m.showRecentPopup = true
+		m.recentCursor = 0
+		// Start on the previous project so Enter flips back to it
+		if m.selectedProject != nil && m.recentProjects[0].ID == m.selectedProject.ID && len(m.recentProjects) > 1 {
+			m.recentCursor = 1
+		}
+		return m, nil
+	}
+
+	// ctrl+o goes back to the previous project, like a browser's back button
+	if msg.String() == "ctrl+o" && !m.popupOpen() && !m.promptOpen() {
+		return m, m.goBack()
+	}
+
+	// ctrl+y copies a markdown link to whatever is under the cursor
+	if msg.String() == "ctrl+y" {
+		link := m.focusedMarkdownLink()
+		if link == "" {
+			m.statusMsg = "Nothing to link"
+		} else if err := copyToClipboard(link); err != nil {
+			m.statusMsg = "Copy failed: " + err.Error()
+		} else {
+			m.statusMsg = "Copied: " + link
+		}
+		return m, nil
+	}
+
+	// ctrl+l copies a lazylab link to the view for a teammate
+	if msg.String() == "ctrl+l" && !m.popupOpen() && !m.promptOpen() {
+		link, ok := m.currentDeepLink()
+		if !ok {
+			m.statusMsg = "Open a project to link to it"
+		} else if err := copyToClipboard(link.String()); err != nil {
+			m.statusMsg = "Copy failed: " + err.Error()
+		} else {
+			m.statusMsg = "Copied: " + link.String()
+		}
+		return m, nil
+	}
+
+	// Handle popups first
+	if m.showHelpPopup {
+		return m.handleHelpPopup(msg)
+	}
+	if m.showJobLogPopup {
+		return m.handleJobLogPopup(msg)
+	}
+	if m.showBranchPopup {
+		return m.handleBranchPopup(msg)
+	}
+	if m.showRunnersPopup {
+		return m.handleRunnersPopup(msg)
+	}
+	if m.showMRDetailPopup {
+		return m.handleMRDetailPopup(msg)
+	}
+	if m.showEnvPopup {
+		return m.handleEnvPopup(msg)
+	}
+	if m.showVarsPopup {
+		return m.handleVarsPopup(msg)
+	}
+	if m.showSnippetsPopup {
+		return m.handleSnippetsPopup(msg)
+	}
+	if m.showWikiPopup {
+		return m.handleWikiPopup(msg)
+	}
+	if m.showContributorsPopup {
+		return m.handleContributorsPopup(msg)
+	}
+	if m.showLabelsPopup {
+		return m.handleLabelsPopup(msg)
+	}
+	if m.showActivityPopup {
+		return m.handleActivityPopup(msg)
+	}
+	if m.showCodeSearch {
+		return m.handleCodeSearch(msg)
+	}
+	if m.showRecentPopup {
+		return m.handleRecentPopup(msg)
+	}
+	if m.showComparePopup {
+		return m.handleComparePopup(msg)
+	}
+	if m.showCommitDiffPopup {
+		return m.handleCommitDiffPopup(msg)
+	}
+	if m.showProjectInfo {
+		return m.handleProjectInfoPopup(msg)
+	}
+	if m.showMyMRsPopup {
+		return m.handleMyMRsPopup(msg)
+	}
+	if m.showReleasePopup {
+		return m.handleReleasePopup(msg)
+	}
+	if m.showFolderBrowser {
+		return m.handleFolderBrowser(msg)
+	}
+	// Text prompts take q as input, but ctrl+c still quits
+	if msg.Type == tea.KeyCtrlC && m.promptOpen() {
+		return m, tea.Quit
+	}
+	if m.mrFilterActive {
+		return m.handleMRFilterInput(msg)
+	}
+	if m.navFilterActive {
+		return m.handleNavFilterInput(msg)
+	}
+	if m.lineJumpActive {
+		return m.handleLineJumpInput(msg)
+	}
+	if m.refJumpActive {
+		return m.handleRefJumpInput(msg)
+	}
+	// The second key of zo/zc/za, before global keys can claim it
+	if m.navFoldPending {
+		return m.handleNavFold(msg)
+	}
+
+	if key.Matches(msg, m.keymap.Quit) {
+		return m, tea.Quit
+	}
+
+	// Clear error on Escape
+	if key.Matches(msg, m.keymap.Back) {
+		// Abort an in-flight download first; its completion message reports it
+		if m.downloadCancel != nil {
+			m.downloadCancel()
+			m.downloadCancel = nil
+			return m, nil
+		}
+		if m.lastError != "" {
+			m.lastError = ""
+			return m, nil
+		}
+	}
+
+	// Retry on 'r' key if there's an error
+	if key.Matches(msg, m.keymap.Refresh) && m.lastError != "" && m.retryCmd != nil {
+		m.lastError = ""
+		m.loading = true
+		m.loadingMsg = "Retrying..."
+		cmd := m.retryCmd
+		return m, cmd
+	}
+
+	// Yank clone URLs when project is selected
+	if m.selectedProject != nil {
+		switch msg.String() {
+		case "S":
+			// Yank SSH URL
+			if m.selectedProject.SSHURLToRepo != "" {
+				if err := copyToClipboard(m.selectedProject.SSHURLToRepo); err != nil {
+					m.statusMsg = "Copy failed: " + err.Error()
+				} else {
+					m.statusMsg = "SSH: " + m.selectedProject.SSHURLToRepo
+				}
+				return m, nil
+			}
+		case "U":
+			// Yank HTTPS URL
+			if m.selectedProject.HTTPURLToRepo != "" {
+				if err := copyToClipboard(m.selectedProject.HTTPURLToRepo); err != nil {
+					m.statusMsg = "Copy failed: " + err.Error()
+				} else {
+					m.statusMsg = "HTTPS: " + m.selectedProject.HTTPURLToRepo
+				}
+				return m, nil
+			}
+		}
+	}
+
+	// 'b' to open branch selector (when viewing files)
+	if msg.String() == "b" && m.selectedProject != nil && m.contentTab == TabFiles {
+		m.showBranchPopup = true
+		m.selectedBranchIdx = 0
+		// Find current branch in list
+		for i, br := range m.branches {
+			if br.Name == m.currentBranch {
+				m.selectedBranchIdx = i
+				break
+			}
+		}
+		if len(m.branches) == 0 && !m.isDemo {
+			m.loading = true
+			m.loadingMsg = "Loading branches..."
+			cmd := m.loadBranches()
+			m.retryCmd = cmd
+			return m, cmd
+		}
+		return m, nil
+	}
+
+	// 'R' to open runners/jobs popup (shows all running/pending jobs)
+	if msg.String() == "R" {
+		m.showRunnersPopup = true
+		m.runnersCursor = 0
+		m.runnersTab = 0
+		m.runnersLoading = true
+		return m, m.loadAllJobs()
+	}
+
+	// 'E' to open environments/deployments popup for the selected project
+	if msg.String() == "E" && m.selectedProject != nil {
+		m.showEnvPopup = true
+		m.envCursor = 0
+		m.envSelected = ""
+		m.envErr = ""
+		if m.isDemo {
+			m.environments = mockEnvironments()
+			return m, nil
+		}
+		m.environments = nil
+		m.envLoading = true
+		return m, m.loadEnvironments()
+	}
+
+	// 'A' toggles between the default group and all groups
+	if msg.String() == "A" && m.defaultGroup != "" {
+		if m.groupFilter != "" {
+			m.statusMsg = "Showing all groups"
+			return m, m.setGroupFilter("")
+		}
+		m.statusMsg = "Showing " + m.defaultGroup
+		return m, m.setGroupFilter(m.defaultGroup)
+	}
+
+	// '<'/'>' shrink and grow the focused panel
+	if msg.String() == "<" || msg.String() == ">" {
+		m.resizePanel(msg.String() == ">")
+		return m, nil
+	}
+
+	// 'i' shows an overview of the project under the cursor
+	if msg.String() == "i" {
+		project := m.selectedProject
+		if m.focusedPanel == PanelNavigator && m.selectedNodeIdx < len(m.treeNodes) {
+			if node := m.treeNodes[m.selectedNodeIdx]; node.Project != nil {
+				project = node.Project
+			} else if node.Type == "project" {
+				// A favorite whose group isn't loaded; fetch it rather than
+				// showing the open project
+				m.statusMsg = "Loading project..."
+				return m, m.loadProjectInfo(node.FullPath)
+			}
+		}
+		if project != nil {
+			m.infoProject = project
+			m.showProjectInfo = true
+			return m, nil
+		}
+	}
+
+	// 'n' to browse the selected project's snippets
+	if msg.String() == "n" && m.selectedProject != nil {
+		m.showSnippetsPopup = true
+		m.snippetsErr = ""
+		if m.isDemo {
+			m.snippets = mockSnippets()
+			return m, nil
+		}
+		m.snippetsCursor = 0
+		m.snippets = nil
+		m.snippetsLoading = true
+		return m, m.loadSnippets()
+	}
+
+	// 'C' to see who commits to the selected project
+	if msg.String() == "C" && m.selectedProject != nil {
+		m.showContributorsPopup = true
+		m.contributorsErr = ""
+		m.contributorsCursor = 0
+		if m.isDemo {
+			m.contributors = sortContributors(mockContributors())
+			return m, nil
+		}
+		m.contributors = nil
+		m.contributorsLoading = true
+		return m, m.loadContributors()
+	}
+
+	// 'T' to triage with the selected project's labels and milestones
+	if msg.String() == "T" && m.selectedProject != nil {
+		m.showLabelsPopup = true
+		m.labelsShowMilestones = false
+		m.labelsErr = ""
+		m.labelsCursor = 0
+		if m.isDemo {
+			m.labels = sortLabels(mockLabels())
+			m.milestones = sortMilestones(mockMilestones())
+			return m, nil
+		}
+		m.labels = nil
+		m.milestones = nil
+		m.labelsLoading = true
+		return m, m.loadLabels()
+	}
+
+	// 'a' to catch up on the selected project's recent activity
+	if msg.String() == "a" && m.selectedProject != nil {
+		m.showActivityPopup = true
+		m.activityErr = ""
+		m.activityCursor = 0
+		if m.isDemo {
+			m.events = sortEvents(mockEvents())
+			return m, nil
+		}
+		m.events = nil
+		m.activityLoading = true
+		return m, m.loadActivity()
+	}
+
+	// '?' to open the keybinding reference
+	if key.Matches(msg, m.keymap.Help) {
+		m.showHelpPopup = true
+		m.helpScroll = 0
+		return m, nil
+	}
+
+	// 'M' to open my merge requests popup (assigned to me / to review)
+	if msg.String() == "M" {
+		m.showMyMRsPopup = true
+		m.myMRsCursor = 0
+		m.myMRsTab = 0
+		m.myMRsErr = ""
+		if m.isDemo {
+			m.myAssignedMRs, m.myReviewMRs = mockMyMergeRequests()
+			return m, nil
+		}
+		m.myMRsLoading = true
+		return m, m.loadMyMRs()
+	}
+
+	// Panel navigation with Shift+HJKL
+	// Layout:
+	// [1 Navigator] [2 Content ]
+	//               [3 README  ]
+	switch msg.String() {
+	case "H", "shift+left":
+		switch m.focusedPanel {
+		case PanelContent, PanelReadme:
+			m.focusedPanel = PanelNavigator
+		}
+		return m, nil
+	case "L", "shift+right":
+		switch m.focusedPanel {
+		case PanelNavigator:
+			m.focusedPanel = PanelContent
+		}
+		return m, nil
+	case "K", "shift+up":
+		switch m.focusedPanel {
+		case PanelReadme:
+			m.focusedPanel = PanelContent
+		}
+		return m, nil
+	case "J", "shift+down":
+		switch m.focusedPanel {
+		case PanelContent:
+			m.focusedPanel = PanelReadme
+		}
+		return m, nil
+	case "1", "2", "3":
+		// After a count prefix, digits extend the count instead
+		if m.count > 0 {
+			break
+		}
+		switch msg.String() {
+		case "1":
+			m.focusedPanel = PanelNavigator
+		case "2":
+			m.focusedPanel = PanelContent
+		case "3":
+			m.focusedPanel = PanelReadme
+		}
+		return m, nil
+	}
+
+	switch m.focusedPanel {
+	case PanelNavigator:
+		return m.handleNavigatorNav(msg)
+	case PanelContent:
+		return m.handleContentNav(msg)
+	case PanelReadme:
+		return m.handleReadmeNav(msg)
+	}
+
+	return m, nil
+}
+
+// maxCount caps count prefixes so they can't overflow
+const maxCount = 99999
+
+// countDigit extends the count prefix when msg is a digit. '0' only extends
+// a count, so on its own it stays free for other bindings.
+func (m *MainScreen) countDigit(msg tea.KeyMsg) bool {
+	s := msg.String()
+	if len(s) != 1 || s[0] < '0' || s[0] > '9' || (s == "0" && m.count == 0) {
+		return false
+	}
+	m.countPrefix = min(m.count*10+int(s[0]-'0'), maxCount)
+	return true
+}
+
+// repeat returns how many times to apply a motion: the count, or once
+func (m *MainScreen) repeat() int {
+	return max(m.count, 1)
+}
+
+// countLine returns the 0-based line a counted G/gg jumps to, clamped to
+// last, or def when no count was typed
+func (m *MainScreen) countLine(def, last int) int {
+	if m.count == 0 {
+		return def
+	}
+	return max(min(m.count-1, last), 0)
+}
+
+func (m *MainScreen) handleNavigatorNav(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keymap.Search):
+		m.navFilterInput = textinput.New()
+		m.navFilterInput.Prompt = "/"
+		m.navFilterInput.Placeholder = "group or project"
+		m.navFilterInput.SetValue(m.navFilter)
+		m.navFilterActive = true
+		return m, m.navFilterInput.Focus()
+	case key.Matches(msg, m.keymap.Back):
+		if m.navFilter != "" {
+			m.setNavFilter("")
+			return m, nil
+		}
+	case msg.String() == "z":
+		// Fold all groups: zo expands, zc collapses, za toggles
+		m.navFoldPending = true
+		return m, nil
+	}
+	if len(m.treeNodes) == 0 {
+		return m, nil
+	}
+	if m.countDigit(msg) {
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keymap.Down):
+		m.selectedNodeIdx = min(m.selectedNodeIdx+m.repeat(), len(m.treeNodes)-1)
+	case key.Matches(msg, m.keymap.Up):
+		m.selectedNodeIdx = max(m.selectedNodeIdx-m.repeat(), 0)
+	case key.Matches(msg, m.keymap.Bottom):
+		m.selectedNodeIdx = m.countLine(len(m.treeNodes)-1, len(m.treeNodes)-1)
+	case key.Matches(msg, m.keymap.Right), key.Matches(msg, m.keymap.Select):
+		if m.selectedNodeIdx >= len(m.treeNodes) {
+			return m, nil
+		}
+
+		node := m.treeNodes[m.selectedNodeIdx]
+
+		if node.Type == "group" && node.ID == favoritesGroupID {
+			// Favorites are always known, nothing to load
+			m.expandedGroups[node.ID] = !m.expandedGroups[node.ID]
+			m.rebuildNavTree()
+		} else if node.Type == "group" {
+			// Toggle group expansion
+			if m.expandedGroups[node.ID] {
+				// Collapse
+				m.expandedGroups[node.ID] = false
+				m.rebuildNavTree()
+			} else {
+				// Expand - check if we have projects cached
+				m.expandedGroups[node.ID] = true
+				if _, ok := m.groupProjects[node.ID]; !ok {
+					// Need to load projects
+					m.loading = true
+					m.loadingMsg = "Loading projects..."
+					cmd := m.loadGroupProjects(node.ID, node.FullPath)
+					m.retryCmd = cmd
+					return m, cmd
+				}
+				m.rebuildNavTree()
+			}
+		} else if node.Type == "project" && node.Project != nil {
+			// Select project and load its content
+			m.selectProject(node.Project)
+
+			// In demo mode, data is pre-populated - don't clear or reload
+			if m.isDemo {
+				return m, nil
+			}
+
+			m.loading = true
+			m.loadingMsg = "Loading repository..."
+			cmd := m.loadProjectContent()
+			m.retryCmd = cmd
+			return m, cmd
+		} else if node.Type == "project" {
+			// Favorite from a group that hasn't been loaded yet
+			m.loading = true
+			m.loadingMsg = "Loading project..."
+			cmd := m.loadFavoriteProject(node.FullPath)
+			m.retryCmd = cmd
+			return m, cmd
+		}
+	case msg.String() == "*":
+		if m.selectedNodeIdx < len(m.treeNodes) && m.treeNodes[m.selectedNodeIdx].Type == "project" {
+			m.toggleFavorite(m.treeNodes[m.selectedNodeIdx])
+		}
+	case key.Matches(msg, m.keymap.Left):
+		if m.selectedNodeIdx >= len(m.treeNodes) {
+			return m, nil
+		}
+
+		node := m.treeNodes[m.selectedNodeIdx]
+		if node.Type == "group" && m.expandedGroups[node.ID] {
+			// Collapse the group
+			m.expandedGroups[node.ID] = false
+			m.rebuildNavTree()
+		}
+	}
+	return m, nil
+}
+
+// selectProject makes project the active project and clears per-project state.
+// Callers are responsible for triggering the initial load.
+func (m *MainScreen) selectProject(project *gitlab.Project) {
+	if m.selectedProject != nil && m.selectedProject.ID != project.ID {
+		m.backStack.Push(m.currentContext())
+	}
+	m.selectedProject = project
+	m.currentPath = nil
+	m.restartLastCommits()
+	// Come back to the ref last browsed in this project
+	m.currentBranch = m.projectRefs[project.ID]
+	m.contentTab = TabFiles
+	m.focusedPanel = PanelContent
+	m.clearMRFilter()
+	m.addRecentProject()
+
+	// In demo mode, data is pre-populated - don't clear
+	if m.isDemo {
+		return
+	}
+
+	m.files = nil
+	m.repoEmpty = false
+	m.mergeRequests = nil
+	m.mrApprovals = nil
+	m.mrNextPage, m.pipelineNextPage = 0, 0
+	m.loadingMore = false
+	m.pipelines = nil
+	m.releases = nil
+	m.tags = nil
+	m.showTags = false
+	m.branches = nil
+	m.fileContent = ""
+	m.readmeContent = ""
+	m.projectLanguages = nil
+	m.projectStats = nil
+	m.expandedPipelines = nil
+	m.rememberProject()
+}
+
+func (m *MainScreen) handleContentNav(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.countDigit(msg) {
+		return m, nil
+	}
+
+	// Handle escape for going back
+	if key.Matches(msg, m.keymap.Back) {
+		// If viewing a file, go back to file list
+		if m.viewingFile {
+			m.viewingFile = false
+			m.fileContent = ""
+			m.viewingFilePath = ""
+			m.viewingFileRef = ""
+			// Snippets go back to the list they were opened from
+			if m.viewingSnippet {
+				m.viewingSnippet = false
+				m.showSnippetsPopup = true
+			}
+			if m.viewingWiki {
+				m.viewingWiki = false
+				m.showWikiPopup = true
+			}
+			return m, nil
+		}
+		// Clear an active MR filter before leaving the panel
+		if m.contentTab == TabMRs && (m.mrFilter != "" || m.mrHideDrafts) {
+			m.clearMRFilter()
+			m.selectedContent = 0
+			m.fileScrollOffset = 0
+			return m, nil
+		}
+		// If in a directory, go up
+		if m.contentTab == TabFiles && len(m.currentPath) > 0 {
+			m.currentPath = m.currentPath[:len(m.currentPath)-1]
+			// Demo mode doesn't support directory navigation
+			if m.isDemo {
+				return m, nil
+			}
+			m.loading = true
+			m.loadingMsg = "Loading..."
+			path := strings.Join(m.currentPath, "/")
+			cmd := m.loadDirectory(path)
+			m.retryCmd = cmd
+			return m, cmd
+		}
+		// If at root, go back to navigator
+		m.focusedPanel = PanelNavigator
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keymap.Left):
+		// h - switch to previous tab
+		if i := slices.Index(m.visibleTabs(), m.contentTab); i > 0 {
+			return m, m.switchTab(m.visibleTabs()[i-1])
+		}
+		// At first tab, go to navigator panel
+		m.focusedPanel = PanelNavigator
+
+	case key.Matches(msg, m.keymap.Right):
+		// l - switch to next tab
+		if tabs := m.visibleTabs(); slices.Index(tabs, m.contentTab)+1 < len(tabs) {
+			return m, m.switchTab(tabs[slices.Index(tabs, m.contentTab)+1])
+		}
+
+	case key.Matches(msg, m.keymap.Select):
+		// Enter - drill into directory or view file
+		if m.contentTab == TabFiles && m.selectedContent < len(m.files) {
+			entry := m.files[m.selectedContent]
+			if entry.Type == "tree" {
+				// Demo mode doesn't support directory navigation
+				if m.isDemo {
+					return m, nil
+				}
+				// Entries expanded inline may be several levels down
+				m.currentPath = strings.Split(entry.Path, "/")
+				m.loading = true
+				m.loadingMsg = "Loading..."
+				cmd := m.loadDirectory(entry.Path)
+				m.retryCmd = cmd
+				return m, cmd
+			} else {
+				// Demo mode uses mock file content
+				if m.isDemo {
+					if content, ok := MockFileContent[entry.Name]; ok {
+						m.fileContent = content
+						m.viewingFile = true
+						m.viewingFilePath = entry.Path
+						m.fileIsMarkdown = isMarkdownFile(entry.Path)
+						m.fileShowRaw = false
+						m.fileSaveRaw = false
+					}
+					return m, nil
+				}
+				m.loading = true
+				m.loadingMsg = "Loading file..."
+				cmd := m.loadFile(entry.Path)
+				m.retryCmd = cmd
+				return m, cmd
+			}
+		}
+		// Load jobs for selected pipeline and show popup
+		if visible := m.visiblePipelines(); m.contentTab == TabPipelines && m.selectedContent < len(visible) {
+			// Demo mode doesn't support job log viewing
+			if m.isDemo {
+				return m, nil
+			}
+			pipeline := visible[m.selectedContent]
+			m.jobs = nil
+			m.jobLog = ""
+			m.showJobLogPopup = true
+			m.jobLogFocused = false // Start focused on job list
+			m.jobLogCursor = 0
+			m.jobLogHScroll = 0
+			m.currentPipelineID = pipeline.ID
+			m.loading = true
+			m.loadingMsg = "Loading jobs..."
+			cmd := m.loadPipelineJobs(pipeline.ID)
+			m.retryCmd = cmd
+			return m, cmd
+		}
+		// Show merge request detail popup
+		if mrs := m.visibleMRs(); m.contentTab == TabMRs && m.selectedContent < len(mrs) {
+			m.openMRDetail(mrs[m.selectedContent])
+			return m, nil
+		}
+		// Show the release popup for a tag that has a release
+		if m.contentTab == TabReleases && m.showTags {
+			if m.selectedContent >= len(m.tags) {
+				return m, nil
+			}
+			tag := m.tags[m.selectedContent]
+			for i, rel := range m.releases {
+				if rel.TagName == tag.Name {
+					m.selectedReleaseIdx = i
+					m.releaseAssetCursor = 0
+					m.releaseScrollOffset = 0
+					m.showReleasePopup = true
+					return m, nil
+				}
+			}
+			if tag.Release != nil {
+				// Older releases aren't in the loaded page, fetch this one by tag
+				m.statusMsg = "Loading release " + tag.Name + "..."
+				return m, m.loadRelease(tag.Name)
+			}
+			m.statusMsg = "No release for tag " + tag.Name
+			return m, nil
+		}
+		// Show release assets popup
+		if m.contentTab == TabReleases && m.selectedContent < len(m.releases) {
+			m.selectedReleaseIdx = m.selectedContent
+			m.releaseAssetCursor = 0
+			m.releaseScrollOffset = 0
+			m.showReleasePopup = true
+			return m, nil
+		}
+
+	case key.Matches(msg, m.keymap.Down):
+		// If viewing file, scroll down
+		if m.viewingFile {
+			m.fileViewport.ScrollDown(m.repeat())
+			return m, nil
+		}
+		maxItems := m.getContentCount()
+		if m.selectedContent < maxItems-1 {
+			m.selectedContent = min(m.selectedContent+m.repeat(), maxItems-1)
+			if m.contentTab == TabFiles {
+				m.fileContent = ""
+				m.viewingFile = false
+			}
+			m.adjustScrollOffset()
+		}
+		return m, m.maybeLoadMore()
+	case key.Matches(msg, m.keymap.Up):
+		// If viewing file, scroll up
+		if m.viewingFile {
+			m.fileViewport.ScrollUp(m.repeat())
+			return m, nil
+		}
+		if m.selectedContent > 0 {
+			m.selectedContent = max(m.selectedContent-m.repeat(), 0)
+			if m.contentTab == TabFiles {
+				m.fileContent = ""
+				m.viewingFile = false
+			}
+			m.adjustScrollOffset()
+		}
+	case key.Matches(msg, m.keymap.Bottom) && !m.viewingFile:
+		// G - last item, or item N with a count
+		if maxItems := m.getContentCount(); maxItems > 0 {
+			m.selectedContent = m.countLine(maxItems-1, maxItems-1)
+			m.adjustScrollOffset()
+		}
+		return m, m.maybeLoadMore()
+	}
+
+	// 'g' prompts for a branch, tag or SHA to browse
+	if m.contentTab == TabFiles && msg.String() == "g" && !m.viewingFile && m.selectedProject != nil {
+		m.refJumpInput = textinput.New()
+		m.refJumpInput.Prompt = "ref: "
+		m.refJumpInput.Placeholder = "branch, tag or commit SHA"
+		m.refJumpActive = true
+		return m, m.refJumpInput.Focus()
+	}
+
+	// '/' searches the project's code
+	if m.contentTab == TabFiles && key.Matches(msg, m.keymap.Search) && !m.viewingFile && m.selectedProject != nil {
+		m.showCodeSearch = true
+		m.codeSearchInput = textinput.New()
+		m.codeSearchInput.Prompt = "/"
+		m.codeSearchInput.Placeholder = "search code"
+		m.codeSearchInput.SetValue(m.codeSearchQuery)
+		m.codeSearchTyping = true
+		return m, m.codeSearchInput.Focus()
+	}
+
+	// 'W' browses the project wiki
+	if m.contentTab == TabFiles && msg.String() == "W" && !m.viewingFile && m.selectedProject != nil {
+		m.showWikiPopup = true
+		m.wikiErr = ""
+		if m.isDemo {
+			m.wikiPages = sortWikiPages(mockWikiPages())
+			return m, nil
+		}
+		m.wikiCursor = 0
+		m.wikiPages = nil
+		m.wikiLoading = true
+		return m, m.loadWikiPages()
+	}
+
+	// Space expands a directory inline rather than entering it
+	if m.contentTab == TabFiles && msg.String() == " " && !m.viewingFile && m.selectedContent < len(m.files) {
+		return m, m.toggleDir(m.selectedContent)
+	}
+
+	// 'D' shows the diff of the selected file's last commit
+	if m.contentTab == TabFiles && msg.String() == "D" && m.selectedContent < len(m.files) {
+		return m, m.openCommitDiff(m.files[m.selectedContent])
+	}
+
+	// Filter keys for the merge request list
+	if m.contentTab == TabMRs {
+		if key.Matches(msg, m.keymap.Search) {
+			m.mrFilterInput = textinput.New()
+			m.mrFilterInput.Prompt = "/"
+			m.mrFilterInput.Placeholder = "title, author or label"
+			m.mrFilterInput.SetValue(m.mrFilter)
+			m.mrFilterActive = true
+			return m, m.mrFilterInput.Focus()
+		}
+		switch msg.String() {
+		case "D":
+			m.mrHideDrafts = !m.mrHideDrafts
+			m.selectedContent = 0
+			m.fileScrollOffset = 0
+		case "y":
+			// Yank MR web URL
+			mrs := m.visibleMRs()
+			if m.selectedContent < len(mrs) && mrs[m.selectedContent].WebURL != "" {
+				mr := mrs[m.selectedContent]
+				if err := copyToClipboard(mr.WebURL); err != nil {
+					m.statusMsg = "Copy failed: " + err.Error()
+				} else {
+					m.statusMsg = fmt.Sprintf("MR !%d: %s", mr.IID, mr.WebURL)
+				}
+			}
+			return m, nil
+		}
+	}
+
+	// Toggle between releases and tags
+	if m.contentTab == TabReleases && msg.String() == "t" {
+		m.showTags = !m.showTags
+		m.selectedContent = 0
+		m.fileScrollOffset = 0
+		if m.showTags && m.tags == nil && !m.isDemo {
+			m.loading = true
+			m.loadingMsg = "Loading tags..."
+			cmd := m.loadTags()
+			m.retryCmd = cmd
+			return m, cmd
+		}
+		return m, nil
+	}
+
+	// Sort/filter keys for the pipeline list
+	if m.contentTab == TabPipelines {
+		switch msg.String() {
+		case "s":
+			id := m.selectedPipelineID()
+			m.pipelineSort = (m.pipelineSort + 1) % 2
+			m.restorePipelineSelection(id)
+		case "f":
+			id := m.selectedPipelineID()
+			for i, f := range pipelineStatusFilters {
+				if f == m.pipelineFilter {
+					m.pipelineFilter = pipelineStatusFilters[(i+1)%len(pipelineStatusFilters)]
+					break
+				}
+			}
+			m.restorePipelineSelection(id)
+		case " ":
+			// Expand the pipeline in place to show its stages and jobs
+			pipelines := m.visiblePipelines()
+			if m.selectedContent >= len(pipelines) {
+				return m, nil
+			}
+			id := pipelines[m.selectedContent].ID
+			if m.expandedPipelines == nil {
+				m.expandedPipelines = make(map[int]bool)
+			}
+			m.expandedPipelines[id] = !m.expandedPipelines[id]
+			if m.expandedPipelines[id] && len(m.pipelineJobs[id]) == 0 {
+				return m, m.loadPipelineJobsForList(id)
+			}
+		case "v":
+			// CI/CD variables of the project
+			m.showVarsPopup = true
+			m.varsCursor = 0
+			m.varsErr = ""
+			m.varsShowValues = false
+			if m.isDemo {
+				m.variables = mockVariables()
+				return m, nil
+			}
+			m.variables = nil
+			m.varsLoading = true
+			return m, m.loadVariables()
+		case "y":
+			// Yank full commit SHA
+			pipelines := m.visiblePipelines()
+			if m.selectedContent < len(pipelines) && pipelines[m.selectedContent].SHA != "" {
+				sha := pipelines[m.selectedContent].SHA
+				if err := copyToClipboard(sha); err != nil {
+					m.statusMsg = "Copy failed: " + err.Error()
+				} else {
+					m.statusMsg = "SHA: " + sha
+				}
+			}
+			return m, nil
+		case "Y":
+			// Yank pipeline web URL
+			pipelines := m.visiblePipelines()
+			if m.selectedContent < len(pipelines) && pipelines[m.selectedContent].WebURL != "" {
+				url := pipelines[m.selectedContent].WebURL
+				if err := copyToClipboard(url); err != nil {
+					m.statusMsg = "Copy failed: " + err.Error()
+				} else {
+					m.statusMsg = "Pipeline: " + url
+				}
+			}
+			return m, nil
+		case "F":
+			// Copy a summary of the failed jobs to paste into chat
+			pipelines := m.visiblePipelines()
+			if m.selectedContent >= len(pipelines) {
+				return m, nil
+			}
+			p := pipelines[m.selectedContent]
+			if jobs := m.pipelineJobs[p.ID]; len(jobs) > 0 || m.isDemo {
+				m.copyFailureSummary(pipelineFailureSummary(p, jobs))
+				return m, nil
+			}
+			m.statusMsg = "Loading jobs..."
+			return m, m.loadFailureSummary(p)
+		}
+	}
+
+	// Additional scroll keys when viewing file
+	if m.viewingFile {
+		switch {
+		case key.Matches(msg, m.keymap.PageDown):
+			m.fileViewport.HalfPageDown()
+			return m, nil
+		case key.Matches(msg, m.keymap.PageUp):
+			m.fileViewport.HalfPageUp()
+			return m, nil
+		case key.Matches(msg, m.keymap.Top):
+			m.fileViewport.GotoTop()
+			return m, nil
+		case key.Matches(msg, m.keymap.Bottom):
+			// NG jumps to line N, like :N
+			if m.count > 0 {
+				return m, m.jumpToFileLine(m.count)
+			}
+			m.fileViewport.GotoBottom()
+			return m, nil
+		}
+		switch msg.String() {
+		case "e":
+			// Open the file in $EDITOR / $PAGER
+			return m, openInEditor(m.viewingFilePath, m.fileContent)
+		case "y":
+			// Copy the repo-relative path
+			if m.viewingSnippet || m.viewingWiki {
+				m.statusMsg = "Not a repository file"
+			} else if err := copyToClipboard(m.viewingFilePath); err != nil {
+				m.statusMsg = "Copy failed: " + err.Error()
+			} else {
+				m.statusMsg = "Path: " + m.viewingFilePath
+			}
+		case "Y":
+			// Copy a permalink pinned to the current commit
+			return m, m.copyFilePermalink()
+		case "ctrl+s":
+			// Save the file to disk via the download folder browser
+			m.downloadURL = ""
+			m.downloadFilename = path.Base(m.viewingFilePath)
+			m.downloadChecksumURL = ""
+			m.saveViewedFile = true
+			m.openFolderBrowser()
+		case "m":
+			// Toggle rendered markdown / raw source
+			if m.fileIsMarkdown {
+				m.fileShowRaw = !m.fileShowRaw
+				m.fileViewport.SetContent(m.fileViewContent())
+				m.fileViewport.GotoTop()
+			}
+		case ":":
+			// Prompt for a line number to jump to
+			m.lineJumpInput = textinput.New()
+			m.lineJumpInput.Prompt = ":"
+			m.lineJumpInput.CharLimit = 10
+			m.lineJumpActive = true
+			return m, m.lineJumpInput.Focus()
+		}
+	}
+
+	return m, nil
+}
+
+// listVisibleLines returns how many content list items fit on screen
+func (m *MainScreen) listVisibleLines() int {
+	// Calculate visible area matching renderContentPanel calculation
+	// listHeight = list part of m.height - StatusBarHeight (1)
+	// visibleLines = listHeight - 6, less the MR filter line (in renderListSection)
+	contentHeight := m.height - config.StatusBarHeight
+	return max(m.contentListHeight(contentHeight)-6-m.mrFilterLines(), 1)
+}
+
+func (m *MainScreen) adjustScrollOffset() {
+	visibleLines := m.listVisibleLines()
+
+	// Adjust offset to keep selected item visible
+	if m.selectedContent < m.fileScrollOffset {
+		m.fileScrollOffset = m.selectedContent
+	} else if m.selectedContent >= m.fileScrollOffset+visibleLines {
+		m.fileScrollOffset = m.selectedContent - visibleLines + 1
+	}
+}
+
+func (m *MainScreen) handleReadmeNav(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	pressed := msg.String()
+
+	// Clear key sequence unless it's a sequence key (g, y)
+	if !key.Matches(msg, m.keymap.Top) && pressed != "y" {
+		m.readmeLastKey = ""
+	}
+	if m.countDigit(msg) {
+		return m, nil
+	}
+
+	// Get line count from raw content
+	maxLine := strings.Count(m.readmeContent, "\n")
+
+	switch {
+	case pressed == "H" || pressed == "shift+left":
+		m.focusedPanel = PanelNavigator
+		return m, nil
+	case pressed == "L" || pressed == "shift+right":
+		m.focusedPanel = PanelContent
+		return m, nil
+	case key.Matches(msg, m.keymap.Left):
+		// Scroll left through wide lines, switching panels once at the start
+		if m.readmeHScroll > 0 {
+			m.scrollReadmeTo(m.readmeHScroll - m.repeat()*m.scrollStep())
+			return m, nil
+		}
+		m.focusedPanel = PanelNavigator
+		return m, nil
+	case key.Matches(msg, m.keymap.Right):
+		// Scroll right through wide lines, switching panels once at the end
+		if m.readmeHScroll < m.readmeMaxHScroll() {
+			m.scrollReadmeTo(m.readmeHScroll + m.repeat()*m.scrollStep())
+			return m, nil
+		}
+		m.focusedPanel = PanelContent
+		return m, nil
+	case pressed == "0":
+		// Go to start of line
+		m.readmeHScroll = 0
+	case pressed == "$":
+		// Go to end of the longest line
+		m.scrollReadmeTo(m.readmeMaxWidth)
+	case key.Matches(msg, m.keymap.Down):
+		if m.readmeCursor < maxLine {
+			m.readmeCursor = min(m.readmeCursor+m.repeat(), maxLine)
+			if m.readmeVisualMode {
+				m.readmeVisualEnd = m.readmeCursor
+			}
+		}
+		// Keep cursor in view
+		viewportBottom := m.readmeViewport.YOffset + m.readmeViewport.Height - 1
+		if m.readmeCursor > viewportBottom {
+			m.readmeViewport.ScrollDown(m.readmeCursor - viewportBottom)
+		}
+	case key.Matches(msg, m.keymap.Up):
+		if m.readmeCursor > 0 {
+			m.readmeCursor = max(m.readmeCursor-m.repeat(), 0)
+			if m.readmeVisualMode {
+				m.readmeVisualEnd = m.readmeCursor
+			}
+		}
+		// Keep cursor in view
+		if m.readmeCursor < m.readmeViewport.YOffset {
+			m.readmeViewport.ScrollUp(m.readmeViewport.YOffset - m.readmeCursor)
+		}
+	case key.Matches(msg, m.keymap.PageDown):
+		m.readmeViewport.HalfPageDown()
+		m.readmeCursor += m.readmeViewport.Height / 2
+		if m.readmeCursor > maxLine {
+			m.readmeCursor = maxLine
+		}
+		if m.readmeVisualMode {
+			m.readmeVisualEnd = m.readmeCursor
+		}
+	case key.Matches(msg, m.keymap.PageUp):
+		m.readmeViewport.HalfPageUp()
+		m.readmeCursor -= m.readmeViewport.Height / 2
+		if m.readmeCursor < 0 {
+			m.readmeCursor = 0
+		}
+		if m.readmeVisualMode {
+			m.readmeVisualEnd = m.readmeCursor
+		}
+	case key.Matches(msg, m.keymap.Top):
+		if m.readmeLastKey == "g" && m.count > 0 {
+			// Ngg - go to line N
+			m.readmeLastKey = ""
+			m.readmeGotoLine(m.countLine(0, maxLine))
+			return m, nil
+		}
+		if m.readmeLastKey == "g" {
+			// gg - go to top
+			m.readmeViewport.GotoTop()
+			m.readmeCursor = 0
+			if m.readmeVisualMode {
+				m.readmeVisualEnd = m.readmeCursor
+			}
+			m.readmeLastKey = "gg"
+			return m, nil
+		}
+		// Keep the count for the second g
+		m.countPrefix = m.count
+		m.readmeLastKey = "g"
+		return m, nil
+	case key.Matches(msg, m.keymap.Bottom):
+		if m.count > 0 {
+			// NG - go to line N
+			m.readmeGotoLine(m.countLine(maxLine, maxLine))
+			return m, nil
+		}
+		m.readmeViewport.GotoBottom()
+		m.readmeCursor = maxLine
+		if m.readmeVisualMode {
+			m.readmeVisualEnd = m.readmeCursor
+		}
+	case pressed == "V":
+		// Toggle visual line mode
+		if m.readmeVisualMode {
+			m.readmeVisualMode = false
+		} else {
+			m.readmeVisualMode = true
+			m.readmeVisualStart = m.readmeCursor
+			m.readmeVisualEnd = m.readmeCursor
+		}
+	case pressed == "y":
+		if m.readmeContent == "" {
+			m.readmeLastKey = ""
+			return m, nil
+		}
+		lines := strings.Split(m.readmeContent, "\n")
+		if m.readmeVisualMode {
+			// Copy selected lines
+			startLine := m.readmeVisualStart
+			endLine := m.readmeVisualEnd
+			if startLine > endLine {
+				startLine, endLine = endLine, startLine
+			}
+			if startLine < 0 {
+				startLine = 0
+			}
+			if endLine >= len(lines) {
+				endLine = len(lines) - 1
+			}
+			selected := strings.Join(lines[startLine:endLine+1], "\n")
+			if err := copyToClipboard(selected); err != nil {
+				m.statusMsg = "Copy failed: " + err.Error()
+			} else {
+				m.statusMsg = fmt.Sprintf("Copied %d lines!", endLine-startLine+1)
+			}
+			m.readmeVisualMode = false
+		} else if m.readmeLastKey == "gg" {
+			// ggy - yank entire readme
+			if err := copyToClipboard(m.readmeContent); err != nil {
+				m.statusMsg = "Copy failed: " + err.Error()
+			} else {
+				m.statusMsg = fmt.Sprintf("Yanked all %d lines!", len(lines))
+			}
+		} else if m.readmeLastKey == "y" {
+			// yy - yank current line
+			if m.readmeCursor >= 0 && m.readmeCursor < len(lines) {
+				if err := copyToClipboard(lines[m.readmeCursor]); err != nil {
+					m.statusMsg = "Copy failed: " + err.Error()
+				} else {
+					m.statusMsg = "Yanked line!"
+				}
+			}
+		} else {
+			m.readmeLastKey = "y"
+			return m, nil
+		}
+		m.readmeLastKey = ""
+	case key.Matches(msg, m.keymap.Back):
+		if m.readmeVisualMode {
+			m.readmeVisualMode = false
+			return m, nil
+		}
+	}
+	return m, nil
+}
+
+// readmeMaxHScroll returns how far the README can scroll sideways, 0 when
+// every line fits the panel
+func (m *MainScreen) readmeMaxHScroll() int {
+	return clampHScroll(m.readmeMaxWidth, m.readmeMaxWidth, m.readmeViewport.Width)
+}
+
+// scrollReadmeTo scrolls the README sideways to offset, kept between the
+// start of the lines and the end of the longest one
+func (m *MainScreen) scrollReadmeTo(offset int) {
+	m.readmeHScroll = clampHScroll(offset, m.readmeMaxWidth, m.readmeViewport.Width)
+}
+
+// readmeGotoLine moves the README cursor to line and scrolls it into view
+func (m *MainScreen) readmeGotoLine(line int) {
+	m.readmeCursor = line
+	if m.readmeVisualMode {
+		m.readmeVisualEnd = m.readmeCursor
+	}
+	if line < m.readmeViewport.YOffset || line >= m.readmeViewport.YOffset+m.readmeViewport.Height {
+		m.readmeViewport.SetYOffset(line)
+	}
+}
+
+func (m *MainScreen) handleBranchPopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc", "escape":
+		// Drop the comparison mark first, then close
+		if m.compareMark != "" {
+			m.compareMark = ""
+			return m, nil
+		}
+		m.showBranchPopup = false
+		return m, nil
+	case " ":
+		// Mark the branch as the base of a comparison
+		if m.selectedBranchIdx < len(m.branches) {
+			name := m.branches[m.selectedBranchIdx].Name
+			if m.compareMark == name {
+				m.compareMark = ""
+			} else {
+				m.compareMark = name
+			}
+		}
+	case "j", "down":
+		if m.selectedBranchIdx < len(m.branches)-1 {
+			m.selectedBranchIdx++
+		}
+	case "k", "up":
+		if m.selectedBranchIdx > 0 {
+			m.selectedBranchIdx--
+		}
+	case "y", "Y":
+		// 'y' copies the branch name, 'Y' the full SHA of its last commit
+		if m.selectedBranchIdx >= len(m.branches) {
+			return m, nil
+		}
+		branch := m.branches[m.selectedBranchIdx]
+		text, label := branch.Name, "Branch: "
+		if msg.String() == "Y" {
+			text, label = branch.Commit.ID, "SHA: "
+		}
+		if text == "" {
+			return m, nil
+		}
+		if err := copyToClipboard(text); err != nil {
+			m.statusMsg = "Copy failed: " + err.Error()
+		} else {
+			m.statusMsg = label + text
+		}
+	case "a":
+		// Count commits ahead of / behind the default branch
+		if m.selectedBranchIdx >= len(m.branches) {
+			return m, nil
+		}
+		name := m.branches[m.selectedBranchIdx].Name
+		defaultBranch := m.defaultBranchName()
+		if name == defaultBranch {
+			m.statusMsg = name + " is the default branch"
+			return m, nil
+		}
+		if m.isDemo {
+			if m.branchDivergence == nil {
+				m.branchDivergence = make(map[string]aheadBehind)
+			}
+			m.branchDivergence[name] = aheadBehind{ahead: len(mockComparison().Commits), behind: 2}
+			return m, nil
+		}
+		m.divergenceLoading = name
+		return m, m.loadDivergence(name, defaultBranch)
+	case "enter":
+		// With a branch marked, Enter on another branch compares the two
+		if m.compareMark != "" && m.selectedBranchIdx < len(m.branches) && m.branches[m.selectedBranchIdx].Name != m.compareMark {
+			from := m.compareMark
+			m.compareMark = ""
+			m.showBranchPopup = false
+			return m, m.openComparison(from, m.branches[m.selectedBranchIdx].Name)
+		}
+		if m.selectedBranchIdx < len(m.branches) {
+			m.compareMark = ""
+			m.showBranchPopup = false
+			return m, m.switchRef(m.branches[m.selectedBranchIdx].Name)
+		}
+	}
+	return m, nil
+}
+
+// branchTags renders the default, protected and merged tags of a branch
+func branchTags(b gitlab.Branch) string {
+	var tags string
+	if b.Default {
+		tags += lipgloss.NewStyle().Foreground(styles.ColorCyan).Render(" default")
+	}
+	if b.Protected {
+		tags += lipgloss.NewStyle().Foreground(styles.ColorYellow).Render(" protected")
+	}
+	if b.Merged {
+		tags += styles.DimmedText.Render(" merged")
+	}
+	return tags
+}
+
+// switchRef shows the files of ref, which may be a branch, tag or commit SHA
+func (m *MainScreen) switchRef(ref string) tea.Cmd {
+	m.currentBranch = ref
+	m.rememberRef(ref)
+	// Demo mode doesn't support branch switching
+	if m.isDemo {
+		return nil
+	}
+	// Reload files for new branch
+	m.files = nil
+	m.currentPath = nil
+	m.fileContent = ""
+	m.viewingFile = false
+	m.readmeContent = ""
+	m.loading = true
+	m.loadingMsg = "Loading files..."
+	cmd := m.loadProjectContentForBranch(ref)
+	m.retryCmd = cmd
+	return cmd
+}
+
+// rememberRef records ref as the one to come back to in the selected project
+func (m *MainScreen) rememberRef(ref string) {
+	if m.selectedProject == nil {
+		return
+	}
+	if m.projectRefs == nil {
+		m.projectRefs = make(map[int]string)
+	}
+	if ref == m.selectedProject.DefaultBranch {
+		delete(m.projectRefs, m.selectedProject.ID)
+	} else {
+		m.projectRefs[m.selectedProject.ID] = ref
+	}
+}
+
+func (m *MainScreen) handleRunnersPopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Get current job list based on tab
+	jobs := m.runningJobs
+	if m.runnersTab == 1 {
+		jobs = m.pendingJobs
+	}
+
+	switch msg.String() {
+	case "q", "esc", "escape":
+		m.showRunnersPopup = false
+		return m, nil
+	case "j", "down":
+		if m.runnersCursor < len(jobs)-1 {
+			m.runnersCursor++
+		}
+	case "k", "up":
+		if m.runnersCursor > 0 {
+			m.runnersCursor--
+		}
+	case "tab", "l", "right":
+		// Switch between running/pending tabs
+		m.runnersTab = (m.runnersTab + 1) % 2
+		m.runnersCursor = 0
+	case "shift+tab", "h", "left":
+		m.runnersTab = (m.runnersTab + 1) % 2
+		m.runnersCursor = 0
+	case "r":
+		// Manual refresh
+		m.runnersLoading = true
+		return m, m.loadAllJobs()
+	case "g":
+		if m.runnersLastKey == "g" {
+			m.runnersCursor = 0
+			m.runnersLastKey = ""
+			return m, nil
+		}
+		m.runnersLastKey = "g"
+		return m, nil
+	case "G":
+		m.runnersCursor = len(jobs) - 1
+		if m.runnersCursor < 0 {
+			m.runnersCursor = 0
+		}
+	}
+	// Clear key sequence for non-sequence keys
+	if msg.String() != "g" {
+		m.runnersLastKey = ""
+	}
+	return m, nil
+}
+
+// visibleJobs returns the indexes into m.jobs shown in the job list, narrowed
+// to jobStageFilter when set
+func (m *MainScreen) visibleJobs() []int {
+	var visible []int
+	for i, job := range m.jobs {
+		if m.jobStageFilter == "" || job.Stage == m.jobStageFilter {
+			visible = append(visible, i)
+		}
+	}
+	return visible
+}
+
+// moveJobSelection moves the job selection by delta rows of the job list
+func (m *MainScreen) moveJobSelection(delta int) tea.Cmd {
+	visible := m.visibleJobs()
+	if len(visible) == 0 {
+		return nil
+	}
+	pos := max(slices.Index(visible, m.selectedJobIdx), 0)
+	next := visible[max(min(pos+delta, len(visible)-1), 0)]
+	if next == m.selectedJobIdx {
+		return nil
+	}
+	return m.selectJob(next)
+}
+
+// selectJob selects the job at idx in m.jobs and loads its log
+func (m *MainScreen) selectJob(idx int) tea.Cmd {
+	m.selectedJobIdx = idx
+	if m.isDemo {
+		return nil
+	}
+	m.jobLog = ""
+	m.jobLogReady = false
+	m.jobLogHScroll = 0
+	m.visualLineMode = false
+	m.loading = true
+	m.loadingMsg = "Loading job log..."
+	m.statusMsg = ""
+	cmd := m.loadJobLog(m.jobs[idx].ID)
+	m.retryCmd = cmd
+	return cmd
+}
+
+// cycleJobStageFilter narrows the job list to the next stage in pipeline
+// order, back to all jobs after the last. The selection moves to the
+// stage's first job when the selected one is filtered out.
+func (m *MainScreen) cycleJobStageFilter() tea.Cmd {
+	stages := groupJobsByStage(m.jobs)
+	if len(stages) == 0 {
+		return nil
+	}
+	// Position 0 is all jobs and i+1 is stages[i]
+	pos := 0
+	for i, stage := range stages {
+		if stage.name == m.jobStageFilter {
+			pos = i + 1
+			break
+		}
+	}
+	pos = (pos + 1) % (len(stages) + 1)
+	if pos == 0 {
+		m.jobStageFilter = ""
+		return nil
+	}
+	m.jobStageFilter = stages[pos-1].name
+
+	visible := m.visibleJobs()
+	if len(visible) == 0 || slices.Contains(visible, m.selectedJobIdx) {
+		return nil
+	}
+	return m.selectJob(visible[0])
+}
+
+func (m *MainScreen) handleJobLogPopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.jobLogSearchActive {
+		return m.handleJobLogSearchInput(msg)
+	}
+
+	key := msg.String()
+
+	// Clear key sequence unless it's a sequence key (g, y)
+	if key != "g" && key != "y" {
+		m.jobLogLastKey = ""
+	}
+	if m.countDigit(msg) {
+		return m, nil
+	}
+
+	switch key {
+	case "q":
+		m.showJobLogPopup = false
+		m.jobs = nil
+		m.jobLog = ""
+		m.statusMsg = ""
+		m.lastError = ""
+		m.jobLogFocused = false
+		m.clearJobLogSearch()
+		return m, nil
+	case "esc", "escape":
+		// Cancel visual mode first
+		if m.visualLineMode {
+			m.visualLineMode = false
+			return m, nil
+		}
+		// Then clear search highlights
+		if m.jobLogSearch != "" {
+			m.clearJobLogSearch()
+			return m, nil
+		}
+		// Switch to job list first, then close
+		if m.jobLogFocused {
+			m.jobLogFocused = false
+			return m, nil
+		}
+		m.showJobLogPopup = false
+		m.jobs = nil
+		m.jobLog = ""
+		m.statusMsg = ""
+		m.lastError = ""
+		m.visualLineMode = false
+		return m, nil
+	case "/":
+		// Search the log
+		if m.jobLog != "" {
+			m.jobLogFocused = true
+			m.jobLogSearchInput = textinput.New()
+			m.jobLogSearchInput.Prompt = "/"
+			m.jobLogSearchInput.SetValue(m.jobLogSearch)
+			m.jobLogSearchInput.CursorEnd()
+			m.jobLogSearchActive = true
+			return m, m.jobLogSearchInput.Focus()
+		}
+		return m, nil
+	case "n":
+		// Next match
+		if len(m.jobLogMatches) > 0 {
+			m.jumpToLogMatch((m.jobLogMatchIdx + 1) % len(m.jobLogMatches))
+		}
+		return m, nil
+	case "N":
+		// Previous match
+		if len(m.jobLogMatches) > 0 {
+			m.jumpToLogMatch((m.jobLogMatchIdx - 1 + len(m.jobLogMatches)) % len(m.jobLogMatches))
+		}
+		return m, nil
+	case "H", "shift+left":
+		// Switch to job list panel
+		if m.jobLogFocused {
+			m.jobLogFocused = false
+		}
+		return m, nil
+	case "L", "shift+right", "enter":
+		// Switch to log panel
+		if !m.jobLogFocused {
+			m.jobLogFocused = true
+		}
+		return m, nil
+	case "f":
+		// Narrow the job list to the next stage
+		return m, m.cycleJobStageFilter()
+	case "w":
+		// Toggle line wrapping (kept for the rest of the session)
+		m.jobLogWrap = !m.jobLogWrap
+		m.jobLogHScroll = 0
+		m.jobLogReady = false
+		return m, nil
+	case "h", "left":
+		// Scroll left
+		if m.jobLogFocused {
+			m.scrollJobLogTo(m.jobLogHScroll - m.repeat()*m.scrollStep())
+		}
+		return m, nil
+	case "l", "right":
+		// Scroll right, stopping at the end of the longest line (nothing to scroll when wrapped)
+		if m.jobLogFocused && !m.jobLogWrap {
+			m.scrollJobLogTo(m.jobLogHScroll + m.repeat()*m.scrollStep())
+		}
+		return m, nil
+	case "j", "down":
+		if m.jobLogFocused {
+			maxLine := strings.Count(m.jobLog, "\n")
+			if m.jobLogCursor < maxLine {
+				m.jobLogCursor = min(m.jobLogCursor+m.repeat(), maxLine)
+				if m.visualLineMode {
+					m.visualEndLine = m.jobLogCursor
+				}
+			}
+			m.keepJobLogCursorInView()
+		} else {
+			// Next job in list
+			return m, m.moveJobSelection(m.repeat())
+		}
+	case "k", "up":
+		if m.jobLogFocused {
+			if m.jobLogCursor > 0 {
+				m.jobLogCursor = max(m.jobLogCursor-m.repeat(), 0)
+				if m.visualLineMode {
+					m.visualEndLine = m.jobLogCursor
+				}
+			}
+			m.keepJobLogCursorInView()
+		} else {
+			// Previous job in list
+			return m, m.moveJobSelection(-m.repeat())
+		}
+	case "ctrl+d":
+		if m.jobLogFocused {
+			m.jobLogViewport.HalfPageDown()
+			maxLine := strings.Count(m.jobLog, "\n")
+			m.jobLogCursor += m.jobLogViewport.Height / 2
+			if m.jobLogCursor > maxLine {
+				m.jobLogCursor = maxLine
+			}
+			m.keepJobLogCursorInView()
+			if m.visualLineMode {
+				m.visualEndLine = m.jobLogCursor
+			}
+		}
+	case "ctrl+u":
+		if m.jobLogFocused {
+			m.jobLogViewport.HalfPageUp()
+			m.jobLogCursor -= m.jobLogViewport.Height / 2
+			if m.jobLogCursor < 0 {
+				m.jobLogCursor = 0
+			}
+			m.keepJobLogCursorInView()
+			if m.visualLineMode {
+				m.visualEndLine = m.jobLogCursor
+			}
+		}
+	case "g":
+		if m.jobLogFocused {
+			if m.jobLogLastKey == "g" && m.count > 0 {
+				// Ngg - go to line N
+				m.jobLogLastKey = ""
+				m.jobLogCursor = m.countLine(0, strings.Count(m.jobLog, "\n"))
+				if m.visualLineMode {
+					m.visualEndLine = m.jobLogCursor
+				}
+				m.keepJobLogCursorInView()
+				return m, nil
+			}
+			if m.jobLogLastKey == "g" {
+				// gg - go to top
+				m.jobLogViewport.GotoTop()
+				m.jobLogCursor = 0
+				if m.visualLineMode {
+					m.visualEndLine = m.jobLogCursor
+				}
+				m.jobLogLastKey = "gg" // Mark that we did gg
+				return m, nil
+			}
+			// Keep the count for the second g
+			m.countPrefix = m.count
+			m.jobLogLastKey = "g"
+			return m, nil
+		}
+	case "G":
+		if m.jobLogFocused {
+			maxLine := strings.Count(m.jobLog, "\n")
+			if m.count > 0 {
+				// NG - go to line N
+				m.jobLogCursor = m.countLine(maxLine, maxLine)
+				m.keepJobLogCursorInView()
+			} else {
+				m.jobLogViewport.GotoBottom()
+				m.jobLogCursor = maxLine
+			}
+			if m.visualLineMode {
+				m.visualEndLine = m.jobLogCursor
+			}
+		}
+	case "V":
+		// Toggle visual line mode
+		if m.jobLogFocused {
+			if m.visualLineMode {
+				m.visualLineMode = false
+			} else {
+				m.visualLineMode = true
+				m.visualStartLine = m.jobLogCursor
+				m.visualEndLine = m.jobLogCursor
+			}
+		}
+	case "y":
+		if m.jobLog == "" {
+			m.jobLogLastKey = ""
+			return m, nil
+		}
+		lines := strings.Split(m.jobLog, "\n")
+		if m.visualLineMode {
+			// Copy selected lines
+			startLine := m.visualStartLine
+			endLine := m.visualEndLine
+			if startLine > endLine {
+				startLine, endLine = endLine, startLine
+			}
+			// Clamp to valid range
+			if startLine < 0 {
+				startLine = 0
+			}
+			if endLine >= len(lines) {
+				endLine = len(lines) - 1
+			}
+			selected := strings.Join(lines[startLine:endLine+1], "\n")
+			cleanLog := stripANSI(selected)
+			if err := copyToClipboard(cleanLog); err != nil {
+				m.statusMsg = "Copy failed: " + err.Error()
+			} else {
+				m.statusMsg = fmt.Sprintf("Copied %d lines!", endLine-startLine+1)
+			}
+			m.visualLineMode = false
+		} else if m.jobLogLastKey == "gg" {
+			// ggy - yank entire log
+			cleanLog := stripANSI(m.jobLog)
+			if err := copyToClipboard(cleanLog); err != nil {
+				m.statusMsg = "Copy failed: " + err.Error()
+			} else {
+				m.statusMsg = fmt.Sprintf("Yanked all %d lines!", len(lines))
+			}
+		} else if m.jobLogLastKey == "y" {
+			// yy - yank current line
+			if m.jobLogCursor >= 0 && m.jobLogCursor < len(lines) {
+				cleanLine := stripANSI(lines[m.jobLogCursor])
+				if err := copyToClipboard(cleanLine); err != nil {
+					m.statusMsg = "Copy failed: " + err.Error()
+				} else {
+					m.statusMsg = "Yanked line!"
+				}
+			}
+		} else {
+			// First y - wait for second key
+			m.jobLogLastKey = "y"
+			return m, nil
+		}
+		m.jobLogLastKey = ""
+	case "Y":
+		// Copy the failing tail of the log
+		if m.jobLog == "" {
+			return m, nil
+		}
+		markers, tail := m.settings.ErrorSummary()
+		summary := errorSummary(m.jobLog, markers, tail)
+		if err := copyToClipboard(strings.Join(summary, "\n")); err != nil {
+			m.statusMsg = "Copy failed: " + err.Error()
+		} else {
+			m.statusMsg = fmt.Sprintf("Copied %d lines of error summary!", len(summary))
+		}
+		m.jobLogLastKey = ""
+		m.visualLineMode = false
+	case "0":
+		// Go to start of line
+		if m.jobLogFocused {
+			m.jobLogHScroll = 0
+		}
+	case "$":
+		// Go to end of the longest line
+		if m.jobLogFocused && m.jobLog != "" && !m.jobLogWrap {
+			m.scrollJobLogTo(m.jobLogMaxWidth)
+		}
+	}
+	return m, nil
+}
+
+// scrollStep returns how many columns h/l scroll the job log and README
+func (m *MainScreen) scrollStep() int {
+	return m.settings.ScrollStep()
+}
+
+// scrollJobLogTo scrolls the job log sideways to offset, kept between the
+// start of the lines and the end of the longest one
+func (m *MainScreen) scrollJobLogTo(offset int) {
+	viewWidth := m.jobLogViewport.Width
+	if viewWidth <= 0 {
+		viewWidth = 80
+	}
+	m.jobLogHScroll = clampHScroll(offset, m.jobLogMaxWidth, viewWidth)
+}
+
+// clampHScroll limits a horizontal scroll offset so the view never scrolls
+// past the end of content that is contentWidth columns wide
+func clampHScroll(offset, contentWidth, viewWidth int) int {
+	return max(min(offset, contentWidth-viewWidth), 0)
+}
+
+// maxLineWidth returns the display width of the widest line
+func maxLineWidth(lines []string) int {
+	widest := 0
+	for _, line := range lines {
+		widest = max(widest, lipgloss.Width(line))
+	}
+	return widest
+}
+
+// setJobLogContent loads the job log into the viewport
+func (m *MainScreen) setJobLogContent() {
+	// Keep ANSI colors the terminal can show but clean up problematic characters
+	cleanLog := downsampleLogColors(m.jobLog, m.logColorMode)
+	// Replace tabs with spaces (tabs mess up width calculation)
+	cleanLog = strings.ReplaceAll(cleanLog, "\t", "    ")
+	// Remove carriage returns (CI logs use these for progress updates)
+	cleanLog = strings.ReplaceAll(cleanLog, "\r", "")
+	lines := strings.Split(cleanLog, "\n")
+	m.jobLogMaxWidth = maxLineWidth(lines)
+	if m.jobLogWrap {
+		// Wrap, remembering where each logical line starts so the
+		// cursor and visual selection keep working on real lines
+		rows, starts := wrapLogLines(lines, m.jobLogViewport.Width)
+		m.jobLogRowStarts = starts
+		m.jobLogViewport.SetContent(strings.Join(rows, "\n"))
+		return
+	}
+	// Don't wrap - truncate lines to preserve line numbers for visual selection
+	m.jobLogRowStarts = nil
+	m.jobLogViewport.SetContent(cleanLog)
+}
+
+// handleJobLogSearchInput handles keyboard input for the job log '/' prompt
+func (m *MainScreen) handleJobLogSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "escape":
+		m.jobLogSearchActive = false
+		return m, nil
+	case "enter":
+		m.jobLogSearchActive = false
+		m.jobLogSearch = m.jobLogSearchInput.Value()
+		m.updateJobLogMatches()
+		if m.jobLogSearch == "" {
+			return m, nil
+		}
+		if len(m.jobLogMatches) == 0 {
+			m.statusMsg = "Pattern not found: " + m.jobLogSearch
+			return m, nil
+		}
+		// Like less, search forward from the cursor and wrap around
+		next := sort.SearchInts(m.jobLogMatches, m.jobLogCursor+1)
+		m.jumpToLogMatch(next % len(m.jobLogMatches))
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.jobLogSearchInput, cmd = m.jobLogSearchInput.Update(msg)
+	return m, cmd
+}
+
+// updateJobLogMatches recomputes search matches after the query or log changes
+func (m *MainScreen) updateJobLogMatches() {
+	m.jobLogMatches = findLogMatches(m.jobLog, m.jobLogSearch)
+	if m.jobLogMatchIdx >= len(m.jobLogMatches) {
+		m.jobLogMatchIdx = 0
+	}
+}
+
+// clearJobLogSearch drops the search query and its highlights
+func (m *MainScreen) clearJobLogSearch() {
+	m.jobLogSearch = ""
+	m.jobLogMatches = nil
+	m.jobLogMatchIdx = 0
+}
+
+// jumpToLogMatch moves the log cursor to a search match and centers it
+func (m *MainScreen) jumpToLogMatch(idx int) {
+	m.jobLogMatchIdx = idx
+	m.jobLogCursor = m.jobLogMatches[idx]
+	if m.visualLineMode {
+		m.visualEndLine = m.jobLogCursor
+	}
+	first, _ := m.jobLogRows(m.jobLogCursor)
+	m.jobLogViewport.SetYOffset(first - m.jobLogViewport.Height/2)
+}
+
+// jobLogRows returns the first and last display rows of a log line,
+// which differ only when wrapping splits it across several rows
+func (m *MainScreen) jobLogRows(line int) (int, int) {
+	if !m.jobLogWrap || line < 0 || line >= len(m.jobLogRowStarts) {
+		return line, line
+	}
+	first := m.jobLogRowStarts[line]
+	last := m.jobLogViewport.TotalLineCount() - 1
+	if line+1 < len(m.jobLogRowStarts) {
+		last = m.jobLogRowStarts[line+1] - 1
+	}
+	return first, last
+}
+
+// keepJobLogCursorInView scrolls the log viewport so the cursor line is visible
+func (m *MainScreen) keepJobLogCursorInView() {
+	first, last := m.jobLogRows(m.jobLogCursor)
+	bottom := m.jobLogViewport.YOffset + m.jobLogViewport.Height - 1
+	if last > bottom {
+		m.jobLogViewport.SetYOffset(last - m.jobLogViewport.Height + 1)
+	}
+	if first < m.jobLogViewport.YOffset {
+		m.jobLogViewport.SetYOffset(first)
+	}
+}
+
+// visibleTabs returns the content tabs in the order they're shown
+func (m *MainScreen) visibleTabs() []ContentTab {
+	if len(m.tabs) == 0 {
+		return []ContentTab{TabFiles, TabMRs, TabPipelines, TabReleases}
+	}
+	return m.tabs
+}
+
+// tabVisible reports whether the tabs config shows tab
+func (m *MainScreen) tabVisible(tab ContentTab) bool {
+	return slices.Contains(m.visibleTabs(), tab)
+}
+
+func (m *MainScreen) switchTab(tab ContentTab) tea.Cmd {
+	// Hidden tabs, e.g. from a link or the last session, fall back to files
+	if !m.tabVisible(tab) {
+		tab = TabFiles
+	}
+	m.contentTab = tab
+	m.selectedContent = 0
+	m.fileContent = ""
+	m.rememberProject()
+
+	if m.selectedProject == nil || m.isDemo {
+		return nil
+	}
+
+	switch tab {
+	case TabFiles:
+		if len(m.files) == 0 && !m.repoEmpty {
+			m.loading = true
+			m.loadingMsg = "Loading files..."
+			m.currentPath = nil
+			cmd := m.loadProjectContent()
+			m.retryCmd = cmd
+			return cmd
+		}
+	case TabMRs:
+		if len(m.mergeRequests) == 0 {
+			m.loading = true
+			m.loadingMsg = "Loading merge requests..."
+			cmd := m.loadMRs()
+			m.retryCmd = cmd
+			return cmd
+		}
+	case TabPipelines:
+		if len(m.pipelines) == 0 {
+			m.loading = true
+			m.loadingMsg = "Loading pipelines..."
+			cmd := m.loadPipelines()
+			m.retryCmd = cmd
+			return cmd
+		}
+	case TabReleases:
+		if len(m.releases) == 0 {
+			m.loading = true
+			m.loadingMsg = "Loading releases..."
+			cmd := m.loadReleases()
+			m.retryCmd = cmd
+			return cmd
+		}
+	}
+	return nil
+}
+
+func (m *MainScreen) getContentCount() int {
+	if !m.tabVisible(m.contentTab) {
+		return 0
+	}
+	switch m.contentTab {
+	case TabFiles:
+		return len(m.files)
+	case TabMRs:
+		return len(m.visibleMRs())
+	case TabPipelines:
+		return len(m.visiblePipelines())
+	case TabReleases:
+		if m.showTags {
+			return len(m.tags)
+		}
+		return len(m.releases)
+	}
+	return 0
+}
+
+// View renders the screen
+func (m *MainScreen) View() string {
+	if m.width == 0 || m.height == 0 {
+		return "Loading..."
+	}
+
+	if m.errMsg != "" {
+		return fmt.Sprintf("Error: %s\n\nPress q to quit", m.errMsg)
+	}
+
+	if m.confirm.Active {
+		return m.confirm.View(m.width, m.height-1) + styles.StatusBar.Width(m.width).Render("")
+	}
+
+	// If popup is shown, render only the popup
+	if m.showJobLogPopup {
+		return m.renderJobLogPopup()
+	}
+	if m.showBranchPopup {
+		return m.renderBranchPopup()
+	}
+	if m.showRunnersPopup {
+		return m.renderRunnersPopup()
+	}
+	if m.showHelpPopup {
+		return m.renderHelpPopup()
+	}
+	if m.showMRDetailPopup {
+		return m.renderMRDetailPopup()
+	}
+	if m.showEnvPopup {
+		return m.renderEnvPopup()
+	}
+	if m.showVarsPopup {
+		return m.renderVarsPopup()
+	}
+	if m.showSnippetsPopup {
+		return m.renderSnippetsPopup()
+	}
+	if m.showWikiPopup {
+		return m.renderWikiPopup()
+	}
+	if m.showContributorsPopup {
+		return m.renderContributorsPopup()
+	}
+	if m.showLabelsPopup {
+		return m.renderLabelsPopup()
+	}
+	if m.showActivityPopup {
+		return m.renderActivityPopup()
+	}
+	if m.showCodeSearch {
+		return m.renderCodeSearch()
+	}
+	if m.showRecentPopup {
+		return m.renderRecentPopup()
+	}
+	if m.showComparePopup {
+		return m.renderComparePopup()
+	}
+	if m.showCommitDiffPopup {
+		return m.renderCommitDiffPopup()
+	}
+	if m.showProjectInfo {
+		return m.renderProjectInfoPopup()
+	}
+	if m.showMyMRsPopup {
+		return m.renderMyMRsPopup()
+	}
+	if m.showReleasePopup {
+		return m.renderReleasePopup()
+	}
+	if m.showFolderBrowser {
+		return m.renderFolderBrowser()
+	}
+
+	// Calculate dimensions using the layout ratios
+	contentHeight := m.height - config.StatusBarHeight
+	navWidth := m.navigatorWidth()
+	contentWidth := m.width - navWidth
+
+	// Render panels
+	navPanel := m.renderNavigatorPanel(navWidth, contentHeight)
+	contentPanel := m.renderContentPanel(contentWidth, contentHeight)
+
+	// Combine all
+	main := lipgloss.JoinHorizontal(lipgloss.Top, navPanel, contentPanel)
+	statusBar := m.renderStatusBar()
+
+	return main + "\n" + statusBar
+}
+
+// layoutRatios returns the navigator width and README height ratios
+func (m *MainScreen) layoutRatios() (navigator, readme float64) {
+	return m.settings.LayoutRatios()
+}
+
+// navigatorWidth returns the navigator panel's width
+func (m *MainScreen) navigatorWidth() int {
+	navigator, _ := m.layoutRatios()
+	return int(float64(m.width) * navigator)
+}
+
+// contentListHeight returns the list section's height in a content panel of
+// the given height; the rest goes to the README in the Files tab at the root
+func (m *MainScreen) contentListHeight(height int) int {
+	if m.contentTab != TabFiles || len(m.currentPath) > 0 || m.readmeContent == "" {
+		return height
+	}
+	_, readme := m.layoutRatios()
+	return height - int(float64(height)*readme)
+}
+
+// resizePanel grows or shrinks the focused panel by one step and saves the
+// new layout to the config
+func (m *MainScreen) resizePanel(grow bool) {
+	step := config.LayoutRatioStep
+	if !grow {
+		step = -step
+	}
+	navigator, readme := m.layoutRatios()
+	switch m.focusedPanel {
+	case PanelNavigator:
+		navigator += step
+	case PanelContent:
+		navigator -= step
+	case PanelReadme:
+		readme += step
+	}
+	// Round away float drift so saved ratios stay readable
+	navigator, readme = config.ClampLayoutRatios(math.Round(navigator*100)/100, math.Round(readme*100)/100)
+	m.settings.NavigatorWidthRatio, m.settings.ReadmeHeightRatio = navigator, readme
+	m.statusMsg = fmt.Sprintf("Layout: navigator %.0f%%, README %.0f%%", navigator*100, readme*100)
+
+	if m.isDemo {
+		return
+	}
+	cfg, err := config.LoadLazyLabConfigForUpdate()
+	if err != nil {
+		m.statusMsg = "Failed to save layout, config didn't load: " + err.Error()
+		return
+	}
+	cfg.NavigatorWidthRatio, cfg.ReadmeHeightRatio = navigator, readme
+	if err := config.SaveLazyLabConfig(cfg); err != nil {
+		m.statusMsg = "Failed to save layout: " + err.Error()
+	}
+}
+
+// loadingText returns loadingMsg behind the spinner
+func (m *MainScreen) loadingText() string {
+	return m.spinner.View() + " " + m.loadingMsg
+}
+
+// navScrollWindow returns how many navigator rows fit in a panel of the given
+// height and the first visible row, keeping the selected node in view.
+func (m *MainScreen) navScrollWindow(height int) (visibleLines, scrollOffset int) {
+	visibleLines = height - config.BorderSize - 2 - m.navHeaderLines() // account for borders, padding and the filter
+	if visibleLines < 1 {
+		visibleLines = 10
+	}
+	if m.selectedNodeIdx >= visibleLines {
+		scrollOffset = m.selectedNodeIdx - visibleLines + 1
+	}
+	return visibleLines, scrollOffset
+}
+
+func (m *MainScreen) renderNavigatorPanel(width, height int) string {
+	var content strings.Builder
+
+	if m.navFilterActive {
+		content.WriteString(m.navFilterInput.View() + "\n")
+	} else if m.navFilter != "" {
+		content.WriteString(styles.DimmedText.Render(truncateWidth("/"+m.navFilter, width-config.BorderSize-2)) + "\n")
+	}
+
+	if m.loading && len(m.treeNodes) == 0 {
+		content.WriteString(m.loadingText())
+	} else if len(m.treeNodes) == 0 && m.navFilter != "" {
+		content.WriteString(styles.DimmedText.Render("No matches"))
+	} else if len(m.treeNodes) == 0 {
+		content.WriteString(styles.DimmedText.Render("No groups or projects"))
+	} else {
+		visibleLines, scrollOffset := m.navScrollWindow(height)
+		endIdx := scrollOffset + visibleLines
+		if endIdx > len(m.treeNodes) {
+			endIdx = len(m.treeNodes)
+		}
+
+		for i := scrollOffset; i < endIdx; i++ {
+			node := m.treeNodes[i]
+
+			// Build indent based on depth
+			indent := strings.Repeat("  ", node.Depth)
+
+			// Build icon
+			icon := ""
+			if node.Type == "group" {
+				if m.expandedGroups[node.ID] {
+					icon = "▼ "
+				} else {
+					icon = "▶ "
+				}
+			} else {
+				icon = "  📦 "
+			}
+
+			line := indent + icon + node.Name
+
+			// Open MR/issue badge for projects, e.g. "!3 ⊙2"
+			badge := ""
+			if node.Type == "project" {
+				badge = projectCountsBadge(m.projectCounts[node.ID])
+			}
+
+			// Truncate if too long
+			maxLineLen := width - config.BorderSize - 4
+			if badge != "" {
+				maxLineLen -= lipgloss.Width(badge) + 1
+			}
+			if maxLineLen > 0 {
+				line = truncateWidth(line, maxLineLen)
+			}
+
+			if i == m.selectedNodeIdx {
+				line = styles.SelectedItem.Render("> " + line)
+			} else {
+				line = styles.NormalItem.Render("  " + line)
+			}
+			if badge != "" {
+				line += " " + styles.DimmedText.Render(badge)
+			}
+			content.WriteString(line + "\n")
+		}
+
+		// Show scroll indicator
+		if len(m.treeNodes) > visibleLines {
+			content.WriteString(styles.DimmedText.Render(fmt.Sprintf("\n[%d/%d]", m.selectedNodeIdx+1, len(m.treeNodes))))
+		}
+	}
+
+	title := "Navigator"
+	if m.groupFilter != "" {
+		title += " · " + m.groupFilter
+	}
+	return components.SimpleBorderedPanel(title, content.String(), width, height, m.focusedPanel == PanelNavigator)
+}
+
+func (m *MainScreen) renderContentPanel(width, height int) string {
+	// Split: file list on top, README below (only in Files tab at root)
+	listHeight := m.contentListHeight(height)
+
+	// Build the file/content list panel
+	listPanel := m.renderListSection(width, listHeight)
+
+	if listHeight == height {
+		return listPanel
+	}
+
+	// Build the README panel
+	readmePanel := m.renderReadmeSection(width, height-listHeight)
+	return lipgloss.JoinVertical(lipgloss.Left, listPanel, readmePanel)
+}
+
+func (m *MainScreen) renderListSection(width, height int) string {
+	var content strings.Builder
+
+	// Project header with branch and last commit
+	if m.selectedProject != nil {
+		projectHeader := styles.SelectedItem.Render(m.selectedProject.Name)
+		if m.currentBranch != "" {
+			projectHeader += styles.DimmedText.Render(" (" + m.currentBranch + ")")
+		}
+		if stats := projectStatsBadge(m.projectStats); stats != "" && lipgloss.Width(projectHeader+stats) <= width-config.BorderSize-2 {
+			projectHeader += styles.DimmedText.Render(stats)
+		}
+		badgeWidth := width - config.BorderSize - 2 - lipgloss.Width(projectHeader)
+		if badges := projectBadges(m.projectLanguages, m.selectedProject.Topics, badgeWidth); badges != "" {
+			projectHeader += styles.DimmedText.Render(badges)
+		}
+		content.WriteString(projectHeader + "\n")
+
+		// Show last commit from current branch
+		for _, b := range m.branches {
+			if b.Name == m.currentBranch && b.Commit.Title != "" {
+				commitInfo := styles.DimmedText.Render("Last commit: ") + truncateString(b.Commit.Title, width-20)
+				if b.Commit.AuthorName != "" {
+					commitInfo += styles.DimmedText.Render(" by " + b.Commit.AuthorName)
+				}
+				content.WriteString(commitInfo + "\n")
+				break
+			}
+		}
+	}
+
+	// Tab header
+	for _, tab := range m.visibleTabs() {
+		name := contentTabNames[tab]
+		if tab == TabPipelines {
+			name = m.pipelinesTabName()
+		}
+		if tab == m.contentTab {
+			content.WriteString(styles.StatusBarKey.Render("[" + name + "]"))
+		} else {
+			content.WriteString(styles.DimmedText.Render(" " + name + " "))
+		}
+		content.WriteString(" ")
+	}
+	content.WriteString("\n")
+
+	// Path breadcrumb for files
+	if m.contentTab == TabFiles && len(m.currentPath) > 0 {
+		content.WriteString(styles.DimmedText.Render("/"+strings.Join(m.currentPath, "/")) + "\n")
+	}
+	// Go-to-ref prompt for files
+	if m.contentTab == TabFiles && m.refJumpActive {
+		content.WriteString(m.refJumpInput.View() + "\n")
+	}
+	// Filter line for merge requests
+	if m.mrFilterLines() > 0 {
+		if m.mrFilterActive {
+			content.WriteString(m.mrFilterInput.View() + "\n")
+		} else {
+			filterInfo := ""
+			if m.mrFilter != "" {
+				filterInfo = "/" + m.mrFilter + " "
+			}
+			if m.mrHideDrafts {
+				filterInfo += "(drafts hidden)"
+			}
+			content.WriteString(styles.DimmedText.Render(strings.TrimSpace(filterInfo)) + "\n")
+		}
+	}
+	content.WriteString("\n")
+
+	if m.selectedProject == nil {
+		content.WriteString(styles.DimmedText.Render("Select a project"))
+	} else if m.loading {
+		content.WriteString(m.loadingText())
+	} else {
+		// Calculate visible lines for scrolling
+		visibleLines := height - 6 - m.mrFilterLines() // account for headers and borders
+		if visibleLines < 1 {
+			visibleLines = 10
+		}
+
+		switch m.contentTab {
+		case TabFiles:
+			// If viewing a file, show its content
+			if m.viewingFile && m.fileContent != "" {
+				// Show file path
+				pathLine := m.viewingFilePath
+				if m.viewingFileRef != "" {
+					pathLine += " @ " + shortSHA(m.viewingFileRef)
+				}
+				if m.fileImage != nil {
+					pathLine += " " + m.fileContent
+				} else if m.fileTruncated {
+					pathLine += " " + truncatedNote
+				} else if m.fileTooLarge() {
+					pathLine += " " + largeFileNote
+				}
+				content.WriteString(styles.DimmedText.Render(pathLine) + "\n")
+				hint := "Esc: back | j/k: scroll | g/G: top/bottom | :: line | e: editor"
+				if m.fileIsMarkdown {
+					if m.fileShowRaw {
+						hint += " | m: rendered"
+					} else {
+						hint += " | m: raw"
+					}
+				}
+				content.WriteString(styles.DimmedText.Render(hint) + "\n\n")
+
+				// Use viewport for file content
+				fileViewHeight := visibleLines - 3
+				innerWidth := width - 4
+				if !m.fileViewReady {
+					m.fileViewport = viewport.New(innerWidth, fileViewHeight)
+					// Apply syntax highlighting
+					m.fileViewport.SetContent(m.fileViewContent())
+					m.fileViewReady = true
+					if m.fileJumpPending {
+						m.scrollToFileLine(m.fileFlashLine)
+						m.fileJumpPending = false
+					}
+				}
+				content.WriteString(m.fileViewport.View())
+
+				// Line jump prompt
+				if m.lineJumpActive {
+					content.WriteString("\n" + m.lineJumpInput.View())
+				}
+
+				// Scroll indicator
+				if m.fileViewport.TotalLineCount() > fileViewHeight {
+					content.WriteString(styles.DimmedText.Render(fmt.Sprintf("\n[%d%%]", int(m.fileViewport.ScrollPercent()*100))))
+				}
+			} else if m.repoEmpty {
+				content.WriteString(styles.DimmedText.Render("This repository is empty"))
+			} else {
+				// Show file list
+				endIdx := m.fileScrollOffset + visibleLines
+				if endIdx > len(m.files) {
+					endIdx = len(m.files)
+				}
+				for i := m.fileScrollOffset; i < endIdx; i++ {
+					f := m.files[i]
+					icon := "📄"
+					if f.Type == "tree" && m.expandedDirs[f.Path] {
+						icon = "📂"
+					} else if f.Type == "tree" {
+						icon = "📁"
+					}
+					// Build commit info
+					commitInfo := ""
+					if f.LastCommit != nil {
+						commitInfo = fmt.Sprintf(" %s @%s", timeAgo(f.LastCommit.AuthoredDate), f.LastCommit.AuthorName)
+					}
+					line := fmt.Sprintf("%s%s %s", strings.Repeat("  ", m.fileDepth(f)), icon, f.Name)
+					meta := styles.DimmedText.Render(commitInfo)
+					if i == m.selectedContent {
+						line = styles.SelectedItem.Render("> "+line) + meta
+					} else {
+						line = "  " + line + meta
+					}
+					content.WriteString(line + "\n")
+				}
+				// Show scroll indicator
+				if len(m.files) > visibleLines {
+					content.WriteString(styles.DimmedText.Render(fmt.Sprintf("\n[%d/%d]", m.selectedContent+1, len(m.files))))
+				}
+				// Show selected file info
+				if m.selectedContent < len(m.files) {
+					f := m.files[m.selectedContent]
+					fileType := "File"
+					if f.Type == "tree" {
+						fileType = "Directory"
+					}
+					infoLine := fileType + ": " + f.Path
+					if f.LastCommit != nil && f.LastCommit.Title != "" {
+						infoLine += " | " + truncateString(f.LastCommit.Title, width-len(infoLine)-10)
+					}
+					content.WriteString("\n" + styles.DimmedText.Render(infoLine))
+				}
+			}
+		case TabMRs:
+			mrs := m.visibleMRs()
+			endIdx := m.fileScrollOffset + visibleLines
+			if endIdx > len(mrs) {
+				endIdx = len(mrs)
+			}
+			for i := m.fileScrollOffset; i < endIdx; i++ {
+				mr := mrs[i]
+				icon := "○"
+				if mr.Draft {
+					icon = "◐"
+				}
+				// Build reviewer string
+				reviewerStr := ""
+				if len(mr.Reviewers) > 0 {
+					reviewerStr = " → " + mr.Reviewers[0].Username
+					if len(mr.Reviewers) > 1 {
+						reviewerStr += fmt.Sprintf(" +%d", len(mr.Reviewers)-1)
+					}
+				}
+				// Head pipeline icon and approvals, e.g. "✓ ✔2/2"
+				status := ""
+				if mr.HeadPipeline != nil {
+					status += " " + styles.PipelineStatus(mr.HeadPipeline.Status).Render(styles.PipelineIcon(mr.HeadPipeline.Status))
+				}
+				if badge := approvalBadge(m.mrApprovals[mr.IID]); badge != "" {
+					approvalStyle := styles.DimmedText
+					if a := m.mrApprovals[mr.IID]; a.ApprovalsRequired > 0 && a.ApprovalsLeft == 0 {
+						approvalStyle = lipgloss.NewStyle().Foreground(styles.ColorSuccess)
+					}
+					status += " " + approvalStyle.Render(badge)
+				}
+				line := fmt.Sprintf("%s !%d %s", icon, mr.IID, truncateString(mr.Title, width-45-lipgloss.Width(status))) + status
+				meta := styles.DimmedText.Render(fmt.Sprintf(" @%s%s %s", mr.Author.Username, reviewerStr, timeAgo(mr.CreatedAt)))
+				if i == m.selectedContent {
+					line = styles.SelectedItem.Render("> ") + line + meta
+				} else {
+					line = "  " + line + meta
+				}
+				content.WriteString(line + "\n")
+			}
+			if len(m.mergeRequests) == 0 {
+				content.WriteString(styles.DimmedText.Render("No open merge requests"))
+			} else if len(mrs) == 0 {
+				content.WriteString(styles.DimmedText.Render(fmt.Sprintf("No merge requests match the filter (%d hidden)", len(m.mergeRequests))))
+			} else {
+				if len(mrs) > visibleLines || len(mrs) != len(m.mergeRequests) {
+					content.WriteString(styles.DimmedText.Render(fmt.Sprintf("\n[%d/%d]", m.selectedContent+1, len(mrs))))
+				}
+				if m.loadingMore {
+					content.WriteString("\n" + m.spinner.View() + styles.DimmedText.Render(" loading more…"))
+				}
+				// Show selected MR info
+				if m.selectedContent < len(mrs) {
+					mr := mrs[m.selectedContent]
+					mrInfo := fmt.Sprintf("%s → %s", mr.SourceBranch, mr.TargetBranch)
+					if mr.HasConflicts {
+						mrInfo += " (conflicts)"
+					}
+					content.WriteString("\n" + styles.DimmedText.Render(mrInfo))
+				}
+			}
+		case TabPipelines:
+			pipelines := m.visiblePipelines()
+			rows := m.pipelineRows(pipelines)
+			m.keepPipelineInView(rows, visibleLines)
+			endIdx := min(m.pipelineRowOffset+visibleLines, len(rows))
+			for r := m.pipelineRowOffset; r < endIdx; r++ {
+				row := rows[r]
+				if !row.isPipeline() {
+					content.WriteString(renderPipelineSubRow(row) + "\n")
+					continue
+				}
+				i := row.pipeline
+				p := pipelines[i]
+				icon := styles.PipelineIcon(p.Status)
+				statusStyle := styles.PipelineStatus(p.Status)
+
+				// Build job stages icons
+				stagesStr := ""
+				if jobs, ok := m.pipelineJobs[p.ID]; ok && len(jobs) > 0 {
+					// Build stage icons with names
+					for _, stage := range groupJobsByStage(jobs) {
+						stageIcon := styles.PipelineIcon(stage.status)
+						stageStyle := styles.PipelineStatus(stage.status)
+						stagesStr += stageStyle.Render(stageIcon) + styles.DimmedText.Render("("+stage.name+")") + " "
+					}
+				} else {
+					// No jobs loaded yet - show status text for pending/created pipelines
+					stagesStr = statusStyle.Render("(" + p.Status + ")")
+				}
+
+				// Build meta info: user, time, source
+				userStr := ""
+				if p.User.Username != "" {
+					userStr = "@" + p.User.Username
+				}
+				durationStr := ""
+				if d, ok := pipelineDuration(p, m.pipelineJobs[p.ID]); ok {
+					durationStr = " " + formatDuration(d)
+				}
+				meta := styles.DimmedText.Render(fmt.Sprintf(" %s %s%s %s", userStr, p.Source, durationStr, timeAgo(p.CreatedAt)))
+				if badge := coverageBadge(m.pipelineCoverage(p), m.coverageGoal()); badge != "" {
+					meta = " " + badge + meta
+				}
+
+				// Truncate the ref rather than overflowing narrow panels
+				ref := p.Ref
+				fixedWidth := 2 + lipgloss.Width(fmt.Sprintf("%s #%d  %s", icon, p.IID, stagesStr)) + lipgloss.Width(meta)
+				if maxRef := width - config.BorderSize - 2 - fixedWidth; lipgloss.Width(ref) > maxRef {
+					ref = truncateString(ref, max(maxRef, 8))
+				}
+
+				line := fmt.Sprintf("%s #%d %s %s", statusStyle.Render(icon), p.IID, ref, stagesStr)
+				if i == m.selectedContent {
+					line = styles.SelectedItem.Render("> ") + line + meta
+				} else {
+					line = "  " + line + meta
+				}
+				content.WriteString(line + "\n")
+			}
+			if len(pipelines) == 0 {
+				if m.pipelineFilter != "" {
+					content.WriteString(styles.DimmedText.Render("No " + m.pipelineFilter + " pipelines"))
+				} else {
+					content.WriteString(styles.DimmedText.Render("No pipelines"))
+				}
+			} else {
+				if len(rows) > visibleLines {
+					content.WriteString(styles.DimmedText.Render(fmt.Sprintf("\n[%d/%d]", m.selectedContent+1, len(pipelines))))
+				}
+				if m.loadingMore {
+					content.WriteString("\n" + m.spinner.View() + styles.DimmedText.Render(" loading more…"))
+				}
+				// Show selected pipeline info
+				if m.selectedContent < len(pipelines) {
+					p := pipelines[m.selectedContent]
+					sha := p.SHA
+					if len(sha) > 8 {
+						sha = sha[:8]
+					}
+					pInfo := fmt.Sprintf("%s | %s", p.Status, sha)
+					if d, ok := pipelineDuration(p, m.pipelineJobs[p.ID]); ok {
+						if p.Status == "running" {
+							pInfo += " | running for " + formatDuration(d)
+						} else {
+							finishedAt := p.UpdatedAt
+							if p.FinishedAt != nil {
+								finishedAt = *p.FinishedAt
+							}
+							pInfo += fmt.Sprintf(" | took %s, finished %s", formatDuration(d), timeAgo(finishedAt))
+						}
+					}
+					if badge := coverageBadge(m.pipelineCoverage(p), m.coverageGoal()); badge != "" {
+						pInfo += " | coverage " + badge
+					}
+					content.WriteString("\n" + styles.DimmedText.Render(pInfo))
+				}
+			}
+		case TabReleases:
+			if m.showTags {
+				content.WriteString(m.renderTagList(width, visibleLines))
+				break
+			}
+			endIdx := m.fileScrollOffset + visibleLines
+			if endIdx > len(m.releases) {
+				endIdx = len(m.releases)
+			}
+			for i := m.fileScrollOffset; i < endIdx; i++ {
+				rel := m.releases[i]
+				// Count downloadable assets (links + source archives)
+				assetCount := len(rel.Assets.Links) + len(rel.Assets.Sources)
+				assetStr := ""
+				if assetCount > 0 {
+					assetStr = fmt.Sprintf(" [%d]", assetCount)
+				}
+
+				// Format release time
+				relTime := timeAgo(rel.CreatedAt)
+				if rel.ReleasedAt != nil {
+					relTime = timeAgo(*rel.ReleasedAt)
+				}
+
+				line := fmt.Sprintf("📦 %s%s", rel.TagName, assetStr)
+				meta := styles.DimmedText.Render(fmt.Sprintf(" @%s %s", rel.Author.Username, relTime))
+				if i == m.selectedContent {
+					line = styles.SelectedItem.Render("> ") + line + meta
+				} else {
+					line = "  " + line + meta
+				}
+				content.WriteString(line + "\n")
+			}
+			if len(m.releases) == 0 {
+				content.WriteString(styles.DimmedText.Render("No releases"))
+			} else {
+				if len(m.releases) > visibleLines {
+					content.WriteString(styles.DimmedText.Render(fmt.Sprintf("\n[%d/%d]", m.selectedContent+1, len(m.releases))))
+				}
+				// Show selected release info
+				if m.selectedContent < len(m.releases) {
+					rel := m.releases[m.selectedContent]
+					name := rel.Name
+					if name == "" {
+						name = rel.TagName
+					}
+					relInfo := fmt.Sprintf("%s | commit: %s", name, rel.Commit.ShortID)
+					content.WriteString("\n" + styles.DimmedText.Render(relInfo))
+				}
+			}
+		}
+	}
+
+	title := contentTabNames[m.contentTab]
+	if m.contentTab == TabPipelines {
+		title = m.pipelinesTabName()
+	}
+	if m.contentTab == TabReleases && m.showTags {
+		title = "Tags"
+	}
+	return components.SimpleBorderedPanel(title, content.String(), width, height, m.focusedPanel == PanelContent)
+}
+
+// renderTagList renders the tags view of the releases tab
+// jobStage is a pipeline stage with its jobs and combined status
+type jobStage struct {
+	name   string
+	status string
+	jobs   []gitlab.Job
+}
+
+// groupJobsByStage groups jobs into stages in pipeline order
+func groupJobsByStage(jobs []gitlab.Job) []jobStage {
+	// Sort jobs by ID to get correct stage order (earlier stages have lower IDs)
+	sortedJobs := make([]gitlab.Job, len(jobs))
+	copy(sortedJobs, jobs)
+	sort.Slice(sortedJobs, func(i, j int) bool {
+		return sortedJobs[i].ID < sortedJobs[j].ID
+	})
+
+	var stages []jobStage
+	index := make(map[string]int)
+	for _, job := range sortedJobs {
+		i, exists := index[job.Stage]
+		if !exists {
+			index[job.Stage] = len(stages)
+			stages = append(stages, jobStage{name: job.Stage, status: job.Status, jobs: []gitlab.Job{job}})
+			continue
+		}
+		stage := &stages[i]
+		stage.jobs = append(stage.jobs, job)
+		// If any job in stage failed, stage is failed
+		current := stage.status
+		if job.Status == "failed" {
+			stage.status = "failed"
+		} else if job.Status == "running" && current != "failed" {
+			stage.status = "running"
+		} else if job.Status == "pending" && current != "failed" && current != "running" {
+			stage.status = "pending"
+		}
+	}
+	return stages
+}
+
+// pipelineRow is one line of the pipelines list: a pipeline, or a stage or
+// job of an expanded pipeline
+type pipelineRow struct {
+	pipeline int         // index into the visible pipelines
+	stage    *jobStage   // stage header of an expanded pipeline
+	job      *gitlab.Job // job of an expanded pipeline
+	noJobs   bool        // expanded pipeline without loaded jobs
+}
+
+func (r pipelineRow) isPipeline() bool {
+	return r.stage == nil && r.job == nil && !r.noJobs
+}
+
+// pipelineRows flattens pipelines and the stages and jobs of expanded ones
+func (m *MainScreen) pipelineRows(pipelines []gitlab.Pipeline) []pipelineRow {
+	rows := make([]pipelineRow, 0, len(pipelines))
+	for i, p := range pipelines {
+		rows = append(rows, pipelineRow{pipeline: i})
+		if !m.expandedPipelines[p.ID] {
+			continue
+		}
+		jobs := m.pipelineJobs[p.ID]
+		if len(jobs) == 0 {
+			rows = append(rows, pipelineRow{pipeline: i, noJobs: true})
+			continue
+		}
+		for _, stage := range groupJobsByStage(jobs) {
+			rows = append(rows, pipelineRow{pipeline: i, stage: &stage})
+			for _, job := range stage.jobs {
+				rows = append(rows, pipelineRow{pipeline: i, job: &job})
+			}
+		}
+	}
+	return rows
+}
+
+// keepPipelineInView scrolls the pipelines list so the selected pipeline, and
+// as much of its expanded detail as fits, is visible
+func (m *MainScreen) keepPipelineInView(rows []pipelineRow, visibleLines int) {
+	first, last := -1, -1
+	for r, row := range rows {
+		if row.pipeline == m.selectedContent {
+			if first < 0 {
+				first = r
+			}
+			last = r
+		}
+	}
+	if first >= 0 {
+		if last >= m.pipelineRowOffset+visibleLines {
+			m.pipelineRowOffset = last - visibleLines + 1
+		}
+		if first < m.pipelineRowOffset || first >= m.pipelineRowOffset+visibleLines {
+			m.pipelineRowOffset = first
+		}
+	}
+	m.pipelineRowOffset = max(min(m.pipelineRowOffset, len(rows)-visibleLines), 0)
+}
+
+// jobDuration returns how long a job ran, or has been running
+func jobDuration(job gitlab.Job) (time.Duration, bool) {
+	if job.Duration > 0 {
+		return time.Duration(job.Duration * float64(time.Second)), true
+	}
+	if job.StartedAt != nil && job.Status == "running" {
+		return time.Since(*job.StartedAt), true
+	}
+	return 0, false
+}
+
+// renderPipelineSubRow renders a stage or job line of an expanded pipeline
+func renderPipelineSubRow(row pipelineRow) string {
+	switch {
+	case row.noJobs:
+		return "      " + styles.DimmedText.Render("no jobs loaded")
+	case row.stage != nil:
+		return "    " + styles.PipelineStatus(row.stage.status).Render(styles.PipelineIcon(row.stage.status)) + " " +
+			styles.DimmedText.Render(row.stage.name)
+	}
+	job := row.job
+	line := "      " + styles.PipelineStatus(job.Status).Render(styles.PipelineIcon(job.Status)) + " " + job.Name
+	if d, ok := jobDuration(*job); ok {
+		line += styles.DimmedText.Render(" " + formatDuration(d))
+	}
+	return line
+}
+
+func (m *MainScreen) renderTagList(width, visibleLines int) string {
+	var content strings.Builder
+
+	// Tags with a release are marked so they can be opened
+	released := make(map[string]bool)
+	for _, rel := range m.releases {
+		released[rel.TagName] = true
+	}
+
+	endIdx := m.fileScrollOffset + visibleLines
+	if endIdx > len(m.tags) {
+		endIdx = len(m.tags)
+	}
+	for i := m.fileScrollOffset; i < endIdx; i++ {
+		tag := m.tags[i]
+		releaseStr := ""
+		if tag.Release != nil || released[tag.Name] {
+			releaseStr = " 📦"
+		}
+
+		line := fmt.Sprintf("🏷 %s %s%s", tag.Name, styles.DimmedText.Render(shortSHA(tag.Commit.ID)), releaseStr)
+		meta := styles.DimmedText.Render(" " + timeAgo(tag.Commit.CommittedDate))
+		if i == m.selectedContent {
+			line = styles.SelectedItem.Render("> ") + line + meta
+		} else {
+			line = "  " + line + meta
+		}
+		content.WriteString(line + "\n")
+	}
+	if len(m.tags) == 0 {
+		content.WriteString(styles.DimmedText.Render("No tags"))
+		return content.String()
+	}
+	if len(m.tags) > visibleLines {
+		content.WriteString(styles.DimmedText.Render(fmt.Sprintf("\n[%d/%d]", m.selectedContent+1, len(m.tags))))
+	}
+	// Show selected tag info
+	if m.selectedContent < len(m.tags) {
+		tag := m.tags[m.selectedContent]
+		info := tag.Commit.Title
+		if msg := strings.TrimSpace(tag.Message); msg != "" {
+			info = strings.SplitN(msg, "\n", 2)[0]
+		}
+		content.WriteString("\n" + styles.DimmedText.Render(truncateString(info, width-6)))
+	}
+	return content.String()
+}
+
+func (m *MainScreen) renderReadmeSection(width, height int) string {
+	// Update viewport dimensions and content
+	innerWidth := width - 4   // account for borders
+	innerHeight := height - 3 // account for borders and title
+
+	// Render the markdown for the panel's actual width so tables and
+	// wrapped text fit; keeps the scroll position when reflowing
+	if m.readmeWidth != innerWidth {
+		m.readmeRendered = renderMarkdown(m.readmeContent, innerWidth)
+		m.readmeWidth = innerWidth
+		// Code blocks and long URLs aren't wrapped; h/l scroll to them
+		m.readmeMaxWidth = maxLineWidth(strings.Split(m.readmeRendered, "\n"))
+		m.readmeHScroll = clampHScroll(m.readmeHScroll, m.readmeMaxWidth, innerWidth)
+		if m.readmeReady {
+			m.readmeViewport.Width = innerWidth
+			m.readmeViewport.SetContent(m.readmeRendered)
+		}
+	}
+
+	if !m.readmeReady {
+		m.readmeViewport = viewport.New(innerWidth, innerHeight)
+		m.readmeViewport.SetContent(m.readmeRendered)
+		m.readmeReady = true
+	} else {
+		m.readmeViewport.Width = innerWidth
+		m.readmeViewport.Height = innerHeight
+	}
+
+	// Build the panel manually with viewport content
+	var content strings.Builder
+
+	// Apply cursor and visual selection highlighting; the viewport cuts
+	// lines at the horizontal offset, keeping their colors
+	m.readmeViewport.SetXOffset(m.readmeHScroll)
+	viewContent := m.readmeViewport.View()
+	lines := strings.Split(viewContent, "\n")
+
+	// Calculate visual selection range
+	selStart := m.readmeVisualStart
+	selEnd := m.readmeVisualEnd
+	if selStart > selEnd {
+		selStart, selEnd = selEnd, selStart
+	}
+
+	for i, line := range lines {
+		viewportLine := m.readmeViewport.YOffset + i
+
+		// Highlight visual selection
+		if m.readmeVisualMode && viewportLine >= selStart && viewportLine <= selEnd {
+			line = lipgloss.NewStyle().Background(lipgloss.Color("238")).Render(line)
+		}
+
+		// Show cursor line when focused
+		if m.focusedPanel == PanelReadme && viewportLine == m.readmeCursor {
+			line = lipgloss.NewStyle().Reverse(true).Render(line)
+		}
+		lines[i] = line
+	}
+	content.WriteString(strings.Join(lines, "\n"))
+
+	// Add scroll indicator and visual mode status
+	var statusParts []string
+	if m.readmeViewport.TotalLineCount() > innerHeight {
+		scrollPercent := int(m.readmeViewport.ScrollPercent() * 100)
+		statusParts = append(statusParts, fmt.Sprintf("[%d%%]", scrollPercent))
+	}
+	if m.readmeMaxWidth > innerWidth {
+		statusParts = append(statusParts, fmt.Sprintf("[col %d/%d]", m.readmeHScroll+1, m.readmeMaxWidth))
+	}
+	if m.readmeVisualMode {
+		lineCount := m.readmeVisualEnd - m.readmeVisualStart
+		if lineCount < 0 {
+			lineCount = -lineCount
+		}
+		lineCount++
+		statusParts = append(statusParts, fmt.Sprintf("VISUAL(%d)", lineCount))
+	}
+	if len(statusParts) > 0 {
+		content.WriteString(styles.DimmedText.Render(" " + strings.Join(statusParts, " ")))
+	}
+
+	return components.SimpleBorderedPanel("README", content.String(), width, height, m.focusedPanel == PanelReadme)
+}
+
+func (m *MainScreen) renderJobLogPopup() string {
+	// Use full screen
+	popupWidth := m.width
+	popupHeight := m.height - 1
+
+	// Split: left panel for job list, right panel for log
+	jobListWidth := 30
+	logWidth := popupWidth - jobListWidth
+
+	// Render job list panel
+	var jobList strings.Builder
+	visibleJobs := m.visibleJobs()
+	for _, i := range visibleJobs {
+		job := m.jobs[i]
+		icon := styles.PipelineIcon(job.Status)
+		statusStyle := styles.PipelineStatus(job.Status)
+
+		// Format: icon name (status)
+		line := fmt.Sprintf("%s %s (%s)", icon, job.Name, job.Status)
+
+		// Truncate if too long
+		if len(line) > jobListWidth-4 {
+			line = line[:jobListWidth-5] + "…"
+		}
+
+		if i == m.selectedJobIdx {
+			jobList.WriteString(styles.SelectedItem.Render("> " + statusStyle.Render(line)))
+		} else {
+			jobList.WriteString("  " + statusStyle.Render(line))
+		}
+		jobList.WriteString("\n")
+	}
+
+	// Job panel - focused when not in log
+	jobTitle := fmt.Sprintf("Jobs (%d)", len(m.jobs))
+	if m.jobStageFilter != "" {
+		jobTitle = fmt.Sprintf("Jobs: %s (%d/%d)", m.jobStageFilter, len(visibleJobs), len(m.jobs))
+	}
+	jobPanel := components.SimpleBorderedPanel(
+		jobTitle,
+		jobList.String(),
+		jobListWidth,
+		popupHeight,
+		!m.jobLogFocused,
+	)
+
+	// Render log panel
+	logInnerWidth := logWidth - 2
+	logInnerHeight := popupHeight - 2
+
+	var logContent strings.Builder
+	if m.jobLog == "" {
+		if m.loading {
+			logContent.WriteString(m.loadingText())
+		} else {
+			logContent.WriteString(styles.DimmedText.Render("Select a job to view log"))
+		}
+	} else {
+		if !m.jobLogReady || m.jobLogViewport.Width != logInnerWidth || m.jobLogViewport.Height != logInnerHeight {
+			m.jobLogViewport = viewport.New(logInnerWidth, logInnerHeight)
+			m.setJobLogContent()
+			// Keep the cursor line visible (starts at bottom where errors usually are)
+			m.jobLogViewport.GotoBottom()
+			m.keepJobLogCursorInView()
+			m.jobLogReady = true
+		}
+		// Get viewport content and apply cursor/selection highlighting + horizontal scroll
+		viewContent := m.jobLogViewport.View()
+		lines := strings.Split(viewContent, "\n")
+
+		// Calculate visual selection range
+		selStart := m.visualStartLine
+		selEnd := m.visualEndLine
+		if selStart > selEnd {
+			selStart, selEnd = selEnd, selStart
+		}
+
+		for i, line := range lines {
+			line = strings.ReplaceAll(line, "\t", "    ")
+			viewportLine := m.jobLogViewport.YOffset + i
+			if m.jobLogWrap {
+				viewportLine = logicalLineAt(m.jobLogRowStarts, viewportLine)
+			} else {
+				// Apply horizontal scroll
+				line = sliceByWidth(line, m.jobLogHScroll, logInnerWidth)
+			}
+
+			// Highlight search matches, the current one brighter
+			if m.jobLogSearch != "" {
+				if idx := sort.SearchInts(m.jobLogMatches, viewportLine); idx < len(m.jobLogMatches) && m.jobLogMatches[idx] == viewportLine {
+					bg := lipgloss.Color("58")
+					if idx == m.jobLogMatchIdx {
+						bg = lipgloss.Color("136")
+					}
+					line = lipgloss.NewStyle().Background(bg).Render(line)
+				}
+			}
+
+			// Highlight visual selection
+			if m.visualLineMode && viewportLine >= selStart && viewportLine <= selEnd {
+				line = lipgloss.NewStyle().Background(lipgloss.Color("238")).Render(line)
+			}
+
+			// Show cursor line when focused (on top of selection)
+			if m.jobLogFocused && viewportLine == m.jobLogCursor {
+				line = lipgloss.NewStyle().Reverse(true).Render(line)
+			}
+			lines[i] = line
+		}
+		logContent.WriteString(strings.Join(lines, "\n"))
+	}
+
+	// Build log title with job info
+	logTitle := "Log"
+	if m.selectedJobIdx < len(m.jobs) {
+		job := m.jobs[m.selectedJobIdx]
+		duration := ""
+		if job.Duration > 0 {
+			duration = fmt.Sprintf(" (%.1fs)", job.Duration)
+		}
+		logTitle = fmt.Sprintf("%s - %s%s", job.Name, job.Status, duration)
+	}
+
+	// Log panel - focused when in log
+	logPanel := components.SimpleBorderedPanel(logTitle, logContent.String(), logWidth, popupHeight, m.jobLogFocused)
+
+	// Join panels horizontally
+	combined := lipgloss.JoinHorizontal(lipgloss.Top, jobPanel, logPanel)
+
+	// Status bar
+	scrollInfo := ""
+	if m.jobLogReady && m.jobLogViewport.TotalLineCount() > logInnerHeight {
+		scrollInfo = fmt.Sprintf(" [%d%%]", int(m.jobLogViewport.ScrollPercent()*100))
+	}
+	if m.idle {
+		scrollInfo += " [paused (idle)]"
+	} else if m.refreshPaused {
+		scrollInfo += " [paused]"
+	}
+	if m.jobLogWrap {
+		scrollInfo += " [wrap]"
+	} else if m.jobLogMaxWidth > logInnerWidth {
+		scrollInfo += fmt.Sprintf(" [col %d/%d]", m.jobLogHScroll+1, m.jobLogMaxWidth)
+	}
+
+	statusContent := styles.StatusBarKey.Render("H/L") + styles.StatusBarDesc.Render(" panels") + " │ " +
+		styles.StatusBarKey.Render("hjkl") + styles.StatusBarDesc.Render(" nav") + " │ " +
+		styles.StatusBarKey.Render("V") + styles.StatusBarDesc.Render(" select") + " │ " +
+		styles.StatusBarKey.Render("yy") + styles.StatusBarDesc.Render(" yank") + " │ " +
+		styles.StatusBarKey.Render("ggy") + styles.StatusBarDesc.Render(" all") + " │ " +
+		styles.StatusBarKey.Render("/") + styles.StatusBarDesc.Render(" search") + " │ " +
+		styles.StatusBarKey.Render("w") + styles.StatusBarDesc.Render(" wrap") + " │ " +
+		styles.StatusBarKey.Render("q") + styles.StatusBarDesc.Render(" close") +
+		scrollInfo
+
+	if m.visualLineMode {
+		lineCount := m.visualEndLine - m.visualStartLine
+		if lineCount < 0 {
+			lineCount = -lineCount
+		}
+		lineCount++
+		statusContent = styles.SelectedItem.Render(fmt.Sprintf("VISUAL LINE (%d)", lineCount)) + " │ " + statusContent
+	}
+
+	if m.jobLogSearchActive {
+		statusContent = m.jobLogSearchInput.View() + " │ " + statusContent
+	} else if m.jobLogSearch != "" {
+		current := 0
+		if len(m.jobLogMatches) > 0 {
+			current = m.jobLogMatchIdx + 1
+		}
+		statusContent = styles.SelectedItem.Render(fmt.Sprintf("/%s [%d/%d]", m.jobLogSearch, current, len(m.jobLogMatches))) + " │ " + statusContent
+	}
+
+	if m.statusMsg != "" {
+		statusContent = styles.SelectedItem.Render(m.statusMsg) + " │ " + statusContent
+	}
+
+	statusBar := styles.StatusBar.Width(m.width).Render(statusContent)
+
+	return combined + "\n" + statusBar
+}
+
+func (m *MainScreen) renderBranchPopup() string {
+	// Centered popup for branch selection
+	popupWidth := 64
+	popupHeight := 20
+
+	if popupWidth > m.width-4 {
+		popupWidth = m.width - 4
+	}
+	if popupHeight > m.height-4 {
+		popupHeight = m.height - 4
+	}
+
+	var content strings.Builder
+
+	// Header
+	content.WriteString(styles.DimmedText.Render("Current: ") + styles.SelectedItem.Render(m.currentBranch) + "\n\n")
+
+	if len(m.branches) == 0 {
+		if m.loading {
+			content.WriteString(m.loadingText())
+		} else {
+			content.WriteString(styles.DimmedText.Render("No branches found"))
+		}
+	} else {
+		visibleLines := popupHeight - 6
+		if visibleLines < 5 {
+			visibleLines = 5
+		}
+
+		// Calculate scroll offset for branch list
+		startIdx := 0
+		if m.selectedBranchIdx >= visibleLines {
+			startIdx = m.selectedBranchIdx - visibleLines + 1
+		}
+		endIdx := startIdx + visibleLines
+		if endIdx > len(m.branches) {
+			endIdx = len(m.branches)
+		}
+
+		for i := startIdx; i < endIdx; i++ {
+			b := m.branches[i]
+			icon := "○"
+			if b.Default {
+				icon = "●"
+			}
+			if b.Name == m.currentBranch {
+				icon = "✓"
+			}
+
+			line := fmt.Sprintf("%s %s", icon, b.Name)
+			if i == m.selectedBranchIdx {
+				line = styles.SelectedItem.Render("> " + line)
+			} else if b.Merged {
+				// Merged branches are candidates for deletion
+				line = styles.DimmedText.Render("  " + line)
+			} else {
+				line = "  " + line
+			}
+			line += branchTags(b)
+			if counts, ok := m.branchDivergence[b.Name]; ok {
+				line += styles.DimmedText.Render(fmt.Sprintf(" ↑%d ↓%d", counts.ahead, counts.behind))
+			} else if b.Name == m.divergenceLoading {
+				line += styles.DimmedText.Render(" ...")
+			}
+			if b.Name == m.compareMark {
+				line += styles.StatusBarKey.Render(" [base]")
+			}
+			content.WriteString(line + "\n")
+		}
+
+		// Scroll indicator
+		if len(m.branches) > visibleLines {
+			content.WriteString(styles.DimmedText.Render(fmt.Sprintf("\n[%d/%d]", m.selectedBranchIdx+1, len(m.branches))))
+		}
+	}
+
+	// Build popup panel
+	popup := components.SimpleBorderedPanel("Switch Branch", content.String(), popupWidth, popupHeight, true)
+
+	// Center the popup
+	popupLines := strings.Split(popup, "\n")
+	topPadding := (m.height - len(popupLines)) / 2
+	leftPadding := (m.width - popupWidth) / 2
+	if topPadding < 0 {
+		topPadding = 0
+	}
+	if leftPadding < 0 {
+		leftPadding = 0
+	}
+
+	var result strings.Builder
+	for i := 0; i < topPadding; i++ {
+		result.WriteString("\n")
+	}
+	for _, line := range popupLines {
+		result.WriteString(strings.Repeat(" ", leftPadding) + line + "\n")
+	}
+
+	// Status bar at bottom
+	statusContent := styles.StatusBarKey.Render("Esc") + styles.StatusBarDesc.Render(" cancel") + " │ " +
+		styles.StatusBarKey.Render("j/k") + styles.StatusBarDesc.Render(" navigate") + " │ " +
+		styles.StatusBarKey.Render("Enter") + styles.StatusBarDesc.Render(" switch") + " │ " +
+		styles.StatusBarKey.Render("Space") + styles.StatusBarDesc.Render(" mark for compare") + " │ " +
+		styles.StatusBarKey.Render("y/Y") + styles.StatusBarDesc.Render(" copy name/SHA") + " │ " +
+		styles.StatusBarKey.Render("a") + styles.StatusBarDesc.Render(" ahead/behind")
+	if m.compareMark != "" {
+		statusContent = styles.StatusBarKey.Render("Esc") + styles.StatusBarDesc.Render(" unmark") + " │ " +
+			styles.StatusBarKey.Render("j/k") + styles.StatusBarDesc.Render(" navigate") + " │ " +
+			styles.StatusBarKey.Render("Enter") + styles.StatusBarDesc.Render(" compare with "+m.compareMark)
+	}
+	if m.statusMsg != "" {
+		statusContent = styles.SelectedItem.Render(m.statusMsg) + " │ " + statusContent
+	}
+
+	// Pad to bottom
+	currentLines := topPadding + len(popupLines)
+	for i := currentLines; i < m.height-1; i++ {
+		result.WriteString("\n")
+	}
+
+	result.WriteString(styles.StatusBar.Width(m.width).Render(statusContent))
+
+	return result.String()
+}
+
+func (m *MainScreen) renderRunnersPopup() string {
+	// Larger popup for runners view
+	popupWidth := int(float64(m.width) * 0.8)
+	popupHeight := int(float64(m.height) * 0.8)
+
+	if popupWidth < 60 {
+		popupWidth = 60
+	}
+	if popupHeight < 15 {
+		popupHeight = 15
+	}
+	if popupWidth > m.width-4 {
+		popupWidth = m.width - 4
+	}
+	if popupHeight > m.height-4 {
+		popupHeight = m.height - 4
+	}
+
+	var content strings.Builder
+
+	// Tab headers
+	runningTab := fmt.Sprintf("Running (%d)", len(m.runningJobs))
+	pendingTab := fmt.Sprintf("Pending (%d)", len(m.pendingJobs))
+
+	if m.runnersTab == 0 {
+		content.WriteString(styles.SelectedItem.Render("["+runningTab+"]") + " " + styles.DimmedText.Render(pendingTab))
+	} else {
+		content.WriteString(styles.DimmedText.Render(runningTab) + " " + styles.SelectedItem.Render("["+pendingTab+"]"))
+	}
+	content.WriteString("\n\n")
+
+	// Get current job list
+	jobs := m.runningJobs
+	if m.runnersTab == 1 {
+		jobs = m.pendingJobs
+	}
+
+	if m.runnersErr != "" {
+		content.WriteString(lipgloss.NewStyle().Foreground(styles.ColorRed).Render(m.runnersErr) + "\n\n")
+	} else if m.runnersNote != "" && !m.runnersLoading {
+		content.WriteString(styles.DimmedText.Render(m.runnersNote) + "\n\n")
+	}
+
+	if m.runnersLoading {
+		content.WriteString(styles.DimmedText.Render("Loading jobs..."))
+	} else if len(jobs) == 0 {
+		if m.runnersTab == 0 {
+			content.WriteString(styles.DimmedText.Render("No running jobs"))
+		} else {
+			content.WriteString(styles.DimmedText.Render("No pending jobs"))
+		}
+	} else {
+		visibleLines := popupHeight - 8
+		if m.runnersErr != "" || m.runnersNote != "" {
+			visibleLines -= 2
+		}
+		if visibleLines < 5 {
+			visibleLines = 5
+		}
+
+		// Calculate scroll offset
+		startIdx := 0
+		if m.runnersCursor >= visibleLines {
+			startIdx = m.runnersCursor - visibleLines + 1
+		}
+		endIdx := startIdx + visibleLines
+		if endIdx > len(jobs) {
+			endIdx = len(jobs)
+		}
+
+		// Column header
+		header := fmt.Sprintf("%-20s %-30s %-15s %8s", "PROJECT", "JOB", "RUNNER", "DURATION")
+		content.WriteString(styles.DimmedText.Render(header) + "\n")
+		content.WriteString(styles.DimmedText.Render(strings.Repeat("─", popupWidth-4)) + "\n")
+
+		for i := startIdx; i < endIdx; i++ {
+			job := jobs[i]
+			icon := styles.PipelineIcon(job.Status)
+			statusStyle := styles.PipelineStatus(job.Status)
+
+			// Project name (truncate if needed)
+			project := job.Project.Name
+			if len(project) > 18 {
+				project = project[:17] + "…"
+			}
+
+			// Job name (truncate if needed)
+			jobName := job.Name
+			if job.Stage != "" && job.Stage != job.Name {
+				jobName = job.Stage + "/" + job.Name
+			}
+			if len(jobName) > 28 {
+				jobName = jobName[:27] + "…"
+			}
+
+			// Runner info
+			runnerName := "-"
+			if job.Runner != nil {
+				runnerName = job.Runner.Description
+				if runnerName == "" {
+					runnerName = job.Runner.Name
+				}
+				if runnerName == "" {
+					runnerName = fmt.Sprintf("#%d", job.Runner.ID)
+				}
+			}
+			if len(runnerName) > 13 {
+				runnerName = runnerName[:12] + "…"
+			}
+
+			// Duration: final duration for finished jobs, live elapsed time for running ones
+			duration := "-"
+			if job.Duration > 0 {
+				duration = formatElapsed(time.Duration(job.Duration * float64(time.Second)))
+			} else if job.StartedAt != nil {
+				duration = formatElapsed(time.Since(*job.StartedAt))
+			}
+
+			line := fmt.Sprintf("%s %-20s %-30s %-15s %8s",
+				statusStyle.Render(icon),
+				project,
+				jobName,
+				runnerName,
+				duration)
+
+			if i == m.runnersCursor {
+				line = styles.SelectedItem.Render("> ") + line
+			} else {
+				line = "  " + line
+			}
+			content.WriteString(line + "\n")
+		}
+
+		// Scroll indicator
+		if len(jobs) > visibleLines {
+			content.WriteString(styles.DimmedText.Render(fmt.Sprintf("\n[%d/%d]", m.runnersCursor+1, len(jobs))))
+		}
+	}
+
+	// Build popup panel
+	title := "CI/CD Jobs"
+	if m.runnersLoading {
+		title += " (loading...)"
+	}
+	popup := components.SimpleBorderedPanel(title, content.String(), popupWidth, popupHeight, true)
+
+	// Center the popup
+	popupLines := strings.Split(popup, "\n")
+	topPadding := (m.height - len(popupLines)) / 2
+	leftPadding := (m.width - popupWidth) / 2
+	if topPadding < 0 {
+		topPadding = 0
+	}
+	if leftPadding < 0 {
+		leftPadding = 0
+	}
+
+	var result strings.Builder
+	for i := 0; i < topPadding; i++ {
+		result.WriteString("\n")
+	}
+	for _, line := range popupLines {
+		result.WriteString(strings.Repeat(" ", leftPadding) + line + "\n")
+	}
+
+	// Status bar at bottom
+	statusContent := styles.StatusBarKey.Render("Esc") + styles.StatusBarDesc.Render(" close") + " │ " +
+		styles.StatusBarKey.Render("Tab") + styles.StatusBarDesc.Render(" switch") + " │ " +
+		styles.StatusBarKey.Render("j/k") + styles.StatusBarDesc.Render(" navigate") + " │ " +
+		styles.StatusBarKey.Render("r") + styles.StatusBarDesc.Render(" refresh")
+
+	// Pad to bottom
+	currentLines := topPadding + len(popupLines)
+	for i := currentLines; i < m.height-1; i++ {
+		result.WriteString("\n")
+	}
+
+	result.WriteString(styles.StatusBar.Width(m.width).Render(statusContent))
+
+	return result.String()
+}
+
+// userLabel describes who the session is authenticated as, e.g. "@alice (3 todos)"
+func (m *MainScreen) userLabel() string {
+	if m.anonymous {
+		return styles.DimmedText.Render("anonymous")
+	}
+	if m.currentUser == nil {
+		return ""
+	}
+	label := styles.StatusBarKey.Render("@" + m.currentUser.Username)
+	switch {
+	case m.todosCount == 1:
+		label += styles.StatusBarDesc.Render(" (1 todo)")
+	case m.todosCount >= 0:
+		label += styles.StatusBarDesc.Render(fmt.Sprintf(" (%d todos)", m.todosCount))
+	}
+	return label
+}
+
+func (m *MainScreen) renderStatusBar() string {
+	// If there's a status message, show it prominently
+	if m.statusMsg != "" {
+		msgStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Bold(true) // Green
+		return styles.StatusBar.Width(m.width).Render(msgStyle.Render(m.statusMsg))
+	}
+
+	// If there's an error, show it prominently with retry hint
+	if m.lastError != "" {
+		errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true) // Red
+		errorMsg := m.lastError
+		// Truncate long error messages
+		maxLen := m.width - 30
+		if maxLen > 0 && len(errorMsg) > maxLen {
+			errorMsg = errorMsg[:maxLen] + "..."
+		}
+		errText := errorStyle.Render("Error: " + errorMsg)
+		retryHint := styles.StatusBarKey.Render(" r") + styles.StatusBarDesc.Render(" retry") + " │ " +
+			styles.StatusBarKey.Render("Esc") + styles.StatusBarDesc.Render(" dismiss")
+		return styles.StatusBar.Width(m.width).Render(errText + " " + retryHint)
+	}
+
+	panels := []struct {
+		id   PanelID
+		key  string
+		name string
+	}{
+		{PanelNavigator, "1", "navigator"},
+		{PanelContent, "2", "content"},
+		{PanelReadme, "3", "readme"},
+	}
+
+	var parts []string
+	for _, p := range panels {
+		if p.id == m.focusedPanel {
+			parts = append(parts, styles.StatusBarKey.Render("["+p.key+"]")+styles.StatusBarDesc.Render(" "+p.name))
+		} else {
+			parts = append(parts, styles.DimmedText.Render(" "+p.key+" "+p.name))
+		}
+	}
+
+	left := strings.Join(parts, " ")
+	if user := m.userLabel(); user != "" {
+		left = user + " │ " + left
+	}
+	if back, ok := m.backStack.Peek(); ok {
+		left += " │ " + styles.DimmedText.Render("C-o ‹ "+back.project.Name)
+	}
+	if m.idle {
+		left += " │ " + styles.StatusBarKey.Render("⏸ paused (idle)")
+	} else if m.refreshPaused {
+		left += " │ " + styles.StatusBarKey.Render("⏸ paused")
+	}
+	if m.loading {
+		left += " │ " + m.loadingText()
+	}
+	if m.countPrefix > 0 {
+		left += " │ " + styles.StatusBarKey.Render(fmt.Sprint(m.countPrefix))
+	}
+
+	var help string
+	if m.focusedPanel == PanelReadme {
+		// README-specific keybindings
+		help = styles.StatusBarKey.Render("j/k") + styles.StatusBarDesc.Render(" scroll") + " │ " +
+			styles.StatusBarKey.Render("V") + styles.StatusBarDesc.Render(" select") + " │ " +
+			styles.StatusBarKey.Render("yy") + styles.StatusBarDesc.Render(" yank") + " │ " +
+			styles.StatusBarKey.Render("ggy") + styles.StatusBarDesc.Render(" all") + " │ " +
+			styles.StatusBarKey.Render("q") + styles.StatusBarDesc.Render(" quit")
+	} else if m.focusedPanel == PanelContent && m.contentTab == TabPipelines {
+		help = styles.StatusBarKey.Render("j/k") + styles.StatusBarDesc.Render(" nav") + " │ " +
+			styles.StatusBarKey.Render("Enter") + styles.StatusBarDesc.Render(" jobs") + " │ " +
+			styles.StatusBarKey.Render("Space") + styles.StatusBarDesc.Render(" expand") + " │ " +
+			styles.StatusBarKey.Render("s") + styles.StatusBarDesc.Render(" sort") + " │ " +
+			styles.StatusBarKey.Render("f") + styles.StatusBarDesc.Render(" filter") + " │ " +
+			styles.StatusBarKey.Render("y/Y") + styles.StatusBarDesc.Render(" copy SHA/URL") + " │ " +
+			styles.StatusBarKey.Render("v") + styles.StatusBarDesc.Render(" variables") + " │ " +
+			styles.StatusBarKey.Render("q") + styles.StatusBarDesc.Render(" quit")
+	} else {
+		help = styles.StatusBarKey.Render("j/k") + styles.StatusBarDesc.Render(" nav") + " │ " +
+			styles.StatusBarKey.Render("Enter") + styles.StatusBarDesc.Render(" select") + " │ " +
+			styles.StatusBarKey.Render("S") + styles.StatusBarDesc.Render(" ssh") + " " +
+			styles.StatusBarKey.Render("U") + styles.StatusBarDesc.Render(" https") + " │ " +
+			styles.StatusBarKey.Render("R") + styles.StatusBarDesc.Render(" jobs") + " │ " +
+			styles.StatusBarKey.Render("?") + styles.StatusBarDesc.Render(" help") + " │ " +
+			styles.StatusBarKey.Render("q") + styles.StatusBarDesc.Render(" quit")
+	}
+
+	leftWidth := lipgloss.Width(left)
+	// Drop hints before the final "q quit" until the bar fits on one line
+	hints := strings.Split(help, " │ ")
+	for len(hints) > 2 && leftWidth+lipgloss.Width(strings.Join(hints, " │ "))+2 > m.width {
+		hints = append(hints[:len(hints)-2], hints[len(hints)-1])
+	}
+	help = strings.Join(hints, " │ ")
+	rightWidth := lipgloss.Width(help)
+	padding := m.width - leftWidth - rightWidth - 2
+	if padding < 0 {
+		padding = 0
+	}
+
+	return styles.StatusBar.Width(m.width).Render(left + strings.Repeat(" ", padding) + help)
+}
+
+// handleReleasePopup handles keyboard input for the release assets popup
+func (m *MainScreen) handleReleasePopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.selectedReleaseIdx >= len(m.releases) {
+		m.showReleasePopup = false
+		return m, nil
+	}
+
+	rel := m.releases[m.selectedReleaseIdx]
+	totalAssets := len(rel.Assets.Sources) + len(rel.Assets.Links)
+
+	switch msg.String() {
+	case "esc", "escape", "q":
+		m.showReleasePopup = false
+		return m, nil
+	case "j", "down":
+		if m.releaseAssetCursor < totalAssets-1 {
+			m.releaseAssetCursor++
+		}
+	case "k", "up":
+		if m.releaseAssetCursor > 0 {
+			m.releaseAssetCursor--
+		}
+	case "g":
+		m.releaseAssetCursor = 0
+	case "G":
+		if totalAssets > 0 {
+			m.releaseAssetCursor = totalAssets - 1
+		}
+	case "y", "enter":
+		// Copy the URL of selected asset to clipboard
+		url := m.getSelectedReleaseAssetURL()
+		if url != "" {
+			if err := copyToClipboard(url); err != nil {
+				m.statusMsg = "Copy failed: " + err.Error()
+			} else {
+				m.statusMsg = "Copied: " + truncateString(url, 60)
+			}
+		}
+		return m, nil
+	case "o":
+		// Open release web URL in browser
+		if rel.Links.Self != "" {
+			m.statusMsg = "Open: " + rel.Links.Self
+			// Just copy for now - could open browser in future
+			if err := copyToClipboard(rel.Links.Self); err != nil {
+				m.statusMsg = "Copy failed: " + err.Error()
+			} else {
+				m.statusMsg = "Copied release URL: " + truncateString(rel.Links.Self, 50)
+			}
+		}
+		return m, nil
+	case "d":
+		// Download the selected asset - open folder browser
+		url := m.getSelectedReleaseAssetURL()
+		filename := m.getSelectedReleaseAssetFilename()
+		if url != "" && filename != "" {
+			m.downloadURL = url
+			m.downloadFilename = filename
+			m.downloadChecksumURL = m.getSelectedReleaseChecksumURL(filename)
+			m.saveViewedFile = false
+			m.showReleasePopup = false
+			m.openFolderBrowser()
+		}
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// getSelectedReleaseAssetFilename returns the filename of the currently selected asset
+func (m *MainScreen) getSelectedReleaseAssetFilename() string {
+	if m.selectedReleaseIdx >= len(m.releases) {
+		return ""
+	}
+
+	rel := m.releases[m.selectedReleaseIdx]
+	cursor := m.releaseAssetCursor
+
+	// First, source archives
+	if cursor < len(rel.Assets.Sources) {
+		src := rel.Assets.Sources[cursor]
+		return fmt.Sprintf("%s-%s.%s", rel.TagName, "source", src.Format)
+	}
+
+	// Then, asset links
+	linkIdx := cursor - len(rel.Assets.Sources)
+	if linkIdx < len(rel.Assets.Links) {
+		return rel.Assets.Links[linkIdx].Name
+	}
+
+	return ""
+}
+
+// openFolderBrowser initializes and shows the folder browser
+func (m *MainScreen) openFolderBrowser() {
+	// Start from home directory
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = "/"
+	}
+	m.folderBrowserPath = home
+	m.folderBrowserCursor = 0
+	m.folderBrowserScroll = 0
+	m.loadFolderEntries()
+	m.showFolderBrowser = true
+}
+
+// loadFolderEntries loads directory entries for the current folder browser path
+func (m *MainScreen) loadFolderEntries() {
+	entries, err := os.ReadDir(m.folderBrowserPath)
+	if err != nil {
+		m.folderBrowserEntries = []string{}
+		return
+	}
+
+	m.folderBrowserEntries = []string{}
+	for _, entry := range entries {
+		name := entry.Name()
+		// Skip hidden files/directories (starting with .) unless toggled on
+		if !m.folderBrowserHidden && strings.HasPrefix(name, ".") {
+			continue
+		}
+		if entry.IsDir() {
+			m.folderBrowserEntries = append(m.folderBrowserEntries, name)
+		}
+	}
+	sort.Strings(m.folderBrowserEntries)
+}
+
+// getSelectedReleaseAssetURL returns the URL of the currently selected asset
+func (m *MainScreen) getSelectedReleaseAssetURL() string {
+	if m.selectedReleaseIdx >= len(m.releases) {
+		return ""
+	}
+
+	rel := m.releases[m.selectedReleaseIdx]
+	cursor := m.releaseAssetCursor
+
+	// First, source archives
+	if cursor < len(rel.Assets.Sources) {
+		return rel.Assets.Sources[cursor].URL
+	}
+
+	// Then, asset links
+	linkIdx := cursor - len(rel.Assets.Sources)
+	if linkIdx < len(rel.Assets.Links) {
+		return rel.Assets.Links[linkIdx].URL
+	}
+
+	return ""
+}
+
+// getSelectedReleaseChecksumURL returns the URL of the asset published next
+// to filename with its SHA-256, e.g. app.tar.gz.sha256, or "" if there's none
+func (m *MainScreen) getSelectedReleaseChecksumURL(filename string) string {
+	if m.selectedReleaseIdx >= len(m.releases) {
+		return ""
+	}
+	for _, link := range m.releases[m.selectedReleaseIdx].Assets.Links {
+		if strings.EqualFold(link.Name, filename+".sha256") || strings.EqualFold(link.Name, filename+".sha256sum") {
+			return link.URL
+		}
+	}
+	return ""
+}
+
+// renderReleasePopup renders the release assets popup
+func (m *MainScreen) renderReleasePopup() string {
+	if m.selectedReleaseIdx >= len(m.releases) {
+		return ""
+	}
+
+	rel := m.releases[m.selectedReleaseIdx]
+
+	// Popup dimensions
+	popupWidth := min(m.width-4, 80)
+	popupHeight := min(m.height-4, 30)
+
+	var content strings.Builder
+
+	// Release info header
+	name := rel.Name
+	if name == "" {
+		name = rel.TagName
+	}
+	content.WriteString(styles.ActivePanelTitle.Render("Release: "+name) + "\n")
+	content.WriteString(styles.DimmedText.Render("Tag: "+rel.TagName) + "\n")
+	if rel.Commit.ShortID != "" {
+		content.WriteString(styles.DimmedText.Render("Commit: "+rel.Commit.ShortID) + "\n")
+	}
+	content.WriteString("\n")
+
+	// Downloadable assets
+	content.WriteString(styles.ActivePanelTitle.Render("Downloads:") + "\n")
+
+	visibleLines := popupHeight - 10
+	cursor := 0
+
+	// Source archives first
+	for i, src := range rel.Assets.Sources {
+		icon := "📦"
+		line := fmt.Sprintf("%s Source code (%s)", icon, src.Format)
+
+		if cursor == m.releaseAssetCursor {
+			content.WriteString(styles.SelectedItem.Render("> ") + line + "\n")
+		} else {
+			content.WriteString("  " + line + "\n")
+		}
+		cursor++
+
+		if i >= visibleLines {
+			break
+		}
+	}
+
+	// Asset links
+	for i, link := range rel.Assets.Links {
+		icon := "📎"
+		switch link.LinkType {
+		case "package":
+			icon = "📦"
+		case "image":
+			icon = "🖼️"
+		case "runbook":
+			icon = "📖"
+		}
+
+		line := fmt.Sprintf("%s %s", icon, link.Name)
+		line = truncateWidth(line, popupWidth-6)
+
+		if cursor == m.releaseAssetCursor {
+			content.WriteString(styles.SelectedItem.Render("> ") + line + "\n")
+		} else {
+			content.WriteString("  " + line + "\n")
+		}
+		cursor++
+
+		if i+len(rel.Assets.Sources) >= visibleLines {
+			break
+		}
+	}
+
+	totalAssets := len(rel.Assets.Sources) + len(rel.Assets.Links)
+	if totalAssets == 0 {
+		content.WriteString(styles.DimmedText.Render("  No downloadable assets") + "\n")
+	} else {
+		content.WriteString(styles.DimmedText.Render(fmt.Sprintf("\n[%d/%d]", m.releaseAssetCursor+1, totalAssets)) + "\n")
+	}
+
+	// Show selected URL
+	selectedURL := m.getSelectedReleaseAssetURL()
+	if selectedURL != "" {
+		content.WriteString("\n" + styles.DimmedText.Render("URL: "+truncateString(selectedURL, popupWidth-8)) + "\n")
+	}
+
+	// Build popup panel
+	popup := components.SimpleBorderedPanel(
+		fmt.Sprintf("Release: %s", rel.TagName),
+		content.String(),
+		popupWidth,
+		popupHeight,
+		true,
+	)
+
+	// Center the popup
+	popupLines := strings.Split(popup, "\n")
+	topPadding := (m.height - len(popupLines)) / 2
+	leftPadding := (m.width - popupWidth) / 2
+	if topPadding < 0 {
+		topPadding = 0
+	}
+	if leftPadding < 0 {
+		leftPadding = 0
+	}
+
+	var result strings.Builder
+	for i := 0; i < topPadding; i++ {
+		result.WriteString("\n")
+	}
+	for _, line := range popupLines {
+		result.WriteString(strings.Repeat(" ", leftPadding) + line + "\n")
+	}
+
+	// Status bar at bottom
+	statusContent := styles.StatusBarKey.Render("Esc") + styles.StatusBarDesc.Render(" close") + " │ " +
+		styles.StatusBarKey.Render("j/k") + styles.StatusBarDesc.Render(" navigate") + " │ " +
+		styles.StatusBarKey.Render("y/Enter") + styles.StatusBarDesc.Render(" copy URL") + " │ " +
+		styles.StatusBarKey.Render("d") + styles.StatusBarDesc.Render(" download") + " │ " +
+		styles.StatusBarKey.Render("o") + styles.StatusBarDesc.Render(" copy release URL")
+
+	// Pad to bottom
+	currentLines := topPadding + len(popupLines)
+	for i := currentLines; i < m.height-1; i++ {
+		result.WriteString("\n")
+	}
+
+	result.WriteString(styles.StatusBar.Width(m.width).Render(statusContent))
+
+	return result.String()
+}
+
+// toggleHiddenFolders shows or hides dot-directories in the folder browser,
+// keeping the cursor on the same directory when it is still listed
+func (m *MainScreen) toggleHiddenFolders() {
+	selected := ""
+	if m.folderBrowserCursor < len(m.folderBrowserEntries) {
+		selected = m.folderBrowserEntries[m.folderBrowserCursor]
+	}
+	m.folderBrowserHidden = !m.folderBrowserHidden
+	m.loadFolderEntries()
+
+	m.folderBrowserCursor = 0
+	for i, name := range m.folderBrowserEntries {
+		if name == selected {
+			m.folderBrowserCursor = i
+			break
+		}
+	}
+
+	visibleLines := min(m.height-4, 25) - 12
+	if visibleLines < 3 {
+		visibleLines = 3
+	}
+	if m.folderBrowserCursor < m.folderBrowserScroll {
+		m.folderBrowserScroll = m.folderBrowserCursor
+	} else if m.folderBrowserCursor >= m.folderBrowserScroll+visibleLines {
+		m.folderBrowserScroll = m.folderBrowserCursor - visibleLines + 1
+	}
+	if maxScroll := max(len(m.folderBrowserEntries)-visibleLines, 0); m.folderBrowserScroll > maxScroll {
+		m.folderBrowserScroll = maxScroll
+	}
+}
+
+// handleFolderBrowser handles keyboard input for the folder browser popup
+func (m *MainScreen) handleFolderBrowser(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "escape", "q":
+		m.showFolderBrowser = false
+		m.downloadURL = ""
+		m.downloadFilename = ""
+		m.downloadChecksumURL = ""
+		m.saveViewedFile = false
+		return m, nil
+
+	case "j", "down":
+		if m.folderBrowserCursor < len(m.folderBrowserEntries)-1 {
+			m.folderBrowserCursor++
+			// Adjust scroll - match the visibleLines calculation in renderFolderBrowser
+			popupHeight := min(m.height-4, 25)
+			visibleLines := popupHeight - 12
+			if visibleLines < 3 {
+				visibleLines = 3
+			}
+			if m.folderBrowserCursor >= m.folderBrowserScroll+visibleLines {
+				m.folderBrowserScroll = m.folderBrowserCursor - visibleLines + 1
+			}
+		}
+
+	case "k", "up":
+		if m.folderBrowserCursor > 0 {
+			m.folderBrowserCursor--
+			if m.folderBrowserCursor < m.folderBrowserScroll {
+				m.folderBrowserScroll = m.folderBrowserCursor
+			}
+		}
+
+	case "g":
+		m.folderBrowserCursor = 0
+		m.folderBrowserScroll = 0
+
+	case "G":
+		if len(m.folderBrowserEntries) > 0 {
+			m.folderBrowserCursor = len(m.folderBrowserEntries) - 1
+			popupHeight := min(m.height-4, 25)
+			visibleLines := popupHeight - 12
+			if visibleLines < 3 {
+				visibleLines = 3
+			}
+			if m.folderBrowserCursor >= visibleLines {
+				m.folderBrowserScroll = m.folderBrowserCursor - visibleLines + 1
+			}
+		}
+
+	case "l", "enter":
+		// Enter selected directory
+		if m.folderBrowserCursor < len(m.folderBrowserEntries) {
+			selectedDir := m.folderBrowserEntries[m.folderBrowserCursor]
+			newPath := filepath.Join(m.folderBrowserPath, selectedDir)
+			// Verify it's accessible
+			if _, err := os.ReadDir(newPath); err == nil {
+				m.folderBrowserPath = newPath
+				m.folderBrowserCursor = 0
+				m.folderBrowserScroll = 0
+				m.loadFolderEntries()
+			}
+		}
+
+	case "h", "backspace":
+		// Go up one directory
+		parent := filepath.Dir(m.folderBrowserPath)
+		if parent != m.folderBrowserPath {
+			m.folderBrowserPath = parent
+			m.folderBrowserCursor = 0
+			m.folderBrowserScroll = 0
+			m.loadFolderEntries()
+		}
+
+	case ".":
+		m.toggleHiddenFolders()
+
+	case "~":
+		// Go to home directory
+		if home, err := os.UserHomeDir(); err == nil {
+			m.folderBrowserPath = home
+			m.folderBrowserCursor = 0
+			m.folderBrowserScroll = 0
+			m.loadFolderEntries()
+		}
+
+	case "d", " ":
+		// Download to current directory
+		if (m.downloadURL != "" || m.saveViewedFile) && m.downloadFilename != "" {
+			destPath := filepath.Join(m.folderBrowserPath, m.downloadFilename)
+			m.showFolderBrowser = false
+			// Don't silently replace an existing file
+			if _, err := os.Stat(destPath); err == nil {
+				m.pendingDownload = destPath
+				m.confirm.Ask(confirmOverwriteDownload, destPath+" already exists. Overwrite it?")
+				return m, nil
+			}
+			return m, m.downloadTo(destPath)
+		}
+	}
+
+	return m, nil
+}
+
+// downloadProgressInterval throttles progress updates to the UI
+const downloadProgressInterval = 100 * time.Millisecond
+
+// confirmOverwriteDownload identifies the prompt before replacing a file
+const confirmOverwriteDownload = "overwrite-download"
+
+// downloadTo starts downloading the selected asset to destPath
+func (m *MainScreen) downloadTo(destPath string) tea.Cmd {
+	if m.saveViewedFile {
+		m.saveViewedFile = false
+		return m.saveFileTo(destPath)
+	}
+	m.loading = true
+	m.loadingMsg = "Downloading " + m.downloadFilename + "..."
+	return m.startDownload(m.downloadURL, m.downloadFilename, destPath, m.downloadChecksumURL)
+}
+
+// saveFileTo writes the viewed file to destPath. The text on screen is written
+// as is; binary, truncated or re-encoded files are fetched raw first.
+func (m *MainScreen) saveFileTo(destPath string) tea.Cmd {
+	filename := m.downloadFilename
+	if !m.fileSaveRaw {
+		data := []byte(m.fileContent)
+		return func() tea.Msg {
+			err := os.WriteFile(destPath, data, 0644)
+			return downloadCompleteMsg{filename: filename, bytes: int64(len(data)), err: err}
+		}
+	}
+	if m.selectedProject == nil || m.isDemo || m.viewingSnippet || m.viewingWiki {
+		m.statusMsg = "Can't fetch the raw bytes of " + filename
+		return nil
+	}
+
+	m.loading = true
+	m.loadingMsg = "Saving " + filename + "..."
+	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
+	filePath, ref := m.viewingFilePath, cmp.Or(m.viewingFileRef, m.currentRef())
+	client := m.client
+	return func() tea.Msg {
+		written, err := client.SaveFileRaw(projectID, filePath, ref, destPath)
+		return downloadCompleteMsg{filename: filename, bytes: written, err: err}
+	}
+}
+
+// startDownload downloads url to destPath in the background. Progress and the
+// final downloadCompleteMsg are delivered through m.downloadUpdates. When
+// checksumURL is set the saved file is checked against the SHA-256 it holds.
+func (m *MainScreen) startDownload(url, filename, destPath, checksumURL string) tea.Cmd {
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := make(chan tea.Msg, 1)
+	m.downloadCancel = cancel
+	m.downloadUpdates = updates
+	client := m.client
+
+	go func() {
+		defer cancel()
+		var last time.Time
+		bytes, digest, err := client.DownloadFile(ctx, url, destPath, func(written, total int64) {
+			if time.Since(last) < downloadProgressInterval {
+				return
+			}
+			last = time.Now()
+			// Drop updates while the UI is still handling the previous one
+			select {
+			case updates <- downloadProgressMsg{filename: filename, bytesSoFar: written, total: total}:
+			default:
+			}
+		})
+		done := downloadCompleteMsg{
+			filename: filename,
+			bytes:    bytes,
+			digest:   digest,
+			err:      err,
+		}
+		if err == nil && checksumURL != "" {
+			done.checksum, done.checksumErr = fetchChecksum(ctx, client, checksumURL, filename)
+		}
+		updates <- done
+	}()
+	return waitForDownload(updates)
+}
+
+// fetchChecksum downloads a .sha256 asset and returns the digest it lists
+// for filename
+func fetchChecksum(ctx context.Context, client gitlab.API, url, filename string) (string, error) {
+	tmp, err := os.CreateTemp("", "lazylab-*.sha256")
+	if err != nil {
+		return "", err
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if _, _, err := client.DownloadFile(ctx, url, tmp.Name(), nil); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return "", err
+	}
+	return parseChecksum(string(data), filename)
+}
+
+// parseChecksum reads the SHA-256 of filename from a checksum file, either a
+// bare digest or sha256sum output ("<digest>  <name>" per line)
+func parseChecksum(data, filename string) (string, error) {
+	isDigest := func(s string) bool {
+		_, err := hex.DecodeString(s)
+		return len(s) == 64 && err == nil
+	}
+	lines := strings.Split(strings.TrimSpace(data), "\n")
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || !isDigest(fields[0]) {
+			continue
+		}
+		if len(fields) == 1 && len(lines) == 1 {
+			return strings.ToLower(fields[0]), nil
+		}
+		if len(fields) == 2 && path.Base(strings.TrimPrefix(fields[1], "*")) == filename {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("no SHA-256 for %s in checksum file", filename)
+}
+
+// waitForDownload waits for the next progress or completion message
+func waitForDownload(updates <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-updates
+	}
+}
+
+// formatBytes formats a byte count with a binary unit, e.g. "12.3 MB"
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// downloadProgressText renders download progress as a percentage bar, or as
+// a byte count when the total size is unknown
+func downloadProgressText(filename string, written, total int64) string {
+	if total <= 0 {
+		return fmt.Sprintf("Downloading %s... %s (Esc to cancel)", filename, formatBytes(written))
+	}
+	const barWidth = 20
+	written = min(written, total)
+	filled := int(written * barWidth / total)
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", barWidth-filled)
+	return fmt.Sprintf("Downloading %s [%s] %d%% %s / %s (Esc to cancel)",
+		filename, bar, written*100/total, formatBytes(written), formatBytes(total))
+}
+
+// renderFolderBrowser renders the folder browser popup
+func (m *MainScreen) renderFolderBrowser() string {
+	popupWidth := min(m.width-4, 70)
+	popupHeight := min(m.height-4, 25)
+
+	var content strings.Builder
+
+	// Current path
+	content.WriteString(styles.ActivePanelTitle.Render("Location:") + "\n")
+	displayPath := m.folderBrowserPath
+	displayPath = truncateWidthLeft(displayPath, popupWidth-6)
+	content.WriteString(styles.DimmedText.Render(displayPath) + "\n\n")
+
+	// File to download
+	content.WriteString(styles.ActivePanelTitle.Render("File:") + " " + m.downloadFilename + "\n\n")
+
+	// Directory listing
+	foldersTitle := styles.ActivePanelTitle.Render("Folders:")
+	if m.folderBrowserHidden {
+		foldersTitle += styles.DimmedText.Render(" (including hidden)")
+	}
+	content.WriteString(foldersTitle + "\n")
+
+	visibleLines := popupHeight - 12
+	if visibleLines < 3 {
+		visibleLines = 3
+	}
+
+	endIdx := m.folderBrowserScroll + visibleLines
+	if endIdx > len(m.folderBrowserEntries) {
+		endIdx = len(m.folderBrowserEntries)
+	}
+
+	if len(m.folderBrowserEntries) == 0 {
+		content.WriteString(styles.DimmedText.Render("  (empty directory)") + "\n")
+	} else {
+		for i := m.folderBrowserScroll; i < endIdx; i++ {
+			entry := m.folderBrowserEntries[i]
+			icon := "📁"
+
+			line := fmt.Sprintf("%s %s", icon, entry)
+			line = truncateWidth(line, popupWidth-6)
+
+			if i == m.folderBrowserCursor {
+				content.WriteString(styles.SelectedItem.Render("> ") + line + "\n")
+			} else {
+				content.WriteString("  " + line + "\n")
+			}
+		}
+	}
+
+	// Scroll indicator
+	if len(m.folderBrowserEntries) > visibleLines {
+		content.WriteString(styles.DimmedText.Render(fmt.Sprintf("\n[%d/%d]", m.folderBrowserCursor+1, len(m.folderBrowserEntries))) + "\n")
+	}
+
+	// Build popup panel
+	popup := components.SimpleBorderedPanel(
+		"Select Download Location",
+		content.String(),
+		popupWidth,
+		popupHeight,
+		true,
+	)
+
+	// Center the popup
+	popupLines := strings.Split(popup, "\n")
+	topPadding := (m.height - len(popupLines)) / 2
+	leftPadding := (m.width - popupWidth) / 2
+	if topPadding < 0 {
+		topPadding = 0
+	}
+	if leftPadding < 0 {
+		leftPadding = 0
+	}
+
+	var result strings.Builder
+	for i := 0; i < topPadding; i++ {
+		result.WriteString("\n")
+	}
+	for _, line := range popupLines {
+		result.WriteString(strings.Repeat(" ", leftPadding) + line + "\n")
+	}
+
+	// Status bar at bottom
+	folderStatusContent := styles.StatusBarKey.Render("Esc") + styles.StatusBarDesc.Render(" cancel") + " │ " +
+		styles.StatusBarKey.Render("j/k") + styles.StatusBarDesc.Render(" navigate") + " │ " +
+		styles.StatusBarKey.Render("l/Enter") + styles.StatusBarDesc.Render(" open") + " │ " +
+		styles.StatusBarKey.Render("h/Bksp") + styles.StatusBarDesc.Render(" up") + " │ " +
+		styles.StatusBarKey.Render("~") + styles.StatusBarDesc.Render(" home") + " │ " +
+		styles.StatusBarKey.Render(".") + styles.StatusBarDesc.Render(" hidden") + " │ " +
+		styles.StatusBarKey.Render("d/Space") + styles.StatusBarDesc.Render(" download here")
+
+	// Pad to bottom
+	folderCurrentLines := topPadding + len(popupLines)
+	for i := folderCurrentLines; i < m.height-1; i++ {
+		result.WriteString("\n")
+	}
+
+	result.WriteString(styles.StatusBar.Width(m.width).Render(folderStatusContent))
+
+	return result.String()
+}
+
+// currentMyMRs returns the merge request list for the active tab of the my MRs popup
+func (m *MainScreen) currentMyMRs() []gitlab.MergeRequest {
+	if m.myMRsTab == 1 {
+		return m.myReviewMRs
+	}
+	return m.myAssignedMRs
+}
+
+// mrProjectPath extracts the project path from an MR's full reference ("group/project!12")
+func mrProjectPath(mr gitlab.MergeRequest) string {
+	if idx := strings.LastIndex(mr.References.Full, "!"); idx > 0 {
+		return mr.References.Full[:idx]
+	}
+	return fmt.Sprintf("#%d", mr.ProjectID)
+}
+
+// handleMyMRsPopup handles keyboard input for the my merge requests popup
+func (m *MainScreen) handleMyMRsPopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	mrs := m.currentMyMRs()
+
+	switch msg.String() {
+	case "q", "esc", "escape":
+		m.showMyMRsPopup = false
+		return m, nil
+	case "j", "down":
+		if m.myMRsCursor < len(mrs)-1 {
+			m.myMRsCursor++
+		}
+	case "k", "up":
+		if m.myMRsCursor > 0 {
+			m.myMRsCursor--
+		}
+	case "tab", "l", "right", "shift+tab", "h", "left":
+		// Switch between assigned/review tabs
+		m.myMRsTab = (m.myMRsTab + 1) % 2
+		m.myMRsCursor = 0
+	case "r":
+		// Manual refresh
+		if !m.isDemo {
+			m.myMRsLoading = true
+			return m, m.loadMyMRs()
+		}
+	case "g":
+		if m.myMRsLastKey == "g" {
+			m.myMRsCursor = 0
+			m.myMRsLastKey = ""
+			return m, nil
+		}
+		m.myMRsLastKey = "g"
+		return m, nil
+	case "G":
+		m.myMRsCursor = max(len(mrs)-1, 0)
+	case "enter":
+		if m.myMRsCursor >= len(mrs) {
+			return m, nil
+		}
+		mr := mrs[m.myMRsCursor]
+		m.showMyMRsPopup = false
+		// Demo mode resolves the project from the mock data
+		if m.isDemo {
+			for _, projects := range m.groupProjects {
+				for i := range projects {
+					if projects[i].ID == mr.ProjectID {
+						m.selectProject(&projects[i])
+						if m.tabVisible(TabMRs) {
+							m.contentTab = TabMRs
+						}
+						m.openMRDetail(mr)
+						return m, nil
+					}
+				}
+			}
+			return m, nil
+		}
+		m.loading = true
+		m.loadingMsg = "Loading project..."
+		cmd := m.loadMRProject(mr)
+		m.retryCmd = cmd
+		return m, cmd
+	}
+	// Clear key sequence for non-sequence keys
+	m.myMRsLastKey = ""
+	return m, nil
+}
+
+// renderMyMRsPopup renders the my merge requests popup
+func (m *MainScreen) renderMyMRsPopup() string {
+	popupWidth := int(float64(m.width) * 0.8)
+	popupHeight := int(float64(m.height) * 0.8)
+
+	if popupWidth < 60 {
+		popupWidth = 60
+	}
+	if popupHeight < 15 {
+		popupHeight = 15
+	}
+	if popupWidth > m.width-4 {
+		popupWidth = m.width - 4
+	}
+	if popupHeight > m.height-4 {
+		popupHeight = m.height - 4
+	}
+
+	var content strings.Builder
+
+	// Tab headers
+	assignedTab := fmt.Sprintf("Assigned (%d)", len(m.myAssignedMRs))
+	reviewTab := fmt.Sprintf("To Review (%d)", len(m.myReviewMRs))
+
+	if m.myMRsTab == 0 {
+		content.WriteString(styles.SelectedItem.Render("["+assignedTab+"]") + " " + styles.DimmedText.Render(reviewTab))
+	} else {
+		content.WriteString(styles.DimmedText.Render(assignedTab) + " " + styles.SelectedItem.Render("["+reviewTab+"]"))
+	}
+	content.WriteString("\n\n")
+
+	mrs := m.currentMyMRs()
+
+	if m.myMRsErr != "" {
+		content.WriteString(lipgloss.NewStyle().Foreground(styles.ColorRed).Render("Error: " + m.myMRsErr))
+	} else if m.myMRsLoading && len(mrs) == 0 {
+		content.WriteString(styles.DimmedText.Render("Loading merge requests..."))
+	} else if len(mrs) == 0 {
+		if m.myMRsTab == 0 {
+			content.WriteString(styles.DimmedText.Render("No merge requests assigned to you"))
+		} else {
+			content.WriteString(styles.DimmedText.Render("No merge requests awaiting your review"))
+		}
+	} else {
+		visibleLines := popupHeight - 8
+		if visibleLines < 5 {
+			visibleLines = 5
+		}
+
+		// Calculate scroll offset
+		startIdx := 0
+		if m.myMRsCursor >= visibleLines {
+			startIdx = m.myMRsCursor - visibleLines + 1
+		}
+		endIdx := startIdx + visibleLines
+		if endIdx > len(mrs) {
+			endIdx = len(mrs)
+		}
+
+		// Column header
+		titleWidth := popupWidth - 4 - 2 - 31 - 8 - 10
+		if titleWidth < 10 {
+			titleWidth = 10
+		}
+		header := fmt.Sprintf("  %-30s %-7s %-*s %s", "PROJECT", "MR", titleWidth, "TITLE", "AGE")
+		content.WriteString(styles.DimmedText.Render(header) + "\n")
+		content.WriteString(styles.DimmedText.Render(strings.Repeat("─", popupWidth-4)) + "\n")
+
+		for i := startIdx; i < endIdx; i++ {
+			mr := mrs[i]
+
+			project := mrProjectPath(mr)
+			if runes := []rune(project); len(runes) > 30 {
+				project = "…" + string(runes[len(runes)-29:])
+			}
+
+			title := mr.Title
+			if mr.Draft {
+				title = "◐ " + title
+			}
+			if lipgloss.Width(title) > titleWidth {
+				title = hardTruncate(title, titleWidth-1) + "…"
+			}
+			titlePad := titleWidth - lipgloss.Width(title)
+			if titlePad < 0 {
+				titlePad = 0
+			}
+
+			line := fmt.Sprintf("%-30s %-7s %s%s %s",
+				project,
+				fmt.Sprintf("!%d", mr.IID),
+				title,
+				strings.Repeat(" ", titlePad),
+				styles.DimmedText.Render(timeAgo(mr.CreatedAt)))
+
+			if i == m.myMRsCursor {
+				line = styles.SelectedItem.Render("> ") + line
+			} else {
+				line = "  " + line
+			}
+			content.WriteString(line + "\n")
+		}
+
+		// Scroll indicator
+		if len(mrs) > visibleLines {
+			content.WriteString(styles.DimmedText.Render(fmt.Sprintf("\n[%d/%d]", m.myMRsCursor+1, len(mrs))))
+		}
+	}
+
+	// Build popup panel
+	title := "My Merge Requests"
+	if m.myMRsLoading {
+		title += " (loading...)"
+	}
+	popup := components.SimpleBorderedPanel(title, content.String(), popupWidth, popupHeight, true)
+
+	// Center the popup
+	popupLines := strings.Split(popup, "\n")
+	topPadding := (m.height - len(popupLines)) / 2
+	leftPadding := (m.width - popupWidth) / 2
+	if topPadding < 0 {
+		topPadding = 0
+	}
+	if leftPadding < 0 {
+		leftPadding = 0
+	}
+
+	var result strings.Builder
+	for i := 0; i < topPadding; i++ {
+		result.WriteString("\n")
+	}
+	for _, line := range popupLines {
+		result.WriteString(strings.Repeat(" ", leftPadding) + line + "\n")
+	}
+
+	// Status bar at bottom
+	statusContent := styles.StatusBarKey.Render("Esc") + styles.StatusBarDesc.Render(" close") + " │ " +
+		styles.StatusBarKey.Render("Tab") + styles.StatusBarDesc.Render(" switch") + " │ " +
+		styles.StatusBarKey.Render("j/k") + styles.StatusBarDesc.Render(" navigate") + " │ " +
+		styles.StatusBarKey.Render("Enter") + styles.StatusBarDesc.Render(" open") + " │ " +
+		styles.StatusBarKey.Render("r") + styles.StatusBarDesc.Render(" refresh")
+
+	// Pad to bottom
+	currentLines := topPadding + len(popupLines)
+	for i := currentLines; i < m.height-1; i++ {
+		result.WriteString("\n")
+	}
+
+	result.WriteString(styles.StatusBar.Width(m.width).Render(statusContent))
+
+	return result.String()
+}
+
+// openMRDetail shows the detail popup for a merge request
+func (m *MainScreen) openMRDetail(mr gitlab.MergeRequest) {
+	m.mrDetail = &mr
+	m.mrDetailReady = false
+	m.showMRDetailPopup = true
+}
+
+// handleMRDetailPopup handles keyboard input for the merge request detail popup
+func (m *MainScreen) handleMRDetailPopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc", "escape":
+		m.showMRDetailPopup = false
+	case "j", "down":
+		m.mrDetailViewport.ScrollDown(1)
+	case "k", "up":
+		m.mrDetailViewport.ScrollUp(1)
+	case "ctrl+d":
+		m.mrDetailViewport.HalfPageDown()
+	case "ctrl+u":
+		m.mrDetailViewport.HalfPageUp()
+	case "g":
+		m.mrDetailViewport.GotoTop()
+	case "G":
+		m.mrDetailViewport.GotoBottom()
+	case "y":
+		// Copy MR web URL
+		if m.mrDetail != nil && m.mrDetail.WebURL != "" {
+			if err := copyToClipboard(m.mrDetail.WebURL); err != nil {
+				m.statusMsg = "Copy failed: " + err.Error()
+			} else {
+				m.statusMsg = "Copied MR URL"
+			}
+		}
+	case "p":
+		// Copy the head pipeline URL
+		if m.mrDetail == nil {
+			return m, nil
+		}
+		if m.mrDetail.HeadPipeline == nil || m.mrDetail.HeadPipeline.WebURL == "" {
+			m.statusMsg = "No pipeline for this MR"
+			return m, nil
+		}
+		if err := copyToClipboard(m.mrDetail.HeadPipeline.WebURL); err != nil {
+			m.statusMsg = "Copy failed: " + err.Error()
+		} else {
+			m.statusMsg = "Copied pipeline URL"
+		}
+	case "a":
+		// Copy the review app deployed from the MR's source branch
+		if m.mrDetail == nil {
+			return m, nil
+		}
+		if m.isDemo {
+			m.copyReviewApp(m.mrDetail.SourceBranch, reviewAppURL(mockEnvironments(), m.mrDetail.SourceBranch))
+			return m, nil
+		}
+		m.statusMsg = "Looking up review app..."
+		return m, m.loadReviewApp(*m.mrDetail)
+	case "b":
+		// Browse the files of the MR's source branch
+		if m.mrDetail == nil {
+			return m, nil
+		}
+		if m.isDemo {
+			return m, m.browseMRBranch(m.mrDetail.SourceBranch)
+		}
+		m.statusMsg = "Checking " + m.mrDetail.SourceBranch + "..."
+		return m, m.checkMRBranch(*m.mrDetail)
+	}
+	return m, nil
+}
+
+// mrBranchCheckedMsg reports whether an MR's source branch still exists
+type mrBranchCheckedMsg struct {
+	projectID int
+	iid       int
+	branch    string
+	err       error
+}
+
+// checkMRBranch looks up an MR's source branch, which is often deleted on merge
+func (m *MainScreen) checkMRBranch(mr gitlab.MergeRequest) tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
+		return nil
+	}
+	project := m.selectedProject.ID
+	projectID := fmt.Sprintf("%d", project)
+
+	return func() tea.Msg {
+		_, err := m.client.ResolveRefSHA(projectID, mr.SourceBranch)
+		return mrBranchCheckedMsg{projectID: project, iid: mr.IID, branch: mr.SourceBranch, err: err}
+	}
+}
+
+// browseMRBranch closes the MR popup and shows the files of branch
+func (m *MainScreen) browseMRBranch(branch string) tea.Cmd {
+	m.showMRDetailPopup = false
+	m.contentTab = TabFiles
+	m.selectedContent = 0
+	m.focusedPanel = PanelContent
+	m.rememberProject()
+	m.statusMsg = "Browsing " + branch
+	return m.switchRef(branch)
+}
+
+// failureJobsLoadedMsg carries the jobs of a pipeline whose failure summary
+// is waiting to be copied
+type failureJobsLoadedMsg struct {
+	projectID int
+	pipeline  gitlab.Pipeline
+	jobs      []gitlab.Job
+	err       error
+}
+
+// loadFailureSummary fetches a pipeline's jobs so its failure summary can be copied
+func (m *MainScreen) loadFailureSummary(p gitlab.Pipeline) tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
+		return nil
+	}
+	project := m.selectedProject.ID
+	projectID := fmt.Sprintf("%d", project)
+
+	return func() tea.Msg {
+		jobs, err := m.client.ListPipelineJobs(projectID, p.ID)
+		return failureJobsLoadedMsg{projectID: project, pipeline: p, jobs: jobs, err: err}
+	}
+}
+
+// pipelineFailureSummary describes a pipeline and its failed jobs in one
+// line, e.g. "Pipeline #42 on main FAILED — test/unit (failed)"
+func pipelineFailureSummary(p gitlab.Pipeline, jobs []gitlab.Job) string {
+	summary := fmt.Sprintf("Pipeline #%d on %s %s", p.IID, p.Ref, strings.ToUpper(p.Status))
+	var failed []string
+	for _, stage := range groupJobsByStage(jobs) {
+		for _, job := range stage.jobs {
+			if job.Status == "failed" {
+				failed = append(failed, fmt.Sprintf("%s/%s (%s)", job.Stage, job.Name, job.Status))
+			}
+		}
+	}
+	if len(failed) > 0 {
+		summary += " — " + strings.Join(failed, ", ")
+	}
+	return summary
+}
+
+// copyFailureSummary copies a pipeline failure summary and reports it
+func (m *MainScreen) copyFailureSummary(summary string) {
+	if err := copyToClipboard(summary); err != nil {
+		m.statusMsg = "Copy failed: " + err.Error()
+		return
+	}
+	m.statusMsg = "Copied: " + summary
+}
+
+// reviewAppLoadedMsg carries the review app URL of a merge request, empty if none
+type reviewAppLoadedMsg struct {
+	projectID int
+	iid       int
+	branch    string
+	url       string
+	err       error
+}
+
+// loadReviewApp looks up the environment deployed from an MR's source branch
+func (m *MainScreen) loadReviewApp(mr gitlab.MergeRequest) tea.Cmd {
+	if m.selectedProject == nil || m.isDemo {
+		return nil
+	}
+	project := m.selectedProject.ID
+	projectID := fmt.Sprintf("%d", project)
+	client := m.client
+	sem := m.limiter()
+
+	return func() tea.Msg {
+		// Only available environments with a URL can be the review app
+		environments, err := client.ListAvailableEnvironments(projectID)
+		environments = slices.DeleteFunc(environments, func(e gitlab.Environment) bool { return e.ExternalURL == "" })
+		fillLastDeployments(client, sem, projectID, environments)
+		return reviewAppLoadedMsg{
+			projectID: project,
+			iid:       mr.IID,
+			branch:    mr.SourceBranch,
+			url:       reviewAppURL(environments, mr.SourceBranch),
+			err:       err,
+		}
+	}
+}
+
+// reviewAppURL returns the external URL of the available environment last
+// deployed from branch, preferring review/ environments over shared ones
+// like staging that happen to run the branch
+func reviewAppURL(environments []gitlab.Environment, branch string) string {
+	var fallback string
+	for _, env := range environments {
+		if env.State != "available" || env.ExternalURL == "" ||
+			env.LastDeployment == nil || env.LastDeployment.Ref != branch {
+			continue
+		}
+		if strings.HasPrefix(env.Name, "review/") {
+			return env.ExternalURL
+		}
+		if fallback == "" {
+			fallback = env.ExternalURL
+		}
+	}
+	return fallback
+}
+
+// copyReviewApp copies a review app URL, or says there is none for branch
+func (m *MainScreen) copyReviewApp(branch, url string) {
+	if url == "" {
+		m.statusMsg = "No review app for " + branch
+		return
+	}
+	if err := copyToClipboard(url); err != nil {
+		m.statusMsg = "Copy failed: " + err.Error()
+	} else {
+		m.statusMsg = "Review app: " + url
+	}
+}
+
+// renderMRDetailPopup renders the merge request detail popup
+func (m *MainScreen) renderMRDetailPopup() string {
+	if m.mrDetail == nil {
+		return ""
+	}
+	mr := m.mrDetail
+
+	popupWidth := int(float64(m.width) * 0.8)
+	popupHeight := int(float64(m.height) * 0.8)
+
+	if popupWidth < 60 {
+		popupWidth = 60
+	}
+	if popupHeight < 15 {
+		popupHeight = 15
+	}
+	if popupWidth > m.width-4 {
+		popupWidth = m.width - 4
+	}
+	if popupHeight > m.height-4 {
+		popupHeight = m.height - 4
+	}
+	innerWidth := popupWidth - 4
+
+	var header strings.Builder
+
+	// Title, state and branches
+	header.WriteString(styles.SelectedItem.Render(hardTruncate(fmt.Sprintf("!%d %s", mr.IID, mr.Title), innerWidth)) + "\n")
+	state := mr.State
+	if mr.Draft {
+		state = "draft"
+	}
+	header.WriteString(styles.MRStatus(mr.State, mr.Draft).Render(state) +
+		styles.DimmedText.Render(fmt.Sprintf(" · @%s · %s", mr.Author.Username, timeAgo(mr.CreatedAt))) + "\n")
+	header.WriteString(styles.DimmedText.Render(fmt.Sprintf("%s → %s", mr.SourceBranch, mr.TargetBranch)))
+	if mr.HasConflicts {
+		header.WriteString(lipgloss.NewStyle().Foreground(styles.ColorRed).Render(" (conflicts)"))
+	}
+	header.WriteString("\n")
+
+	if len(mr.Reviewers) > 0 {
+		var names []string
+		for _, r := range mr.Reviewers {
+			names = append(names, "@"+r.Username)
+		}
+		header.WriteString(styles.DimmedText.Render(hardTruncate("Reviewers: "+strings.Join(names, ", "), innerWidth)) + "\n")
+	}
+	if len(mr.Labels) > 0 {
+		header.WriteString(styles.DimmedText.Render(hardTruncate("Labels: "+strings.Join(mr.Labels, ", "), innerWidth)) + "\n")
+	}
+	header.WriteString(styles.DimmedText.Render(strings.Repeat("─", innerWidth)) + "\n")
+
+	// Description in a scrollable viewport
+	headerLines := strings.Count(header.String(), "\n")
+	viewHeight := popupHeight - 3 - headerLines
+	if viewHeight < 3 {
+		viewHeight = 3
+	}
+	if !m.mrDetailReady || m.mrDetailViewport.Width != innerWidth || m.mrDetailViewport.Height != viewHeight {
+		m.mrDetailViewport = viewport.New(innerWidth, viewHeight)
+		description := styles.DimmedText.Render("No description")
+		if strings.TrimSpace(mr.Description) != "" {
+			description = renderMarkdown(mr.Description, innerWidth)
+		}
+		m.mrDetailViewport.SetContent(description)
+		m.mrDetailReady = true
+	}
+
+	content := header.String() + m.mrDetailViewport.View()
+
+	popup := components.SimpleBorderedPanel("Merge Request", content, popupWidth, popupHeight, true)
+
+	// Center the popup
+	popupLines := strings.Split(popup, "\n")
+	topPadding := (m.height - len(popupLines)) / 2
+	leftPadding := (m.width - popupWidth) / 2
+	if topPadding < 0 {
+		topPadding = 0
+	}
+	if leftPadding < 0 {
+		leftPadding = 0
+	}
+
+	var result strings.Builder
+	for i := 0; i < topPadding; i++ {
+		result.WriteString("\n")
+	}
+	for _, line := range popupLines {
+		result.WriteString(strings.Repeat(" ", leftPadding) + line + "\n")
+	}
+
+	// Status bar at bottom
+	statusContent := styles.StatusBarKey.Render("Esc") + styles.StatusBarDesc.Render(" close") + " │ " +
+		styles.StatusBarKey.Render("j/k") + styles.StatusBarDesc.Render(" scroll") + " │ " +
+		styles.StatusBarKey.Render("y") + styles.StatusBarDesc.Render(" copy URL") + " │ " +
+		styles.StatusBarKey.Render("p") + styles.StatusBarDesc.Render(" pipeline") + " │ " +
+		styles.StatusBarKey.Render("a") + styles.StatusBarDesc.Render(" review app") + " │ " +
+		styles.StatusBarKey.Render("b") + styles.StatusBarDesc.Render(" browse branch")
+	if m.statusMsg != "" {
+		statusContent = styles.SelectedItem.Render(m.statusMsg) + " │ " + statusContent
+	}
+
+	// Pad to bottom
+	currentLines := topPadding + len(popupLines)
+	for i := currentLines; i < m.height-1; i++ {
+		result.WriteString("\n")
+	}
+
+	result.WriteString(styles.StatusBar.Width(m.width).Render(statusContent))
+
+	return result.String()
+}
+
+// comparisonIsEmpty reports whether two refs have no differences
+func comparisonIsEmpty(c *gitlab.Comparison) bool {
+	return c.CompareSameRef || (len(c.Commits) == 0 && len(c.Diffs) == 0)
+}
+
+// renderDiff renders file diffs with a header per file and colored
+// additions, deletions and hunk markers, truncating lines to width
+func renderDiff(diffs []gitlab.Diff, width int) string {
+	addStyle := lipgloss.NewStyle().Foreground(styles.ColorGreen)
+	delStyle := lipgloss.NewStyle().Foreground(styles.ColorRed)
+	hunkStyle := lipgloss.NewStyle().Foreground(styles.ColorCyan)
+	fileStyle := lipgloss.NewStyle().Bold(true)
+
+	var b strings.Builder
+	for i, d := range diffs {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		name := d.NewPath
+		switch {
+		case d.NewFile:
+			name += " (new)"
+		case d.DeletedFile:
+			name = d.OldPath + " (deleted)"
+		case d.RenamedFile:
+			name = d.OldPath + " → " + d.NewPath
+		}
+		b.WriteString(fileStyle.Render(hardTruncate(name, width)) + "\n")
+
+		if d.Diff == "" {
+			b.WriteString(styles.DimmedText.Render("  (binary or too large to show)") + "\n")
+			continue
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(d.Diff, "\n"), "\n") {
+			line = sliceByWidth(strings.ReplaceAll(line, "\t", "    "), 0, width)
+			switch {
+			case strings.HasPrefix(line, "@@"):
+				line = hunkStyle.Render(line)
+			case strings.HasPrefix(line, "+"):
+				line = addStyle.Render(line)
+			case strings.HasPrefix(line, "-"):
+				line = delStyle.Render(line)
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+	return b.String()
+}
+
+// openComparison opens the comparison popup and loads the diff between two branches
+func (m *MainScreen) openComparison(from, to string) tea.Cmd {
+	m.showComparePopup = true
+	m.compareFrom = from
+	m.compareTo = to
+	m.compareErr = ""
+	m.compareReady = false
+	if m.isDemo {
+		m.comparison = mockComparison()
+		return nil
+	}
+	m.comparison = nil
+	m.compareLoading = true
+	return m.loadComparison(from, to)
+}
+
+// handleComparePopup handles keyboard input for the branch comparison popup
+func (m *MainScreen) handleComparePopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc", "escape":
+		m.showComparePopup = false
+	case "j", "down":
+		m.compareViewport.ScrollDown(1)
+	case "k", "up":
+		m.compareViewport.ScrollUp(1)
+	case "ctrl+d":
+		m.compareViewport.HalfPageDown()
+	case "ctrl+u":
+		m.compareViewport.HalfPageUp()
+	case "g":
+		m.compareViewport.GotoTop()
+	case "G":
+		m.compareViewport.GotoBottom()
+	}
+	return m, nil
+}
+
+// compareContent builds the scrollable body of the comparison popup
+func (m *MainScreen) compareContent(width int) string {
+	if m.compareErr != "" {
+		return lipgloss.NewStyle().Foreground(styles.ColorRed).Render("Error: " + m.compareErr)
+	}
+	if m.comparison == nil {
+		return styles.DimmedText.Render("Loading comparison...")
+	}
+	c := m.comparison
+	if comparisonIsEmpty(c) {
+		return styles.DimmedText.Render(fmt.Sprintf("%s and %s are identical, nothing to compare.", m.compareFrom, m.compareTo))
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%d commits · %d files changed\n", len(c.Commits), len(c.Diffs)))
+	if c.CompareTimeout {
+		b.WriteString(lipgloss.NewStyle().Foreground(styles.ColorYellow).Render("Comparison timed out, results may be incomplete") + "\n")
+	}
+
+	b.WriteString("\n" + styles.SelectedItem.Render("Commits") + "\n")
+	if len(c.Commits) == 0 {
+		b.WriteString(styles.DimmedText.Render("  No commits") + "\n")
+	}
+	for _, commit := range c.Commits {
+		meta := fmt.Sprintf(" · %s · %s", commit.AuthorName, timeAgo(commit.AuthoredDate))
+		title := hardTruncate(commit.Title, max(width-10-lipgloss.Width(meta), 10))
+		b.WriteString(styles.DimmedText.Render(shortSHA(commit.ID)) + " " + title + styles.DimmedText.Render(meta) + "\n")
+	}
+
+	b.WriteString("\n" + styles.SelectedItem.Render("Changes") + "\n")
+	b.WriteString(renderDiff(c.Diffs, width))
+	return b.String()
+}
+
+// renderComparePopup renders the branch comparison popup
+func (m *MainScreen) renderComparePopup() string {
+	popupWidth := int(float64(m.width) * 0.8)
+	popupHeight := int(float64(m.height) * 0.8)
+
+	if popupWidth < 60 {
+		popupWidth = 60
+	}
+	if popupHeight < 15 {
+		popupHeight = 15
+	}
+	if popupWidth > m.width-4 {
+		popupWidth = m.width - 4
+	}
+	if popupHeight > m.height-4 {
+		popupHeight = m.height - 4
+	}
+	innerWidth := popupWidth - 4
+	viewHeight := popupHeight - 3
+
+	if !m.compareReady || m.compareViewport.Width != innerWidth || m.compareViewport.Height != viewHeight {
+		m.compareViewport = viewport.New(innerWidth, viewHeight)
+		m.compareViewport.SetContent(m.compareContent(innerWidth))
+		m.compareReady = true
+	}
+
+	title := fmt.Sprintf("Compare %s...%s", m.compareFrom, m.compareTo)
+	if m.compareLoading {
+		title += " (loading...)"
+	}
+	popup := components.SimpleBorderedPanel(title, m.compareViewport.View(), popupWidth, popupHeight, true)
+
+	// Center the popup
+	popupLines := strings.Split(popup, "\n")
+	topPadding := (m.height - len(popupLines)) / 2
+	leftPadding := (m.width - popupWidth) / 2
+	if topPadding < 0 {
+		topPadding = 0
+	}
+	if leftPadding < 0 {
+		leftPadding = 0
+	}
+
+	var result strings.Builder
+	for i := 0; i < topPadding; i++ {
+		result.WriteString("\n")
+	}
+	for _, line := range popupLines {
+		result.WriteString(strings.Repeat(" ", leftPadding) + line + "\n")
+	}
+
+	// Status bar at bottom
+	statusContent := styles.StatusBarKey.Render("Esc") + styles.StatusBarDesc.Render(" close") + " │ " +
+		styles.StatusBarKey.Render("j/k") + styles.StatusBarDesc.Render(" scroll") + " │ " +
+		styles.StatusBarKey.Render("C-d/C-u") + styles.StatusBarDesc.Render(" page") + " │ " +
+		styles.StatusBarKey.Render("g/G") + styles.StatusBarDesc.Render(" top/bottom")
+	if m.statusMsg != "" {
+		statusContent = styles.SelectedItem.Render(m.statusMsg) + " │ " + statusContent
+	}
+
+	// Pad to bottom
+	currentLines := topPadding + len(popupLines)
+	for i := currentLines; i < m.height-1; i++ {
+		result.WriteString("\n")
+	}
+
+	result.WriteString(styles.StatusBar.Width(m.width).Render(statusContent))
+
+	return result.String()
+}
+
+// openCommitDiff opens the popup with the diff of the entry's last commit
+func (m *MainScreen) openCommitDiff(entry gitlab.TreeEntry) tea.Cmd {
+	m.showCommitDiffPopup = true
+	m.commitDiffPath = entry.Path
+	m.commitDiffDir = entry.Type == "tree"
+	m.commitDiffCommit = entry.LastCommit
+	m.commitDiffErr = ""
+	m.commitDiffReady = false
+	m.commitDiffFile = 0
+	if m.isDemo {
+		m.commitDiffs = mockCommitDiff(entry)
+		return nil
+	}
+	m.commitDiffs = nil
+	m.commitDiffLoading = true
+	return m.loadCommitDiff(entry)
+}
+
+// handleCommitDiffPopup handles keyboard input for the last commit diff popup
+func (m *MainScreen) handleCommitDiffPopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc", "escape":
+		m.showCommitDiffPopup = false
+	case "j", "down":
+		m.commitDiffViewport.ScrollDown(1)
+	case "k", "up":
+		m.commitDiffViewport.ScrollUp(1)
+	case "ctrl+d":
+		m.commitDiffViewport.HalfPageDown()
+	case "ctrl+u":
+		m.commitDiffViewport.HalfPageUp()
+	case "g":
+		m.commitDiffViewport.GotoTop()
+	case "G":
+		m.commitDiffViewport.GotoBottom()
+	case "n":
+		m.commitDiffFile = min(m.commitDiffFile+1, max(len(m.commitDiffs)-1, 0))
+	case "p":
+		m.commitDiffFile = max(m.commitDiffFile-1, 0)
+	case "o":
+		return m, m.openDiffFileAtCommit()
+	}
+	return m, nil
+}
+
+// openDiffFileAtCommit opens the selected changed file in the file viewer
+// as it was at the popup's commit
+func (m *MainScreen) openDiffFileAtCommit() tea.Cmd {
+	if m.commitDiffCommit == nil || m.commitDiffFile >= len(m.commitDiffs) {
+		return nil
+	}
+	if m.isDemo {
+		m.statusMsg = "Older file versions aren't available in demo mode"
+		return nil
+	}
+	filePath := m.commitDiffs[m.commitDiffFile].NewPath
+	m.statusMsg = "Opening " + filePath + " at " + shortSHA(m.commitDiffCommit.ID) + "..."
+	return m.loadFileAt(filePath, m.commitDiffCommit.ID)
+}
+
+// commitDiffContent builds the scrollable body of the last commit diff popup
+func (m *MainScreen) commitDiffContent(width int) string {
+	if m.commitDiffErr != "" {
+		return lipgloss.NewStyle().Foreground(styles.ColorRed).Render("Error: " + m.commitDiffErr)
+	}
+
+	var b strings.Builder
+	if c := m.commitDiffCommit; c != nil {
+		meta := fmt.Sprintf(" · %s · %s", c.AuthorName, timeAgo(c.AuthoredDate))
+		title := hardTruncate(c.Title, max(width-10-lipgloss.Width(meta), 10))
+		b.WriteString(styles.DimmedText.Render(shortSHA(c.ID)) + " " + title + styles.DimmedText.Render(meta) + "\n\n")
+	}
+	switch {
+	case m.commitDiffLoading:
+		b.WriteString(styles.DimmedText.Render("Loading diff..."))
+	case len(m.commitDiffs) == 0:
+		b.WriteString(styles.DimmedText.Render("No changes to " + m.commitDiffPath + " in this commit."))
+	default:
+		b.WriteString(renderDiff(m.commitDiffs, width))
+	}
+	return b.String()
+}
+
+// renderCommitDiffPopup renders the last commit diff popup
+func (m *MainScreen) renderCommitDiffPopup() string {
+	popupWidth := int(float64(m.width) * 0.8)
+	popupHeight := int(float64(m.height) * 0.8)
+
+	if popupWidth < 60 {
+		popupWidth = 60
+	}
+	if popupHeight < 15 {
+		popupHeight = 15
+	}
+	if popupWidth > m.width-4 {
+		popupWidth = m.width - 4
+	}
+	if popupHeight > m.height-4 {
+		popupHeight = m.height - 4
+	}
+	innerWidth := popupWidth - 4
+	viewHeight := popupHeight - 3
+
+	if !m.commitDiffReady || m.commitDiffViewport.Width != innerWidth || m.commitDiffViewport.Height != viewHeight {
+		m.commitDiffViewport = viewport.New(innerWidth, viewHeight)
+		m.commitDiffViewport.SetContent(m.commitDiffContent(innerWidth))
+		m.commitDiffReady = true
+	}
+
+	title := "Last commit · " + m.commitDiffPath
+	if m.commitDiffDir {
+		title += "/"
+	}
+	if m.commitDiffLoading {
+		title += " (loading...)"
+	}
+	popup := components.SimpleBorderedPanel(truncateWidth(title, innerWidth), m.commitDiffViewport.View(), popupWidth, popupHeight, true)
+
+	// Center the popup
+	popupLines := strings.Split(popup, "\n")
+	topPadding := (m.height - len(popupLines)) / 2
+	leftPadding := (m.width - popupWidth) / 2
+	if topPadding < 0 {
+		topPadding = 0
+	}
+	if leftPadding < 0 {
+		leftPadding = 0
+	}
+
+	var result strings.Builder
+	for i := 0; i < topPadding; i++ {
+		result.WriteString("\n")
+	}
+	for _, line := range popupLines {
+		result.WriteString(strings.Repeat(" ", leftPadding) + line + "\n")
+	}
+
+	// Status bar at bottom
+	statusContent := styles.StatusBarKey.Render("Esc") + styles.StatusBarDesc.Render(" close") + " │ " +
+		styles.StatusBarKey.Render("j/k") + styles.StatusBarDesc.Render(" scroll") + " │ " +
+		styles.StatusBarKey.Render("C-d/C-u") + styles.StatusBarDesc.Render(" page") + " │ " +
+		styles.StatusBarKey.Render("g/G") + styles.StatusBarDesc.Render(" top/bottom")
+	if m.commitDiffCommit != nil && m.commitDiffFile < len(m.commitDiffs) {
+		if len(m.commitDiffs) > 1 {
+			statusContent += " │ " + styles.StatusBarKey.Render("n/p") +
+				styles.StatusBarDesc.Render(fmt.Sprintf(" file %d/%d", m.commitDiffFile+1, len(m.commitDiffs)))
+		}
+		statusContent += " │ " + styles.StatusBarKey.Render("o") +
+			styles.StatusBarDesc.Render(" open "+path.Base(m.commitDiffs[m.commitDiffFile].NewPath)+" at "+shortSHA(m.commitDiffCommit.ID))
+	}
+	if m.statusMsg != "" {
+		statusContent = styles.SelectedItem.Render(m.statusMsg) + " │ " + statusContent
+	}
+
+	// Pad to bottom
+	currentLines := topPadding + len(popupLines)
+	for i := currentLines; i < m.height-1; i++ {
+		result.WriteString("\n")
+	}
+
+	result.WriteString(styles.StatusBar.Width(m.width).Render(statusContent))
+
+	return result.String()
+}
+
+// orDash returns s, or "—" for a field the API didn't return
+func orDash(s string) string {
+	if s == "" {
+		return "—"
+	}
+	return s
+}
+
+// projectInfoRows returns the label/value rows of the project info popup
+func projectInfoRows(p *gitlab.Project) [][2]string {
+	activity := "—"
+	if !p.LastActivityAt.IsZero() {
+		activity = timeAgo(p.LastActivityAt)
+	}
+	return [][2]string{
+		{"Stars", fmt.Sprintf("%d", p.StarCount)},
+		{"Forks", fmt.Sprintf("%d", p.ForksCount)},
+		{"Last activity", activity},
+		{"Default branch", orDash(p.DefaultBranch)},
+		{"Visibility", orDash(p.Visibility)},
+		{"SSH", orDash(p.SSHURLToRepo)},
+		{"HTTPS", orDash(p.HTTPURLToRepo)},
+	}
+}
+
+// readmeSummary returns the first prose paragraph of a README, skipping
+// headings, badges, images and HTML
+func readmeSummary(content string) string {
+	var para []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			if len(para) > 0 {
+				break
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "<") ||
+			strings.HasPrefix(line, "![") || strings.HasPrefix(line, "[![") ||
+			strings.HasPrefix(line, "---") || strings.HasPrefix(line, "===") {
+			if len(para) > 0 {
+				break
+			}
+			continue
 		}
-	case "$":
-		// Go to end of line (find max line width)
-		if m.jobLogFocused && m.jobLog != "" {
-			lines := strings.Split(m.jobLog, "\n")
-			maxWidth := 0
-			for _, line := range lines {
-				w := lipgloss.Width(stripANSI(line))
-				if w > maxWidth {
-					maxWidth = w
-				}
+		para = append(para, line)
+	}
+	return strings.Join(para, "\n")
+}
+
+// handleProjectInfoPopup handles keyboard input for the project info popup
+func (m *MainScreen) handleProjectInfoPopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := m.infoProject
+	switch msg.String() {
+	case "q", "esc", "escape", "i":
+		m.showProjectInfo = false
+	case "S":
+		if p.SSHURLToRepo != "" {
+			if err := copyToClipboard(p.SSHURLToRepo); err != nil {
+				m.statusMsg = "Copy failed: " + err.Error()
+			} else {
+				m.statusMsg = "SSH: " + p.SSHURLToRepo
 			}
-			// Scroll to show end of longest line
-			if maxWidth > 80 {
-				m.jobLogHScroll = maxWidth - 80
+		}
+	case "U":
+		if p.HTTPURLToRepo != "" {
+			if err := copyToClipboard(p.HTTPURLToRepo); err != nil {
+				m.statusMsg = "Copy failed: " + err.Error()
+			} else {
+				m.statusMsg = "HTTPS: " + p.HTTPURLToRepo
 			}
 		}
 	}
 	return m, nil
 }
 
-func (m *MainScreen) switchTab(tab ContentTab) tea.Cmd {
-	m.contentTab = tab
-	m.selectedContent = 0
-	m.fileContent = ""
+// renderProjectInfoPopup renders the project info popup
+func (m *MainScreen) renderProjectInfoPopup() string {
+	p := m.infoProject
+	popupWidth := min(m.width-4, 80)
+	popupHeight := min(m.height-4, 26)
+	innerWidth := popupWidth - 4
 
-	if m.selectedProject == nil || m.isDemo {
-		return nil
+	var content strings.Builder
+	name := p.NameWithNamespace
+	if name == "" {
+		name = p.PathWithNamespace
 	}
-
-	switch tab {
-	case TabFiles:
-		if len(m.files) == 0 {
-			m.loading = true
-			m.loadingMsg = "Loading files..."
-			m.currentPath = nil
-			cmd := m.loadProjectContent()
-			m.retryCmd = cmd
-			return cmd
-		}
-	case TabMRs:
-		if len(m.mergeRequests) == 0 {
-			m.loading = true
-			m.loadingMsg = "Loading merge requests..."
-			cmd := m.loadMRs()
-			m.retryCmd = cmd
-			return cmd
-		}
-	case TabPipelines:
-		if len(m.pipelines) == 0 {
-			m.loading = true
-			m.loadingMsg = "Loading pipelines..."
-			cmd := m.loadPipelines()
-			m.retryCmd = cmd
-			return cmd
-		}
-	case TabReleases:
-		if len(m.releases) == 0 {
-			m.loading = true
-			m.loadingMsg = "Loading releases..."
-			cmd := m.loadReleases()
-			m.retryCmd = cmd
-			return cmd
-		}
+	content.WriteString(styles.ActivePanelTitle.Render(truncateWidth(orDash(name), innerWidth)) + "\n")
+	description := styles.DimmedText.Render("—")
+	if p.Description != "" {
+		description = lipgloss.NewStyle().Width(innerWidth).Render(p.Description)
 	}
-	return nil
-}
+	content.WriteString(description + "\n\n")
 
-func (m *MainScreen) getContentCount() int {
-	switch m.contentTab {
-	case TabFiles:
-		return len(m.files)
-	case TabMRs:
-		return len(m.mergeRequests)
-	case TabPipelines:
-		return len(m.pipelines)
-	case TabReleases:
-		return len(m.releases)
+	for _, row := range projectInfoRows(p) {
+		label := styles.DimmedText.Render(fmt.Sprintf("%-16s", row[0]))
+		content.WriteString(label + truncateWidth(row[1], innerWidth-16) + "\n")
 	}
-	return 0
-}
 
-// View renders the screen
-func (m *MainScreen) View() string {
-	if m.width == 0 || m.height == 0 {
-		return "Loading..."
+	// The README is only loaded for the open project
+	if p == m.selectedProject || (m.selectedProject != nil && p.ID == m.selectedProject.ID) {
+		if summary := readmeSummary(m.readmeContent); summary != "" {
+			content.WriteString("\n" + styles.ActivePanelTitle.Render("README") + "\n")
+			content.WriteString(renderMarkdown(summary, innerWidth) + "\n")
+		}
 	}
 
-	if m.errMsg != "" {
-		return fmt.Sprintf("Error: %s\n\nPress q to quit", m.errMsg)
+	// Keep the body inside the panel
+	lines := strings.Split(strings.TrimSuffix(content.String(), "\n"), "\n")
+	if maxLines := popupHeight - 2; len(lines) > maxLines && maxLines > 0 {
+		lines = lines[:maxLines]
 	}
 
-	// If popup is shown, render only the popup
-	if m.showJobLogPopup {
-		return m.renderJobLogPopup()
+	popup := components.SimpleBorderedPanel("Project info", strings.Join(lines, "\n"), popupWidth, popupHeight, true)
+
+	// Center the popup
+	popupLines := strings.Split(popup, "\n")
+	topPadding := (m.height - len(popupLines)) / 2
+	leftPadding := (m.width - popupWidth) / 2
+	if topPadding < 0 {
+		topPadding = 0
 	}
-	if m.showBranchPopup {
-		return m.renderBranchPopup()
+	if leftPadding < 0 {
+		leftPadding = 0
 	}
-	if m.showRunnersPopup {
-		return m.renderRunnersPopup()
+
+	var result strings.Builder
+	for i := 0; i < topPadding; i++ {
+		result.WriteString("\n")
 	}
-	if m.showReleasePopup {
-		return m.renderReleasePopup()
+	for _, line := range popupLines {
+		result.WriteString(strings.Repeat(" ", leftPadding) + line + "\n")
 	}
-	if m.showFolderBrowser {
-		return m.renderFolderBrowser()
+
+	// Status bar at bottom
+	statusContent := styles.StatusBarKey.Render("Esc") + styles.StatusBarDesc.Render(" close") + " │ " +
+		styles.StatusBarKey.Render("S") + styles.StatusBarDesc.Render(" copy SSH") + " │ " +
+		styles.StatusBarKey.Render("U") + styles.StatusBarDesc.Render(" copy HTTPS")
+	if m.statusMsg != "" {
+		statusContent = styles.SelectedItem.Render(m.statusMsg) + " │ " + statusContent
 	}
 
-	// Calculate dimensions using config ratios
-	contentHeight := m.height - config.StatusBarHeight
-	navWidth := int(float64(m.width) * config.NavigatorWidthRatio)
-	contentWidth := m.width - navWidth
+	// Pad to bottom
+	currentLines := topPadding + len(popupLines)
+	for i := currentLines; i < m.height-1; i++ {
+		result.WriteString("\n")
+	}
 
-	// Render panels
-	navPanel := m.renderNavigatorPanel(navWidth, contentHeight)
-	contentPanel := m.renderContentPanel(contentWidth, contentHeight)
+	result.WriteString(styles.StatusBar.Width(m.width).Render(statusContent))
 
-	// Combine all
-	main := lipgloss.JoinHorizontal(lipgloss.Top, navPanel, contentPanel)
-	statusBar := m.renderStatusBar()
+	return result.String()
+}
 
-	return main + "\n" + statusBar
+// helpBinding is a key/description pair shown in the help overlay
+type helpBinding struct {
+	key  string
+	desc string
 }
 
-func (m *MainScreen) renderNavigatorPanel(width, height int) string {
-	var content strings.Builder
+// helpSection groups help bindings under a panel or popup title
+type helpSection struct {
+	title    string
+	bindings []helpBinding
+}
 
-	if m.loading && len(m.treeNodes) == 0 {
-		content.WriteString(m.loadingMsg)
-	} else if len(m.treeNodes) == 0 {
-		content.WriteString(styles.DimmedText.Render("No groups or projects"))
-	} else {
-		// Calculate visible area for scrolling
-		visibleLines := height - config.BorderSize - 2 // account for borders and padding
-		if visibleLines < 1 {
-			visibleLines = 10
+// helpSections lists all MainScreen keybindings, grouped by context.
+// Keep in sync with the handle* functions when adding keys.
+var helpSections = []helpSection{
+	{"Global", []helpBinding{
+		{"?", "toggle this help"},
+		{"q", "quit"},
+		{"1/2/3", "focus navigator / content / readme"},
+		{"H/J/K/L", "move between panels"},
+		{"{n}j/k", "move n rows (counts start with 4-9 in panels)"},
+		{"{n}G/{n}gg", "go to row or line n"},
+		{"S / U", "copy SSH / HTTPS clone URL"},
+		{"R", "running and pending jobs"},
+		{"M", "my merge requests"},
+		{"E", "environments and deployments"},
+		{"n", "project snippets"},
+		{"C", "contributors by commits"},
+		{"T", "labels and milestones"},
+		{"a", "project activity feed"},
+		{"i", "project info (stars, forks, clone URLs, README summary)"},
+		{"A", "toggle default group / all groups"},
+		{"</>", "shrink / grow focused panel"},
+		{"ctrl+p", "pause / resume auto-refresh"},
+		{"ctrl+y", "copy markdown link to project, MR, pipeline or release"},
+		{"ctrl+l", "copy lazylab link to the current view"},
+		{"ctrl+r", "recent projects"},
+		{"ctrl+o", "back to the previous project, tab and directory"},
+		{"r", "retry after error"},
+		{"Esc", "cancel download / dismiss error / go back"},
+	}},
+	{"Navigator", []helpBinding{
+		{"j/k", "move up/down"},
+		{"G", "last item"},
+		{"Enter/l", "expand group / open project"},
+		{"h", "collapse group"},
+		{"zo/zc/za", "expand / collapse / toggle all groups"},
+		{"*", "toggle favorite project"},
+		{"/", "filter by name (Esc clears)"},
+	}},
+	{"Content", []helpBinding{
+		{"h/l", "previous / next tab"},
+		{"j/k", "move up/down"},
+		{"G", "last item"},
+		{"Enter", "open file, directory, MR, pipeline or release"},
+		{"Esc", "back / up one directory"},
+		{"b", "switch branch (files)"},
+		{"Space", "expand / collapse directory in place (files)"},
+		{"D", "last commit diff (files)"},
+		{"W", "browse wiki pages (files)"},
+		{"/", "search code, Enter opens a match at its line (files)"},
+		{"g", "go to branch, tag or SHA (files)"},
+		{"C-d/C-u", "scroll file half page"},
+		{"g/G", "file top / bottom"},
+		{"e", "open file in $EDITOR (file view)"},
+		{":", "jump to line (file view)"},
+		{"y / Y", "copy path / permalink (file view)"},
+		{"C-s", "save file to disk (file view)"},
+		{"m", "toggle rendered markdown (file view)"},
+		{"/", "filter merge requests (MRs)"},
+		{"D", "toggle drafts (MRs)"},
+		{"y", "copy MR URL (MRs)"},
+		{"s", "sort pipelines (pipelines)"},
+		{"f", "filter pipelines by status (pipelines)"},
+		{"Space", "expand stages and jobs inline (pipelines)"},
+		{"y/Y", "copy commit SHA / pipeline URL (pipelines)"},
+		{"F", "copy failed jobs summary (pipelines)"},
+		{"v", "CI/CD variables (pipelines)"},
+		{"t", "toggle releases / tags (releases)"},
+	}},
+	{"README", []helpBinding{
+		{"j/k", "move cursor"},
+		{"C-d/C-u", "half page down/up"},
+		{"gg/G", "top / bottom"},
+		{"h/l", "scroll wide lines (switch panels at the edge)"},
+		{"0/$", "start / end of line"},
+		{"V", "visual line mode"},
+		{"yy", "yank current line"},
+		{"y", "yank selection (visual mode)"},
+		{"ggy", "yank entire readme"},
+	}},
+	{"Job log", []helpBinding{
+		{"j/k", "switch job / move cursor"},
+		{"f", "filter jobs by stage (cycles)"},
+		{"L/Enter", "focus log"},
+		{"H", "focus job list"},
+		{"h/l", "scroll left/right"},
+		{"0/$", "start / end of line"},
+		{"w", "toggle line wrap"},
+		{"C-d/C-u", "half page down/up"},
+		{"gg/G", "top / bottom"},
+		{"V", "visual line mode"},
+		{"yy", "yank current line"},
+		{"y", "yank selection (visual mode)"},
+		{"ggy", "yank entire log"},
+		{"Y", "yank error summary"},
+		{"/", "search log"},
+		{"n/N", "next / previous match"},
+		{"Esc/q", "close"},
+	}},
+	{"Branches", []helpBinding{
+		{"j/k", "move up/down"},
+		{"Enter", "switch branch / compare with marked"},
+		{"Space", "mark branch as comparison base"},
+		{"y/Y", "copy branch name / last commit SHA"},
+		{"a", "commits ahead / behind the default branch"},
+		{"Esc/q", "unmark / close"},
+	}},
+	{"Compare", []helpBinding{
+		{"j/k", "scroll"},
+		{"C-d/C-u", "half page down/up"},
+		{"g/G", "top / bottom"},
+		{"Esc/q", "close"},
+	}},
+	{"Project info", []helpBinding{
+		{"S / U", "copy SSH / HTTPS clone URL"},
+		{"Esc/q/i", "close"},
+	}},
+	{"Last commit diff", []helpBinding{
+		{"j/k", "scroll"},
+		{"C-d/C-u", "half page down/up"},
+		{"g/G", "top / bottom"},
+		{"n/p", "select changed file"},
+		{"o", "open selected file at this commit"},
+		{"Esc/q", "close"},
+	}},
+	{"Jobs / My MRs", []helpBinding{
+		{"Tab/h/l", "switch tab"},
+		{"j/k", "move up/down"},
+		{"gg/G", "top / bottom"},
+		{"Enter", "open merge request (My MRs)"},
+		{"r", "refresh"},
+		{"Esc/q", "close"},
+	}},
+	{"Environments", []helpBinding{
+		{"j/k", "move up/down"},
+		{"Enter", "show deployments"},
+		{"y", "copy deployment SHA"},
+		{"r", "refresh"},
+		{"Esc/q", "back / close"},
+	}},
+	{"CI/CD variables", []helpBinding{
+		{"j/k", "move up/down"},
+		{"s", "show / hide unmasked values"},
+		{"y", "copy variable name"},
+		{"r", "refresh"},
+		{"Esc/q", "close"},
+	}},
+	{"Snippets", []helpBinding{
+		{"j/k", "move up/down"},
+		{"Enter", "view snippet (Esc returns to the list)"},
+		{"y", "copy snippet URL"},
+		{"r", "refresh"},
+		{"Esc/q", "close"},
+	}},
+	{"Contributors", []helpBinding{
+		{"j/k", "move up/down"},
+		{"y", "copy email"},
+		{"r", "refresh"},
+		{"Esc/q", "close"},
+	}},
+	{"Labels and milestones", []helpBinding{
+		{"Tab", "switch labels / milestones"},
+		{"j/k", "move up/down"},
+		{"y", "copy label name or milestone URL"},
+		{"r", "refresh"},
+		{"Esc/q", "close"},
+	}},
+	{"Activity", []helpBinding{
+		{"j/k", "move up/down"},
+		{"y", "copy event"},
+		{"r", "refresh"},
+		{"Esc/q", "close"},
+	}},
+	{"Wiki", []helpBinding{
+		{"j/k", "move up/down"},
+		{"Enter", "view page (Esc returns to the list)"},
+		{"r", "refresh"},
+		{"Esc/q", "close"},
+	}},
+	{"Merge request", []helpBinding{
+		{"j/k", "scroll description"},
+		{"C-d/C-u", "half page down/up"},
+		{"g/G", "top / bottom"},
+		{"y", "copy MR URL"},
+		{"p", "copy head pipeline URL"},
+		{"a", "copy review app URL"},
+		{"Esc/q", "close"},
+	}},
+	{"Release", []helpBinding{
+		{"j/k", "select asset"},
+		{"g/G", "first / last asset"},
+		{"y/Enter", "copy asset URL"},
+		{"o", "copy release URL"},
+		{"d", "download asset"},
+		{"Esc/q", "close"},
+	}},
+	{"Folder browser", []helpBinding{
+		{"j/k", "move up/down"},
+		{"l/Enter", "enter directory"},
+		{"h/Backspace", "parent directory"},
+		{"~", "home directory"},
+		{".", "show/hide hidden directories"},
+		{"d/Space", "download here"},
+		{"Esc/q", "cancel"},
+	}},
+}
+
+// helpLines renders helpSections into display lines
+func helpLines() []string {
+	keyWidth := 0
+	for _, section := range helpSections {
+		for _, b := range section.bindings {
+			keyWidth = max(keyWidth, lipgloss.Width(b.key))
 		}
+	}
 
-		// Calculate scroll offset to keep selected item visible
-		scrollOffset := 0
-		if m.selectedNodeIdx >= visibleLines {
-			scrollOffset = m.selectedNodeIdx - visibleLines + 1
+	var lines []string
+	for i, section := range helpSections {
+		if i > 0 {
+			lines = append(lines, "")
 		}
-		endIdx := scrollOffset + visibleLines
-		if endIdx > len(m.treeNodes) {
-			endIdx = len(m.treeNodes)
+		lines = append(lines, styles.ActivePanelTitle.Render(section.title))
+		for _, b := range section.bindings {
+			pad := strings.Repeat(" ", keyWidth-lipgloss.Width(b.key))
+			lines = append(lines, "  "+styles.StatusBarKey.Render(b.key)+pad+"  "+styles.DimmedText.Render(b.desc))
 		}
+	}
+	return lines
+}
 
-		for i := scrollOffset; i < endIdx; i++ {
-			node := m.treeNodes[i]
+// handleHelpPopup handles keyboard input for the help overlay
+func (m *MainScreen) handleHelpPopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	maxScroll := max(len(helpLines())-1, 0)
 
-			// Build indent based on depth
-			indent := strings.Repeat("  ", node.Depth)
+	switch msg.String() {
+	case "?", "q", "esc", "escape":
+		m.showHelpPopup = false
+	case "j", "down":
+		if m.helpScroll < maxScroll {
+			m.helpScroll++
+		}
+	case "k", "up":
+		if m.helpScroll > 0 {
+			m.helpScroll--
+		}
+	case "ctrl+d":
+		m.helpScroll = min(m.helpScroll+10, maxScroll)
+	case "ctrl+u":
+		m.helpScroll = max(m.helpScroll-10, 0)
+	case "g":
+		m.helpScroll = 0
+	case "G":
+		m.helpScroll = maxScroll
+	}
+	return m, nil
+}
 
-			// Build icon
-			icon := ""
-			if node.Type == "group" {
-				if m.expandedGroups[node.ID] {
-					icon = "▼ "
-				} else {
-					icon = "▶ "
-				}
-			} else {
-				icon = "  📦 "
-			}
+// renderHelpPopup renders the keybinding reference overlay
+func (m *MainScreen) renderHelpPopup() string {
+	popupWidth := 70
+	popupHeight := int(float64(m.height) * 0.8)
 
-			line := indent + icon + node.Name
+	if popupHeight < 15 {
+		popupHeight = 15
+	}
+	if popupWidth > m.width-4 {
+		popupWidth = m.width - 4
+	}
+	if popupHeight > m.height-4 {
+		popupHeight = m.height - 4
+	}
 
-			// Truncate if too long
-			maxLineLen := width - config.BorderSize - 4
-			if maxLineLen > 0 && len(line) > maxLineLen {
-				line = line[:maxLineLen-1] + "…"
-			}
+	lines := helpLines()
+	visibleLines := popupHeight - 2
+	if visibleLines < 1 {
+		visibleLines = 1
+	}
 
-			if i == m.selectedNodeIdx {
-				line = styles.SelectedItem.Render("> " + line)
-			} else {
-				line = styles.NormalItem.Render("  " + line)
-			}
-			content.WriteString(line + "\n")
-		}
+	// Clamp scroll so the last page stays full
+	if m.helpScroll > len(lines)-visibleLines {
+		m.helpScroll = max(len(lines)-visibleLines, 0)
+	}
+	endIdx := min(m.helpScroll+visibleLines, len(lines))
 
-		// Show scroll indicator
-		if len(m.treeNodes) > visibleLines {
-			content.WriteString(styles.DimmedText.Render(fmt.Sprintf("\n[%d/%d]", m.selectedNodeIdx+1, len(m.treeNodes))))
-		}
+	content := strings.Join(lines[m.helpScroll:endIdx], "\n")
+
+	title := "Keybindings"
+	if len(lines) > visibleLines {
+		title += fmt.Sprintf(" [%d/%d]", endIdx, len(lines))
+	}
+	popup := components.SimpleBorderedPanel(title, content, popupWidth, popupHeight, true)
+
+	// Center the popup
+	popupLines := strings.Split(popup, "\n")
+	topPadding := (m.height - len(popupLines)) / 2
+	leftPadding := (m.width - popupWidth) / 2
+	if topPadding < 0 {
+		topPadding = 0
+	}
+	if leftPadding < 0 {
+		leftPadding = 0
+	}
+
+	var result strings.Builder
+	for i := 0; i < topPadding; i++ {
+		result.WriteString("\n")
+	}
+	for _, line := range popupLines {
+		result.WriteString(strings.Repeat(" ", leftPadding) + line + "\n")
+	}
+
+	// Status bar at bottom
+	statusContent := styles.StatusBarKey.Render("?/Esc") + styles.StatusBarDesc.Render(" close") + " │ " +
+		styles.StatusBarKey.Render("j/k") + styles.StatusBarDesc.Render(" scroll")
+
+	// Pad to bottom
+	currentLines := topPadding + len(popupLines)
+	for i := currentLines; i < m.height-1; i++ {
+		result.WriteString("\n")
+	}
+
+	result.WriteString(styles.StatusBar.Width(m.width).Render(statusContent))
+
+	return result.String()
+}
+
+// lineAnchorRegex matches GitLab-style line anchors: "#L42" or "#L42-50"
+var lineAnchorRegex = regexp.MustCompile(`#L(\d+)(?:-\d+)?$`)
+
+// splitLineAnchor strips a "#Lnn" anchor from a file path, returning the
+// path and the line number (0 when there is no anchor)
+func splitLineAnchor(path string) (string, int) {
+	match := lineAnchorRegex.FindStringSubmatchIndex(path)
+	if match == nil {
+		return path, 0
+	}
+	line, err := strconv.Atoi(path[match[2]:match[3]])
+	if err != nil {
+		return path, 0
+	}
+	return path[:match[0]], line
+}
+
+// parseLineNumber parses the line jump prompt, accepting "42", "L42" or "#L42"
+func parseLineNumber(input string) (int, bool) {
+	input = strings.TrimSpace(input)
+	input = strings.TrimPrefix(input, "#")
+	input = strings.TrimPrefix(strings.TrimPrefix(input, "L"), "l")
+	n, err := strconv.Atoi(input)
+	if err != nil || n < 1 {
+		return 0, false
+	}
+	return n, true
+}
+
+// fileViewContent returns the highlighted file content (or rendered markdown),
+// with the flashed line (if any) rendered in reverse video
+func (m *MainScreen) fileViewContent() string {
+	var highlighted string
+	if m.fileImage != nil {
+		return renderImageBlocks(m.fileImage, m.fileViewport.Width)
+	}
+	if m.fileTooLarge() {
+		// Highlighting megabytes of minified code would stall the UI
+		highlighted = m.fileContent
+	} else if m.fileIsMarkdown && !m.fileShowRaw {
+		highlighted = strings.TrimRight(renderMarkdown(m.fileContent, m.fileViewport.Width), "\n")
+	} else {
+		highlighted = highlightCode(m.fileContent, m.viewingFilePath)
+	}
+	if m.fileFlashLine <= 0 {
+		return highlighted
 	}
+	lines := strings.Split(highlighted, "\n")
+	if idx := m.fileFlashLine - 1; idx < len(lines) {
+		lines[idx] = lipgloss.NewStyle().Reverse(true).Render(stripANSI(lines[idx]))
+	}
+	return strings.Join(lines, "\n")
+}
 
-	return components.SimpleBorderedPanel("Navigator", content.String(), width, height, m.focusedPanel == PanelNavigator)
+// fileTooLarge reports whether the viewed file exceeds the highlighting limit
+func (m *MainScreen) fileTooLarge() bool {
+	return len(m.fileContent) > m.settings.HighlightLimit()
 }
 
-func (m *MainScreen) renderContentPanel(width, height int) string {
-	// Split: top half for file list, bottom half for README (only in Files tab at root)
-	showReadme := m.contentTab == TabFiles && len(m.currentPath) == 0 && m.readmeContent != ""
+// uiTickMsg redraws the screen so relative times keep aging between refreshes
+type uiTickMsg struct{}
 
-	listHeight := height
-	readmeHeight := 0
-	if showReadme {
-		readmeHeight = int(float64(height) * config.ReadmeHeightRatio)
-		listHeight = height - readmeHeight
-	}
+// uiTickCmd schedules the next redraw tick. It never touches the network, so
+// it runs alongside the pipeline and job log refresh tickers.
+func uiTickCmd() tea.Cmd {
+	return tea.Tick(config.RelativeTimeRefresh, func(time.Time) tea.Msg {
+		return uiTickMsg{}
+	})
+}
 
-	// Build the file/content list panel
-	listPanel := m.renderListSection(width, listHeight)
+// scrollToFileLine scrolls the file viewport so the 1-based line is visible
+// near the top third of the view
+func (m *MainScreen) scrollToFileLine(line int) {
+	m.fileViewport.SetYOffset(max(line-1-m.fileViewport.Height/3, 0))
+}
 
-	if !showReadme {
-		return listPanel
+// jumpToFileLine clamps line to the file, scrolls to it, and flashes it
+func (m *MainScreen) jumpToFileLine(line int) tea.Cmd {
+	// Line numbers count source lines, which rendered markdown doesn't keep
+	if m.fileIsMarkdown && !m.fileShowRaw {
+		m.fileShowRaw = true
+		m.fileViewport.SetContent(m.fileViewContent())
 	}
+	total := m.fileViewport.TotalLineCount()
+	if total == 0 {
+		return nil
+	}
+	line = max(min(line, total), 1)
 
-	// Build the README panel
-	readmePanel := m.renderReadmeSection(width, readmeHeight)
-	return lipgloss.JoinVertical(lipgloss.Left, listPanel, readmePanel)
+	m.fileFlashLine = line
+	m.fileFlashSeq++
+	m.fileViewport.SetContent(m.fileViewContent())
+	m.scrollToFileLine(line)
+	return fileFlashCmd(m.fileFlashSeq)
 }
 
-func (m *MainScreen) renderListSection(width, height int) string {
-	var content strings.Builder
-
-	// Project header with branch and last commit
-	if m.selectedProject != nil {
-		projectHeader := styles.SelectedItem.Render(m.selectedProject.Name)
-		if m.currentBranch != "" {
-			projectHeader += styles.DimmedText.Render(" (" + m.currentBranch + ")")
+// handleLineJumpInput handles keyboard input for the ':' line jump prompt
+func (m *MainScreen) handleLineJumpInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "escape":
+		m.lineJumpActive = false
+		return m, nil
+	case "enter":
+		m.lineJumpActive = false
+		line, ok := parseLineNumber(m.lineJumpInput.Value())
+		if !ok {
+			m.statusMsg = "Invalid line number: " + m.lineJumpInput.Value()
+			return m, nil
 		}
-		content.WriteString(projectHeader + "\n")
+		return m, m.jumpToFileLine(line)
+	}
 
-		// Show last commit from current branch
-		for _, b := range m.branches {
-			if b.Name == m.currentBranch && b.Commit.Title != "" {
-				commitInfo := styles.DimmedText.Render("Last commit: ") + truncateString(b.Commit.Title, width-20)
-				if b.Commit.AuthorName != "" {
-					commitInfo += styles.DimmedText.Render(" by " + b.Commit.AuthorName)
-				}
-				content.WriteString(commitInfo + "\n")
-				break
-			}
+	var cmd tea.Cmd
+	m.lineJumpInput, cmd = m.lineJumpInput.Update(msg)
+	return m, cmd
+}
+
+// handleRefJumpInput handles keys while the go-to-ref prompt is open
+func (m *MainScreen) handleRefJumpInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "escape":
+		m.refJumpActive = false
+		return m, nil
+	case "enter":
+		m.refJumpActive = false
+		ref := strings.TrimSpace(m.refJumpInput.Value())
+		if ref == "" || ref == m.currentBranch {
+			return m, nil
 		}
+		// A ref that doesn't exist surfaces as an API error
+		return m, m.switchRef(ref)
 	}
 
-	// Tab header
-	for i, name := range contentTabNames {
-		if ContentTab(i) == m.contentTab {
-			content.WriteString(styles.StatusBarKey.Render("[" + name + "]"))
-		} else {
-			content.WriteString(styles.DimmedText.Render(" " + name + " "))
-		}
-		content.WriteString(" ")
+	var cmd tea.Cmd
+	m.refJumpInput, cmd = m.refJumpInput.Update(msg)
+	return m, cmd
+}
+
+// shortSHA returns the first 8 characters of a commit SHA
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
 	}
-	content.WriteString("\n")
+	return sha
+}
 
-	// Path breadcrumb for files
-	if m.contentTab == TabFiles && len(m.currentPath) > 0 {
-		content.WriteString(styles.DimmedText.Render("/"+strings.Join(m.currentPath, "/")) + "\n")
+// blobPermalink returns the web URL of filePath at ref
+func blobPermalink(webURL, ref, filePath string) string {
+	segments := strings.Split(filePath, "/")
+	for i, seg := range segments {
+		segments[i] = url.PathEscape(seg)
 	}
-	content.WriteString("\n")
+	return webURL + "/-/blob/" + ref + "/" + strings.Join(segments, "/")
+}
 
-	if m.selectedProject == nil {
-		content.WriteString(styles.DimmedText.Render("Select a project"))
-	} else if m.loading {
-		content.WriteString(m.loadingMsg)
-	} else {
-		// Calculate visible lines for scrolling
-		visibleLines := height - 6 // account for headers and borders
-		if visibleLines < 1 {
-			visibleLines = 10
-		}
+// permalinkResolvedMsg carries the commit SHA the viewed file's ref points
+// at; on error the permalink falls back to the ref name
+type permalinkResolvedMsg struct {
+	ref      string
+	filePath string
+	sha      string
+	err      error
+}
 
-		switch m.contentTab {
-		case TabFiles:
-			// If viewing a file, show its content
-			if m.viewingFile && m.fileContent != "" {
-				// Show file path
-				content.WriteString(styles.DimmedText.Render(m.viewingFilePath) + "\n")
-				content.WriteString(styles.DimmedText.Render("Esc: back | j/k: scroll | g/G: top/bottom") + "\n\n")
+// copyFilePermalink copies a permalink to the viewed file. The ref is pinned
+// to a commit SHA from the loaded branch list, or resolved by the API.
+func (m *MainScreen) copyFilePermalink() tea.Cmd {
+	if m.viewingSnippet || m.viewingWiki {
+		m.statusMsg = "Not a repository file"
+		return nil
+	}
+	if m.selectedProject == nil || m.selectedProject.WebURL == "" {
+		m.statusMsg = "No web URL for this project"
+		return nil
+	}
+	ref, filePath := m.currentBranch, m.viewingFilePath
+	if m.viewingFileRef != "" {
+		// Already pinned to a commit
+		m.copyPermalink(blobPermalink(m.selectedProject.WebURL, m.viewingFileRef, filePath), true)
+		return nil
+	}
+	for _, b := range m.branches {
+		if b.Name == ref && b.Commit.ID != "" {
+			m.copyPermalink(blobPermalink(m.selectedProject.WebURL, b.Commit.ID, filePath), true)
+			return nil
+		}
+	}
+	if m.isDemo {
+		m.copyPermalink(blobPermalink(m.selectedProject.WebURL, ref, filePath), false)
+		return nil
+	}
+	projectID := fmt.Sprintf("%d", m.selectedProject.ID)
+	return func() tea.Msg {
+		sha, err := m.client.ResolveRefSHA(projectID, ref)
+		return permalinkResolvedMsg{ref: ref, filePath: filePath, sha: sha, err: err}
+	}
+}
 
-				// Use viewport for file content
-				fileViewHeight := visibleLines - 3
-				innerWidth := width - 4
-				if !m.fileViewReady {
-					m.fileViewport = viewport.New(innerWidth, fileViewHeight)
-					// Apply syntax highlighting
-					highlighted := highlightCode(m.fileContent, m.viewingFilePath)
-					m.fileViewport.SetContent(highlighted)
-					m.fileViewReady = true
-				}
-				content.WriteString(m.fileViewport.View())
+// copyPermalink copies a file permalink, noting when it follows a ref
+// rather than a fixed commit
+func (m *MainScreen) copyPermalink(link string, pinned bool) {
+	if err := copyToClipboard(link); err != nil {
+		m.statusMsg = "Copy failed: " + err.Error()
+	} else if pinned {
+		m.statusMsg = "Permalink: " + link
+	} else {
+		m.statusMsg = "Link (not pinned to a commit): " + link
+	}
+}
 
-				// Scroll indicator
-				if m.fileViewport.TotalLineCount() > fileViewHeight {
-					content.WriteString(styles.DimmedText.Render(fmt.Sprintf("\n[%d%%]", int(m.fileViewport.ScrollPercent()*100))))
-				}
-			} else {
-				// Show file list
-				endIdx := m.fileScrollOffset + visibleLines
-				if endIdx > len(m.files) {
-					endIdx = len(m.files)
-				}
-				for i := m.fileScrollOffset; i < endIdx; i++ {
-					f := m.files[i]
-					icon := "📄"
-					if f.Type == "tree" {
-						icon = "📁"
-					}
-					// Build commit info
-					commitInfo := ""
-					if f.LastCommit != nil {
-						commitInfo = fmt.Sprintf(" %s @%s", timeAgo(f.LastCommit.AuthoredDate), f.LastCommit.AuthorName)
-					}
-					line := fmt.Sprintf("%s %s", icon, f.Name)
-					meta := styles.DimmedText.Render(commitInfo)
-					if i == m.selectedContent {
-						line = styles.SelectedItem.Render("> "+line) + meta
-					} else {
-						line = "  " + line + meta
-					}
-					content.WriteString(line + "\n")
-				}
-				// Show scroll indicator
-				if len(m.files) > visibleLines {
-					content.WriteString(styles.DimmedText.Render(fmt.Sprintf("\n[%d/%d]", m.selectedContent+1, len(m.files))))
-				}
-				// Show selected file info
-				if m.selectedContent < len(m.files) {
-					f := m.files[m.selectedContent]
-					fileType := "File"
-					if f.Type == "tree" {
-						fileType = "Directory"
-					}
-					infoLine := fileType + ": " + f.Path
-					if f.LastCommit != nil && f.LastCommit.Title != "" {
-						infoLine += " | " + truncateString(f.LastCommit.Title, width-len(infoLine)-10)
-					}
-					content.WriteString("\n" + styles.DimmedText.Render(infoLine))
-				}
+// handleEnvPopup handles keyboard input for the environments popup
+func (m *MainScreen) handleEnvPopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Deployments view of a single environment
+	if m.envSelected != "" {
+		switch msg.String() {
+		case "q":
+			m.showEnvPopup = false
+		case "esc", "escape", "h", "left", "backspace":
+			// Back to environment list
+			m.envSelected = ""
+			m.envErr = ""
+		case "j", "down":
+			if m.envDeployCursor < len(m.envDeployments)-1 {
+				m.envDeployCursor++
 			}
-		case TabMRs:
-			endIdx := m.fileScrollOffset + visibleLines
-			if endIdx > len(m.mergeRequests) {
-				endIdx = len(m.mergeRequests)
+		case "k", "up":
+			if m.envDeployCursor > 0 {
+				m.envDeployCursor--
 			}
-			for i := m.fileScrollOffset; i < endIdx; i++ {
-				mr := m.mergeRequests[i]
-				icon := "○"
-				if mr.Draft {
-					icon = "◐"
-				}
-				// Build reviewer string
-				reviewerStr := ""
-				if len(mr.Reviewers) > 0 {
-					reviewerStr = " → " + mr.Reviewers[0].Username
-					if len(mr.Reviewers) > 1 {
-						reviewerStr += fmt.Sprintf(" +%d", len(mr.Reviewers)-1)
-					}
-				}
-				line := fmt.Sprintf("%s !%d %s", icon, mr.IID, truncateString(mr.Title, width-45))
-				meta := styles.DimmedText.Render(fmt.Sprintf(" @%s%s %s", mr.Author.Username, reviewerStr, timeAgo(mr.CreatedAt)))
-				if i == m.selectedContent {
-					line = styles.SelectedItem.Render("> ") + line + meta
+		case "g":
+			m.envDeployCursor = 0
+		case "G":
+			m.envDeployCursor = max(len(m.envDeployments)-1, 0)
+		case "y":
+			if m.envDeployCursor < len(m.envDeployments) {
+				sha := m.envDeployments[m.envDeployCursor].SHA
+				if err := copyToClipboard(sha); err != nil {
+					m.statusMsg = "Copy failed: " + err.Error()
 				} else {
-					line = "  " + line + meta
+					m.statusMsg = "Copied SHA: " + shortSHA(sha)
 				}
-				content.WriteString(line + "\n")
 			}
-			if len(m.mergeRequests) == 0 {
-				content.WriteString(styles.DimmedText.Render("No open merge requests"))
+		case "r":
+			if !m.isDemo {
+				m.envLoading = true
+				return m, m.loadDeployments(m.envSelected)
+			}
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "q", "esc", "escape":
+		m.showEnvPopup = false
+	case "j", "down":
+		if m.envCursor < len(m.environments)-1 {
+			m.envCursor++
+		}
+	case "k", "up":
+		if m.envCursor > 0 {
+			m.envCursor--
+		}
+	case "g":
+		m.envCursor = 0
+	case "G":
+		m.envCursor = max(len(m.environments)-1, 0)
+	case "y":
+		if m.envCursor < len(m.environments) && m.environments[m.envCursor].LastDeployment != nil {
+			sha := m.environments[m.envCursor].LastDeployment.SHA
+			if err := copyToClipboard(sha); err != nil {
+				m.statusMsg = "Copy failed: " + err.Error()
 			} else {
-				if len(m.mergeRequests) > visibleLines {
-					content.WriteString(styles.DimmedText.Render(fmt.Sprintf("\n[%d/%d]", m.selectedContent+1, len(m.mergeRequests))))
-				}
-				// Show selected MR info
-				if m.selectedContent < len(m.mergeRequests) {
-					mr := m.mergeRequests[m.selectedContent]
-					mrInfo := fmt.Sprintf("%s → %s", mr.SourceBranch, mr.TargetBranch)
-					if mr.HasConflicts {
-						mrInfo += " (conflicts)"
-					}
-					content.WriteString("\n" + styles.DimmedText.Render(mrInfo))
-				}
+				m.statusMsg = "Copied SHA: " + shortSHA(sha)
 			}
-		case TabPipelines:
-			endIdx := m.fileScrollOffset + visibleLines
-			if endIdx > len(m.pipelines) {
-				endIdx = len(m.pipelines)
+		}
+	case "r":
+		if !m.isDemo {
+			m.envLoading = true
+			return m, m.loadEnvironments()
+		}
+	case "enter", "l", "right":
+		if m.envCursor >= len(m.environments) {
+			return m, nil
+		}
+		m.envSelected = m.environments[m.envCursor].Name
+		m.envDeployCursor = 0
+		m.envErr = ""
+		if m.isDemo {
+			m.envDeployments = mockDeployments(m.envSelected)
+			return m, nil
+		}
+		m.envDeployments = nil
+		m.envLoading = true
+		return m, m.loadDeployments(m.envSelected)
+	}
+	return m, nil
+}
+
+// renderEnvPopup renders the environments / deployments popup
+func (m *MainScreen) renderEnvPopup() string {
+	popupWidth := int(float64(m.width) * 0.8)
+	popupHeight := int(float64(m.height) * 0.8)
+
+	if popupWidth < 60 {
+		popupWidth = 60
+	}
+	if popupHeight < 15 {
+		popupHeight = 15
+	}
+	if popupWidth > m.width-4 {
+		popupWidth = m.width - 4
+	}
+	if popupHeight > m.height-4 {
+		popupHeight = m.height - 4
+	}
+
+	visibleLines := popupHeight - 6
+	if visibleLines < 5 {
+		visibleLines = 5
+	}
+
+	var content strings.Builder
+
+	if m.envErr != "" {
+		content.WriteString(lipgloss.NewStyle().Foreground(styles.ColorRed).Render("Error: " + m.envErr))
+	} else if m.envSelected != "" {
+		// Deployments of the selected environment
+		if m.envLoading && len(m.envDeployments) == 0 {
+			content.WriteString(styles.DimmedText.Render("Loading deployments..."))
+		} else if len(m.envDeployments) == 0 {
+			content.WriteString(styles.DimmedText.Render("No deployments"))
+		} else {
+			header := fmt.Sprintf("  %-7s %-24s %-9s %-10s %-16s %s", "ID", "REF", "SHA", "STATUS", "USER", "DEPLOYED")
+			content.WriteString(styles.DimmedText.Render(header) + "\n")
+			content.WriteString(styles.DimmedText.Render(strings.Repeat("─", popupWidth-4)) + "\n")
+
+			startIdx := 0
+			if m.envDeployCursor >= visibleLines {
+				startIdx = m.envDeployCursor - visibleLines + 1
 			}
-			for i := m.fileScrollOffset; i < endIdx; i++ {
-				p := m.pipelines[i]
-				icon := styles.PipelineIcon(p.Status)
-				statusStyle := styles.PipelineStatus(p.Status)
+			endIdx := min(startIdx+visibleLines, len(m.envDeployments))
 
-				// Build job stages icons
-				stagesStr := ""
-				if jobs, ok := m.pipelineJobs[p.ID]; ok && len(jobs) > 0 {
-					// Sort jobs by ID to get correct stage order (earlier stages have lower IDs)
-					sortedJobs := make([]gitlab.Job, len(jobs))
-					copy(sortedJobs, jobs)
-					sort.Slice(sortedJobs, func(i, j int) bool {
-						return sortedJobs[i].ID < sortedJobs[j].ID
-					})
-					// Group jobs by stage and get stage status
-					stageOrder := []string{}
-					stageStatus := make(map[string]string)
-					for _, job := range sortedJobs {
-						if _, exists := stageStatus[job.Stage]; !exists {
-							stageOrder = append(stageOrder, job.Stage)
-							stageStatus[job.Stage] = job.Status
-						} else {
-							// If any job in stage failed, stage is failed
-							current := stageStatus[job.Stage]
-							if job.Status == "failed" {
-								stageStatus[job.Stage] = "failed"
-							} else if job.Status == "running" && current != "failed" {
-								stageStatus[job.Stage] = "running"
-							} else if job.Status == "pending" && current != "failed" && current != "running" {
-								stageStatus[job.Stage] = "pending"
-							}
-						}
-					}
-					// Build stage icons with names
-					for _, stage := range stageOrder {
-						status := stageStatus[stage]
-						stageIcon := styles.PipelineIcon(status)
-						stageStyle := styles.PipelineStatus(status)
-						stagesStr += stageStyle.Render(stageIcon) + styles.DimmedText.Render("("+stage+")") + " "
-					}
-				} else {
-					// No jobs loaded yet - show status text for pending/created pipelines
-					stagesStr = statusStyle.Render("(" + p.Status + ")")
-				}
+			for i := startIdx; i < endIdx; i++ {
+				d := m.envDeployments[i]
+				statusStyle := styles.PipelineStatus(d.Status)
 
-				// Build meta info: user, time, source
-				userStr := ""
-				if p.User.Username != "" {
-					userStr = "@" + p.User.Username
+				ref := d.Ref
+				if len(ref) > 24 {
+					ref = ref[:23] + "…"
+				}
+				user := "@" + d.User.Username
+				if len(user) > 16 {
+					user = user[:15] + "…"
 				}
-				meta := styles.DimmedText.Render(fmt.Sprintf(" %s %s %s", userStr, p.Source, timeAgo(p.CreatedAt)))
 
-				line := fmt.Sprintf("%s #%d %s %s", statusStyle.Render(icon), p.IID, p.Ref, stagesStr)
-				if i == m.selectedContent {
-					line = styles.SelectedItem.Render("> ") + line + meta
+				line := fmt.Sprintf("%-7s %-24s %-9s %s %-16s %s",
+					fmt.Sprintf("#%d", d.IID),
+					ref,
+					shortSHA(d.SHA),
+					statusStyle.Render(fmt.Sprintf("%-10s", d.Status)),
+					user,
+					styles.DimmedText.Render(timeAgo(d.CreatedAt)))
+
+				if i == m.envDeployCursor {
+					line = styles.SelectedItem.Render("> ") + line
 				} else {
-					line = "  " + line + meta
+					line = "  " + line
 				}
 				content.WriteString(line + "\n")
 			}
-			if len(m.pipelines) == 0 {
-				content.WriteString(styles.DimmedText.Render("No pipelines"))
-			} else {
-				if len(m.pipelines) > visibleLines {
-					content.WriteString(styles.DimmedText.Render(fmt.Sprintf("\n[%d/%d]", m.selectedContent+1, len(m.pipelines))))
-				}
-				// Show selected pipeline info
-				if m.selectedContent < len(m.pipelines) {
-					p := m.pipelines[m.selectedContent]
-					sha := p.SHA
-					if len(sha) > 8 {
-						sha = sha[:8]
-					}
-					pInfo := fmt.Sprintf("%s | %s", p.Status, sha)
-					content.WriteString("\n" + styles.DimmedText.Render(pInfo))
-				}
-			}
-		case TabReleases:
-			endIdx := m.fileScrollOffset + visibleLines
-			if endIdx > len(m.releases) {
-				endIdx = len(m.releases)
+		}
+	} else {
+		// Environment list with last deployment
+		if m.envLoading && len(m.environments) == 0 {
+			content.WriteString(styles.DimmedText.Render("Loading environments..."))
+		} else if len(m.environments) == 0 {
+			content.WriteString(styles.DimmedText.Render("No environments"))
+		} else {
+			header := fmt.Sprintf("  %-24s %-10s %-9s %-10s %s", "ENVIRONMENT", "STATE", "SHA", "STATUS", "DEPLOYED")
+			content.WriteString(styles.DimmedText.Render(header) + "\n")
+			content.WriteString(styles.DimmedText.Render(strings.Repeat("─", popupWidth-4)) + "\n")
+
+			startIdx := 0
+			if m.envCursor >= visibleLines {
+				startIdx = m.envCursor - visibleLines + 1
 			}
-			for i := m.fileScrollOffset; i < endIdx; i++ {
-				rel := m.releases[i]
-				// Count downloadable assets (links + source archives)
-				assetCount := len(rel.Assets.Links) + len(rel.Assets.Sources)
-				assetStr := ""
-				if assetCount > 0 {
-					assetStr = fmt.Sprintf(" [%d]", assetCount)
+			endIdx := min(startIdx+visibleLines, len(m.environments))
+
+			for i := startIdx; i < endIdx; i++ {
+				env := m.environments[i]
+
+				name := env.Name
+				if len(name) > 24 {
+					name = name[:23] + "…"
 				}
 
-				// Format release time
-				relTime := timeAgo(rel.CreatedAt)
-				if rel.ReleasedAt != nil {
-					relTime = timeAgo(*rel.ReleasedAt)
+				sha, status, deployed := "-", "-", "-"
+				statusStyle := styles.DimmedText
+				if d := env.LastDeployment; d != nil {
+					sha = shortSHA(d.SHA)
+					status = d.Status
+					statusStyle = styles.PipelineStatus(d.Status)
+					deployed = timeAgo(d.CreatedAt)
 				}
 
-				line := fmt.Sprintf("📦 %s%s", rel.TagName, assetStr)
-				meta := styles.DimmedText.Render(fmt.Sprintf(" @%s %s", rel.Author.Username, relTime))
-				if i == m.selectedContent {
-					line = styles.SelectedItem.Render("> ") + line + meta
+				line := fmt.Sprintf("%-24s %-10s %-9s %s %s",
+					name,
+					env.State,
+					sha,
+					statusStyle.Render(fmt.Sprintf("%-10s", status)),
+					styles.DimmedText.Render(deployed))
+
+				if i == m.envCursor {
+					line = styles.SelectedItem.Render("> ") + line
 				} else {
-					line = "  " + line + meta
+					line = "  " + line
 				}
 				content.WriteString(line + "\n")
 			}
-			if len(m.releases) == 0 {
-				content.WriteString(styles.DimmedText.Render("No releases"))
+		}
+	}
+
+	// Build popup panel
+	title := "Environments"
+	if m.selectedProject != nil {
+		title += " - " + m.selectedProject.Name
+	}
+	if m.envSelected != "" {
+		title = "Deployments - " + m.envSelected
+	}
+	if m.envLoading {
+		title += " (loading...)"
+	}
+	popup := components.SimpleBorderedPanel(title, content.String(), popupWidth, popupHeight, true)
+
+	// Center the popup
+	popupLines := strings.Split(popup, "\n")
+	topPadding := (m.height - len(popupLines)) / 2
+	leftPadding := (m.width - popupWidth) / 2
+	if topPadding < 0 {
+		topPadding = 0
+	}
+	if leftPadding < 0 {
+		leftPadding = 0
+	}
+
+	var result strings.Builder
+	for i := 0; i < topPadding; i++ {
+		result.WriteString("\n")
+	}
+	for _, line := range popupLines {
+		result.WriteString(strings.Repeat(" ", leftPadding) + line + "\n")
+	}
+
+	// Status bar at bottom
+	var statusContent string
+	if m.envSelected != "" {
+		statusContent = styles.StatusBarKey.Render("Esc") + styles.StatusBarDesc.Render(" back") + " │ " +
+			styles.StatusBarKey.Render("j/k") + styles.StatusBarDesc.Render(" navigate") + " │ " +
+			styles.StatusBarKey.Render("y") + styles.StatusBarDesc.Render(" copy SHA") + " │ " +
+			styles.StatusBarKey.Render("r") + styles.StatusBarDesc.Render(" refresh")
+	} else {
+		statusContent = styles.StatusBarKey.Render("Esc") + styles.StatusBarDesc.Render(" close") + " │ " +
+			styles.StatusBarKey.Render("j/k") + styles.StatusBarDesc.Render(" navigate") + " │ " +
+			styles.StatusBarKey.Render("Enter") + styles.StatusBarDesc.Render(" deployments") + " │ " +
+			styles.StatusBarKey.Render("y") + styles.StatusBarDesc.Render(" copy SHA") + " │ " +
+			styles.StatusBarKey.Render("r") + styles.StatusBarDesc.Render(" refresh")
+	}
+	if m.statusMsg != "" {
+		statusContent = styles.SelectedItem.Render(m.statusMsg) + " │ " + statusContent
+	}
+
+	// Pad to bottom
+	currentLines := topPadding + len(popupLines)
+	for i := currentLines; i < m.height-1; i++ {
+		result.WriteString("\n")
+	}
+
+	result.WriteString(styles.StatusBar.Width(m.width).Render(statusContent))
+
+	return result.String()
+}
+
+// handleVarsPopup handles keyboard input for the CI/CD variables popup
+func (m *MainScreen) handleVarsPopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc", "escape":
+		m.showVarsPopup = false
+	case "j", "down":
+		if m.varsCursor < len(m.variables)-1 {
+			m.varsCursor++
+		}
+	case "k", "up":
+		if m.varsCursor > 0 {
+			m.varsCursor--
+		}
+	case "g":
+		m.varsCursor = 0
+	case "G":
+		m.varsCursor = max(len(m.variables)-1, 0)
+	case "s":
+		// Reveal unmasked values; masked values are never shown
+		m.varsShowValues = !m.varsShowValues
+	case "y":
+		if m.varsCursor < len(m.variables) {
+			name := m.variables[m.varsCursor].Key
+			if err := copyToClipboard(name); err != nil {
+				m.statusMsg = "Copy failed: " + err.Error()
 			} else {
-				if len(m.releases) > visibleLines {
-					content.WriteString(styles.DimmedText.Render(fmt.Sprintf("\n[%d/%d]", m.selectedContent+1, len(m.releases))))
-				}
-				// Show selected release info
-				if m.selectedContent < len(m.releases) {
-					rel := m.releases[m.selectedContent]
-					name := rel.Name
-					if name == "" {
-						name = rel.TagName
-					}
-					relInfo := fmt.Sprintf("%s | commit: %s", name, rel.Commit.ShortID)
-					content.WriteString("\n" + styles.DimmedText.Render(relInfo))
-				}
+				m.statusMsg = "Copied: " + name
 			}
 		}
+	case "r":
+		if !m.isDemo {
+			m.varsLoading = true
+			return m, m.loadVariables()
+		}
 	}
+	return m, nil
+}
 
-	title := contentTabNames[m.contentTab]
-	return components.SimpleBorderedPanel(title, content.String(), width, height, m.focusedPanel == PanelContent)
+// variableValue returns what to display for a variable's value
+func (m *MainScreen) variableValue(v gitlab.CIVariable) string {
+	switch {
+	case v.Masked:
+		return "[masked]"
+	case !m.varsShowValues:
+		return "••••••"
+	case v.VariableType == "file":
+		return "[file]"
+	}
+	return strings.ReplaceAll(v.Value, "\n", "⏎")
 }
 
-func (m *MainScreen) renderReadmeSection(width, height int) string {
-	// Update viewport dimensions and content
-	innerWidth := width - 4   // account for borders
-	innerHeight := height - 3 // account for borders and title
+func (m *MainScreen) renderVarsPopup() string {
+	popupWidth := int(float64(m.width) * 0.8)
+	popupHeight := int(float64(m.height) * 0.8)
 
-	if !m.readmeReady {
-		m.readmeViewport = viewport.New(innerWidth, innerHeight)
-		m.readmeViewport.SetContent(m.readmeRendered)
-		m.readmeReady = true
-	} else {
-		m.readmeViewport.Width = innerWidth
-		m.readmeViewport.Height = innerHeight
+	if popupWidth < 60 {
+		popupWidth = 60
+	}
+	if popupHeight < 15 {
+		popupHeight = 15
+	}
+	if popupWidth > m.width-4 {
+		popupWidth = m.width - 4
+	}
+	if popupHeight > m.height-4 {
+		popupHeight = m.height - 4
+	}
+
+	visibleLines := popupHeight - 6
+	if visibleLines < 5 {
+		visibleLines = 5
 	}
 
-	// Build the panel manually with viewport content
 	var content strings.Builder
 
-	// Apply cursor and visual selection highlighting
-	viewContent := m.readmeViewport.View()
-	lines := strings.Split(viewContent, "\n")
+	if m.varsErr != "" {
+		content.WriteString(lipgloss.NewStyle().Foreground(styles.ColorRed).Render(m.varsErr))
+	} else if m.varsLoading && len(m.variables) == 0 {
+		content.WriteString(styles.DimmedText.Render("Loading variables..."))
+	} else if len(m.variables) == 0 {
+		content.WriteString(styles.DimmedText.Render("No variables"))
+	} else {
+		header := fmt.Sprintf("  %-32s %-8s %-10s %-7s %-16s %s", "KEY", "TYPE", "PROTECTED", "MASKED", "SCOPE", "VALUE")
+		content.WriteString(styles.DimmedText.Render(header) + "\n")
+		content.WriteString(styles.DimmedText.Render(strings.Repeat("─", popupWidth-4)) + "\n")
 
-	// Calculate visual selection range
-	selStart := m.readmeVisualStart
-	selEnd := m.readmeVisualEnd
-	if selStart > selEnd {
-		selStart, selEnd = selEnd, selStart
-	}
+		startIdx := 0
+		if m.varsCursor >= visibleLines {
+			startIdx = m.varsCursor - visibleLines + 1
+		}
+		endIdx := min(startIdx+visibleLines, len(m.variables))
 
-	for i, line := range lines {
-		viewportLine := m.readmeViewport.YOffset + i
+		yesNo := func(b bool) string {
+			if b {
+				return "yes"
+			}
+			return "no"
+		}
 
-		// Highlight visual selection
-		if m.readmeVisualMode && viewportLine >= selStart && viewportLine <= selEnd {
-			line = lipgloss.NewStyle().Background(lipgloss.Color("238")).Render(line)
+		for i := startIdx; i < endIdx; i++ {
+			v := m.variables[i]
+
+			name := v.Key
+			if len(name) > 32 {
+				name = name[:31] + "…"
+			}
+			scope := v.EnvironmentScope
+			if len(scope) > 16 {
+				scope = scope[:15] + "…"
+			}
+			varType := v.VariableType
+			if varType == "env_var" {
+				varType = "env"
+			}
+
+			line := fmt.Sprintf("%-32s %-8s %-10s %-7s %-16s ",
+				name,
+				varType,
+				yesNo(v.Protected),
+				yesNo(v.Masked),
+				scope)
+			valueWidth := popupWidth - 6 - lipgloss.Width(line)
+			line += styles.DimmedText.Render(sliceByWidth(m.variableValue(v), 0, max(valueWidth, 0)))
+
+			if i == m.varsCursor {
+				line = styles.SelectedItem.Render("> ") + line
+			} else {
+				line = "  " + line
+			}
+			content.WriteString(line + "\n")
 		}
+	}
 
-		// Show cursor line when focused
-		if m.focusedPanel == PanelReadme && viewportLine == m.readmeCursor {
-			line = lipgloss.NewStyle().Reverse(true).Render(line)
+	// Build popup panel
+	title := "CI/CD Variables"
+	if m.selectedProject != nil {
+		title += " - " + m.selectedProject.Name
+	}
+	if m.varsLoading {
+		title += " (loading...)"
+	}
+	popup := components.SimpleBorderedPanel(title, content.String(), popupWidth, popupHeight, true)
+
+	// Center the popup
+	popupLines := strings.Split(popup, "\n")
+	topPadding := (m.height - len(popupLines)) / 2
+	leftPadding := (m.width - popupWidth) / 2
+	if topPadding < 0 {
+		topPadding = 0
+	}
+	if leftPadding < 0 {
+		leftPadding = 0
+	}
+
+	var result strings.Builder
+	for i := 0; i < topPadding; i++ {
+		result.WriteString("\n")
+	}
+	for _, line := range popupLines {
+		result.WriteString(strings.Repeat(" ", leftPadding) + line + "\n")
+	}
+
+	// Status bar at bottom
+	valuesHint := " show values"
+	if m.varsShowValues {
+		valuesHint = " hide values"
+	}
+	statusContent := styles.StatusBarKey.Render("Esc") + styles.StatusBarDesc.Render(" close") + " │ " +
+		styles.StatusBarKey.Render("j/k") + styles.StatusBarDesc.Render(" navigate") + " │ " +
+		styles.StatusBarKey.Render("s") + styles.StatusBarDesc.Render(valuesHint) + " │ " +
+		styles.StatusBarKey.Render("y") + styles.StatusBarDesc.Render(" copy name") + " │ " +
+		styles.StatusBarKey.Render("r") + styles.StatusBarDesc.Render(" refresh")
+	if m.statusMsg != "" {
+		statusContent = styles.SelectedItem.Render(m.statusMsg) + " │ " + statusContent
+	}
+
+	// Pad to bottom
+	currentLines := topPadding + len(popupLines)
+	for i := currentLines; i < m.height-1; i++ {
+		result.WriteString("\n")
+	}
+
+	result.WriteString(styles.StatusBar.Width(m.width).Render(statusContent))
+
+	return result.String()
+}
+
+// handleSnippetsPopup handles keyboard input for the snippets popup
+func (m *MainScreen) handleSnippetsPopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	visibleLines := m.snippetsVisibleLines()
+
+	switch msg.String() {
+	case "q", "esc", "escape":
+		m.showSnippetsPopup = false
+	case "j", "down":
+		if m.snippetsCursor < len(m.snippets)-1 {
+			m.snippetsCursor++
+		}
+	case "k", "up":
+		if m.snippetsCursor > 0 {
+			m.snippetsCursor--
+		}
+	case "ctrl+d":
+		m.snippetsCursor = min(m.snippetsCursor+visibleLines/2, max(len(m.snippets)-1, 0))
+	case "ctrl+u":
+		m.snippetsCursor = max(m.snippetsCursor-visibleLines/2, 0)
+	case "g":
+		m.snippetsCursor = 0
+	case "G":
+		m.snippetsCursor = max(len(m.snippets)-1, 0)
+	case "y":
+		if m.snippetsCursor < len(m.snippets) {
+			webURL := m.snippets[m.snippetsCursor].WebURL
+			if webURL == "" {
+				return m, nil
+			}
+			if err := copyToClipboard(webURL); err != nil {
+				m.statusMsg = "Copy failed: " + err.Error()
+			} else {
+				m.statusMsg = "Snippet: " + webURL
+			}
+		}
+	case "r":
+		if !m.isDemo {
+			m.snippetsLoading = true
+			return m, m.loadSnippets()
+		}
+	case "enter":
+		if m.snippetsCursor >= len(m.snippets) {
+			return m, nil
+		}
+		snippet := m.snippets[m.snippetsCursor]
+		m.showSnippetsPopup = false
+		m.contentTab = TabFiles
+		m.focusedPanel = PanelContent
+		if m.isDemo {
+			return m.Update(fileContentMsg{content: mockSnippetContent[snippet.ID], path: snippetPath(snippet), snippet: true})
 		}
-		lines[i] = line
+		m.loading = true
+		m.loadingMsg = "Loading snippet..."
+		cmd := m.loadSnippetContent(snippet)
+		m.retryCmd = cmd
+		return m, cmd
 	}
-	content.WriteString(strings.Join(lines, "\n"))
+	return m, nil
+}
 
-	// Add scroll indicator and visual mode status
-	var statusParts []string
-	if m.readmeViewport.TotalLineCount() > innerHeight {
-		scrollPercent := int(m.readmeViewport.ScrollPercent() * 100)
-		statusParts = append(statusParts, fmt.Sprintf("[%d%%]", scrollPercent))
-	}
-	if m.readmeVisualMode {
-		lineCount := m.readmeVisualEnd - m.readmeVisualStart
-		if lineCount < 0 {
-			lineCount = -lineCount
-		}
-		lineCount++
-		statusParts = append(statusParts, fmt.Sprintf("VISUAL(%d)", lineCount))
+// snippetsVisibleLines returns how many snippets fit in the popup
+func (m *MainScreen) snippetsVisibleLines() int {
+	popupHeight := int(float64(m.height) * 0.8)
+	if popupHeight < 15 {
+		popupHeight = 15
 	}
-	if len(statusParts) > 0 {
-		content.WriteString(styles.DimmedText.Render(" " + strings.Join(statusParts, " ")))
+	if popupHeight > m.height-4 {
+		popupHeight = m.height - 4
 	}
-
-	return components.SimpleBorderedPanel("README", content.String(), width, height, m.focusedPanel == PanelReadme)
+	return max(popupHeight-6, 5)
 }
 
-func (m *MainScreen) renderJobLogPopup() string {
-	// Use full screen
-	popupWidth := m.width
-	popupHeight := m.height - 1
-
-	// Split: left panel for job list, right panel for log
-	jobListWidth := 30
-	logWidth := popupWidth - jobListWidth
-
-	// Render job list panel
-	var jobList strings.Builder
-	for i, job := range m.jobs {
-		icon := styles.PipelineIcon(job.Status)
-		statusStyle := styles.PipelineStatus(job.Status)
-
-		// Format: icon name (status)
-		line := fmt.Sprintf("%s %s (%s)", icon, job.Name, job.Status)
-
-		// Truncate if too long
-		if len(line) > jobListWidth-4 {
-			line = line[:jobListWidth-5] + "…"
-		}
+func (m *MainScreen) renderSnippetsPopup() string {
+	popupWidth := int(float64(m.width) * 0.8)
+	popupHeight := int(float64(m.height) * 0.8)
 
-		if i == m.selectedJobIdx {
-			jobList.WriteString(styles.SelectedItem.Render("> " + statusStyle.Render(line)))
-		} else {
-			jobList.WriteString("  " + statusStyle.Render(line))
-		}
-		jobList.WriteString("\n")
+	if popupWidth < 60 {
+		popupWidth = 60
+	}
+	if popupHeight < 15 {
+		popupHeight = 15
+	}
+	if popupWidth > m.width-4 {
+		popupWidth = m.width - 4
+	}
+	if popupHeight > m.height-4 {
+		popupHeight = m.height - 4
 	}
 
-	// Job panel - focused when not in log
-	jobPanel := components.SimpleBorderedPanel(
-		fmt.Sprintf("Jobs (%d)", len(m.jobs)),
-		jobList.String(),
-		jobListWidth,
-		popupHeight,
-		!m.jobLogFocused,
-	)
+	visibleLines := m.snippetsVisibleLines()
 
-	// Render log panel
-	logInnerWidth := logWidth - 2
-	logInnerHeight := popupHeight - 2
+	var content strings.Builder
 
-	var logContent strings.Builder
-	if m.jobLog == "" {
-		if m.loading {
-			logContent.WriteString(m.loadingMsg)
-		} else {
-			logContent.WriteString(styles.DimmedText.Render("Select a job to view log"))
-		}
+	if m.snippetsErr != "" {
+		content.WriteString(lipgloss.NewStyle().Foreground(styles.ColorRed).Render(m.snippetsErr))
+	} else if m.snippetsLoading && len(m.snippets) == 0 {
+		content.WriteString(styles.DimmedText.Render("Loading snippets..."))
+	} else if len(m.snippets) == 0 {
+		content.WriteString(styles.DimmedText.Render("No snippets"))
 	} else {
-		if !m.jobLogReady || m.jobLogViewport.Width != logInnerWidth || m.jobLogViewport.Height != logInnerHeight {
-			m.jobLogViewport = viewport.New(logInnerWidth, logInnerHeight)
-			// Keep ANSI colors but clean up problematic characters
-			cleanLog := m.jobLog
-			// Replace tabs with spaces (tabs mess up width calculation)
-			cleanLog = strings.ReplaceAll(cleanLog, "\t", "    ")
-			// Remove carriage returns (CI logs use these for progress updates)
-			cleanLog = strings.ReplaceAll(cleanLog, "\r", "")
-			// Don't wrap - truncate lines to preserve line numbers for visual selection
-			m.jobLogViewport.SetContent(cleanLog)
-			// Start at bottom where errors usually are
-			m.jobLogViewport.GotoBottom()
-			m.jobLogReady = true
-		}
-		// Get viewport content and apply cursor/selection highlighting + horizontal scroll
-		viewContent := m.jobLogViewport.View()
-		lines := strings.Split(viewContent, "\n")
-
-		// Calculate visual selection range
-		selStart := m.visualStartLine
-		selEnd := m.visualEndLine
-		if selStart > selEnd {
-			selStart, selEnd = selEnd, selStart
+		startIdx := 0
+		if m.snippetsCursor >= visibleLines {
+			startIdx = m.snippetsCursor - visibleLines + 1
 		}
+		endIdx := min(startIdx+visibleLines, len(m.snippets))
 
-		for i, line := range lines {
-			line = strings.ReplaceAll(line, "\t", "    ")
-			// Apply horizontal scroll
-			line = sliceByWidth(line, m.jobLogHScroll, logInnerWidth)
-
-			viewportLine := m.jobLogViewport.YOffset + i
+		for i := startIdx; i < endIdx; i++ {
+			sn := m.snippets[i]
 
-			// Highlight visual selection
-			if m.visualLineMode && viewportLine >= selStart && viewportLine <= selEnd {
-				line = lipgloss.NewStyle().Background(lipgloss.Color("238")).Render(line)
+			meta := styles.DimmedText.Render(fmt.Sprintf(" %s @%s %s", sn.Visibility, sn.Author.Username, timeAgo(sn.UpdatedAt)))
+			line := fmt.Sprintf("$%-5d %s", sn.ID, sn.Title)
+			if sn.FileName != "" {
+				line += styles.DimmedText.Render(" (" + sn.FileName + ")")
 			}
+			line = truncateWidth(line, popupWidth-6-lipgloss.Width(meta)) + meta
 
-			// Show cursor line when focused (on top of selection)
-			if m.jobLogFocused && viewportLine == m.jobLogCursor {
-				line = lipgloss.NewStyle().Reverse(true).Render(line)
+			if i == m.snippetsCursor {
+				line = styles.SelectedItem.Render("> ") + line
+			} else {
+				line = "  " + line
 			}
-			lines[i] = line
+			content.WriteString(line + "\n")
 		}
-		logContent.WriteString(strings.Join(lines, "\n"))
-	}
 
-	// Build log title with job info
-	logTitle := "Log"
-	if m.selectedJobIdx < len(m.jobs) {
-		job := m.jobs[m.selectedJobIdx]
-		duration := ""
-		if job.Duration > 0 {
-			duration = fmt.Sprintf(" (%.1fs)", job.Duration)
+		if len(m.snippets) > visibleLines {
+			content.WriteString(styles.DimmedText.Render(fmt.Sprintf("\n[%d/%d]", m.snippetsCursor+1, len(m.snippets))))
 		}
-		logTitle = fmt.Sprintf("%s - %s%s", job.Name, job.Status, duration)
 	}
 
-	// Log panel - focused when in log
-	logPanel := components.SimpleBorderedPanel(logTitle, logContent.String(), logWidth, popupHeight, m.jobLogFocused)
-
-	// Join panels horizontally
-	combined := lipgloss.JoinHorizontal(lipgloss.Top, jobPanel, logPanel)
-
-	// Status bar
-	scrollInfo := ""
-	if m.jobLogReady && m.jobLogViewport.TotalLineCount() > logInnerHeight {
-		scrollInfo = fmt.Sprintf(" [%d%%]", int(m.jobLogViewport.ScrollPercent()*100))
+	// Build popup panel
+	title := "Snippets"
+	if m.selectedProject != nil {
+		title += " - " + m.selectedProject.Name
 	}
-	if m.jobLogHScroll > 0 {
-		scrollInfo += fmt.Sprintf(" [→%d]", m.jobLogHScroll)
+	if m.snippetsLoading {
+		title += " (loading...)"
 	}
+	popup := components.SimpleBorderedPanel(title, content.String(), popupWidth, popupHeight, true)
 
-	statusContent := styles.StatusBarKey.Render("H/L") + styles.StatusBarDesc.Render(" panels") + " │ " +
-		styles.StatusBarKey.Render("hjkl") + styles.StatusBarDesc.Render(" nav") + " │ " +
-		styles.StatusBarKey.Render("V") + styles.StatusBarDesc.Render(" select") + " │ " +
-		styles.StatusBarKey.Render("yy") + styles.StatusBarDesc.Render(" yank") + " │ " +
-		styles.StatusBarKey.Render("ggy") + styles.StatusBarDesc.Render(" all") + " │ " +
-		styles.StatusBarKey.Render("q") + styles.StatusBarDesc.Render(" close") +
-		scrollInfo
+	// Center the popup
+	popupLines := strings.Split(popup, "\n")
+	topPadding := (m.height - len(popupLines)) / 2
+	leftPadding := (m.width - popupWidth) / 2
+	if topPadding < 0 {
+		topPadding = 0
+	}
+	if leftPadding < 0 {
+		leftPadding = 0
+	}
 
-	if m.visualLineMode {
-		lineCount := m.visualEndLine - m.visualStartLine
-		if lineCount < 0 {
-			lineCount = -lineCount
-		}
-		lineCount++
-		statusContent = styles.SelectedItem.Render(fmt.Sprintf("VISUAL LINE (%d)", lineCount)) + " │ " + statusContent
+	var result strings.Builder
+	for i := 0; i < topPadding; i++ {
+		result.WriteString("\n")
+	}
+	for _, line := range popupLines {
+		result.WriteString(strings.Repeat(" ", leftPadding) + line + "\n")
 	}
 
+	// Status bar at bottom
+	statusContent := styles.StatusBarKey.Render("Esc") + styles.StatusBarDesc.Render(" close") + " │ " +
+		styles.StatusBarKey.Render("j/k") + styles.StatusBarDesc.Render(" navigate") + " │ " +
+		styles.StatusBarKey.Render("Enter") + styles.StatusBarDesc.Render(" view") + " │ " +
+		styles.StatusBarKey.Render("y") + styles.StatusBarDesc.Render(" copy URL") + " │ " +
+		styles.StatusBarKey.Render("r") + styles.StatusBarDesc.Render(" refresh")
 	if m.statusMsg != "" {
 		statusContent = styles.SelectedItem.Render(m.statusMsg) + " │ " + statusContent
 	}
 
-	statusBar := styles.StatusBar.Width(m.width).Render(statusContent)
+	// Pad to bottom
+	currentLines := topPadding + len(popupLines)
+	for i := currentLines; i < m.height-1; i++ {
+		result.WriteString("\n")
+	}
 
-	return combined + "\n" + statusBar
+	result.WriteString(styles.StatusBar.Width(m.width).Render(statusContent))
+
+	return result.String()
 }
 
-func (m *MainScreen) renderBranchPopup() string {
-	// Centered popup for branch selection
-	popupWidth := 50
-	popupHeight := 20
+// sortWikiPages orders pages by slug, segment by segment, so nested pages
+// directly follow their parent
+func sortWikiPages(pages []gitlab.WikiPage) []gitlab.WikiPage {
+	slices.SortStableFunc(pages, func(a, b gitlab.WikiPage) int {
+		return slices.Compare(strings.Split(a.Slug, "/"), strings.Split(b.Slug, "/"))
+	})
+	return pages
+}
+
+// handleWikiPopup handles keyboard input for the wiki popup
+func (m *MainScreen) handleWikiPopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	visibleLines := m.snippetsVisibleLines()
+
+	switch msg.String() {
+	case "q", "esc", "escape":
+		m.showWikiPopup = false
+	case "j", "down":
+		if m.wikiCursor < len(m.wikiPages)-1 {
+			m.wikiCursor++
+		}
+	case "k", "up":
+		if m.wikiCursor > 0 {
+			m.wikiCursor--
+		}
+	case "ctrl+d":
+		m.wikiCursor = min(m.wikiCursor+visibleLines/2, max(len(m.wikiPages)-1, 0))
+	case "ctrl+u":
+		m.wikiCursor = max(m.wikiCursor-visibleLines/2, 0)
+	case "g":
+		m.wikiCursor = 0
+	case "G":
+		m.wikiCursor = max(len(m.wikiPages)-1, 0)
+	case "r":
+		if !m.isDemo {
+			m.wikiLoading = true
+			return m, m.loadWikiPages()
+		}
+	case "enter":
+		if m.wikiCursor >= len(m.wikiPages) {
+			return m, nil
+		}
+		page := m.wikiPages[m.wikiCursor]
+		m.showWikiPopup = false
+		m.contentTab = TabFiles
+		m.focusedPanel = PanelContent
+		if m.isDemo {
+			return m.Update(fileContentMsg{content: mockWikiContent[page.Slug], path: wikiPath(page), wiki: true})
+		}
+		m.loading = true
+		m.loadingMsg = "Loading wiki page..."
+		cmd := m.loadWikiPage(page)
+		m.retryCmd = cmd
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m *MainScreen) renderWikiPopup() string {
+	popupWidth := int(float64(m.width) * 0.8)
+	popupHeight := int(float64(m.height) * 0.8)
 
+	if popupWidth < 60 {
+		popupWidth = 60
+	}
+	if popupHeight < 15 {
+		popupHeight = 15
+	}
 	if popupWidth > m.width-4 {
 		popupWidth = m.width - 4
 	}
@@ -3065,71 +11907,61 @@ func (m *MainScreen) renderBranchPopup() string {
 		popupHeight = m.height - 4
 	}
 
-	var content strings.Builder
-
-	// Header
-	content.WriteString(styles.DimmedText.Render("Current: ") + styles.SelectedItem.Render(m.currentBranch) + "\n\n")
-
-	if len(m.branches) == 0 {
-		if m.loading {
-			content.WriteString(m.loadingMsg)
-		} else {
-			content.WriteString(styles.DimmedText.Render("No branches found"))
-		}
-	} else {
-		visibleLines := popupHeight - 6
-		if visibleLines < 5 {
-			visibleLines = 5
-		}
+	visibleLines := m.snippetsVisibleLines()
 
-		// Calculate scroll offset for branch list
-		startIdx := 0
-		if m.selectedBranchIdx >= visibleLines {
-			startIdx = m.selectedBranchIdx - visibleLines + 1
-		}
-		endIdx := startIdx + visibleLines
-		if endIdx > len(m.branches) {
-			endIdx = len(m.branches)
-		}
+	var content strings.Builder
 
-		for i := startIdx; i < endIdx; i++ {
-			b := m.branches[i]
-			icon := "○"
-			if b.Default {
-				icon = "●"
-			}
-			if b.Name == m.currentBranch {
-				icon = "✓"
+	if m.wikiErr != "" {
+		content.WriteString(lipgloss.NewStyle().Foreground(styles.ColorRed).Render(m.wikiErr))
+	} else if m.wikiLoading && len(m.wikiPages) == 0 {
+		content.WriteString(styles.DimmedText.Render("Loading wiki pages..."))
+	} else if len(m.wikiPages) == 0 {
+		content.WriteString(styles.DimmedText.Render("No wiki pages"))
+	} else {
+		startIdx := 0
+		if m.wikiCursor >= visibleLines {
+			startIdx = m.wikiCursor - visibleLines + 1
+		}
+		endIdx := min(startIdx+visibleLines, len(m.wikiPages))
+
+		for i := startIdx; i < endIdx; i++ {
+			page := m.wikiPages[i]
+
+			// Indent nested pages under their parent
+			indent := strings.Repeat("  ", strings.Count(page.Slug, "/"))
+			line := indent + page.Title
+			if page.Format != "" && page.Format != "markdown" {
+				line += styles.DimmedText.Render(" (" + page.Format + ")")
 			}
+			line = truncateWidth(line, popupWidth-6)
 
-			line := fmt.Sprintf("%s %s", icon, b.Name)
-			if i == m.selectedBranchIdx {
-				line = styles.SelectedItem.Render("> " + line)
+			if i == m.wikiCursor {
+				line = styles.SelectedItem.Render("> ") + line
 			} else {
 				line = "  " + line
 			}
 			content.WriteString(line + "\n")
 		}
 
-		// Scroll indicator
-		if len(m.branches) > visibleLines {
-			content.WriteString(styles.DimmedText.Render(fmt.Sprintf("\n[%d/%d]", m.selectedBranchIdx+1, len(m.branches))))
+		if len(m.wikiPages) > visibleLines {
+			content.WriteString(styles.DimmedText.Render(fmt.Sprintf("\n[%d/%d]", m.wikiCursor+1, len(m.wikiPages))))
 		}
 	}
 
 	// Build popup panel
-	popup := components.SimpleBorderedPanel("Switch Branch", content.String(), popupWidth, popupHeight, true)
+	title := "Wiki"
+	if m.selectedProject != nil {
+		title += " - " + m.selectedProject.Name
+	}
+	if m.wikiLoading {
+		title += " (loading...)"
+	}
+	popup := components.SimpleBorderedPanel(title, content.String(), popupWidth, popupHeight, true)
 
 	// Center the popup
 	popupLines := strings.Split(popup, "\n")
-	topPadding := (m.height - len(popupLines)) / 2
-	leftPadding := (m.width - popupWidth) / 2
-	if topPadding < 0 {
-		topPadding = 0
-	}
-	if leftPadding < 0 {
-		leftPadding = 0
-	}
+	topPadding := max((m.height-len(popupLines))/2, 0)
+	leftPadding := max((m.width-popupWidth)/2, 0)
 
 	var result strings.Builder
 	for i := 0; i < topPadding; i++ {
@@ -3140,9 +11972,13 @@ func (m *MainScreen) renderBranchPopup() string {
 	}
 
 	// Status bar at bottom
-	statusContent := styles.StatusBarKey.Render("Esc") + styles.StatusBarDesc.Render(" cancel") + " │ " +
+	statusContent := styles.StatusBarKey.Render("Esc") + styles.StatusBarDesc.Render(" close") + " │ " +
 		styles.StatusBarKey.Render("j/k") + styles.StatusBarDesc.Render(" navigate") + " │ " +
-		styles.StatusBarKey.Render("Enter") + styles.StatusBarDesc.Render(" switch")
+		styles.StatusBarKey.Render("Enter") + styles.StatusBarDesc.Render(" view") + " │ " +
+		styles.StatusBarKey.Render("r") + styles.StatusBarDesc.Render(" refresh")
+	if m.statusMsg != "" {
+		statusContent = styles.SelectedItem.Render(m.statusMsg) + " │ " + statusContent
+	}
 
 	// Pad to bottom
 	currentLines := topPadding + len(popupLines)
@@ -3155,8 +11991,64 @@ func (m *MainScreen) renderBranchPopup() string {
 	return result.String()
 }
 
-func (m *MainScreen) renderRunnersPopup() string {
-	// Larger popup for runners view
+// handleContributorsPopup handles keyboard input for the contributors popup
+func (m *MainScreen) handleContributorsPopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	visibleLines := m.snippetsVisibleLines()
+
+	switch msg.String() {
+	case "q", "esc", "escape":
+		m.showContributorsPopup = false
+	case "j", "down":
+		if m.contributorsCursor < len(m.contributors)-1 {
+			m.contributorsCursor++
+		}
+	case "k", "up":
+		if m.contributorsCursor > 0 {
+			m.contributorsCursor--
+		}
+	case "ctrl+d":
+		m.contributorsCursor = min(m.contributorsCursor+visibleLines/2, max(len(m.contributors)-1, 0))
+	case "ctrl+u":
+		m.contributorsCursor = max(m.contributorsCursor-visibleLines/2, 0)
+	case "g":
+		m.contributorsCursor = 0
+	case "G":
+		m.contributorsCursor = max(len(m.contributors)-1, 0)
+	case "y":
+		if m.contributorsCursor < len(m.contributors) {
+			email := m.contributors[m.contributorsCursor].Email
+			if email == "" {
+				return m, nil
+			}
+			if err := copyToClipboard(email); err != nil {
+				m.statusMsg = "Copy failed: " + err.Error()
+			} else {
+				m.statusMsg = "Email: " + email
+			}
+		}
+	case "r":
+		if !m.isDemo {
+			m.contributorsLoading = true
+			return m, m.loadContributors()
+		}
+	}
+	return m, nil
+}
+
+// commitBar renders commits relative to the top contributor's as a bar of width cells
+func commitBar(commits, most, width int) string {
+	filled := 0
+	if most > 0 {
+		filled = min(max(commits*width/most, 0), width)
+	}
+	// Anyone with commits gets at least a sliver
+	if filled == 0 && commits > 0 {
+		filled = 1
+	}
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
+
+func (m *MainScreen) renderContributorsPopup() string {
 	popupWidth := int(float64(m.width) * 0.8)
 	popupHeight := int(float64(m.height) * 0.8)
 
@@ -3173,105 +12065,38 @@ func (m *MainScreen) renderRunnersPopup() string {
 		popupHeight = m.height - 4
 	}
 
-	var content strings.Builder
-
-	// Tab headers
-	runningTab := fmt.Sprintf("Running (%d)", len(m.runningJobs))
-	pendingTab := fmt.Sprintf("Pending (%d)", len(m.pendingJobs))
-
-	if m.runnersTab == 0 {
-		content.WriteString(styles.SelectedItem.Render("["+runningTab+"]") + " " + styles.DimmedText.Render(pendingTab))
-	} else {
-		content.WriteString(styles.DimmedText.Render(runningTab) + " " + styles.SelectedItem.Render("["+pendingTab+"]"))
-	}
-	content.WriteString("\n\n")
+	visibleLines := m.snippetsVisibleLines()
 
-	// Get current job list
-	jobs := m.runningJobs
-	if m.runnersTab == 1 {
-		jobs = m.pendingJobs
-	}
+	var content strings.Builder
 
-	if m.runnersLoading {
-		content.WriteString(styles.DimmedText.Render("Loading jobs..."))
-	} else if len(jobs) == 0 {
-		if m.runnersTab == 0 {
-			content.WriteString(styles.DimmedText.Render("No running jobs"))
-		} else {
-			content.WriteString(styles.DimmedText.Render("No pending jobs"))
-		}
+	if m.contributorsErr != "" {
+		content.WriteString(lipgloss.NewStyle().Foreground(styles.ColorRed).Render(m.contributorsErr))
+	} else if m.contributorsLoading && len(m.contributors) == 0 {
+		content.WriteString(styles.DimmedText.Render("Loading contributors..."))
+	} else if len(m.contributors) == 0 {
+		content.WriteString(styles.DimmedText.Render("No contributors"))
 	} else {
-		visibleLines := popupHeight - 8
-		if visibleLines < 5 {
-			visibleLines = 5
-		}
-
-		// Calculate scroll offset
 		startIdx := 0
-		if m.runnersCursor >= visibleLines {
-			startIdx = m.runnersCursor - visibleLines + 1
-		}
-		endIdx := startIdx + visibleLines
-		if endIdx > len(jobs) {
-			endIdx = len(jobs)
+		if m.contributorsCursor >= visibleLines {
+			startIdx = m.contributorsCursor - visibleLines + 1
 		}
+		endIdx := min(startIdx+visibleLines, len(m.contributors))
 
-		// Column header
-		header := fmt.Sprintf("%-20s %-30s %-15s %s", "PROJECT", "JOB", "RUNNER", "DURATION")
-		content.WriteString(styles.DimmedText.Render(header) + "\n")
-		content.WriteString(styles.DimmedText.Render(strings.Repeat("─", popupWidth-4)) + "\n")
+		// Sorted by commits, so the first one sets the scale
+		most := m.contributors[0].Commits
+		const nameWidth, barWidth = 24, 20
 
 		for i := startIdx; i < endIdx; i++ {
-			job := jobs[i]
-			icon := styles.PipelineIcon(job.Status)
-			statusStyle := styles.PipelineStatus(job.Status)
-
-			// Project name (truncate if needed)
-			project := job.Project.Name
-			if len(project) > 18 {
-				project = project[:17] + "…"
-			}
-
-			// Job name (truncate if needed)
-			jobName := job.Name
-			if job.Stage != "" && job.Stage != job.Name {
-				jobName = job.Stage + "/" + job.Name
-			}
-			if len(jobName) > 28 {
-				jobName = jobName[:27] + "…"
-			}
-
-			// Runner info
-			runnerName := "-"
-			if job.Runner != nil {
-				runnerName = job.Runner.Description
-				if runnerName == "" {
-					runnerName = job.Runner.Name
-				}
-				if runnerName == "" {
-					runnerName = fmt.Sprintf("#%d", job.Runner.ID)
-				}
-			}
-			if len(runnerName) > 13 {
-				runnerName = runnerName[:12] + "…"
-			}
-
-			// Duration
-			duration := "-"
-			if job.Duration > 0 {
-				duration = fmt.Sprintf("%.0fs", job.Duration)
-			} else if job.StartedAt != nil {
-				duration = timeAgo(*job.StartedAt)
-			}
+			c := m.contributors[i]
 
-			line := fmt.Sprintf("%s %-20s %-30s %-15s %s",
-				statusStyle.Render(icon),
-				project,
-				jobName,
-				runnerName,
-				duration)
+			name := truncateWidth(c.Name, nameWidth)
+			name += strings.Repeat(" ", max(nameWidth-lipgloss.Width(name), 0))
+			bar := lipgloss.NewStyle().Foreground(styles.ColorSuccess).Render(commitBar(c.Commits, most, barWidth))
+			meta := styles.DimmedText.Render(fmt.Sprintf(" +%d -%d %s", c.Additions, c.Deletions, c.Email))
+			line := fmt.Sprintf("%s %s %5d", name, bar, c.Commits)
+			line = truncateWidth(line+meta, popupWidth-6)
 
-			if i == m.runnersCursor {
+			if i == m.contributorsCursor {
 				line = styles.SelectedItem.Render("> ") + line
 			} else {
 				line = "  " + line
@@ -3279,29 +12104,25 @@ func (m *MainScreen) renderRunnersPopup() string {
 			content.WriteString(line + "\n")
 		}
 
-		// Scroll indicator
-		if len(jobs) > visibleLines {
-			content.WriteString(styles.DimmedText.Render(fmt.Sprintf("\n[%d/%d]", m.runnersCursor+1, len(jobs))))
+		if len(m.contributors) > visibleLines {
+			content.WriteString(styles.DimmedText.Render(fmt.Sprintf("\n[%d/%d]", m.contributorsCursor+1, len(m.contributors))))
 		}
 	}
 
 	// Build popup panel
-	title := "CI/CD Jobs"
-	if m.runnersLoading {
+	title := "Contributors"
+	if m.selectedProject != nil {
+		title += " - " + m.selectedProject.Name
+	}
+	if m.contributorsLoading {
 		title += " (loading...)"
 	}
 	popup := components.SimpleBorderedPanel(title, content.String(), popupWidth, popupHeight, true)
 
 	// Center the popup
 	popupLines := strings.Split(popup, "\n")
-	topPadding := (m.height - len(popupLines)) / 2
-	leftPadding := (m.width - popupWidth) / 2
-	if topPadding < 0 {
-		topPadding = 0
-	}
-	if leftPadding < 0 {
-		leftPadding = 0
-	}
+	topPadding := max((m.height-len(popupLines))/2, 0)
+	leftPadding := max((m.width-popupWidth)/2, 0)
 
 	var result strings.Builder
 	for i := 0; i < topPadding; i++ {
@@ -3313,9 +12134,12 @@ func (m *MainScreen) renderRunnersPopup() string {
 
 	// Status bar at bottom
 	statusContent := styles.StatusBarKey.Render("Esc") + styles.StatusBarDesc.Render(" close") + " │ " +
-		styles.StatusBarKey.Render("Tab") + styles.StatusBarDesc.Render(" switch") + " │ " +
 		styles.StatusBarKey.Render("j/k") + styles.StatusBarDesc.Render(" navigate") + " │ " +
+		styles.StatusBarKey.Render("y") + styles.StatusBarDesc.Render(" copy email") + " │ " +
 		styles.StatusBarKey.Render("r") + styles.StatusBarDesc.Render(" refresh")
+	if m.statusMsg != "" {
+		statusContent = styles.SelectedItem.Render(m.statusMsg) + " │ " + statusContent
+	}
 
 	// Pad to bottom
 	currentLines := topPadding + len(popupLines)
@@ -3328,336 +12152,439 @@ func (m *MainScreen) renderRunnersPopup() string {
 	return result.String()
 }
 
-func (m *MainScreen) renderStatusBar() string {
-	// If there's a status message, show it prominently
-	if m.statusMsg != "" {
-		msgStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Bold(true) // Green
-		return styles.StatusBar.Width(m.width).Render(msgStyle.Render(m.statusMsg))
-	}
-
-	// If there's an error, show it prominently with retry hint
-	if m.lastError != "" {
-		errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true) // Red
-		errorMsg := m.lastError
-		// Truncate long error messages
-		maxLen := m.width - 30
-		if maxLen > 0 && len(errorMsg) > maxLen {
-			errorMsg = errorMsg[:maxLen] + "..."
+// handleRecentPopup handles keyboard input for the recent projects switcher
+func (m *MainScreen) handleRecentPopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc", "escape", "ctrl+r":
+		m.showRecentPopup = false
+	case "j", "down":
+		if m.recentCursor < len(m.recentProjects)-1 {
+			m.recentCursor++
+		}
+	case "k", "up":
+		if m.recentCursor > 0 {
+			m.recentCursor--
+		}
+	case "enter":
+		m.showRecentPopup = false
+		if m.recentCursor < len(m.recentProjects) {
+			return m, m.openRecentProject(m.recentProjects[m.recentCursor])
 		}
-		errText := errorStyle.Render("Error: " + errorMsg)
-		retryHint := styles.StatusBarKey.Render(" r") + styles.StatusBarDesc.Render(" retry") + " │ " +
-			styles.StatusBarKey.Render("Esc") + styles.StatusBarDesc.Render(" dismiss")
-		return styles.StatusBar.Width(m.width).Render(errText + " " + retryHint)
 	}
+	return m, nil
+}
 
-	panels := []struct {
-		id   PanelID
-		key  string
-		name string
-	}{
-		{PanelNavigator, "1", "navigator"},
-		{PanelContent, "2", "content"},
-		{PanelReadme, "3", "readme"},
-	}
+// renderRecentPopup renders the recent projects switcher, sized to the list
+func (m *MainScreen) renderRecentPopup() string {
+	popupWidth := min(m.width-4, 60)
 
-	var parts []string
-	for _, p := range panels {
-		if p.id == m.focusedPanel {
-			parts = append(parts, styles.StatusBarKey.Render("["+p.key+"]")+styles.StatusBarDesc.Render(" "+p.name))
+	var content strings.Builder
+	for i, recent := range m.recentProjects {
+		line := truncateWidth(recent.Path, popupWidth-16)
+		if m.selectedProject != nil && recent.ID == m.selectedProject.ID {
+			line += styles.DimmedText.Render(" (current)")
+		}
+		if i == m.recentCursor {
+			line = styles.SelectedItem.Render("> ") + line
 		} else {
-			parts = append(parts, styles.DimmedText.Render(" "+p.key+" "+p.name))
+			line = "  " + line
 		}
+		content.WriteString(line + "\n")
 	}
+	popupHeight := min(len(m.recentProjects)+2, m.height-4)
+	popup := components.SimpleBorderedPanel("Recent projects", strings.TrimSuffix(content.String(), "\n"), popupWidth, popupHeight, true)
 
-	left := strings.Join(parts, " ")
+	// Center the popup
+	popupLines := strings.Split(popup, "\n")
+	topPadding := max((m.height-len(popupLines))/2, 0)
+	leftPadding := max((m.width-popupWidth)/2, 0)
 
-	var help string
-	if m.focusedPanel == PanelReadme {
-		// README-specific keybindings
-		help = styles.StatusBarKey.Render("j/k") + styles.StatusBarDesc.Render(" scroll") + " │ " +
-			styles.StatusBarKey.Render("V") + styles.StatusBarDesc.Render(" select") + " │ " +
-			styles.StatusBarKey.Render("yy") + styles.StatusBarDesc.Render(" yank") + " │ " +
-			styles.StatusBarKey.Render("ggy") + styles.StatusBarDesc.Render(" all") + " │ " +
-			styles.StatusBarKey.Render("q") + styles.StatusBarDesc.Render(" quit")
-	} else {
-		help = styles.StatusBarKey.Render("j/k") + styles.StatusBarDesc.Render(" nav") + " │ " +
-			styles.StatusBarKey.Render("Enter") + styles.StatusBarDesc.Render(" select") + " │ " +
-			styles.StatusBarKey.Render("S") + styles.StatusBarDesc.Render(" ssh") + " " +
-			styles.StatusBarKey.Render("U") + styles.StatusBarDesc.Render(" https") + " │ " +
-			styles.StatusBarKey.Render("R") + styles.StatusBarDesc.Render(" jobs") + " │ " +
-			styles.StatusBarKey.Render("q") + styles.StatusBarDesc.Render(" quit")
+	var result strings.Builder
+	for i := 0; i < topPadding; i++ {
+		result.WriteString("\n")
+	}
+	for _, line := range popupLines {
+		result.WriteString(strings.Repeat(" ", leftPadding) + line + "\n")
 	}
 
-	leftWidth := lipgloss.Width(left)
-	rightWidth := lipgloss.Width(help)
-	padding := m.width - leftWidth - rightWidth - 2
-	if padding < 0 {
-		padding = 0
+	// Status bar at bottom
+	statusContent := styles.StatusBarKey.Render("Esc") + styles.StatusBarDesc.Render(" close") + " │ " +
+		styles.StatusBarKey.Render("j/k") + styles.StatusBarDesc.Render(" navigate") + " │ " +
+		styles.StatusBarKey.Render("Enter") + styles.StatusBarDesc.Render(" open")
+
+	// Pad to bottom
+	currentLines := topPadding + len(popupLines)
+	for i := currentLines; i < m.height-1; i++ {
+		result.WriteString("\n")
 	}
 
-	return styles.StatusBar.Width(m.width).Render(left + strings.Repeat(" ", padding) + help)
+	result.WriteString(styles.StatusBar.Width(m.width).Render(statusContent))
+
+	return result.String()
 }
 
-// handleReleasePopup handles keyboard input for the release assets popup
-func (m *MainScreen) handleReleasePopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	if m.selectedReleaseIdx >= len(m.releases) {
-		m.showReleasePopup = false
-		return m, nil
+// labelsCount returns the number of rows in the labels popup's current view
+func (m *MainScreen) labelsCount() int {
+	if m.labelsShowMilestones {
+		return len(m.milestones)
 	}
+	return len(m.labels)
+}
 
-	rel := m.releases[m.selectedReleaseIdx]
-	totalAssets := len(rel.Assets.Sources) + len(rel.Assets.Links)
+// handleLabelsPopup handles keyboard input for the labels and milestones popup
+func (m *MainScreen) handleLabelsPopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	visibleLines := m.snippetsVisibleLines()
+	count := m.labelsCount()
 
 	switch msg.String() {
-	case "esc", "escape", "q":
-		m.showReleasePopup = false
-		return m, nil
+	case "q", "esc", "escape":
+		m.showLabelsPopup = false
+	case "tab":
+		m.labelsShowMilestones = !m.labelsShowMilestones
+		m.labelsCursor = 0
 	case "j", "down":
-		if m.releaseAssetCursor < totalAssets-1 {
-			m.releaseAssetCursor++
+		if m.labelsCursor < count-1 {
+			m.labelsCursor++
 		}
 	case "k", "up":
-		if m.releaseAssetCursor > 0 {
-			m.releaseAssetCursor--
+		if m.labelsCursor > 0 {
+			m.labelsCursor--
 		}
+	case "ctrl+d":
+		m.labelsCursor = min(m.labelsCursor+visibleLines/2, max(count-1, 0))
+	case "ctrl+u":
+		m.labelsCursor = max(m.labelsCursor-visibleLines/2, 0)
 	case "g":
-		m.releaseAssetCursor = 0
+		m.labelsCursor = 0
 	case "G":
-		if totalAssets > 0 {
-			m.releaseAssetCursor = totalAssets - 1
+		m.labelsCursor = max(count-1, 0)
+	case "y":
+		if m.labelsCursor >= count {
+			return m, nil
 		}
-	case "y", "enter":
-		// Copy the URL of selected asset to clipboard
-		url := m.getSelectedReleaseAssetURL()
-		if url != "" {
-			if err := copyToClipboard(url); err != nil {
-				m.statusMsg = "Copy failed: " + err.Error()
-			} else {
-				m.statusMsg = "Copied: " + truncateString(url, 60)
-			}
+		var text, label string
+		if m.labelsShowMilestones {
+			text, label = m.milestones[m.labelsCursor].WebURL, "Copied: "
+		} else {
+			text, label = m.labels[m.labelsCursor].Name, "Label: "
 		}
-		return m, nil
-	case "o":
-		// Open release web URL in browser
-		if rel.Links.Self != "" {
-			m.statusMsg = "Open: " + rel.Links.Self
-			// Just copy for now - could open browser in future
-			if err := copyToClipboard(rel.Links.Self); err != nil {
-				m.statusMsg = "Copy failed: " + err.Error()
-			} else {
-				m.statusMsg = "Copied release URL: " + truncateString(rel.Links.Self, 50)
-			}
+		if text == "" {
+			return m, nil
 		}
-		return m, nil
-	case "d":
-		// Download the selected asset - open folder browser
-		url := m.getSelectedReleaseAssetURL()
-		filename := m.getSelectedReleaseAssetFilename()
-		if url != "" && filename != "" {
-			m.downloadURL = url
-			m.downloadFilename = filename
-			m.showReleasePopup = false
-			m.openFolderBrowser()
+		if err := copyToClipboard(text); err != nil {
+			m.statusMsg = "Copy failed: " + err.Error()
+		} else {
+			m.statusMsg = label + text
+		}
+	case "r":
+		if !m.isDemo {
+			m.labelsLoading = true
+			return m, m.loadLabels()
 		}
-		return m, nil
 	}
-
 	return m, nil
 }
 
-// getSelectedReleaseAssetFilename returns the filename of the currently selected asset
-func (m *MainScreen) getSelectedReleaseAssetFilename() string {
-	if m.selectedReleaseIdx >= len(m.releases) {
-		return ""
+// labelPalette is what label colors are mapped onto, so swatches stay within
+// the theme rather than relying on true-color support. It's read when called,
+// as the colors change once styles.Apply sets the configured scheme.
+func labelPalette() []lipgloss.Color {
+	return []lipgloss.Color{
+		styles.ColorRed, styles.ColorGreen, styles.ColorYellow, styles.ColorBlue,
+		styles.ColorMagenta, styles.ColorCyan, styles.ColorWhite, styles.ColorGray,
 	}
+}
 
-	rel := m.releases[m.selectedReleaseIdx]
-	cursor := m.releaseAssetCursor
-
-	// First, source archives
-	if cursor < len(rel.Assets.Sources) {
-		src := rel.Assets.Sources[cursor]
-		return fmt.Sprintf("%s-%s.%s", rel.TagName, "source", src.Format)
+// parseHexColor parses "#rrggbb" or "#rgb" into its components
+func parseHexColor(hex string) (r, g, b int, ok bool) {
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
 	}
-
-	// Then, asset links
-	linkIdx := cursor - len(rel.Assets.Sources)
-	if linkIdx < len(rel.Assets.Links) {
-		return rel.Assets.Links[linkIdx].Name
+	if len(hex) != 6 {
+		return 0, 0, 0, false
 	}
-
-	return ""
-}
-
-// openFolderBrowser initializes and shows the folder browser
-func (m *MainScreen) openFolderBrowser() {
-	// Start from home directory
-	home, err := os.UserHomeDir()
+	v, err := strconv.ParseUint(hex, 16, 32)
 	if err != nil {
-		home = "/"
+		return 0, 0, 0, false
 	}
-	m.folderBrowserPath = home
-	m.folderBrowserCursor = 0
-	m.folderBrowserScroll = 0
-	m.loadFolderEntries()
-	m.showFolderBrowser = true
+	return int(v >> 16), int(v >> 8 & 0xff), int(v & 0xff), true
 }
 
-// loadFolderEntries loads directory entries for the current folder browser path
-func (m *MainScreen) loadFolderEntries() {
-	entries, err := os.ReadDir(m.folderBrowserPath)
-	if err != nil {
-		m.folderBrowserEntries = []string{}
-		return
+// nearestLabelColor maps a label's hex color to the closest palette color,
+// falling back to gray for colors it can't parse
+func nearestLabelColor(hex string) lipgloss.Color {
+	r, g, b, ok := parseHexColor(hex)
+	if !ok {
+		return styles.ColorGray
 	}
-
-	m.folderBrowserEntries = []string{}
-	for _, entry := range entries {
-		name := entry.Name()
-		// Skip hidden files/directories (starting with .)
-		if strings.HasPrefix(name, ".") {
-			continue
-		}
-		if entry.IsDir() {
-			m.folderBrowserEntries = append(m.folderBrowserEntries, name)
-		}
+	colors := labelPalette()
+	palette := make([][3]int, len(colors))
+	for i, c := range colors {
+		pr, pg, pb, _ := parseHexColor(string(c))
+		palette[i] = [3]int{pr, pg, pb}
 	}
-	sort.Strings(m.folderBrowserEntries)
+	return colors[nearestColor([3]int{r, g, b}, palette)]
 }
 
-// getSelectedReleaseAssetURL returns the URL of the currently selected asset
-func (m *MainScreen) getSelectedReleaseAssetURL() string {
-	if m.selectedReleaseIdx >= len(m.releases) {
-		return ""
+// milestoneOverdue reports whether an active milestone's due date has passed
+func milestoneOverdue(ms gitlab.Milestone, now time.Time) bool {
+	if ms.State != "active" || ms.DueDate == "" {
+		return false
 	}
+	return ms.DueDate < now.Format("2006-01-02")
+}
 
-	rel := m.releases[m.selectedReleaseIdx]
-	cursor := m.releaseAssetCursor
+func (m *MainScreen) renderLabelsPopup() string {
+	popupWidth := int(float64(m.width) * 0.8)
+	popupHeight := int(float64(m.height) * 0.8)
 
-	// First, source archives
-	if cursor < len(rel.Assets.Sources) {
-		return rel.Assets.Sources[cursor].URL
+	if popupWidth < 60 {
+		popupWidth = 60
+	}
+	if popupHeight < 15 {
+		popupHeight = 15
+	}
+	if popupWidth > m.width-4 {
+		popupWidth = m.width - 4
+	}
+	if popupHeight > m.height-4 {
+		popupHeight = m.height - 4
 	}
 
-	// Then, asset links
-	linkIdx := cursor - len(rel.Assets.Sources)
-	if linkIdx < len(rel.Assets.Links) {
-		return rel.Assets.Links[linkIdx].URL
+	visibleLines := m.snippetsVisibleLines()
+	count := m.labelsCount()
+	what := "labels"
+	if m.labelsShowMilestones {
+		what = "milestones"
 	}
 
-	return ""
-}
+	var content strings.Builder
 
-// renderReleasePopup renders the release assets popup
-func (m *MainScreen) renderReleasePopup() string {
-	if m.selectedReleaseIdx >= len(m.releases) {
-		return ""
+	if m.labelsErr != "" {
+		content.WriteString(lipgloss.NewStyle().Foreground(styles.ColorRed).Render(m.labelsErr))
+	} else if m.labelsLoading && count == 0 {
+		content.WriteString(styles.DimmedText.Render("Loading " + what + "..."))
+	} else if count == 0 {
+		content.WriteString(styles.DimmedText.Render("No " + what))
+	} else {
+		startIdx := 0
+		if m.labelsCursor >= visibleLines {
+			startIdx = m.labelsCursor - visibleLines + 1
+		}
+		endIdx := min(startIdx+visibleLines, count)
+		now := time.Now()
+
+		for i := startIdx; i < endIdx; i++ {
+			var line string
+			if m.labelsShowMilestones {
+				ms := m.milestones[i]
+				state := lipgloss.NewStyle().Foreground(styles.ColorSuccess).Render("● open  ")
+				if ms.State == "closed" {
+					state = styles.DimmedText.Render("○ closed")
+				}
+				due := styles.DimmedText.Render("no due date")
+				if ms.DueDate != "" {
+					due = styles.DimmedText.Render("due " + ms.DueDate)
+					if milestoneOverdue(ms, now) {
+						due = lipgloss.NewStyle().Foreground(styles.ColorFailed).Render("due " + ms.DueDate + " (overdue)")
+					}
+				}
+				line = fmt.Sprintf("%s %s  %s", state, ms.Title, due)
+			} else {
+				l := m.labels[i]
+				swatch := lipgloss.NewStyle().Foreground(nearestLabelColor(l.Color)).Render("■")
+				line = swatch + " " + l.Name
+				if l.Description != "" {
+					line += styles.DimmedText.Render(" - " + l.Description)
+				}
+			}
+			line = truncateWidth(line, popupWidth-6)
+
+			if i == m.labelsCursor {
+				line = styles.SelectedItem.Render("> ") + line
+			} else {
+				line = "  " + line
+			}
+			content.WriteString(line + "\n")
+		}
+
+		if count > visibleLines {
+			content.WriteString(styles.DimmedText.Render(fmt.Sprintf("\n[%d/%d]", m.labelsCursor+1, count)))
+		}
 	}
 
-	rel := m.releases[m.selectedReleaseIdx]
+	// Build popup panel
+	title := "Labels"
+	if m.labelsShowMilestones {
+		title = "Milestones"
+	}
+	if m.selectedProject != nil {
+		title += " - " + m.selectedProject.Name
+	}
+	if m.labelsLoading {
+		title += " (loading...)"
+	}
+	popup := components.SimpleBorderedPanel(title, content.String(), popupWidth, popupHeight, true)
 
-	// Popup dimensions
-	popupWidth := min(m.width-4, 80)
-	popupHeight := min(m.height-4, 30)
+	// Center the popup
+	popupLines := strings.Split(popup, "\n")
+	topPadding := max((m.height-len(popupLines))/2, 0)
+	leftPadding := max((m.width-popupWidth)/2, 0)
 
-	var content strings.Builder
+	var result strings.Builder
+	for i := 0; i < topPadding; i++ {
+		result.WriteString("\n")
+	}
+	for _, line := range popupLines {
+		result.WriteString(strings.Repeat(" ", leftPadding) + line + "\n")
+	}
 
-	// Release info header
-	name := rel.Name
-	if name == "" {
-		name = rel.TagName
+	// Status bar at bottom
+	copyDesc := " copy name"
+	if m.labelsShowMilestones {
+		copyDesc = " copy URL"
 	}
-	content.WriteString(styles.ActivePanelTitle.Render("Release: "+name) + "\n")
-	content.WriteString(styles.DimmedText.Render("Tag: "+rel.TagName) + "\n")
-	if rel.Commit.ShortID != "" {
-		content.WriteString(styles.DimmedText.Render("Commit: "+rel.Commit.ShortID) + "\n")
+	statusContent := styles.StatusBarKey.Render("Esc") + styles.StatusBarDesc.Render(" close") + " │ " +
+		styles.StatusBarKey.Render("Tab") + styles.StatusBarDesc.Render(" labels/milestones") + " │ " +
+		styles.StatusBarKey.Render("j/k") + styles.StatusBarDesc.Render(" navigate") + " │ " +
+		styles.StatusBarKey.Render("y") + styles.StatusBarDesc.Render(copyDesc) + " │ " +
+		styles.StatusBarKey.Render("r") + styles.StatusBarDesc.Render(" refresh")
+	if m.statusMsg != "" {
+		statusContent = styles.SelectedItem.Render(m.statusMsg) + " │ " + statusContent
 	}
-	content.WriteString("\n")
 
-	// Downloadable assets
-	content.WriteString(styles.ActivePanelTitle.Render("Downloads:") + "\n")
+	// Pad to bottom
+	currentLines := topPadding + len(popupLines)
+	for i := currentLines; i < m.height-1; i++ {
+		result.WriteString("\n")
+	}
 
-	visibleLines := popupHeight - 10
-	cursor := 0
+	result.WriteString(styles.StatusBar.Width(m.width).Render(statusContent))
 
-	// Source archives first
-	for i, src := range rel.Assets.Sources {
-		icon := "📦"
-		line := fmt.Sprintf("%s Source code (%s)", icon, src.Format)
+	return result.String()
+}
 
-		if cursor == m.releaseAssetCursor {
-			content.WriteString(styles.SelectedItem.Render("> ") + line + "\n")
-		} else {
-			content.WriteString("  " + line + "\n")
-		}
-		cursor++
+// handleActivityPopup handles keyboard input for the activity popup
+func (m *MainScreen) handleActivityPopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	visibleLines := m.snippetsVisibleLines()
 
-		if i >= visibleLines {
-			break
+	switch msg.String() {
+	case "q", "esc", "escape":
+		m.showActivityPopup = false
+	case "j", "down":
+		if m.activityCursor < len(m.events)-1 {
+			m.activityCursor++
+		}
+	case "k", "up":
+		if m.activityCursor > 0 {
+			m.activityCursor--
+		}
+	case "ctrl+d":
+		m.activityCursor = min(m.activityCursor+visibleLines/2, max(len(m.events)-1, 0))
+	case "ctrl+u":
+		m.activityCursor = max(m.activityCursor-visibleLines/2, 0)
+	case "g":
+		m.activityCursor = 0
+	case "G":
+		m.activityCursor = max(len(m.events)-1, 0)
+	case "y":
+		// Copy the event for a standup note
+		if m.activityCursor < len(m.events) {
+			summary := eventSummary(m.events[m.activityCursor])
+			if err := copyToClipboard(summary); err != nil {
+				m.statusMsg = "Copy failed: " + err.Error()
+			} else {
+				m.statusMsg = "Copied: " + summary
+			}
+		}
+	case "r":
+		if !m.isDemo {
+			m.activityLoading = true
+			return m, m.loadActivity()
 		}
 	}
+	return m, nil
+}
 
-	// Asset links
-	for i, link := range rel.Assets.Links {
-		icon := "📎"
-		switch link.LinkType {
-		case "package":
-			icon = "📦"
-		case "image":
-			icon = "🖼️"
-		case "runbook":
-			icon = "📖"
-		}
+func (m *MainScreen) renderActivityPopup() string {
+	popupWidth := int(float64(m.width) * 0.8)
+	popupHeight := int(float64(m.height) * 0.8)
+
+	if popupWidth < 60 {
+		popupWidth = 60
+	}
+	if popupHeight < 15 {
+		popupHeight = 15
+	}
+	if popupWidth > m.width-4 {
+		popupWidth = m.width - 4
+	}
+	if popupHeight > m.height-4 {
+		popupHeight = m.height - 4
+	}
 
-		line := fmt.Sprintf("%s %s", icon, link.Name)
-		if len(line) > popupWidth-6 {
-			line = line[:popupWidth-7] + "…"
+	visibleLines := m.snippetsVisibleLines()
+
+	var content strings.Builder
+
+	if m.activityErr != "" {
+		content.WriteString(lipgloss.NewStyle().Foreground(styles.ColorRed).Render(m.activityErr))
+	} else if m.activityLoading && len(m.events) == 0 {
+		content.WriteString(styles.DimmedText.Render("Loading activity..."))
+	} else if len(m.events) == 0 {
+		content.WriteString(styles.DimmedText.Render("No recent activity"))
+	} else {
+		startIdx := 0
+		if m.activityCursor >= visibleLines {
+			startIdx = m.activityCursor - visibleLines + 1
 		}
+		endIdx := min(startIdx+visibleLines, len(m.events))
 
-		if cursor == m.releaseAssetCursor {
-			content.WriteString(styles.SelectedItem.Render("> ") + line + "\n")
-		} else {
-			content.WriteString("  " + line + "\n")
+		const agoWidth = 9
+		for i := startIdx; i < endIdx; i++ {
+			e := m.events[i]
+
+			ago := timeAgo(e.CreatedAt)
+			ago += strings.Repeat(" ", max(agoWidth-lipgloss.Width(ago), 0))
+			line := styles.DimmedText.Render(ago) + " " + eventSummary(e)
+			// Pushes show what was pushed
+			if p := e.PushData; p != nil && p.CommitTitle != "" {
+				meta := " " + p.CommitTitle
+				if p.CommitCount > 1 {
+					meta = fmt.Sprintf(" %s (+%d more)", p.CommitTitle, p.CommitCount-1)
+				}
+				line += styles.DimmedText.Render(meta)
+			}
+			line = truncateWidth(line, popupWidth-6)
+
+			if i == m.activityCursor {
+				line = styles.SelectedItem.Render("> ") + line
+			} else {
+				line = "  " + line
+			}
+			content.WriteString(line + "\n")
 		}
-		cursor++
 
-		if i+len(rel.Assets.Sources) >= visibleLines {
-			break
+		if len(m.events) > visibleLines {
+			content.WriteString(styles.DimmedText.Render(fmt.Sprintf("\n[%d/%d]", m.activityCursor+1, len(m.events))))
 		}
 	}
 
-	totalAssets := len(rel.Assets.Sources) + len(rel.Assets.Links)
-	if totalAssets == 0 {
-		content.WriteString(styles.DimmedText.Render("  No downloadable assets") + "\n")
-	} else {
-		content.WriteString(styles.DimmedText.Render(fmt.Sprintf("\n[%d/%d]", m.releaseAssetCursor+1, totalAssets)) + "\n")
+	// Build popup panel
+	title := "Activity"
+	if m.selectedProject != nil {
+		title += " - " + m.selectedProject.Name
 	}
-
-	// Show selected URL
-	selectedURL := m.getSelectedReleaseAssetURL()
-	if selectedURL != "" {
-		content.WriteString("\n" + styles.DimmedText.Render("URL: "+truncateString(selectedURL, popupWidth-8)) + "\n")
+	if m.activityLoading {
+		title += " (loading...)"
 	}
-
-	// Build popup panel
-	popup := components.SimpleBorderedPanel(
-		fmt.Sprintf("Release: %s", rel.TagName),
-		content.String(),
-		popupWidth,
-		popupHeight,
-		true,
-	)
+	popup := components.SimpleBorderedPanel(title, content.String(), popupWidth, popupHeight, true)
 
 	// Center the popup
 	popupLines := strings.Split(popup, "\n")
-	topPadding := (m.height - len(popupLines)) / 2
-	leftPadding := (m.width - popupWidth) / 2
-	if topPadding < 0 {
-		topPadding = 0
-	}
-	if leftPadding < 0 {
-		leftPadding = 0
-	}
+	topPadding := max((m.height-len(popupLines))/2, 0)
+	leftPadding := max((m.width-popupWidth)/2, 0)
 
 	var result strings.Builder
 	for i := 0; i < topPadding; i++ {
@@ -3670,9 +12597,11 @@ func (m *MainScreen) renderReleasePopup() string {
 	// Status bar at bottom
 	statusContent := styles.StatusBarKey.Render("Esc") + styles.StatusBarDesc.Render(" close") + " │ " +
 		styles.StatusBarKey.Render("j/k") + styles.StatusBarDesc.Render(" navigate") + " │ " +
-		styles.StatusBarKey.Render("y/Enter") + styles.StatusBarDesc.Render(" copy URL") + " │ " +
-		styles.StatusBarKey.Render("d") + styles.StatusBarDesc.Render(" download") + " │ " +
-		styles.StatusBarKey.Render("o") + styles.StatusBarDesc.Render(" copy release URL")
+		styles.StatusBarKey.Render("y") + styles.StatusBarDesc.Render(" copy event") + " │ " +
+		styles.StatusBarKey.Render("r") + styles.StatusBarDesc.Render(" refresh")
+	if m.statusMsg != "" {
+		statusContent = styles.SelectedItem.Render(m.statusMsg) + " │ " + statusContent
+	}
 
 	// Pad to bottom
 	currentLines := topPadding + len(popupLines)
@@ -3685,189 +12614,178 @@ func (m *MainScreen) renderReleasePopup() string {
 	return result.String()
 }
 
-// handleFolderBrowser handles keyboard input for the folder browser popup
-func (m *MainScreen) handleFolderBrowser(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "esc", "escape", "q":
-		m.showFolderBrowser = false
-		m.downloadURL = ""
-		m.downloadFilename = ""
-		return m, nil
-
-	case "j", "down":
-		if m.folderBrowserCursor < len(m.folderBrowserEntries)-1 {
-			m.folderBrowserCursor++
-			// Adjust scroll - match the visibleLines calculation in renderFolderBrowser
-			popupHeight := min(m.height-4, 25)
-			visibleLines := popupHeight - 12
-			if visibleLines < 3 {
-				visibleLines = 3
+// handleCodeSearch handles keyboard input for the code search popup: the
+// query while typing, then the list of matches
+func (m *MainScreen) handleCodeSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.codeSearchTyping {
+		switch msg.String() {
+		case "esc", "escape":
+			// Back to the matches, or out when there are none
+			m.codeSearchTyping = false
+			if m.codeSearchQuery == "" {
+				m.showCodeSearch = false
 			}
-			if m.folderBrowserCursor >= m.folderBrowserScroll+visibleLines {
-				m.folderBrowserScroll = m.folderBrowserCursor - visibleLines + 1
+			return m, nil
+		case "enter":
+			query := strings.TrimSpace(m.codeSearchInput.Value())
+			if len([]rune(query)) < config.SearchMinQueryLength {
+				m.statusMsg = fmt.Sprintf("Type at least %d characters", config.SearchMinQueryLength)
+				return m, nil
+			}
+			m.codeSearchTyping = false
+			m.codeSearchQuery = query
+			m.codeSearchErr = ""
+			m.codeSearchCursor = 0
+			if m.isDemo {
+				m.codeSearchResults = demoSearchBlobs(query)
+				return m, nil
 			}
+			m.codeSearchResults = nil
+			m.codeSearchLoading = true
+			return m, m.loadCodeSearch(query)
 		}
+		var cmd tea.Cmd
+		m.codeSearchInput, cmd = m.codeSearchInput.Update(msg)
+		return m, cmd
+	}
 
+	visibleLines := m.codeSearchVisibleLines()
+	switch msg.String() {
+	case "q", "esc", "escape":
+		m.showCodeSearch = false
+	case "j", "down":
+		if m.codeSearchCursor < len(m.codeSearchResults)-1 {
+			m.codeSearchCursor++
+		}
 	case "k", "up":
-		if m.folderBrowserCursor > 0 {
-			m.folderBrowserCursor--
-			if m.folderBrowserCursor < m.folderBrowserScroll {
-				m.folderBrowserScroll = m.folderBrowserCursor
-			}
+		if m.codeSearchCursor > 0 {
+			m.codeSearchCursor--
 		}
-
+	case "ctrl+d":
+		m.codeSearchCursor = min(m.codeSearchCursor+visibleLines/2, max(len(m.codeSearchResults)-1, 0))
+	case "ctrl+u":
+		m.codeSearchCursor = max(m.codeSearchCursor-visibleLines/2, 0)
 	case "g":
-		m.folderBrowserCursor = 0
-		m.folderBrowserScroll = 0
-
+		m.codeSearchCursor = 0
 	case "G":
-		if len(m.folderBrowserEntries) > 0 {
-			m.folderBrowserCursor = len(m.folderBrowserEntries) - 1
-			popupHeight := min(m.height-4, 25)
-			visibleLines := popupHeight - 12
-			if visibleLines < 3 {
-				visibleLines = 3
-			}
-			if m.folderBrowserCursor >= visibleLines {
-				m.folderBrowserScroll = m.folderBrowserCursor - visibleLines + 1
-			}
-		}
-
-	case "l", "enter":
-		// Enter selected directory
-		if m.folderBrowserCursor < len(m.folderBrowserEntries) {
-			selectedDir := m.folderBrowserEntries[m.folderBrowserCursor]
-			newPath := filepath.Join(m.folderBrowserPath, selectedDir)
-			// Verify it's accessible
-			if _, err := os.ReadDir(newPath); err == nil {
-				m.folderBrowserPath = newPath
-				m.folderBrowserCursor = 0
-				m.folderBrowserScroll = 0
-				m.loadFolderEntries()
-			}
-		}
-
-	case "h", "backspace":
-		// Go up one directory
-		parent := filepath.Dir(m.folderBrowserPath)
-		if parent != m.folderBrowserPath {
-			m.folderBrowserPath = parent
-			m.folderBrowserCursor = 0
-			m.folderBrowserScroll = 0
-			m.loadFolderEntries()
+		m.codeSearchCursor = max(len(m.codeSearchResults)-1, 0)
+	case "/":
+		// Edit the query
+		m.codeSearchTyping = true
+		return m, m.codeSearchInput.Focus()
+	case "r":
+		if !m.isDemo && m.codeSearchQuery != "" {
+			m.codeSearchLoading = true
+			return m, m.loadCodeSearch(m.codeSearchQuery)
 		}
-
-	case "~":
-		// Go to home directory
-		if home, err := os.UserHomeDir(); err == nil {
-			m.folderBrowserPath = home
-			m.folderBrowserCursor = 0
-			m.folderBrowserScroll = 0
-			m.loadFolderEntries()
+	case "enter":
+		// Open the file at the matching line
+		if m.codeSearchCursor >= len(m.codeSearchResults) {
+			return m, nil
 		}
-
-	case "d", " ":
-		// Download to current directory
-		if m.downloadURL != "" && m.downloadFilename != "" {
-			destPath := filepath.Join(m.folderBrowserPath, m.downloadFilename)
-			m.showFolderBrowser = false
-			m.loading = true
-			m.loadingMsg = "Downloading " + m.downloadFilename + "..."
-
-			// Start download in background
-			url := m.downloadURL
-			filename := m.downloadFilename
-			client := m.client
-
-			return m, func() tea.Msg {
-				bytes, err := client.DownloadFile(url, destPath)
-				return downloadCompleteMsg{
-					filename: filename,
-					bytes:    bytes,
-					err:      err,
-				}
-			}
+		blob := m.codeSearchResults[m.codeSearchCursor]
+		line, _ := blobMatch(blob, m.codeSearchQuery)
+		m.showCodeSearch = false
+		m.focusedPanel = PanelContent
+		if m.isDemo {
+			return m.Update(fileContentMsg{content: blob.Data, path: blobPath(blob), line: line})
 		}
+		m.loading = true
+		m.loadingMsg = "Loading file..."
+		cmd := m.loadFile(fmt.Sprintf("%s#L%d", blobPath(blob), line))
+		m.retryCmd = cmd
+		return m, cmd
 	}
-
 	return m, nil
 }
 
-// renderFolderBrowser renders the folder browser popup
-func (m *MainScreen) renderFolderBrowser() string {
-	popupWidth := min(m.width-4, 70)
-	popupHeight := min(m.height-4, 25)
+// codeSearchVisibleLines returns how many matches fit below the query line
+func (m *MainScreen) codeSearchVisibleLines() int {
+	return max(m.snippetsVisibleLines()-2, 3)
+}
 
-	var content strings.Builder
+func (m *MainScreen) renderCodeSearch() string {
+	popupWidth := int(float64(m.width) * 0.8)
+	popupHeight := int(float64(m.height) * 0.8)
 
-	// Current path
-	content.WriteString(styles.ActivePanelTitle.Render("Location:") + "\n")
-	displayPath := m.folderBrowserPath
-	if len(displayPath) > popupWidth-6 {
-		displayPath = "..." + displayPath[len(displayPath)-popupWidth+9:]
+	if popupWidth < 60 {
+		popupWidth = 60
+	}
+	if popupHeight < 15 {
+		popupHeight = 15
+	}
+	if popupWidth > m.width-4 {
+		popupWidth = m.width - 4
+	}
+	if popupHeight > m.height-4 {
+		popupHeight = m.height - 4
 	}
-	content.WriteString(styles.DimmedText.Render(displayPath) + "\n\n")
-
-	// File to download
-	content.WriteString(styles.ActivePanelTitle.Render("File:") + " " + m.downloadFilename + "\n\n")
 
-	// Directory listing
-	content.WriteString(styles.ActivePanelTitle.Render("Folders:") + "\n")
+	visibleLines := m.codeSearchVisibleLines()
 
-	visibleLines := popupHeight - 12
-	if visibleLines < 3 {
-		visibleLines = 3
-	}
+	var content strings.Builder
 
-	endIdx := m.folderBrowserScroll + visibleLines
-	if endIdx > len(m.folderBrowserEntries) {
-		endIdx = len(m.folderBrowserEntries)
+	// Query line: the input while typing, the searched query afterwards
+	if m.codeSearchTyping {
+		content.WriteString(m.codeSearchInput.View())
+	} else {
+		content.WriteString(styles.DimmedText.Render("/" + m.codeSearchQuery))
 	}
+	content.WriteString("\n\n")
 
-	if len(m.folderBrowserEntries) == 0 {
-		content.WriteString(styles.DimmedText.Render("  (empty directory)") + "\n")
+	if m.codeSearchErr != "" {
+		content.WriteString(lipgloss.NewStyle().Foreground(styles.ColorRed).Render(m.codeSearchErr))
+	} else if m.codeSearchLoading && len(m.codeSearchResults) == 0 {
+		content.WriteString(styles.DimmedText.Render("Searching..."))
+	} else if m.codeSearchQuery == "" {
+		content.WriteString(styles.DimmedText.Render(fmt.Sprintf("Type at least %d characters and press Enter", config.SearchMinQueryLength)))
+	} else if len(m.codeSearchResults) == 0 {
+		content.WriteString(styles.DimmedText.Render("No matches"))
 	} else {
-		for i := m.folderBrowserScroll; i < endIdx; i++ {
-			entry := m.folderBrowserEntries[i]
-			icon := "📁"
+		startIdx := 0
+		if m.codeSearchCursor >= visibleLines {
+			startIdx = m.codeSearchCursor - visibleLines + 1
+		}
+		endIdx := min(startIdx+visibleLines, len(m.codeSearchResults))
 
-			line := fmt.Sprintf("%s %s", icon, entry)
-			if len(line) > popupWidth-6 {
-				line = line[:popupWidth-7] + "…"
+		for i := startIdx; i < endIdx; i++ {
+			blob := m.codeSearchResults[i]
+			line, text := blobMatch(blob, m.codeSearchQuery)
+
+			location := fmt.Sprintf("%s:%d", blobPath(blob), line)
+			row := truncateWidth(location+"  "+text, popupWidth-6)
+			// Dim the code after the location, which may have been cut off
+			if len(row) > len(location) && strings.HasPrefix(row, location) {
+				row = location + styles.DimmedText.Render(row[len(location):])
 			}
 
-			if i == m.folderBrowserCursor {
-				content.WriteString(styles.SelectedItem.Render("> ") + line + "\n")
+			if i == m.codeSearchCursor {
+				row = styles.SelectedItem.Render("> ") + row
 			} else {
-				content.WriteString("  " + line + "\n")
+				row = "  " + row
 			}
+			content.WriteString(row + "\n")
 		}
-	}
 
-	// Scroll indicator
-	if len(m.folderBrowserEntries) > visibleLines {
-		content.WriteString(styles.DimmedText.Render(fmt.Sprintf("\n[%d/%d]", m.folderBrowserCursor+1, len(m.folderBrowserEntries))) + "\n")
+		if len(m.codeSearchResults) > visibleLines {
+			content.WriteString(styles.DimmedText.Render(fmt.Sprintf("\n[%d/%d]", m.codeSearchCursor+1, len(m.codeSearchResults))))
+		}
 	}
 
 	// Build popup panel
-	popup := components.SimpleBorderedPanel(
-		"Select Download Location",
-		content.String(),
-		popupWidth,
-		popupHeight,
-		true,
-	)
+	title := "Code search"
+	if m.selectedProject != nil {
+		title += " - " + m.selectedProject.Name + "@" + m.currentRef()
+	}
+	if m.codeSearchLoading {
+		title += " (loading...)"
+	}
+	popup := components.SimpleBorderedPanel(title, content.String(), popupWidth, popupHeight, true)
 
 	// Center the popup
 	popupLines := strings.Split(popup, "\n")
-	topPadding := (m.height - len(popupLines)) / 2
-	leftPadding := (m.width - popupWidth) / 2
-	if topPadding < 0 {
-		topPadding = 0
-	}
-	if leftPadding < 0 {
-		leftPadding = 0
-	}
+	topPadding := max((m.height-len(popupLines))/2, 0)
+	leftPadding := max((m.width-popupWidth)/2, 0)
 
 	var result strings.Builder
 	for i := 0; i < topPadding; i++ {
@@ -3878,20 +12796,27 @@ func (m *MainScreen) renderFolderBrowser() string {
 	}
 
 	// Status bar at bottom
-	folderStatusContent := styles.StatusBarKey.Render("Esc") + styles.StatusBarDesc.Render(" cancel") + " │ " +
-		styles.StatusBarKey.Render("j/k") + styles.StatusBarDesc.Render(" navigate") + " │ " +
-		styles.StatusBarKey.Render("l/Enter") + styles.StatusBarDesc.Render(" open") + " │ " +
-		styles.StatusBarKey.Render("h/Bksp") + styles.StatusBarDesc.Render(" up") + " │ " +
-		styles.StatusBarKey.Render("~") + styles.StatusBarDesc.Render(" home") + " │ " +
-		styles.StatusBarKey.Render("d/Space") + styles.StatusBarDesc.Render(" download here")
+	var statusContent string
+	if m.codeSearchTyping {
+		statusContent = styles.StatusBarKey.Render("Enter") + styles.StatusBarDesc.Render(" search") + " │ " +
+			styles.StatusBarKey.Render("Esc") + styles.StatusBarDesc.Render(" cancel")
+	} else {
+		statusContent = styles.StatusBarKey.Render("Esc") + styles.StatusBarDesc.Render(" close") + " │ " +
+			styles.StatusBarKey.Render("j/k") + styles.StatusBarDesc.Render(" navigate") + " │ " +
+			styles.StatusBarKey.Render("Enter") + styles.StatusBarDesc.Render(" open at line") + " │ " +
+			styles.StatusBarKey.Render("/") + styles.StatusBarDesc.Render(" new search")
+	}
+	if m.statusMsg != "" {
+		statusContent = styles.SelectedItem.Render(m.statusMsg) + " │ " + statusContent
+	}
 
 	// Pad to bottom
-	folderCurrentLines := topPadding + len(popupLines)
-	for i := folderCurrentLines; i < m.height-1; i++ {
+	currentLines := topPadding + len(popupLines)
+	for i := currentLines; i < m.height-1; i++ {
 		result.WriteString("\n")
 	}
 
-	result.WriteString(styles.StatusBar.Width(m.width).Render(folderStatusContent))
+	result.WriteString(styles.StatusBar.Width(m.width).Render(statusContent))
 
 	return result.String()
 }
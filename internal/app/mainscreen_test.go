@@ -1,10 +1,34 @@
 package app
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
 
+	"github.com/EspenTeigen/lazylab/internal/config"
 	"github.com/EspenTeigen/lazylab/internal/gitlab"
+	"github.com/EspenTeigen/lazylab/internal/keymap"
+	"github.com/EspenTeigen/lazylab/internal/ui/styles"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 func TestRebuildNavTree(t *testing.T) {
@@ -33,6 +57,46 @@ func TestRebuildNavTree(t *testing.T) {
 	}
 }
 
+func TestRebuildNavTree_Favorites(t *testing.T) {
+	m := &MainScreen{
+		groups: []gitlab.Group{
+			{ID: 1, Name: "Group A", FullPath: "group-a"},
+		},
+		expandedGroups: map[int]bool{favoritesGroupID: true},
+		groupProjects: map[int][]gitlab.Project{
+			1: {{ID: 10, Name: "api", PathWithNamespace: "group-a/api"}},
+		},
+		favorites: []config.Favorite{
+			{ID: 10, Path: "group-a/api"},
+			{ID: 20, Path: "group-b/deep/web"},
+		},
+	}
+
+	m.rebuildNavTree()
+
+	// Favorites group + 2 favorites + Group A (collapsed)
+	if len(m.treeNodes) != 4 {
+		t.Fatalf("expected 4 nodes, got %d", len(m.treeNodes))
+	}
+	if m.treeNodes[0].ID != favoritesGroupID {
+		t.Errorf("expected favorites group first, got %q", m.treeNodes[0].Name)
+	}
+
+	cached := m.treeNodes[1]
+	if !cached.Favorite || cached.Project == nil || cached.Project.ID != 10 {
+		t.Errorf("expected cached favorite to resolve its project, got %+v", cached)
+	}
+
+	uncached := m.treeNodes[2]
+	if uncached.Project != nil || uncached.FullPath != "group-b/deep/web" || uncached.Name != "web" {
+		t.Errorf("expected unloaded favorite to keep its path, got %+v", uncached)
+	}
+
+	if m.treeNodes[3].Name != "Group A" {
+		t.Errorf("expected real groups after favorites, got %q", m.treeNodes[3].Name)
+	}
+}
+
 func TestRebuildNavTree_WithExpandedGroup(t *testing.T) {
 	m := &MainScreen{
 		groups: []gitlab.Group{
@@ -71,6 +135,116 @@ func TestRebuildNavTree_WithExpandedGroup(t *testing.T) {
 	}
 }
 
+func TestNavigatorFilter(t *testing.T) {
+	m := &MainScreen{
+		keymap:       keymap.DefaultKeyMap(),
+		focusedPanel: PanelNavigator,
+		groups: []gitlab.Group{
+			{ID: 1, Name: "Platform", FullPath: "platform"},
+			{ID: 2, Name: "Web", FullPath: "web"},
+		},
+		expandedGroups: map[int]bool{2: true},
+		groupProjects: map[int][]gitlab.Project{
+			1: {{ID: 10, Name: "api-gateway"}, {ID: 11, Name: "billing"}},
+			2: {{ID: 20, Name: "storefront"}},
+		},
+	}
+	m.rebuildNavTree()
+	m.selectedNodeIdx = 2 // storefront
+
+	names := func() []string {
+		var names []string
+		for _, n := range m.treeNodes {
+			names = append(names, n.Name)
+		}
+		return names
+	}
+
+	// Matches in a collapsed group show with their group for context, ignoring case
+	pressKeys(m, "/", "G", "a", "t")
+	if got := names(); !slices.Equal(got, []string{"Platform", "api-gateway"}) {
+		t.Fatalf("expected matching project under its group, got %v", got)
+	}
+	if m.selectedNodeIdx != 1 {
+		t.Errorf("expected the cursor on the first match, got %d", m.selectedNodeIdx)
+	}
+
+	// Enter keeps the filter; Esc then clears it, opening the match's group
+	// with the cursor on the same node
+	pressKeys(m, "enter")
+	if m.navFilterActive || m.navFilter != "Gat" {
+		t.Fatalf("expected filter kept after Enter, active %v filter %q", m.navFilterActive, m.navFilter)
+	}
+	pressKeys(m, "esc")
+	if m.navFilter != "" || len(m.treeNodes) != 5 || !m.expandedGroups[1] {
+		t.Fatalf("expected Esc to restore the tree with Platform expanded, got %v", names())
+	}
+	if n := m.treeNodes[m.selectedNodeIdx]; n.ID != 10 {
+		t.Errorf("expected the cursor to stay on api-gateway, got %q", n.Name)
+	}
+
+	// No matches leaves an empty tree and a valid cursor
+	pressKeys(m, "/", "x", "y", "z")
+	if len(m.treeNodes) != 0 || m.selectedNodeIdx != 0 {
+		t.Errorf("expected no nodes, got %v at %d", names(), m.selectedNodeIdx)
+	}
+}
+
+func TestNavigatorFoldAll(t *testing.T) {
+	m := &MainScreen{
+		keymap:       keymap.DefaultKeyMap(),
+		focusedPanel: PanelNavigator,
+		groups: []gitlab.Group{
+			{ID: 1, Name: "Platform", FullPath: "platform"},
+			{ID: 2, Name: "Web", FullPath: "web"},
+			{ID: 3, Name: "Data", FullPath: "data"},
+		},
+		expandedGroups: map[int]bool{2: true},
+		groupProjects: map[int][]gitlab.Project{
+			1: {{ID: 10, Name: "api-gateway"}, {ID: 11, Name: "billing"}},
+			2: {{ID: 20, Name: "storefront"}},
+		},
+	}
+	m.rebuildNavTree()
+	m.selectedNodeIdx = 2 // storefront
+
+	// zc leaves the cursor on the collapsed group of the selected project
+	pressKeys(m, "z", "c")
+	if len(m.treeNodes) != 3 || m.treeNodes[m.selectedNodeIdx].Name != "Web" {
+		t.Fatalf("expected all groups collapsed with Web selected, got %d nodes at %d", len(m.treeNodes), m.selectedNodeIdx)
+	}
+
+	// zo expands the loaded groups and asks before fetching the rest
+	pressKeys(m, "z", "o")
+	if len(m.treeNodes) != 6 || m.treeNodes[m.selectedNodeIdx].Name != "Web" {
+		t.Fatalf("expected loaded groups expanded with Web selected, got %d nodes at %d", len(m.treeNodes), m.selectedNodeIdx)
+	}
+	if !m.confirm.Active || m.expandedGroups[3] {
+		t.Fatalf("expected a prompt before loading Data, active %v", m.confirm.Active)
+	}
+	answer := func(k string) tea.Cmd {
+		_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		_, next := m.Update(cmd())
+		return next
+	}
+	answer("n")
+	if m.expandedGroups[3] || m.loading {
+		t.Error("declining should leave unloaded groups alone")
+	}
+
+	m.client = &mockAPI{}
+	pressKeys(m, "z", "o")
+	if cmd := answer("y"); cmd == nil || !m.expandedGroups[3] || !m.loading {
+		t.Errorf("expected Data expanded and its projects loading, loading %v", m.loading)
+	}
+
+	// za collapses again since groups are open
+	pressKeys(m, "z", "a")
+	if len(m.treeNodes) != 3 {
+		t.Errorf("za should collapse all groups, got %d nodes", len(m.treeNodes))
+	}
+}
+
 func TestRebuildNavTree_CollapseGroup(t *testing.T) {
 	m := &MainScreen{
 		groups: []gitlab.Group{
@@ -142,6 +316,46 @@ func TestStripANSI(t *testing.T) {
 	}
 }
 
+func TestAnsi256To16(t *testing.T) {
+	tests := []struct {
+		color, expected int
+	}{
+		{1, 1},    // basic colors are kept
+		{12, 12},  // bright ones too
+		{196, 9},  // pure red in the cube
+		{46, 10},  // pure green
+		{21, 4},   // pure blue is closer to xterm blue than bright blue
+		{226, 11}, // yellow
+		{16, 0},   // cube black
+		{231, 15}, // cube white
+		{232, 0},  // darkest gray
+		{244, 8},  // mid gray
+		{255, 7},  // lightest gray
+		{300, 7},  // out of range
+	}
+	for _, tt := range tests {
+		if got := ansi256To16(tt.color); got != tt.expected {
+			t.Errorf("ansi256To16(%d) = %d, expected %d", tt.color, got, tt.expected)
+		}
+	}
+}
+
+func TestDownsampleLogColors(t *testing.T) {
+	log := "\x1b[1;38;5;196mERROR\x1b[0m on \x1b[48;5;46mgreen\x1b[0m \x1b[38;2;0;0;0mtrue\x1b[m \x1b[32mok\x1b[0m"
+	tests := []struct {
+		mode, expected string
+	}{
+		{config.LogColor256, log},
+		{config.LogColor16, "\x1b[1;91mERROR\x1b[0m on \x1b[102mgreen\x1b[0m \x1b[30mtrue\x1b[m \x1b[32mok\x1b[0m"},
+		{config.LogColorNone, "ERROR on green true ok"},
+	}
+	for _, tt := range tests {
+		if got := downsampleLogColors(log, tt.mode); got != tt.expected {
+			t.Errorf("mode %s: got %q, expected %q", tt.mode, got, tt.expected)
+		}
+	}
+}
+
 func TestWrapText(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -172,6 +386,84 @@ func TestWrapText_ZeroWidth(t *testing.T) {
 	}
 }
 
+func TestWrapLogLines_ColoredLine(t *testing.T) {
+	// A red error message wider than the viewport, then uncolored text
+	line := "\x1b[31mERROR: something went wrong here\x1b[0m then the job carried on"
+	rows, _ := wrapLogLines([]string{line}, 10)
+
+	var plain []string
+	for _, row := range rows {
+		if w := lipgloss.Width(row); w > 10 {
+			t.Errorf("row %q is %d cells wide, want at most 10", row, w)
+		}
+		// No row may carry a cut-off escape sequence
+		if strings.Count(row, "\x1b[") != len(sgrRegex.FindAllString(row, -1)) {
+			t.Errorf("row %q splits an escape sequence", row)
+		}
+		plain = append(plain, stripANSI(row))
+	}
+	if got := strings.Join(plain, ""); got != stripANSI(line) {
+		t.Errorf("wrapping changed the text: %q", got)
+	}
+	// Rows continuing the error stay red, and the color doesn't leak past them
+	if !strings.HasPrefix(rows[1], "\x1b[31m") || !strings.HasSuffix(rows[0], "\x1b[0m") {
+		t.Errorf("a wrapped row should reopen the color: %q", rows)
+	}
+	if last := rows[len(rows)-1]; strings.Contains(last, "\x1b[") {
+		t.Errorf("the uncolored tail shouldn't be red: %q", last)
+	}
+}
+
+func TestWrapLogLines_SelectionMapping(t *testing.T) {
+	lines := []string{"short", "abcdefghijklmnopqrstuvwxyz", "", "end"}
+	rows, starts := wrapLogLines(lines, 10)
+
+	if len(rows) != 6 {
+		t.Fatalf("expected 6 display rows, got %d: %q", len(rows), rows)
+	}
+	if !slices.Equal(starts, []int{0, 1, 4, 5}) {
+		t.Errorf("unexpected row starts: %v", starts)
+	}
+
+	// Every display row of a wrapped line maps back to that line
+	want := []int{0, 1, 1, 1, 2, 3}
+	for row, line := range want {
+		if got := logicalLineAt(starts, row); got != line {
+			t.Errorf("logicalLineAt(%d) = %d, want %d", row, got, line)
+		}
+	}
+
+	// A selection over display rows 2..4 yanks the original logical lines
+	selStart, selEnd := logicalLineAt(starts, 2), logicalLineAt(starts, 4)
+	selected := strings.Join(lines[selStart:selEnd+1], "\n")
+	if selected != "abcdefghijklmnopqrstuvwxyz\n" {
+		t.Errorf("selection copied %q", selected)
+	}
+}
+
+func TestFindLogMatches(t *testing.T) {
+	log := "Running tests\n\x1b[31mERROR\x1b[0m: build failed\nok\nerror: exit code 1"
+
+	matches := findLogMatches(log, "error")
+	if !slices.Equal(matches, []int{1, 3}) {
+		t.Errorf("expected matches on lines 1 and 3, got %v", matches)
+	}
+
+	// ANSI codes must not break matches that span a color change
+	if matches := findLogMatches(log, "error: build"); !slices.Equal(matches, []int{1}) {
+		t.Errorf("expected ANSI-insensitive match on line 1, got %v", matches)
+	}
+
+	// Escape sequences themselves are not searchable
+	if matches := findLogMatches(log, "[31m"); len(matches) != 0 {
+		t.Errorf("expected no matches inside escape codes, got %v", matches)
+	}
+
+	if matches := findLogMatches(log, ""); matches != nil {
+		t.Errorf("expected no matches for empty query, got %v", matches)
+	}
+}
+
 func TestRenderMarkdown(t *testing.T) {
 	// Test empty content
 	result := renderMarkdown("", 80)
@@ -252,3 +544,3515 @@ func TestContentTabNames(t *testing.T) {
 		}
 	}
 }
+
+func TestParseTabs(t *testing.T) {
+	tests := []struct {
+		names   []string
+		want    []ContentTab
+		wantErr bool
+	}{
+		{nil, nil, false},
+		{[]string{"files", "pipelines", "mrs"}, []ContentTab{TabFiles, TabPipelines, TabMRs}, false},
+		{[]string{"Releases", "Files"}, []ContentTab{TabReleases, TabFiles}, false},
+		{[]string{"pipelines"}, []ContentTab{TabFiles, TabPipelines}, false},
+		{[]string{"files", "issues"}, nil, true},
+		{[]string{"mrs", "MRs"}, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := parseTabs(tt.names)
+		if (err != nil) != tt.wantErr || !slices.Equal(got, tt.want) {
+			t.Errorf("parseTabs(%v) = %v, %v; want %v, error %v", tt.names, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestTabs_CustomOrder(t *testing.T) {
+	m := &MainScreen{
+		keymap:          keymap.DefaultKeyMap(),
+		isDemo:          true,
+		selectedProject: &gitlab.Project{ID: 1},
+		focusedPanel:    PanelContent,
+		contentTab:      TabFiles,
+		tabs:            []ContentTab{TabFiles, TabPipelines, TabMRs},
+		releases:        []gitlab.Release{{TagName: "v1.0.0"}},
+	}
+
+	var visited []ContentTab
+	for range 3 {
+		m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("l")})
+		visited = append(visited, m.contentTab)
+	}
+	if want := []ContentTab{TabPipelines, TabMRs, TabMRs}; !slices.Equal(visited, want) {
+		t.Errorf("l visited %v, want %v", visited, want)
+	}
+	m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	if m.contentTab != TabPipelines {
+		t.Errorf("h should go back to pipelines, got %v", m.contentTab)
+	}
+
+	header := m.renderListSection(80, 20)
+	if strings.Contains(header, "Releases") {
+		t.Error("hidden releases tab shouldn't be rendered")
+	}
+	if p, r := strings.Index(header, "Pipelines"), strings.Index(header, "MRs"); p < 0 || r < p {
+		t.Errorf("tabs should render as files, pipelines, mrs: %q", header)
+	}
+
+	// Hidden tabs, e.g. from a link, fall back to files
+	m.switchTab(TabReleases)
+	if m.contentTab != TabFiles || m.getContentCount() != 0 {
+		t.Errorf("switching to a hidden tab should show files, got %v", m.contentTab)
+	}
+}
+
+func TestProjectCountsBadge(t *testing.T) {
+	tests := []struct {
+		counts   gitlab.ProjectCounts
+		expected string
+	}{
+		{gitlab.ProjectCounts{}, ""},
+		{gitlab.ProjectCounts{OpenMergeRequests: 3, OpenIssues: 2}, "!3 ⊙2"},
+		{gitlab.ProjectCounts{OpenMergeRequests: 1}, "!1"},
+		{gitlab.ProjectCounts{OpenIssues: 4}, "⊙4"},
+	}
+
+	for _, tt := range tests {
+		result := projectCountsBadge(tt.counts)
+		if result != tt.expected {
+			t.Errorf("projectCountsBadge(%+v) = %q, expected %q", tt.counts, result, tt.expected)
+		}
+	}
+}
+
+func TestMRProjectPath(t *testing.T) {
+	tests := []struct {
+		mr       gitlab.MergeRequest
+		expected string
+	}{
+		{gitlab.MergeRequest{References: gitlab.References{Full: "group/sub/project!12"}}, "group/sub/project"},
+		{gitlab.MergeRequest{ProjectID: 42}, "#42"},
+	}
+
+	for _, tt := range tests {
+		result := mrProjectPath(tt.mr)
+		if result != tt.expected {
+			t.Errorf("mrProjectPath(%q) = %q, expected %q", tt.mr.References.Full, result, tt.expected)
+		}
+	}
+}
+
+func TestVisiblePipelines(t *testing.T) {
+	now := time.Now()
+	m := &MainScreen{
+		pipelines: []gitlab.Pipeline{
+			{ID: 1, Status: "success", CreatedAt: now.Add(-3 * time.Hour)},
+			{ID: 2, Status: "failed", CreatedAt: now.Add(-2 * time.Hour)},
+			{ID: 3, Status: "running", CreatedAt: now.Add(-1 * time.Hour)},
+			{ID: 4, Status: "failed", CreatedAt: now},
+		},
+	}
+
+	ids := func(pipelines []gitlab.Pipeline) []int {
+		var result []int
+		for _, p := range pipelines {
+			result = append(result, p.ID)
+		}
+		return result
+	}
+
+	// Default: newest first
+	if got := ids(m.visiblePipelines()); !slices.Equal(got, []int{4, 3, 2, 1}) {
+		t.Errorf("expected created order [4 3 2 1], got %v", got)
+	}
+
+	// Status sort: failed, running, success
+	m.pipelineSort = pipelineSortStatus
+	if got := ids(m.visiblePipelines()); !slices.Equal(got, []int{2, 4, 3, 1}) {
+		t.Errorf("expected status order [2 4 3 1], got %v", got)
+	}
+
+	// Filter keeps underlying data intact
+	m.pipelineSort = pipelineSortCreated
+	m.pipelineFilter = "failed"
+	if got := ids(m.visiblePipelines()); !slices.Equal(got, []int{4, 2}) {
+		t.Errorf("expected filtered [4 2], got %v", got)
+	}
+	if len(m.pipelines) != 4 {
+		t.Errorf("expected underlying pipelines untouched, got %d", len(m.pipelines))
+	}
+	if name := m.pipelinesTabName(); name != "Pipelines (failed)" {
+		t.Errorf("expected tab name 'Pipelines (failed)', got %q", name)
+	}
+
+	// Selection is restored by ID and clamped when the set shrinks
+	m.pipelineFilter = ""
+	m.selectedContent = 2 // pipeline 2
+	id := m.selectedPipelineID()
+	m.pipelineFilter = "failed"
+	m.restorePipelineSelection(id)
+	if m.selectedContent != 1 {
+		t.Errorf("expected selection to follow pipeline 2 to index 1, got %d", m.selectedContent)
+	}
+	m.pipelineFilter = "running"
+	m.restorePipelineSelection(id)
+	if m.selectedContent != 0 {
+		t.Errorf("expected selection clamped to 0, got %d", m.selectedContent)
+	}
+}
+
+func TestVisibleMRs(t *testing.T) {
+	m := &MainScreen{
+		mergeRequests: []gitlab.MergeRequest{
+			{IID: 1, Title: "Add login page", Author: gitlab.User{Username: "alice"}, Labels: []string{"frontend"}},
+			{IID: 2, Title: "Fix crash", Author: gitlab.User{Username: "bob"}, Labels: []string{"bug"}, Draft: true},
+			{IID: 3, Title: "Refactor API", Author: gitlab.User{Username: "carol"}, Labels: []string{"backend", "Bug"}},
+		},
+	}
+
+	iids := func(mrs []gitlab.MergeRequest) []int {
+		var result []int
+		for _, mr := range mrs {
+			result = append(result, mr.IID)
+		}
+		return result
+	}
+
+	tests := []struct {
+		filter     string
+		hideDrafts bool
+		expected   []int
+	}{
+		{"", false, []int{1, 2, 3}},
+		{"login", false, []int{1}},
+		{"BOB", false, []int{2}},
+		{"bug", false, []int{2, 3}},
+		{"bug", true, []int{3}},
+		{"", true, []int{1, 3}},
+		{"nomatch", false, nil},
+	}
+
+	for _, tt := range tests {
+		m.mrFilter = tt.filter
+		m.mrHideDrafts = tt.hideDrafts
+		if got := iids(m.visibleMRs()); !slices.Equal(got, tt.expected) {
+			t.Errorf("filter %q hideDrafts=%v: expected %v, got %v", tt.filter, tt.hideDrafts, tt.expected, got)
+		}
+	}
+
+	// Clearing the filter restores everything
+	m.clearMRFilter()
+	if got := len(m.visibleMRs()); got != 3 {
+		t.Errorf("expected 3 MRs after clearing filter, got %d", got)
+	}
+}
+
+func TestMRFilter_LineTakesListRow(t *testing.T) {
+	m := &MainScreen{
+		selectedProject: &gitlab.Project{ID: 1, Name: "demo"},
+		contentTab:      TabMRs,
+		width:           100,
+		height:          30,
+	}
+	for i := range 20 {
+		m.mergeRequests = append(m.mergeRequests, gitlab.MergeRequest{IID: i + 1, Title: "change"})
+	}
+	rows := func() int {
+		return strings.Count(m.renderListSection(80, 14), " change")
+	}
+
+	plain, plainVisible := rows(), m.listVisibleLines()
+	m.mrFilter = "change"
+	if got := rows(); got != plain-1 {
+		t.Errorf("the filter line should take one list row: %d rows, %d without it", got, plain)
+	}
+	if got := m.listVisibleLines(); got != plainVisible-1 {
+		t.Errorf("scrolling should leave room for the filter line: %d lines, %d without it", got, plainVisible)
+	}
+}
+
+func TestHelpSectionsIncludeVimSequences(t *testing.T) {
+	keys := make(map[string]bool)
+	for _, section := range helpSections {
+		for _, b := range section.bindings {
+			keys[b.key] = true
+		}
+	}
+
+	for _, k := range []string{"?", "yy", "ggy", "V"} {
+		if !keys[k] {
+			t.Errorf("expected help overlay to document %q", k)
+		}
+	}
+}
+
+func TestEditorCommand(t *testing.T) {
+	tests := []struct {
+		editor   string
+		pager    string
+		expected []string
+	}{
+		{"nvim", "less", []string{"nvim"}},
+		{"code -w", "", []string{"code", "-w"}},
+		{"", "less -R", []string{"less", "-R"}},
+		{"", "", []string{"vi"}},
+	}
+
+	for _, tt := range tests {
+		t.Setenv("EDITOR", tt.editor)
+		t.Setenv("PAGER", tt.pager)
+		if got := editorCommand(); !slices.Equal(got, tt.expected) {
+			t.Errorf("EDITOR=%q PAGER=%q: expected %v, got %v", tt.editor, tt.pager, tt.expected, got)
+		}
+	}
+}
+
+func TestOpenInEditor_WritesFileInCmd(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	cmd := openInEditor("src/main.go", "package main\n")
+	if entries, _ := os.ReadDir(os.TempDir()); len(entries) != 0 {
+		t.Fatal("the temp file should be written by the command, not when it's created")
+	}
+	msg, ok := cmd().(editorFileReadyMsg)
+	if !ok || !strings.HasSuffix(msg.path, "-main.go") {
+		t.Fatalf("expected a temp file keeping the extension, got %#v", msg)
+	}
+	if data, err := os.ReadFile(msg.path); err != nil || string(data) != "package main\n" {
+		t.Errorf("unexpected temp file content %q, %v", data, err)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d        time.Duration
+		expected string
+	}{
+		{0, "0s"},
+		{-5 * time.Second, "0s"},
+		{45 * time.Second, "45s"},
+		{4*time.Minute + 12*time.Second, "4m12s"},
+		{4*time.Minute + 2*time.Second, "4m02s"},
+		{time.Hour + 3*time.Minute + 20*time.Second, "1h3m"},
+	}
+
+	for _, tt := range tests {
+		if got := formatDuration(tt.d); got != tt.expected {
+			t.Errorf("formatDuration(%v) = %q, expected %q", tt.d, got, tt.expected)
+		}
+	}
+}
+
+func TestPipelineDuration(t *testing.T) {
+	created := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	jobStart := created.Add(30 * time.Second)
+	jobEnd := created.Add(5 * time.Minute)
+
+	// Finished pipeline uses job timings when available
+	p := gitlab.Pipeline{Status: "success", CreatedAt: created, UpdatedAt: created.Add(10 * time.Minute)}
+	jobs := []gitlab.Job{{StartedAt: &jobStart, FinishedAt: &jobEnd}}
+	if d, ok := pipelineDuration(p, jobs); !ok || d != 4*time.Minute+30*time.Second {
+		t.Errorf("expected 4m30s from jobs, got %v (ok=%v)", d, ok)
+	}
+
+	// Without jobs it falls back to created/updated
+	if d, ok := pipelineDuration(p, nil); !ok || d != 10*time.Minute {
+		t.Errorf("expected 10m fallback, got %v (ok=%v)", d, ok)
+	}
+
+	// Explicit duration wins
+	p.Duration = 90
+	if d, _ := pipelineDuration(p, jobs); d != 90*time.Second {
+		t.Errorf("expected 90s from duration field, got %v", d)
+	}
+
+	// Pending pipelines have no duration
+	if _, ok := pipelineDuration(gitlab.Pipeline{Status: "pending"}, nil); ok {
+		t.Error("expected no duration for pending pipeline")
+	}
+}
+
+func TestFormatElapsed(t *testing.T) {
+	tests := []struct {
+		d        time.Duration
+		expected string
+	}{
+		{0, "00:00"},
+		{-time.Second, "00:00"},
+		{7 * time.Second, "00:07"},
+		{2*time.Minute + 5*time.Second, "02:05"},
+		{59*time.Minute + 59*time.Second, "59:59"},
+		{time.Hour + 2*time.Minute + 3*time.Second, "1:02:03"},
+		{1500 * time.Millisecond, "00:02"},
+	}
+
+	for _, tt := range tests {
+		if got := formatElapsed(tt.d); got != tt.expected {
+			t.Errorf("formatElapsed(%v) = %q, expected %q", tt.d, got, tt.expected)
+		}
+	}
+}
+
+func TestLoadProjectContent_EmptyRepository(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"tree 404", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "404 Tree Not Found"}`))
+		}},
+		{"empty tree", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`[]`))
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			m := &MainScreen{
+				client:          gitlab.NewClient(server.URL, "test-token"),
+				selectedProject: &gitlab.Project{ID: 1, DefaultBranch: "main"},
+				loading:         true,
+			}
+
+			cmd := m.loadProjectContentForBranch("main")
+			m.Update(cmd())
+
+			if !m.repoEmpty {
+				t.Error("expected repository to be marked empty")
+			}
+			if m.lastError != "" {
+				t.Errorf("expected no error, got %q", m.lastError)
+			}
+			if m.loading {
+				t.Error("expected loading to be cleared")
+			}
+		})
+	}
+}
+
+func TestSplitLineAnchor(t *testing.T) {
+	tests := []struct {
+		input        string
+		expectedPath string
+		expectedLine int
+	}{
+		{"main.go", "main.go", 0},
+		{"cmd/main.go#L42", "cmd/main.go", 42},
+		{"cmd/main.go#L10-20", "cmd/main.go", 10},
+		{"notes#Lfoo", "notes#Lfoo", 0},
+	}
+
+	for _, tt := range tests {
+		path, line := splitLineAnchor(tt.input)
+		if path != tt.expectedPath || line != tt.expectedLine {
+			t.Errorf("splitLineAnchor(%q) = (%q, %d), expected (%q, %d)", tt.input, path, line, tt.expectedPath, tt.expectedLine)
+		}
+	}
+}
+
+func TestParseLineNumber(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+		ok       bool
+	}{
+		{"42", 42, true},
+		{" L7 ", 7, true},
+		{"#L3", 3, true},
+		{"0", 0, false},
+		{"-1", 0, false},
+		{"abc", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		line, ok := parseLineNumber(tt.input)
+		if line != tt.expected || ok != tt.ok {
+			t.Errorf("parseLineNumber(%q) = (%d, %v), expected (%d, %v)", tt.input, line, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestJumpToFileLineClamps(t *testing.T) {
+	m := &MainScreen{
+		fileContent:     strings.Repeat("line\n", 99) + "line",
+		viewingFilePath: "notes.txt",
+		fileViewport:    viewport.New(80, 10),
+	}
+	m.fileViewport.SetContent(m.fileViewContent())
+
+	if cmd := m.jumpToFileLine(500); cmd == nil {
+		t.Fatal("expected flash command")
+	}
+	if m.fileFlashLine != 100 {
+		t.Errorf("expected line clamped to 100, got %d", m.fileFlashLine)
+	}
+
+	m.jumpToFileLine(50)
+	if m.fileViewport.YOffset != 50-1-10/3 {
+		t.Errorf("expected offset %d, got %d", 50-1-10/3, m.fileViewport.YOffset)
+	}
+
+	// Stale flash timers don't clear a newer highlight
+	m.Update(fileFlashDoneMsg{seq: m.fileFlashSeq - 1})
+	if m.fileFlashLine != 50 {
+		t.Errorf("expected highlight to survive stale timer, got %d", m.fileFlashLine)
+	}
+	m.Update(fileFlashDoneMsg{seq: m.fileFlashSeq})
+	if m.fileFlashLine != 0 {
+		t.Errorf("expected highlight cleared, got %d", m.fileFlashLine)
+	}
+}
+
+func TestJumpToFileLine_MarkdownShowsSource(t *testing.T) {
+	source := "# Title\n\nSome *text* here.\n\n## Usage\n\nrun it\n"
+	m := &MainScreen{
+		fileContent:     source,
+		fileIsMarkdown:  true,
+		viewingFilePath: "README.md",
+		fileViewport:    viewport.New(80, 10),
+	}
+	m.fileViewport.SetContent(m.fileViewContent())
+
+	m.jumpToFileLine(5)
+	if !m.fileShowRaw {
+		t.Fatal("expected the jump to switch to the markdown source")
+	}
+	if lines := strings.Split(m.fileViewContent(), "\n"); !strings.Contains(stripANSI(lines[4]), "## Usage") {
+		t.Errorf("expected line 5 of the source to be highlighted, got %q", stripANSI(lines[4]))
+	}
+
+	// A #L anchor opens the source as well
+	m.Update(fileContentMsg{content: source, path: "docs/guide.md", line: 5})
+	if !m.fileShowRaw || m.fileFlashLine != 5 {
+		t.Errorf("expected the anchor to open the source at line 5, raw %v line %d", m.fileShowRaw, m.fileFlashLine)
+	}
+}
+
+func TestIsMarkdownFile(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{"README.md", true},
+		{"docs/Guide.MARKDOWN", true},
+		{"main.go", false},
+		{"md", false},
+	}
+
+	for _, tt := range tests {
+		if got := isMarkdownFile(tt.path); got != tt.expected {
+			t.Errorf("isMarkdownFile(%q) = %v, expected %v", tt.path, got, tt.expected)
+		}
+	}
+}
+
+func TestCreateClient_ConfiguredPerPage(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := config.SaveLazyLabConfig(&config.LazyLabConfig{PerPage: 100}); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+
+	var perPage string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		perPage = r.URL.Query().Get("per_page")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	if _, err := createClient(server.URL, "token").ListBranches("1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if perPage != "100" {
+		t.Errorf("expected per_page=100 from the config, got %q", perPage)
+	}
+}
+
+func TestLoadCredentials_TokenFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv(config.EnvGitLabHost, "")
+
+	cfg := &config.LazyLabConfig{DefaultHost: "gitlab.example.com"}
+	cfg.SetHostToken("gitlab.example.com", "inline-token")
+	if err := config.SaveLazyLabConfig(cfg); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+
+	tokenFile := filepath.Join(tmpDir, "token")
+	if err := os.WriteFile(tokenFile, []byte("file-token\n"), 0600); err != nil {
+		t.Fatalf("failed to write token file: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		envToken  string
+		tokenFile string
+		expected  string
+	}{
+		{"env token wins over token file", "env-token", tokenFile, "env-token"},
+		{"token file wins over config", "", tokenFile, "file-token"},
+		{"missing token file falls through to config", "", filepath.Join(tmpDir, "missing"), "inline-token"},
+		{"config token without token file", "", "", "inline-token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(config.EnvGitLabToken, tt.envToken)
+			t.Setenv(config.EnvGitLabTokenFile, tt.tokenFile)
+
+			token, host := loadCredentials()
+			if token != tt.expected {
+				t.Errorf("expected token %q, got %q", tt.expected, token)
+			}
+			if host != "https://gitlab.example.com" {
+				t.Errorf("expected host from config, got %q", host)
+			}
+		})
+	}
+
+	// A host given as a URL still finds its config
+	t.Setenv(config.EnvGitLabToken, "")
+	t.Setenv(config.EnvGitLabTokenFile, "")
+	t.Setenv(config.EnvGitLabHost, "https://gitlab.example.com/")
+	if token, host := loadCredentials(); token != "inline-token" || host != "https://gitlab.example.com" {
+		t.Errorf("expected the config token for a URL host, got %q for %q", token, host)
+	}
+}
+
+func TestLoginScreen_KeepsUnparseableConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	path, err := config.GetConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	broken := "favorites: [\n  - id: 1\n"
+	if err := os.WriteFile(path, []byte(broken), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewLoginScreen()
+	if _, cmd := m.saveCredentials("gitlab.com", "glpat-x"); cmd != nil || m.errMsg == "" {
+		t.Error("a config that doesn't parse should stop the login with an error")
+	}
+	if data, _ := os.ReadFile(path); string(data) != broken {
+		t.Errorf("the config was overwritten: %q", data)
+	}
+
+	// Without a config one is created
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, cmd := m.saveCredentials("gitlab.com", "glpat-x"); cmd == nil {
+		t.Errorf("a missing config should be created, got %q", m.errMsg)
+	}
+}
+
+func TestRenderDiff(t *testing.T) {
+	diffs := []gitlab.Diff{
+		{OldPath: "a.go", NewPath: "a.go", Diff: "@@ -1,2 +1,2 @@\n context\n-old line\n+new line\n"},
+		{OldPath: "old.go", NewPath: "new.go", RenamedFile: true},
+		{OldPath: "gone.go", NewPath: "gone.go", DeletedFile: true, Diff: "@@ -1 +0,0 @@\n-package gone\n"},
+	}
+
+	out := stripANSI(renderDiff(diffs, 80))
+	for _, want := range []string{"a.go\n", "-old line", "+new line", " context", "old.go → new.go", "gone.go (deleted)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected diff output to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	// Long lines are truncated to the width
+	long := []gitlab.Diff{{NewPath: "x", Diff: "+" + strings.Repeat("x", 100) + "\n"}}
+	for _, line := range strings.Split(strings.TrimSuffix(stripANSI(renderDiff(long, 20)), "\n"), "\n") {
+		if len(line) > 20 {
+			t.Errorf("expected lines truncated to 20 columns, got %d: %q", len(line), line)
+		}
+	}
+}
+
+func TestComparisonIsEmpty(t *testing.T) {
+	if !comparisonIsEmpty(&gitlab.Comparison{}) {
+		t.Error("expected comparison without commits or diffs to be empty")
+	}
+	if !comparisonIsEmpty(&gitlab.Comparison{CompareSameRef: true}) {
+		t.Error("expected same-ref comparison to be empty")
+	}
+	if comparisonIsEmpty(&gitlab.Comparison{Commits: []gitlab.Commit{{ID: "abc"}}}) {
+		t.Error("expected comparison with commits not to be empty")
+	}
+}
+
+func TestApprovalBadge(t *testing.T) {
+	approvers := func(n int) []gitlab.MRApprover {
+		return make([]gitlab.MRApprover, n)
+	}
+
+	tests := []struct {
+		name      string
+		approvals *gitlab.MRApprovals
+		expected  string
+	}{
+		{"not loaded", nil, ""},
+		{"no rules and no approvals", &gitlab.MRApprovals{}, ""},
+		{"partially approved", &gitlab.MRApprovals{ApprovalsRequired: 2, ApprovedBy: approvers(1)}, "✔1/2"},
+		{"fully approved", &gitlab.MRApprovals{ApprovalsRequired: 2, ApprovedBy: approvers(2)}, "✔2/2"},
+		{"optional approvals", &gitlab.MRApprovals{ApprovedBy: approvers(1)}, "✔1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := approvalBadge(tt.approvals); got != tt.expected {
+				t.Errorf("approvalBadge() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHandleMouse_ClickSelects(t *testing.T) {
+	m := &MainScreen{
+		width:  100,
+		height: 30,
+		treeNodes: []TreeNode{
+			{Type: "group", ID: 1, Name: "a"},
+			{Type: "group", ID: 2, Name: "b"},
+			{Type: "group", ID: 3, Name: "c"},
+		},
+		selectedProject: &gitlab.Project{ID: 1, Name: "api"},
+		contentTab:      TabFiles,
+		currentPath:     []string{"src"},
+		files: []gitlab.TreeEntry{
+			{Name: "a.go"}, {Name: "b.go"}, {Name: "c.go"},
+		},
+	}
+
+	// Third navigator row sits below the top border
+	m.Update(tea.MouseMsg{X: 2, Y: 3, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	if m.focusedPanel != PanelNavigator || m.selectedNodeIdx != 2 {
+		t.Errorf("expected navigator row 2 selected, got panel %d row %d", m.focusedPanel, m.selectedNodeIdx)
+	}
+
+	// Border, project header, tabs, breadcrumb and a blank line come first
+	m.Update(tea.MouseMsg{X: 60, Y: 6, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	if m.focusedPanel != PanelContent || m.selectedContent != 1 {
+		t.Errorf("expected content row 1 selected, got panel %d row %d", m.focusedPanel, m.selectedContent)
+	}
+
+	m.Update(tea.MouseMsg{X: 60, Y: 6, Button: tea.MouseButtonWheelDown, Action: tea.MouseActionPress})
+	m.Update(tea.MouseMsg{X: 60, Y: 6, Button: tea.MouseButtonWheelDown, Action: tea.MouseActionPress})
+	if m.selectedContent != 2 {
+		t.Errorf("expected wheel to stop at the last row, got %d", m.selectedContent)
+	}
+
+	// Clicks are ignored while a popup is open
+	m.showHelpPopup = true
+	m.Update(tea.MouseMsg{X: 2, Y: 1, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	if m.selectedNodeIdx != 2 {
+		t.Errorf("expected popup to swallow the click, got row %d", m.selectedNodeIdx)
+	}
+}
+
+func TestRefreshPaused_SkipsNetworkCalls(t *testing.T) {
+	m := &MainScreen{
+		selectedProject:  &gitlab.Project{ID: 1},
+		contentTab:       TabPipelines,
+		showRunnersPopup: true,
+		pipelineRefresh:  time.Millisecond,
+	}
+
+	m.handleKey(tea.KeyMsg{Type: tea.KeyCtrlP})
+	if !m.refreshPaused {
+		t.Fatal("expected ctrl+p to pause auto-refresh")
+	}
+
+	// Paused ticks only schedule the next tick instead of fetching
+	for _, msg := range []tea.Msg{pipelineTickMsg{}, runnersTickMsg{}} {
+		_, cmd := m.Update(msg)
+		if cmd == nil {
+			t.Fatalf("expected %T to keep ticking while paused", msg)
+		}
+		if next := cmd(); fmt.Sprintf("%T", next) != fmt.Sprintf("%T", msg) {
+			t.Errorf("expected %T while paused, got %T", msg, next)
+		}
+	}
+
+	m.handleKey(tea.KeyMsg{Type: tea.KeyCtrlP})
+	if m.refreshPaused {
+		t.Error("expected ctrl+p to resume auto-refresh")
+	}
+}
+
+func TestTruncateWidth_Multibyte(t *testing.T) {
+	name := "Équipe-Réseau 🚀 platform"
+
+	got := truncateWidth(name, 15)
+	if !utf8.ValidString(got) {
+		t.Fatalf("truncateWidth produced invalid UTF-8: %q", got)
+	}
+	if w := lipgloss.Width(got); w > 15 || !strings.HasSuffix(got, "…") {
+		t.Errorf("expected at most 15 cells ending in an ellipsis, got %q (%d)", got, w)
+	}
+	if got := truncateWidth("short", 15); got != "short" {
+		t.Errorf("expected short names untouched, got %q", got)
+	}
+
+	left := truncateWidthLeft("/home/ünïcödé/🚀/projects", 12)
+	if !utf8.ValidString(left) || lipgloss.Width(left) > 12 || !strings.HasPrefix(left, "...") {
+		t.Errorf("unexpected left truncation %q", left)
+	}
+
+	m := &MainScreen{
+		focusedPanel: PanelNavigator,
+		treeNodes: []TreeNode{
+			{Type: "group", ID: 1, Name: "Équipe-Réseau 🚀 with a very long group name"},
+		},
+	}
+	panel := m.renderNavigatorPanel(20, 10)
+	if !utf8.ValidString(panel) {
+		t.Errorf("navigator produced invalid UTF-8: %q", panel)
+	}
+}
+
+func TestProjectBadges(t *testing.T) {
+	languages := map[string]float64{"Shell": 10, "Go": 85, "Makefile": 5}
+	topics := []string{"cli", "gitlab", "tui"}
+
+	if got := projectBadges(languages, topics, 80); got != " · Go · #cli #gitlab #tui" {
+		t.Errorf("unexpected badges %q", got)
+	}
+	// Only room for the language and one topic
+	if got := projectBadges(languages, topics, 16); got != " · Go · #cli +2" {
+		t.Errorf("unexpected truncated badges %q", got)
+	}
+	if got := projectBadges(nil, nil, 80); got != "" {
+		t.Errorf("expected no badges, got %q", got)
+	}
+	if got := projectBadges(nil, topics, 80); got != " · #cli #gitlab #tui" {
+		t.Errorf("unexpected topic-only badges %q", got)
+	}
+}
+
+func TestLastProject_RestoreAndForget(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := &config.LazyLabConfig{LastProject: &config.LastProject{ID: 7, Path: "group/gone"}}
+	if err := config.SaveLazyLabConfig(cfg); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+
+	// A deleted project leaves the group list in place and is forgotten
+	m := &MainScreen{restoreLast: true, lastProject: cfg.LastProject}
+	m.Update(lastProjectLoadedMsg{err: fmt.Errorf("GET /projects/7: %w", gitlab.ErrNotFound)})
+	if m.selectedProject != nil || m.lastError != "" {
+		t.Errorf("expected a silent fallback, got project %v error %q", m.selectedProject, m.lastError)
+	}
+	if saved, _ := config.LoadLazyLabConfig(); saved.LastProject != nil {
+		t.Errorf("expected last project to be cleared, got %+v", saved.LastProject)
+	}
+
+	// A restored project opens on its saved tab and is remembered again
+	m.Update(lastProjectLoadedMsg{
+		project: &gitlab.Project{ID: 9, PathWithNamespace: "group/api"},
+		tab:     TabPipelines,
+	})
+	if m.selectedProject == nil || m.contentTab != TabPipelines || m.focusedPanel != PanelContent {
+		t.Fatalf("expected project on pipelines tab, got %+v tab %d", m.selectedProject, m.contentTab)
+	}
+	saved, _ := config.LoadLazyLabConfig()
+	if saved.LastProject == nil || saved.LastProject.ID != 9 || saved.LastProject.Tab != "Pipelines" {
+		t.Errorf("unexpected saved last project %+v", saved.LastProject)
+	}
+}
+
+func TestSnippetPath(t *testing.T) {
+	if got := snippetPath(gitlab.Snippet{ID: 12, FileName: "deploy.sh"}); got != "$12/deploy.sh" {
+		t.Errorf("unexpected path %q", got)
+	}
+	// Highlighting keys off the base name, so a missing file name needs a fallback
+	if got := snippetPath(gitlab.Snippet{ID: 3}); got != "$3/snippet.txt" {
+		t.Errorf("unexpected fallback path %q", got)
+	}
+}
+
+func TestSnippetViewer_EscReturnsToPopup(t *testing.T) {
+	m := NewDemoScreen()
+	m.width, m.height = 120, 40
+
+	m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if !m.showSnippetsPopup || len(m.snippets) == 0 {
+		t.Fatal("expected snippets popup with demo snippets")
+	}
+
+	m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.showSnippetsPopup || !m.viewingFile || !m.viewingSnippet {
+		t.Fatalf("expected snippet in the file viewer, popup %v viewing %v", m.showSnippetsPopup, m.viewingFile)
+	}
+	if m.viewingFilePath != snippetPath(m.snippets[0]) {
+		t.Errorf("unexpected viewer path %q", m.viewingFilePath)
+	}
+
+	m.handleKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if !m.showSnippetsPopup || m.viewingFile {
+		t.Error("expected Esc to return to the snippets popup")
+	}
+}
+
+func TestWikiPopup_NestedPagesAndViewer(t *testing.T) {
+	m := NewDemoScreen()
+	m.width, m.height = 120, 40
+	m.focusedPanel = PanelContent
+	m.contentTab = TabFiles
+
+	m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("W")})
+	if !m.showWikiPopup || len(m.wikiPages) == 0 {
+		t.Fatal("expected wiki popup with demo pages")
+	}
+	// Nested pages follow their parent, indented
+	for i, p := range m.wikiPages {
+		if p.Slug == "runbooks/rate-limit-alerts" && (i == 0 || m.wikiPages[i-1].Slug != "runbooks") {
+			t.Errorf("expected nested page after its parent, got order %v", m.wikiPages)
+		}
+	}
+	if !strings.Contains(m.View(), "    rate-limit-alerts") {
+		t.Error("expected nested page to be indented")
+	}
+
+	m.wikiCursor = 0
+	m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.showWikiPopup || !m.viewingFile || !m.viewingWiki || !m.fileIsMarkdown {
+		t.Fatalf("expected wiki page rendered in the file viewer, popup %v viewing %v", m.showWikiPopup, m.viewingFile)
+	}
+
+	m.handleKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if !m.showWikiPopup || m.viewingFile {
+		t.Error("expected Esc to return to the wiki popup")
+	}
+}
+
+func TestWikiPopup_DisabledWiki(t *testing.T) {
+	m := &MainScreen{showWikiPopup: true, wikiLoading: true, selectedProject: &gitlab.Project{ID: 1}}
+	m.Update(wikiPagesLoadedMsg{projectID: 1, err: fmt.Errorf("API error 404: %w", gitlab.ErrNotFound)})
+	if m.wikiLoading || m.wikiErr != "The wiki is disabled for this project" {
+		t.Errorf("expected disabled wiki note, got %q", m.wikiErr)
+	}
+}
+
+func TestSortWikiPages(t *testing.T) {
+	pages := []gitlab.WikiPage{{Slug: "a-b"}, {Slug: "a/c"}, {Slug: "b"}, {Slug: "a"}, {Slug: "a/c/d"}}
+	var got []string
+	for _, p := range sortWikiPages(pages) {
+		got = append(got, p.Slug)
+	}
+	// a-b sorts before a/c character by character, splitting a from its child
+	if want := []string{"a", "a/c", "a/c/d", "a-b", "b"}; !slices.Equal(got, want) {
+		t.Errorf("sortWikiPages = %v, want %v", got, want)
+	}
+}
+
+func TestWiki_IgnoresOtherProject(t *testing.T) {
+	m := &MainScreen{showWikiPopup: true, wikiLoading: true, selectedProject: &gitlab.Project{ID: 2}}
+	m.Update(wikiPagesLoadedMsg{projectID: 1, pages: []gitlab.WikiPage{{Slug: "home"}}})
+	if len(m.wikiPages) != 0 || !m.wikiLoading {
+		t.Errorf("pages of the previous project should be dropped, got %v", m.wikiPages)
+	}
+	m.Update(fileContentMsg{content: "# Home", path: "wiki/home.md", wiki: true, projectID: 1})
+	if m.viewingFile {
+		t.Error("a wiki page of the previous project shouldn't open")
+	}
+}
+
+func TestFileViewer_LargeAndInvalidUTF8(t *testing.T) {
+	m := &MainScreen{settings: config.LazyLabConfig{MaxHighlightBytes: 64}}
+
+	// Invalid UTF-8 (Latin-1 "café") is replaced instead of rendered
+	m.Update(fileContentMsg{content: "caf\xe9\n", path: "notes.txt"})
+	if m.fileContent != nonUTF8Note {
+		t.Errorf("expected non-UTF-8 note, got %q", m.fileContent)
+	}
+
+	// Small files are highlighted
+	m.Update(fileContentMsg{content: "package main\n", path: "main.go"})
+	if m.fileTooLarge() || !strings.Contains(m.fileViewContent(), "\x1b[") {
+		t.Error("expected small Go file to be highlighted")
+	}
+
+	// Files over the limit are shown as-is
+	large := "var x = 1;" + strings.Repeat(" ", 100)
+	m.Update(fileContentMsg{content: large, path: "bundle.min.js"})
+	if !m.fileTooLarge() {
+		t.Fatal("expected file over the limit to be flagged")
+	}
+	if got := m.fileViewContent(); got != large {
+		t.Errorf("expected raw content for large file, got %q", got)
+	}
+}
+
+func TestFileViewer_BinaryDetection(t *testing.T) {
+	utf16le := func(s string) string {
+		out := []byte{0xff, 0xfe}
+		for _, u := range utf16.Encode([]rune(s)) {
+			out = append(out, byte(u), byte(u>>8))
+		}
+		return string(out)
+	}
+	tests := []struct {
+		name, path, content, want string
+	}{
+		{"UTF-16LE with BOM", "setup.ps1", utf16le("Write-Host \"héllo\"\r\n"), "Write-Host \"héllo\"\r\n"},
+		{"UTF-16BE with BOM", "notes.txt", "\xfe\xff\x00h\x00i", "hi"},
+		{"UTF-8 BOM stripped", "data.csv", "\xef\xbb\xbfa,b\n", "a,b\n"},
+		{"PNG header", "logo.dat", "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR", binaryFileNote},
+		{"control bytes without nulls", "blob.dat", strings.Repeat("\x01\x02\x03abcdefg", 20), binaryFileNote},
+		{"colored log", "build.log", "\x1b[31mFAIL\x1b[0m\n\tat main.go\n", "\x1b[31mFAIL\x1b[0m\n\tat main.go\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &MainScreen{}
+			m.Update(fileContentMsg{content: tt.content, path: tt.path})
+			if m.fileContent != tt.want {
+				t.Errorf("file content = %q, want %q", m.fileContent, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrimPartialRune(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"hello", "hello"},
+		{"caf\xc3", "caf"},       // "é" cut after its first byte
+		{"snow\xe2\x98", "snow"}, // "☃" cut after two bytes
+		{"café", "café"},
+	}
+	for _, tt := range tests {
+		if got := trimPartialRune(tt.in); got != tt.want {
+			t.Errorf("trimPartialRune(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFocusedMarkdownLink(t *testing.T) {
+	project := &gitlab.Project{Name: "api", WebURL: "https://gitlab.example.com/g/api"}
+	m := &MainScreen{
+		selectedProject: project,
+		focusedPanel:    PanelContent,
+		mergeRequests: []gitlab.MergeRequest{
+			{IID: 7, Title: "Fix [flaky] test", WebURL: "https://gitlab.example.com/g/api/-/merge_requests/7"},
+		},
+		pipelines: []gitlab.Pipeline{
+			{ID: 1, IID: 42, Ref: "main", WebURL: "https://gitlab.example.com/g/api/-/pipelines/1"},
+		},
+		releases: []gitlab.Release{{TagName: "v1.2.0"}},
+	}
+
+	tests := []struct {
+		tab      ContentTab
+		expected string
+	}{
+		{TabFiles, "[api](https://gitlab.example.com/g/api)"},
+		{TabMRs, `[!7 Fix \[flaky\] test](https://gitlab.example.com/g/api/-/merge_requests/7)`},
+		{TabPipelines, "[#42 main](https://gitlab.example.com/g/api/-/pipelines/1)"},
+		// Releases without a self link fall back to the project's releases page
+		{TabReleases, "[v1.2.0](https://gitlab.example.com/g/api/-/releases/v1.2.0)"},
+	}
+	for _, tt := range tests {
+		m.contentTab = tt.tab
+		if got := m.focusedMarkdownLink(); got != tt.expected {
+			t.Errorf("tab %d: expected %q, got %q", tt.tab, tt.expected, got)
+		}
+	}
+
+	m.showMRDetailPopup = true
+	m.mrDetail = &m.mergeRequests[0]
+	if got := m.focusedMarkdownLink(); !strings.HasPrefix(got, "[!7 ") {
+		t.Errorf("expected MR link from the detail popup, got %q", got)
+	}
+}
+
+func TestParseDeepLink(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    DeepLink
+		wantErr bool
+	}{
+		{
+			in:   "lazylab://gitlab.com/group/sub/project?tab=files&ref=feature/x&path=src/lib",
+			want: DeepLink{Host: "gitlab.com", Project: "group/sub/project", Tab: "files", Ref: "feature/x", Path: "src/lib"},
+		},
+		{
+			in:   "gitlab.example.com:8443/group/project?tab=Pipelines",
+			want: DeepLink{Host: "gitlab.example.com:8443", Project: "group/project", Tab: "pipelines"},
+		},
+		{in: "lazylab://gitlab.com/group/project", want: DeepLink{Host: "gitlab.com", Project: "group/project"}},
+		{in: "https://gitlab.com/group/project", wantErr: true},
+		{in: "lazylab://gitlab.com/project", wantErr: true},
+		{in: "lazylab://gitlab.com/group/project?tab=issues", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDeepLink(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDeepLink(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDeepLink(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+
+	link := DeepLink{Host: "gitlab.com", Project: "group/project", Tab: "files", Ref: "v1.0 rc", Path: "docs/api"}
+	if got := link.String(); got != "lazylab://gitlab.com/group/project?tab=files&ref=v1.0+rc&path=docs/api" {
+		t.Errorf("String() = %q", got)
+	}
+	if got, err := ParseDeepLink(link.String()); err != nil || got != link {
+		t.Errorf("round trip = %+v, %v", got, err)
+	}
+}
+
+func TestDeepLink_CopyAndOpen(t *testing.T) {
+	project := &gitlab.Project{ID: 1, PathWithNamespace: "group/project", WebURL: "https://gitlab.example.com/group/project", DefaultBranch: "main"}
+	m := &MainScreen{
+		selectedProject: project,
+		contentTab:      TabFiles,
+		currentBranch:   "dev",
+		currentPath:     []string{"src", "lib"},
+	}
+	link, ok := m.currentDeepLink()
+	if !ok || link.String() != "lazylab://gitlab.example.com/group/project?tab=files&ref=dev&path=src/lib" {
+		t.Fatalf("currentDeepLink = %q, %v", link.String(), ok)
+	}
+
+	api := &mockAPI{}
+	m = &MainScreen{client: api, projectRefs: make(map[int]string), spinning: true}
+	_, cmd := m.Update(deepLinkLoadedMsg{link: link, project: project})
+	if cmd == nil || m.currentRef() != "dev" || !slices.Equal(m.currentPath, []string{"src", "lib"}) {
+		t.Fatalf("link not applied: ref %q path %v", m.currentRef(), m.currentPath)
+	}
+	cmd()
+	if !slices.Equal(api.calls, []string{"GetTree dev"}) {
+		t.Errorf("expected the linked directory to load, got %v", api.calls)
+	}
+	if m.projectRefs[1] != "dev" {
+		t.Error("the linked ref should be remembered for the project")
+	}
+
+	m = &MainScreen{client: api, host: "https://gitlab.com", deepLink: &link}
+	m.Update(m.loadDeepLink()())
+	if m.selectedProject != nil || !strings.Contains(m.statusMsg, "link is for gitlab.example.com") {
+		t.Errorf("a link for another host should be refused, got %q", m.statusMsg)
+	}
+}
+
+func TestLauncher_OpensDeepLinkAfterLogin(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	link, err := ParseDeepLink("lazylab://gitlab.example.com/group/project?tab=mrs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := NewLauncherAt(link)
+	l.Update(loginSuccessMsg{})
+	if l.mainScreen == nil || l.mainScreen.deepLink == nil || *l.mainScreen.deepLink != link {
+		t.Error("expected the main screen to open the link given before login")
+	}
+}
+
+func TestGoBack_RestoresContext(t *testing.T) {
+	api := &mockAPI{}
+	first := &gitlab.Project{ID: 1, Name: "first", DefaultBranch: "main"}
+	m := &MainScreen{
+		client:          api,
+		keymap:          keymap.DefaultKeyMap(),
+		projectRefs:     make(map[int]string),
+		selectedProject: first,
+		contentTab:      TabFiles,
+		currentBranch:   "dev",
+		currentPath:     []string{"src"},
+		spinning:        true,
+	}
+	m.selectProject(&gitlab.Project{ID: 2, Name: "second"})
+	if m.backStack.Len() != 1 {
+		t.Fatalf("switching projects should record where we were, got %d", m.backStack.Len())
+	}
+
+	_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyCtrlO})
+	if m.selectedProject != first || m.currentRef() != "dev" || !slices.Equal(m.currentPath, []string{"src"}) {
+		t.Fatalf("expected first at dev/src, got %v at %q %v", m.selectedProject.Name, m.currentRef(), m.currentPath)
+	}
+	if m.backStack.Len() != 0 {
+		t.Error("going back shouldn't itself be recorded")
+	}
+	cmd()
+	if !slices.Equal(api.calls, []string{"GetTree dev"}) {
+		t.Errorf("expected the directory to reload, got %v", api.calls)
+	}
+
+	m.handleKey(tea.KeyMsg{Type: tea.KeyCtrlO})
+	if m.statusMsg != "No previous project" {
+		t.Errorf("status = %q", m.statusMsg)
+	}
+}
+
+func TestPipelineFailureSummary(t *testing.T) {
+	p := gitlab.Pipeline{ID: 900, IID: 42, Ref: "main", Status: "failed"}
+	jobs := []gitlab.Job{
+		{ID: 3, Name: "docker", Stage: "build", Status: "failed"},
+		{ID: 1, Name: "lint", Stage: "test", Status: "success"},
+		{ID: 2, Name: "unit", Stage: "test", Status: "failed"},
+		{ID: 4, Name: "deploy", Stage: "deploy", Status: "skipped"},
+	}
+	want := "Pipeline #42 on main FAILED — test/unit (failed), build/docker (failed)"
+	if got := pipelineFailureSummary(p, jobs); got != want {
+		t.Errorf("pipelineFailureSummary() = %q, want %q", got, want)
+	}
+	if got := pipelineFailureSummary(gitlab.Pipeline{IID: 7, Ref: "dev", Status: "success"}, nil); got != "Pipeline #7 on dev SUCCESS" {
+		t.Errorf("summary without failed jobs = %q", got)
+	}
+
+	// Jobs fetched for the summary are kept for the inline view too
+	m := &MainScreen{selectedProject: &gitlab.Project{ID: 1}}
+	m.Update(failureJobsLoadedMsg{projectID: 1, pipeline: p, jobs: jobs})
+	if len(m.pipelineJobs[900]) != 4 || m.statusMsg == "" {
+		t.Errorf("expected jobs stored and a status message, got %d jobs, status %q", len(m.pipelineJobs[900]), m.statusMsg)
+	}
+}
+
+func TestPipelineRows_ExpandKeepsSelectionVisible(t *testing.T) {
+	m := &MainScreen{
+		pipelineJobs: map[int][]gitlab.Job{
+			1: {
+				{ID: 12, Name: "test", Stage: "test", Status: "failed"},
+				{ID: 11, Name: "build", Stage: "build", Status: "success"},
+				{ID: 13, Name: "lint", Stage: "test", Status: "success"},
+			},
+		},
+		expandedPipelines: map[int]bool{1: true, 2: true},
+	}
+	pipelines := []gitlab.Pipeline{{ID: 1}, {ID: 2}, {ID: 3}}
+
+	rows := m.pipelineRows(pipelines)
+	// Pipeline 1: row, build stage + job, test stage + 2 jobs; pipeline 2: row + "no jobs"; pipeline 3
+	if len(rows) != 9 {
+		t.Fatalf("expected 9 rows, got %d", len(rows))
+	}
+	if rows[1].stage == nil || rows[1].stage.name != "build" {
+		t.Errorf("expected build stage first, got %+v", rows[1])
+	}
+	if rows[3].stage == nil || rows[3].stage.status != "failed" {
+		t.Errorf("expected failed test stage, got %+v", rows[3])
+	}
+	if !rows[7].noJobs || rows[7].pipeline != 1 {
+		t.Errorf("expected placeholder row for pipeline without jobs, got %+v", rows[7])
+	}
+
+	// Selecting the last pipeline scrolls past the expanded rows
+	m.selectedContent = 2
+	m.keepPipelineInView(rows, 4)
+	if m.pipelineRowOffset != 5 {
+		t.Errorf("expected offset 5, got %d", m.pipelineRowOffset)
+	}
+
+	// Collapsing everything clamps the offset back
+	m.expandedPipelines = nil
+	m.keepPipelineInView(m.pipelineRows(pipelines), 4)
+	if m.pipelineRowOffset != 0 {
+		t.Errorf("expected offset 0 after collapsing, got %d", m.pipelineRowOffset)
+	}
+}
+
+func TestDefaultGroup_Precedence(t *testing.T) {
+	cfg := &config.LazyLabConfig{
+		Hosts: map[string]config.LazyLabHost{
+			"gitlab.example.com": {Token: "t", DefaultGroup: "platform/team-a/"},
+		},
+	}
+
+	t.Setenv(config.EnvGitLabGroup, "")
+	if got := defaultGroup("https://gitlab.example.com", cfg); got != "platform/team-a" {
+		t.Errorf("config default_group: got %q", got)
+	}
+	if got := defaultGroup("https://gitlab.com", cfg); got != "" {
+		t.Errorf("other host: got %q, want none", got)
+	}
+	if got := defaultGroup("https://gitlab.example.com", nil); got != "" {
+		t.Errorf("nil config: got %q, want none", got)
+	}
+
+	// Plain http hosts keep their scheme in the key
+	cfg.Hosts["http://internal"] = config.LazyLabHost{DefaultGroup: "ops"}
+	if got := defaultGroup("http://internal", cfg); got != "ops" {
+		t.Errorf("http host: got %q, want ops", got)
+	}
+	if got := defaultGroup("https://internal", cfg); got != "" {
+		t.Errorf("https host shouldn't match the http key: got %q", got)
+	}
+
+	t.Setenv(config.EnvGitLabGroup, "infra")
+	if got := defaultGroup("https://gitlab.example.com", cfg); got != "infra" {
+		t.Errorf("GITLAB_GROUP should win over config: got %q", got)
+	}
+}
+
+func TestGroupFilter_NotFoundFallsBack(t *testing.T) {
+	m := &MainScreen{groupFilter: "gone", defaultGroup: "gone"}
+
+	// Groups loaded for another filter are stale
+	m.Update(groupsLoadedMsg{groups: []gitlab.Group{{ID: 1, Name: "all"}}, filter: ""})
+	if len(m.groups) != 0 {
+		t.Fatalf("stale groups applied: %+v", m.groups)
+	}
+
+	m.Update(groupFilterNotFoundMsg{filter: "gone"})
+	if m.groupFilter != "" {
+		t.Errorf("filter should be cleared, got %q", m.groupFilter)
+	}
+	if !strings.Contains(m.statusMsg, "not found") {
+		t.Errorf("statusMsg = %q", m.statusMsg)
+	}
+}
+
+func TestDiffsForPath(t *testing.T) {
+	diffs := []gitlab.Diff{
+		{OldPath: "a.go", NewPath: "a.go"},
+		{OldPath: "old.go", NewPath: "new.go", RenamedFile: true},
+		{OldPath: "b.go", NewPath: "b.go"},
+	}
+
+	if got := diffsForPath(diffs, "a.go"); len(got) != 1 || got[0].NewPath != "a.go" {
+		t.Errorf("a.go: got %+v", got)
+	}
+	if got := diffsForPath(diffs, "old.go"); len(got) != 1 || got[0].NewPath != "new.go" {
+		t.Errorf("renamed from old.go: got %+v", got)
+	}
+	if got := diffsForPath(diffs, "c.go"); len(got) != 0 {
+		t.Errorf("untouched file: got %+v", got)
+	}
+}
+
+func TestCommitDiff_KeepsFetchedCommit(t *testing.T) {
+	m := &MainScreen{files: []gitlab.TreeEntry{{Name: "a.go", Type: "blob", Path: "a.go"}}}
+	m.openCommitDiff(m.files[0])
+	if !m.showCommitDiffPopup || !m.commitDiffLoading {
+		t.Fatal("popup should open and start loading")
+	}
+
+	commit := &gitlab.Commit{ID: "abc123", Title: "Fix a"}
+	m.Update(commitDiffLoadedMsg{path: "other.go", commit: commit})
+	if m.commitDiffCommit != nil {
+		t.Fatal("result for another path should be ignored")
+	}
+
+	m.Update(commitDiffLoadedMsg{path: "a.go", commit: commit, diffs: []gitlab.Diff{{NewPath: "a.go"}}})
+	if m.commitDiffLoading || len(m.commitDiffs) != 1 {
+		t.Errorf("diff not applied: loading=%v diffs=%+v", m.commitDiffLoading, m.commitDiffs)
+	}
+	if m.files[0].LastCommit != commit {
+		t.Error("commit fetched on demand should be kept on the entry")
+	}
+}
+
+func TestCommitDiff_OpenFileAtCommit(t *testing.T) {
+	var gotRef string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.EscapedPath(), "gone.go") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		gotRef = r.URL.Query().Get("ref")
+		w.Write([]byte("package a\n"))
+	}))
+	defer server.Close()
+
+	m := &MainScreen{
+		client:              gitlab.NewClient(server.URL, "token"),
+		selectedProject:     &gitlab.Project{ID: 1, DefaultBranch: "main"},
+		showCommitDiffPopup: true,
+		commitDiffCommit:    &gitlab.Commit{ID: "abc123def456"},
+		commitDiffs:         []gitlab.Diff{{NewPath: "a.go"}, {NewPath: "gone.go", DeletedFile: true}},
+		spinning:            true,
+	}
+	_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	if cmd == nil {
+		t.Fatal("o should load the selected file")
+	}
+	m.Update(cmd())
+	if gotRef != "abc123def456" {
+		t.Errorf("file should be fetched at the commit, got ref %q", gotRef)
+	}
+	if !m.viewingFile || m.viewingFileRef != "abc123def456" || m.showCommitDiffPopup {
+		t.Fatalf("file should open pinned to the commit: viewing=%v ref=%q popup=%v", m.viewingFile, m.viewingFileRef, m.showCommitDiffPopup)
+	}
+	if m.currentRef() != "main" {
+		t.Error("opening an old version shouldn't switch the browsed ref")
+	}
+
+	m.showCommitDiffPopup = true
+	pressKeys(m, "n")
+	_, cmd = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	m.Update(cmd())
+	if m.statusMsg != "gone.go didn't exist at abc123de" {
+		t.Errorf("status = %q", m.statusMsg)
+	}
+}
+
+func TestKeyMap_RemappedActions(t *testing.T) {
+	km, err := keymap.LoadKeyMap(&config.LazyLabConfig{Keys: map[string]string{
+		"search": "x",
+		"help":   "f1",
+		"back":   "backspace",
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	m := &MainScreen{
+		keymap:          km,
+		isDemo:          true,
+		selectedProject: &gitlab.Project{ID: 1},
+		focusedPanel:    PanelContent,
+		contentTab:      TabMRs,
+	}
+
+	// The default keys no longer act
+	m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")})
+	if m.mrFilterActive || m.showHelpPopup {
+		t.Fatal("remapped actions shouldn't answer to their default keys")
+	}
+
+	m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if !m.mrFilterActive {
+		t.Fatal("x should open the MR filter")
+	}
+	m.mrFilterActive = false
+
+	m.handleKey(tea.KeyMsg{Type: tea.KeyF1})
+	if !m.showHelpPopup {
+		t.Fatal("f1 should open the help")
+	}
+	m.showHelpPopup = false
+
+	// Back leaves the file view
+	m.contentTab = TabFiles
+	m.viewingFile = true
+	m.handleKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if !m.viewingFile {
+		t.Fatal("esc is no longer bound to back")
+	}
+	m.handleKey(tea.KeyMsg{Type: tea.KeyBackspace})
+	if m.viewingFile {
+		t.Error("backspace should go back to the file list")
+	}
+}
+
+func TestFiles_ExpandDirInPlace(t *testing.T) {
+	api := &mockAPI{tree: []gitlab.TreeEntry{
+		{Name: "a.go", Type: "blob", Path: "src/a.go"},
+		{Name: "lib", Type: "tree", Path: "src/lib"},
+	}}
+	m := &MainScreen{
+		client:          api,
+		keymap:          keymap.DefaultKeyMap(),
+		selectedProject: &gitlab.Project{ID: 1, DefaultBranch: "main"},
+		focusedPanel:    PanelContent,
+		contentTab:      TabFiles,
+		files: []gitlab.TreeEntry{
+			{Name: "src", Type: "tree", Path: "src"},
+			{Name: "README.md", Type: "blob", Path: "README.md"},
+		},
+		spinning: true,
+	}
+
+	_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(" ")})
+	if cmd == nil {
+		t.Fatal("space on a directory should load its entries")
+	}
+	m.Update(cmd())
+	var paths []string
+	for _, f := range m.files {
+		paths = append(paths, f.Path)
+	}
+	if want := []string{"src", "src/a.go", "src/lib", "README.md"}; !slices.Equal(paths, want) {
+		t.Fatalf("files = %v, want %v", paths, want)
+	}
+	if len(m.currentPath) != 0 || m.fileDepth(m.files[2]) != 1 {
+		t.Error("expanding shouldn't navigate into the directory")
+	}
+
+	// Collapsing keeps the cursor on the same entry
+	m.selectedContent = 3
+	m.toggleDir(0)
+	if len(m.files) != 2 || m.selectedContent != 1 {
+		t.Errorf("collapse: %d files, cursor %d", len(m.files), m.selectedContent)
+	}
+
+	// Enter still drills into nested directories
+	m.Update(cmd())
+	m.selectedContent = 2
+	m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if !slices.Equal(m.currentPath, []string{"src", "lib"}) {
+		t.Errorf("currentPath = %v", m.currentPath)
+	}
+}
+
+func TestLastCommits_ArriveIncrementally(t *testing.T) {
+	m := &MainScreen{
+		client:          &mockAPI{},
+		selectedProject: &gitlab.Project{ID: 1, DefaultBranch: "main"},
+		spinning:        true,
+	}
+	entries := []gitlab.TreeEntry{{Name: "a.go", Type: "blob", Path: "src/a.go"}, {Name: "b.go", Type: "blob", Path: "src/b.go"}}
+	_, cmd := m.Update(treeLoadedMsg{entries: entries, path: "src", ref: "main"})
+	if len(m.files) != 2 || cmd == nil {
+		t.Fatal("the tree should show before its commits are fetched")
+	}
+
+	commit := &gitlab.Commit{ID: "abc123", Title: "Fix a"}
+	m.Update(lastCommitMsg{projectID: 1, ref: "main", path: "src/a.go", commit: commit})
+	if m.files[0].LastCommit != commit || m.files[1].LastCommit != nil {
+		t.Errorf("only a.go should be patched, got %+v", m.files)
+	}
+
+	stale := &gitlab.Commit{ID: "def456"}
+	m.Update(lastCommitMsg{projectID: 2, ref: "main", path: "src/b.go", commit: stale})
+	m.Update(lastCommitMsg{projectID: 1, ref: "dev", path: "src/b.go", commit: stale})
+	if m.files[1].LastCommit != nil {
+		t.Error("commits for another project or branch should be ignored")
+	}
+}
+
+// countingCommitsAPI counts last commit fetches, which run concurrently
+type countingCommitsAPI struct {
+	*mockAPI
+	fetched atomic.Int32
+}
+
+func (a *countingCommitsAPI) GetLastCommitForPath(projectID, ref, filePath string) (*gitlab.Commit, error) {
+	a.fetched.Add(1)
+	return nil, nil
+}
+
+func TestLastCommits_StaleListSkipsFetches(t *testing.T) {
+	api := &countingCommitsAPI{mockAPI: &mockAPI{}}
+	m := &MainScreen{
+		client:          api,
+		selectedProject: &gitlab.Project{ID: 1, DefaultBranch: "main"},
+		settings:        config.LazyLabConfig{FetchConcurrency: 2},
+	}
+	entries := []gitlab.TreeEntry{{Path: "a.go"}, {Path: "b.go"}, {Path: "c.go"}}
+	batch, ok := m.loadLastCommits("main", entries)().(tea.BatchMsg)
+	if !ok || len(batch) != len(entries) {
+		t.Fatalf("expected one fetch per entry, got %v", batch)
+	}
+	if m.loadLastCommits("main", entries); cap(m.limiter()) != 2 {
+		t.Errorf("loads should share one limiter of the configured size, got %d slots", cap(m.limiter()))
+	}
+
+	// Navigating away replaces the list before its fetches ran
+	m.selectProject(&gitlab.Project{ID: 2})
+	for _, cmd := range batch {
+		if msg := cmd(); msg != nil {
+			t.Errorf("a fetch for the old list returned %T", msg)
+		}
+	}
+	if n := api.fetched.Load(); n != 0 {
+		t.Errorf("fetches for the old list shouldn't reach the API, got %d", n)
+	}
+}
+
+// groupProjectsAPI tracks how many group project lists are fetched at once
+type groupProjectsAPI struct {
+	gitlab.API
+	inFlight, peak atomic.Int32
+}
+
+func (a *groupProjectsAPI) ListGroupProjects(groupPath string) ([]gitlab.Project, error) {
+	n := a.inFlight.Add(1)
+	defer a.inFlight.Add(-1)
+	for {
+		peak := a.peak.Load()
+		if n <= peak || a.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	return nil, nil
+}
+
+func TestLoadAllGroupProjects_UsesLimiter(t *testing.T) {
+	api := &groupProjectsAPI{}
+	m := &MainScreen{
+		client:         api,
+		settings:       config.LazyLabConfig{FetchConcurrency: 2},
+		expandedGroups: map[int]bool{},
+		groupProjects:  map[int][]gitlab.Project{3: nil}, // already loaded
+	}
+	for id := 1; id <= 6; id++ {
+		m.groups = append(m.groups, gitlab.Group{ID: id, FullPath: fmt.Sprintf("g%d", id)})
+	}
+
+	batch, ok := m.loadAllGroupProjects()().(tea.BatchMsg)
+	if !ok || len(batch) != 5 {
+		t.Fatalf("expected one fetch per unloaded group, got %v", batch)
+	}
+	var wg sync.WaitGroup
+	for _, cmd := range batch {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cmd()
+		}()
+	}
+	wg.Wait()
+	if peak := api.peak.Load(); peak > 2 {
+		t.Errorf("expected at most 2 fetches at once, got %d", peak)
+	}
+}
+
+func TestLoadMore_Threshold(t *testing.T) {
+	m := &MainScreen{
+		keymap:          keymap.DefaultKeyMap(),
+		selectedProject: &gitlab.Project{ID: 1},
+		contentTab:      TabMRs,
+		focusedPanel:    PanelContent,
+		height:          40,
+		spinning:        true,
+		mrNextPage:      2,
+	}
+	visible := m.listVisibleLines()
+	for i := range 3 * visible {
+		m.mergeRequests = append(m.mergeRequests, gitlab.MergeRequest{IID: i + 1})
+	}
+
+	m.selectedContent = 2*visible - 2
+	_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	if cmd != nil || m.loadingMore {
+		t.Fatalf("more than a screen from the end shouldn't load, cursor %d of %d", m.selectedContent, len(m.mergeRequests))
+	}
+	_, cmd = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	if cmd == nil || !m.loadingMore {
+		t.Fatalf("a screen from the end should load page 2, cursor %d of %d", m.selectedContent, len(m.mergeRequests))
+	}
+	if _, cmd = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")}); cmd != nil {
+		t.Error("only one page should be fetched at a time")
+	}
+
+	// Stale pages are dropped, overlapping MRs aren't listed twice
+	m.Update(moreMRsLoadedMsg{projectID: 2, page: 2, mrs: []gitlab.MergeRequest{{IID: 999}}, nextPage: 3})
+	m.Update(moreMRsLoadedMsg{projectID: 1, page: 2, mrs: []gitlab.MergeRequest{{IID: 1}, {IID: 1000}}})
+	if len(m.mergeRequests) != 3*visible+1 || m.mrNextPage != 0 || m.loadingMore {
+		t.Errorf("expected one MR appended and the list complete, got %d MRs, next page %d", len(m.mergeRequests), m.mrNextPage)
+	}
+	m.selectedContent = len(m.mergeRequests) - 2
+	if _, cmd = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")}); cmd != nil {
+		t.Error("nothing should load after the last page")
+	}
+}
+
+func pressKeys(m *MainScreen, keys ...string) {
+	for _, k := range keys {
+		m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+	}
+}
+
+func TestCountPrefix_Navigator(t *testing.T) {
+	m := &MainScreen{keymap: keymap.DefaultKeyMap(), focusedPanel: PanelNavigator}
+	for i := range 60 {
+		m.treeNodes = append(m.treeNodes, TreeNode{Type: "group", ID: i + 1})
+	}
+
+	pressKeys(m, "5", "j")
+	if m.selectedNodeIdx != 5 {
+		t.Errorf("5j: got %d, want 5", m.selectedNodeIdx)
+	}
+	pressKeys(m, "4", "2", "G")
+	if m.selectedNodeIdx != 41 {
+		t.Errorf("42G: got %d, want 41", m.selectedNodeIdx)
+	}
+	// A non-motion key drops the count
+	pressKeys(m, "5", "x", "j")
+	if m.selectedNodeIdx != 42 {
+		t.Errorf("5xj: got %d, want 42", m.selectedNodeIdx)
+	}
+	// Panel keys extend a pending count instead of switching panels
+	pressKeys(m, "4", "1", "k")
+	if m.selectedNodeIdx != 1 || m.focusedPanel != PanelNavigator {
+		t.Errorf("41k: got %d in panel %d, want 1 in navigator", m.selectedNodeIdx, m.focusedPanel)
+	}
+	pressKeys(m, "G")
+	if m.selectedNodeIdx != 59 {
+		t.Errorf("G: got %d, want 59", m.selectedNodeIdx)
+	}
+	if m.countPrefix != 0 {
+		t.Errorf("count should be consumed, got %d", m.countPrefix)
+	}
+
+	// Without a count, digits still focus panels
+	pressKeys(m, "2")
+	if m.focusedPanel != PanelContent {
+		t.Errorf("2 should focus content, got %d", m.focusedPanel)
+	}
+}
+
+func TestCountPrefix_JobLog(t *testing.T) {
+	var lines []string
+	for i := range 100 {
+		lines = append(lines, fmt.Sprintf("line %d", i+1))
+	}
+	m := &MainScreen{
+		showJobLogPopup: true,
+		jobLogFocused:   true,
+		jobLog:          strings.Join(lines, "\n"),
+		jobLogViewport:  viewport.New(80, 10),
+		jobLogHScroll:   40,
+	}
+
+	pressKeys(m, "1", "0", "G")
+	if m.jobLogCursor != 9 {
+		t.Errorf("10G: got %d, want 9", m.jobLogCursor)
+	}
+	pressKeys(m, "3", "j")
+	if m.jobLogCursor != 12 {
+		t.Errorf("3j: got %d, want 12", m.jobLogCursor)
+	}
+	// A lone 0 is still "start of line"
+	pressKeys(m, "0")
+	if m.jobLogHScroll != 0 || m.jobLogCursor != 12 || m.countPrefix != 0 {
+		t.Errorf("0: hscroll=%d cursor=%d count=%d", m.jobLogHScroll, m.jobLogCursor, m.countPrefix)
+	}
+	pressKeys(m, "2", "0", "g", "g")
+	if m.jobLogCursor != 19 || m.jobLogLastKey != "" {
+		t.Errorf("20gg: cursor=%d lastKey=%q, want 19 and no ggy", m.jobLogCursor, m.jobLogLastKey)
+	}
+	// gg without a count still arms ggy
+	pressKeys(m, "g", "g")
+	if m.jobLogCursor != 0 || m.jobLogLastKey != "gg" {
+		t.Errorf("gg: cursor=%d lastKey=%q", m.jobLogCursor, m.jobLogLastKey)
+	}
+	pressKeys(m, "5", "0", "0", "j")
+	if m.jobLogCursor != 99 {
+		t.Errorf("500j should clamp to the last line, got %d", m.jobLogCursor)
+	}
+}
+
+func TestProjectInfoRows_MissingFields(t *testing.T) {
+	rows := projectInfoRows(&gitlab.Project{StarCount: 3, Visibility: "public"})
+	got := make(map[string]string)
+	for _, r := range rows {
+		got[r[0]] = r[1]
+	}
+
+	if got["Stars"] != "3" || got["Forks"] != "0" || got["Visibility"] != "public" {
+		t.Errorf("unexpected rows: %v", got)
+	}
+	for _, label := range []string{"Last activity", "Default branch", "SSH", "HTTPS"} {
+		if got[label] != "—" {
+			t.Errorf("%s = %q, want —", label, got[label])
+		}
+	}
+}
+
+// projectByPathAPI serves single projects by path
+type projectByPathAPI struct {
+	gitlab.API
+	projects map[string]gitlab.Project
+}
+
+func (a *projectByPathAPI) GetProject(projectID string) (*gitlab.Project, error) {
+	p, ok := a.projects[projectID]
+	if !ok {
+		return nil, gitlab.ErrNotFound
+	}
+	return &p, nil
+}
+
+func TestProjectInfo_UnloadedFavorite(t *testing.T) {
+	m := &MainScreen{
+		client:          &projectByPathAPI{projects: map[string]gitlab.Project{"acme/web": {ID: 11, PathWithNamespace: "acme/web"}}},
+		keymap:          keymap.DefaultKeyMap(),
+		focusedPanel:    PanelNavigator,
+		selectedProject: &gitlab.Project{ID: 10, PathWithNamespace: "acme/api"},
+		treeNodes:       []TreeNode{{Type: "project", FullPath: "acme/web", Favorite: true}},
+	}
+
+	_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	if m.showProjectInfo || cmd == nil {
+		t.Fatal("expected the favorite to be fetched before showing its info")
+	}
+	m.Update(cmd())
+	if !m.showProjectInfo || m.infoProject.ID != 11 {
+		t.Errorf("expected the favorite's info, got %+v", m.infoProject)
+	}
+	if m.selectedProject.ID != 10 {
+		t.Error("showing info shouldn't open the favorite")
+	}
+}
+
+func TestReadmeSummary(t *testing.T) {
+	readme := "# Title\n\n[![build](badge.svg)](ci)\n\nA fast API\ngateway in Go.\n\n## Install\n\nRun it.\n"
+	if got := readmeSummary(readme); got != "A fast API\ngateway in Go." {
+		t.Errorf("got %q", got)
+	}
+	if got := readmeSummary("# Only a heading\n"); got != "" {
+		t.Errorf("heading only: got %q", got)
+	}
+}
+
+func TestDownloadProgressText(t *testing.T) {
+	got := downloadProgressText("app.tar.gz", 5*1024*1024, 10*1024*1024)
+	for _, want := range []string{"app.tar.gz", "50%", "5.0 MB / 10.0 MB", "██████████░░░░░░░░░░"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("%q missing %q", got, want)
+		}
+	}
+	// Unknown size shows only the bytes so far
+	if got := downloadProgressText("app.tar.gz", 2048, -1); !strings.Contains(got, "2.0 KB") || strings.Contains(got, "%") {
+		t.Errorf("unknown total: got %q", got)
+	}
+}
+
+func TestDownload_EscCancels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "1000000")
+		_, _ = w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	dest := filepath.Join(t.TempDir(), "app.tar.gz")
+	m := &MainScreen{client: gitlab.NewClient(server.URL, "token"), keymap: keymap.DefaultKeyMap(), loading: true}
+	cmd := m.startDownload(server.URL, "app.tar.gz", dest, "")
+	m.handleKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.downloadCancel != nil {
+		t.Fatal("Esc should cancel the download")
+	}
+
+	// Drain progress until the download reports back
+	for cmd != nil {
+		_, cmd = m.Update(cmd())
+	}
+	if m.loading || m.downloadUpdates != nil {
+		t.Error("download state should be cleared")
+	}
+	if !strings.Contains(m.statusMsg, "cancelled") {
+		t.Errorf("statusMsg = %q", m.statusMsg)
+	}
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Errorf("partial file should be removed, stat err = %v", err)
+	}
+}
+
+func TestParseChecksum(t *testing.T) {
+	const sum = "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+	tests := []struct {
+		name    string
+		data    string
+		want    string
+		wantErr bool
+	}{
+		{"bare digest", sum + "\n", sum, false},
+		{"sha256sum line", sum + "  app.tar.gz\n", sum, false},
+		{"binary mode and path", strings.ToUpper(sum) + " *dist/app.tar.gz", sum, false},
+		{"picks the right file", strings.Repeat("0", 64) + "  other.zip\n" + sum + "  app.tar.gz", sum, false},
+		{"other file only", sum + "  other.zip", "", true},
+		{"not a digest", "hello", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseChecksum(tt.data, "app.tar.gz")
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("parseChecksum() = %q, %v; want %q, error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDownload_VerifiesChecksum(t *testing.T) {
+	for _, tt := range []struct {
+		checksum string
+		want     string
+	}{
+		{"b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9  app.tar.gz\n", "SHA-256 verified"},
+		{strings.Repeat("0", 64) + "  app.tar.gz\n", "SHA-256 MISMATCH"},
+		{"garbage", "checksum not verified"},
+	} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, ".sha256") {
+				_, _ = w.Write([]byte(tt.checksum))
+				return
+			}
+			_, _ = w.Write([]byte("hello world"))
+		}))
+
+		dest := filepath.Join(t.TempDir(), "app.tar.gz")
+		m := &MainScreen{client: gitlab.NewClient(server.URL, "token"), loading: true, spinning: true}
+		cmd := m.startDownload(server.URL+"/app.tar.gz", "app.tar.gz", dest, server.URL+"/app.tar.gz.sha256")
+		for cmd != nil {
+			_, cmd = m.Update(cmd())
+		}
+		server.Close()
+
+		if !strings.Contains(m.statusMsg, "Downloaded app.tar.gz") || !strings.Contains(m.statusMsg, tt.want) {
+			t.Errorf("statusMsg = %q, want %q", m.statusMsg, tt.want)
+		}
+	}
+}
+
+func TestReleaseChecksumURL(t *testing.T) {
+	m := &MainScreen{releases: []gitlab.Release{{
+		Assets: gitlab.ReleaseAssets{Links: []gitlab.ReleaseAssetLink{
+			{Name: "app.tar.gz", URL: "https://example.com/app.tar.gz"},
+			{Name: "app.tar.gz.SHA256", URL: "https://example.com/app.tar.gz.sha256"},
+		}},
+	}}}
+	if got := m.getSelectedReleaseChecksumURL("app.tar.gz"); got != "https://example.com/app.tar.gz.sha256" {
+		t.Errorf("checksum URL = %q", got)
+	}
+	if got := m.getSelectedReleaseChecksumURL("app.zip"); got != "" {
+		t.Errorf("checksum URL for asset without one = %q", got)
+	}
+}
+
+func TestProjectStatsBadge(t *testing.T) {
+	tests := []struct {
+		stats *gitlab.ProjectStatistics
+		want  string
+	}{
+		{nil, ""},
+		{&gitlab.ProjectStatistics{}, ""},
+		{&gitlab.ProjectStatistics{CommitCount: 1203, RepositorySize: 13002342}, " · 12.4 MB · 1,203 commits"},
+		{&gitlab.ProjectStatistics{CommitCount: 1, RepositorySize: 512}, " · 512 B · 1 commit"},
+		{&gitlab.ProjectStatistics{CommitCount: 1234567, RepositorySize: 3 << 30}, " · 3.0 GB · 1,234,567 commits"},
+	}
+	for _, tt := range tests {
+		if got := projectStatsBadge(tt.stats); got != tt.want {
+			t.Errorf("projectStatsBadge(%+v) = %q, want %q", tt.stats, got, tt.want)
+		}
+	}
+}
+
+func TestRefJump_UnknownRefSurfacesError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"404 Tree Not Found"}`))
+	}))
+	defer server.Close()
+
+	m := &MainScreen{
+		client:          gitlab.NewClient(server.URL, "token"),
+		keymap:          keymap.DefaultKeyMap(),
+		focusedPanel:    PanelContent,
+		contentTab:      TabFiles,
+		selectedProject: &gitlab.Project{ID: 1, DefaultBranch: "main"},
+		currentBranch:   "main",
+	}
+
+	pressKeys(m, "g")
+	if !m.refJumpActive {
+		t.Fatal("g should open the ref prompt")
+	}
+	pressKeys(m, "v", "9", ".", "9")
+	_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.refJumpActive || m.currentBranch != "v9.9" || cmd == nil {
+		t.Fatalf("enter should load v9.9: active=%v ref=%q", m.refJumpActive, m.currentBranch)
+	}
+	if msg, ok := cmd().(errMsg); !ok || !errors.Is(msg.err, gitlab.ErrNotFound) {
+		t.Errorf("unknown ref should surface a not-found error, got %#v", msg)
+	}
+
+	// The default branch of an empty repository still reads as empty
+	if msg, ok := m.loadProjectContentForBranch("main")().(projectContentMsg); !ok || !msg.empty {
+		t.Errorf("default branch 404 should be an empty repo, got %#v", msg)
+	}
+}
+
+func TestReadme_RendersForPanelWidth(t *testing.T) {
+	m := &MainScreen{readmeContent: "# Title\n\n" +
+		"- [x] done\n- [ ] todo\n\n" +
+		"| Option | Description | Default |\n|---|---|---|\n" +
+		"| `timeout` | How long to wait before giving up on a request | 30s |\n"}
+
+	maxWidth := func() int {
+		w := 0
+		for _, line := range strings.Split(m.readmeRendered, "\n") {
+			w = max(w, lipgloss.Width(line))
+		}
+		return w
+	}
+
+	m.renderReadmeSection(60, 20)
+	if got := maxWidth(); got > 56 {
+		t.Errorf("README rendered %d wide in a 56-column panel", got)
+	}
+	if !strings.Contains(m.readmeRendered, "✓") && !strings.Contains(m.readmeRendered, "[x]") {
+		t.Errorf("task list item missing from render:\n%s", m.readmeRendered)
+	}
+
+	// A resize reflows the README instead of keeping the old width
+	m.renderReadmeSection(100, 20)
+	if got := maxWidth(); got <= 56 || got > 96 {
+		t.Errorf("README rendered %d wide after resizing to a 96-column panel", got)
+	}
+	if m.readmeViewport.Width != 96 {
+		t.Errorf("viewport width = %d, want 96", m.readmeViewport.Width)
+	}
+}
+
+func TestReadme_HorizontalScroll(t *testing.T) {
+	m := &MainScreen{
+		keymap:        keymap.DefaultKeyMap(),
+		focusedPanel:  PanelReadme,
+		readmeContent: "# Title\n\n```\n" + strings.Repeat("x", 100) + "END\n```\n",
+	}
+	m.renderReadmeSection(60, 20)
+	if m.readmeMaxWidth <= 56 {
+		t.Fatalf("code block should overflow the panel, widest line is %d", m.readmeMaxWidth)
+	}
+
+	pressKeys(m, "l")
+	if m.readmeHScroll != config.SidewaysScrollStep || m.focusedPanel != PanelReadme {
+		t.Errorf("l should scroll a wide README, got offset %d panel %v", m.readmeHScroll, m.focusedPanel)
+	}
+	pressKeys(m, "$")
+	if m.readmeHScroll != m.readmeMaxWidth-56 {
+		t.Errorf("$ should show the end of the longest line, got %d", m.readmeHScroll)
+	}
+	if view := m.renderReadmeSection(60, 20); !strings.Contains(view, "END") {
+		t.Errorf("end of the code block not visible after $:\n%s", view)
+	}
+
+	// At the edge l moves on to the next panel as before
+	pressKeys(m, "l")
+	if m.focusedPanel != PanelContent {
+		t.Errorf("l at the end should focus the content panel, got %v", m.focusedPanel)
+	}
+	m.focusedPanel = PanelReadme
+	pressKeys(m, "0", "h")
+	if m.readmeHScroll != 0 || m.focusedPanel != PanelNavigator {
+		t.Errorf("h at the start should focus the navigator, got offset %d panel %v", m.readmeHScroll, m.focusedPanel)
+	}
+}
+
+func TestErrorSummary(t *testing.T) {
+	markers := []string{"ERROR", "FAILED", "error:"}
+	tests := []struct {
+		name string
+		log  string
+		want []string
+	}{
+		{"from last marker", "ok\nERROR: first\nmore\n\x1b[31mmain.go:3: error: bad\x1b[0m\nexit 1\n\n",
+			[]string{"main.go:3: error: bad", "exit 1"}},
+		{"no marker uses tail", "a\nb\nc\nd\n", []string{"c", "d"}},
+		{"short log without marker", "only\n", []string{"only"}},
+		{"markers are case sensitive", "a\nError here\nb\nc", []string{"b", "c"}},
+		{"runner epilogue is skipped", "$ go test ./...\n--- FAIL: TestLogin\n    login_test.go:12: error: want 200, got 500\nFAIL\tapp\t0.01s\n" +
+			"section_end:1700000000:step_script\r\x1b[0K\x1b[0Ksection_start:1700000001:cleanup_file_variables\r\x1b[0K\x1b[0K\x1b[36;1mCleaning up project directory and file based variables\x1b[0;m\n" +
+			"section_end:1700000001:cleanup_file_variables\r\x1b[0K\n\x1b[31;1mERROR: Job failed: exit code 1\n\x1b[0;m\n",
+			[]string{"    login_test.go:12: error: want 200, got 500", "FAIL\tapp\t0.01s"}},
+		{"only epilogue", "ERROR: Job failed: exit code 1\n\n", []string{"ERROR: Job failed: exit code 1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorSummary(tt.log, markers, 2); !slices.Equal(got, tt.want) {
+				t.Errorf("errorSummary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResizePanel(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := &MainScreen{
+		keymap:        keymap.DefaultKeyMap(),
+		width:         200,
+		height:        41,
+		focusedPanel:  PanelNavigator,
+		contentTab:    TabFiles,
+		readmeContent: "# Hello",
+	}
+
+	pressKeys(m, ">")
+	if got := m.navigatorWidth(); got != 40 {
+		t.Errorf("navigator width after > = %d, want 40", got)
+	}
+	pressKeys(m, "<", "<", "<", "<")
+	if got := m.navigatorWidth(); got != 20 {
+		t.Errorf("navigator width should clamp at 10%%, got %d", got)
+	}
+
+	// Growing the content panel moves the same boundary the other way
+	m.focusedPanel = PanelContent
+	pressKeys(m, ">")
+	if got := m.navigatorWidth(); got != 20 {
+		t.Errorf("navigator width at the minimum should stay 20, got %d", got)
+	}
+	pressKeys(m, "<")
+	if got := m.navigatorWidth(); got != 30 {
+		t.Errorf("shrinking content should widen the navigator, got %d", got)
+	}
+
+	m.focusedPanel = PanelReadme
+	pressKeys(m, ">", ">")
+	if got := m.contentListHeight(40); got != 12 {
+		t.Errorf("list height with a 70%% README = %d, want 12", got)
+	}
+
+	cfg, err := config.LoadLazyLabConfig()
+	if err != nil {
+		t.Fatalf("layout was not saved: %v", err)
+	}
+	if cfg.NavigatorWidthRatio != 0.15 || cfg.ReadmeHeightRatio != 0.7 {
+		t.Errorf("saved ratios = %v, %v", cfg.NavigatorWidthRatio, cfg.ReadmeHeightRatio)
+	}
+
+	// The list scrolls within the space left by the README
+	m.files = make([]gitlab.TreeEntry, 30)
+	m.selectedContent = 20
+	m.adjustScrollOffset()
+	if m.fileScrollOffset != 20-(12-6)+1 {
+		t.Errorf("scroll offset = %d, want %d", m.fileScrollOffset, 20-(12-6)+1)
+	}
+}
+
+func TestSpinner_TicksOnlyWhileLoading(t *testing.T) {
+	m := &MainScreen{
+		keymap:     keymap.DefaultKeyMap(),
+		spinner:    spinner.New(spinner.WithSpinner(spinner.Dot)),
+		loading:    true,
+		loadingMsg: "Loading groups...",
+	}
+
+	_, cmd := m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	if cmd == nil || !m.spinning {
+		t.Fatal("loading should start the spinner")
+	}
+	tick, ok := cmd().(spinner.TickMsg)
+	if !ok {
+		t.Fatalf("expected a spinner tick, got %T", cmd())
+	}
+	if _, cmd := m.Update(tea.WindowSizeMsg{Width: 80, Height: 24}); cmd != nil {
+		t.Error("a running spinner should not be started twice")
+	}
+	if _, cmd := m.Update(tick); cmd == nil {
+		t.Error("the spinner should keep ticking while loading")
+	}
+	if !strings.Contains(m.renderStatusBar(), "Loading groups...") {
+		t.Error("status bar should show the loading message")
+	}
+
+	m.loading = false
+	if _, cmd := m.Update(tick); cmd != nil || m.spinning {
+		t.Error("the spinner should stop once loading ends")
+	}
+}
+
+func TestDownload_ConfirmsOverwrite(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("new"))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	dest := filepath.Join(dir, "app.tar.gz")
+	if err := os.WriteFile(dest, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := &MainScreen{
+		client:            gitlab.NewClient(server.URL, "token"),
+		keymap:            keymap.DefaultKeyMap(),
+		showFolderBrowser: true,
+		folderBrowserPath: dir,
+		downloadURL:       server.URL,
+		downloadFilename:  "app.tar.gz",
+	}
+
+	pressKeys(m, "d")
+	if !m.confirm.Active || m.loading {
+		t.Fatal("an existing file should be confirmed before downloading")
+	}
+	_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	m.Update(cmd())
+	if m.loading || m.statusMsg != "Download cancelled" {
+		t.Errorf("declining should cancel: loading=%v status=%q", m.loading, m.statusMsg)
+	}
+
+	m.showFolderBrowser = true
+	pressKeys(m, "d")
+	_, cmd = m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	_, cmd = m.Update(cmd())
+	for cmd != nil {
+		msg := cmd()
+		// The download comes before the spinner tick
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			cmd = batch[0]
+			continue
+		}
+		_, cmd = m.Update(msg)
+	}
+	if data, _ := os.ReadFile(dest); string(data) != "new" {
+		t.Errorf("confirming should overwrite the file, got %q", data)
+	}
+}
+
+func TestFolderBrowser_ToggleHidden(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{".local", "docs", "src"} {
+		if err := os.Mkdir(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m := &MainScreen{
+		keymap:            keymap.DefaultKeyMap(),
+		height:            40,
+		showFolderBrowser: true,
+		folderBrowserPath: dir,
+	}
+	m.loadFolderEntries()
+	if len(m.folderBrowserEntries) != 2 || m.folderBrowserEntries[0] != "docs" {
+		t.Fatalf("hidden directories should be skipped by default, got %v", m.folderBrowserEntries)
+	}
+
+	pressKeys(m, "j", ".")
+	if len(m.folderBrowserEntries) != 3 || m.folderBrowserEntries[0] != ".local" {
+		t.Fatalf("'.' should include hidden directories, got %v", m.folderBrowserEntries)
+	}
+	if m.folderBrowserEntries[m.folderBrowserCursor] != "src" {
+		t.Errorf("cursor should stay on src, got %q", m.folderBrowserEntries[m.folderBrowserCursor])
+	}
+
+	pressKeys(m, "g", ".")
+	if len(m.folderBrowserEntries) != 2 || m.folderBrowserCursor != 0 {
+		t.Errorf("hiding .local should reset the cursor: entries=%v cursor=%d", m.folderBrowserEntries, m.folderBrowserCursor)
+	}
+}
+
+func TestFileViewer_SaveToDisk(t *testing.T) {
+	raw := []byte{0x89, 'P', 'N', 'G', 0, 1, 2}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(raw)
+	}))
+	defer server.Close()
+
+	save := func(m *MainScreen, dir, name string) {
+		t.Helper()
+		pressKeys(m, "ctrl+s")
+		if !m.showFolderBrowser || m.downloadFilename != name {
+			t.Fatalf("ctrl+s should open the folder browser, got filename %q", m.downloadFilename)
+		}
+		m.folderBrowserPath = dir
+		_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+		if cmd == nil {
+			t.Fatal("choosing a folder should save the file")
+		}
+		m.Update(cmd())
+	}
+
+	m := &MainScreen{
+		client:          gitlab.NewClient(server.URL, "token"),
+		keymap:          keymap.DefaultKeyMap(),
+		selectedProject: &gitlab.Project{ID: 1},
+		contentTab:      TabFiles,
+		focusedPanel:    PanelContent,
+		spinning:        true,
+	}
+	m.Update(fileContentMsg{path: "cmd/main.go", content: "package main\n"})
+	dir := t.TempDir()
+	save(m, dir, "main.go")
+	if data, _ := os.ReadFile(filepath.Join(dir, "main.go")); string(data) != "package main\n" {
+		t.Errorf("text files should be written as shown, got %q", data)
+	}
+	if m.statusMsg != "Downloaded main.go (13 bytes)" {
+		t.Errorf("status = %q", m.statusMsg)
+	}
+
+	m.Update(fileContentMsg{path: "assets/logo.png", content: string(raw)})
+	save(m, dir, "logo.png")
+	if data, _ := os.ReadFile(filepath.Join(dir, "logo.png")); !bytes.Equal(data, raw) {
+		t.Errorf("binary files should be re-fetched raw, got %q", data)
+	}
+}
+
+func TestUITick_RedrawsWithoutFetching(t *testing.T) {
+	api := &mockAPI{}
+	m := &MainScreen{client: api, selectedProject: &gitlab.Project{ID: 1}, contentTab: TabPipelines, spinning: true}
+	_, cmd := m.Update(uiTickMsg{})
+	if cmd == nil {
+		t.Fatal("the tick should reschedule itself")
+	}
+	if m.loading || len(api.calls) != 0 {
+		t.Errorf("the tick shouldn't hit the API, got calls %v", api.calls)
+	}
+}
+
+func TestIdle_PausesAndResumesPolling(t *testing.T) {
+	api := &mockAPI{}
+	m := &MainScreen{
+		client:          api,
+		keymap:          keymap.DefaultKeyMap(),
+		selectedProject: &gitlab.Project{ID: 1},
+		contentTab:      TabPipelines,
+		idleTimeout:     time.Minute,
+		lastInput:       time.Now().Add(-2 * time.Minute),
+		spinning:        true,
+	}
+	m.Update(uiTickMsg{})
+	if !m.idle {
+		t.Fatal("no input for longer than the timeout should go idle")
+	}
+
+	_, cmd := m.Update(pipelineTickMsg{})
+	if cmd != nil || len(api.calls) != 0 || len(m.idleStopped) != 1 {
+		t.Fatalf("the pipeline ticker should stop while idle, got calls %v", api.calls)
+	}
+
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	if m.idle || len(m.idleStopped) != 0 || cmd == nil {
+		t.Error("input should resume and re-arm the stopped ticker once")
+	}
+	m.Update(uiTickMsg{})
+	if m.idle {
+		t.Error("recent input shouldn't go idle")
+	}
+}
+
+func TestIdle_ResumeDropsSupersededTick(t *testing.T) {
+	api := &mockAPI{}
+	m := &MainScreen{
+		client:           api,
+		keymap:           keymap.DefaultKeyMap(),
+		showRunnersPopup: true,
+		idle:             true,
+		idleTimeout:      time.Minute,
+		spinning:         true,
+	}
+	stale := m.runnersGen
+	m.Update(runnersTickMsg{gen: stale})
+	if len(m.idleStopped) != 1 {
+		t.Fatal("the runners ticker should stop while idle")
+	}
+
+	// 'r' resumes and starts a new chain, so the re-armed tick belongs to a retired one
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	if m.runnersGen == stale {
+		t.Fatal("refreshing should start a new chain")
+	}
+	if _, cmd := m.Update(runnersTickMsg{gen: stale}); cmd != nil || len(api.calls) != 0 {
+		t.Errorf("the re-armed tick should be dropped, got calls %v", api.calls)
+	}
+	if _, cmd := m.Update(runnersLoadedMsg{gen: stale}); cmd != nil {
+		t.Error("a result from the retired chain shouldn't schedule a tick")
+	}
+	if _, cmd := m.Update(runnersTickMsg{gen: m.runnersGen}); cmd == nil {
+		t.Error("the new chain should keep ticking")
+	}
+}
+
+func TestMyMRs_RefreshKeepsOneChain(t *testing.T) {
+	m := &MainScreen{client: &mockAPI{}, keymap: keymap.DefaultKeyMap(), showMyMRsPopup: true, spinning: true}
+	m.loadMyMRs()
+	if _, cmd := m.Update(myMRsLoadedMsg{gen: m.myMRsGen}); cmd == nil {
+		t.Fatal("a load should schedule the next tick")
+	}
+
+	// 'r' starts a new chain; the one already ticking has to stop
+	stale := m.myMRsGen
+	m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	if _, cmd := m.Update(myMRsTickMsg{gen: stale}); cmd != nil {
+		t.Error("a tick from the old chain should be dropped")
+	}
+	if _, cmd := m.Update(myMRsLoadedMsg{gen: stale}); cmd != nil {
+		t.Error("a result from the old chain shouldn't schedule a tick")
+	}
+	if _, cmd := m.Update(myMRsLoadedMsg{gen: m.myMRsGen}); cmd == nil {
+		t.Error("the new chain should keep ticking")
+	}
+	if _, cmd := m.Update(myMRsTickMsg{gen: m.myMRsGen}); cmd == nil {
+		t.Error("a current tick should refresh")
+	}
+}
+
+func TestClampHScroll(t *testing.T) {
+	widest := maxLineWidth([]string{"short", strings.Repeat("x", 130), "\x1b[31m" + strings.Repeat("y", 90) + "\x1b[0m"})
+	if widest != 130 {
+		t.Fatalf("maxLineWidth = %d, want 130", widest)
+	}
+	tests := []struct {
+		offset, viewWidth, want int
+	}{
+		{20, 100, 20},
+		{40, 100, 30},  // past the end of the longest line
+		{-20, 100, 0},  // before the start
+		{20, 200, 0},   // everything fits
+		{200, 130, 0},  // exactly fits
+		{1000, 50, 80}, // clamps to the last full view
+	}
+	for _, tt := range tests {
+		if got := clampHScroll(tt.offset, widest, tt.viewWidth); got != tt.want {
+			t.Errorf("clampHScroll(%d, %d, %d) = %d, want %d", tt.offset, widest, tt.viewWidth, got, tt.want)
+		}
+	}
+}
+
+func TestJobLog_HorizontalScrollStopsAtLongestLine(t *testing.T) {
+	m := &MainScreen{
+		keymap:          keymap.DefaultKeyMap(),
+		showJobLogPopup: true,
+		jobLogFocused:   true,
+		settings:        config.LazyLabConfig{SidewaysScrollStep: 15},
+		jobLog:          "short\n" + strings.Repeat("x", 100),
+		jobLogViewport:  viewport.New(60, 10),
+	}
+	m.setJobLogContent()
+
+	pressKeys(m, "l")
+	if m.jobLogHScroll != 15 {
+		t.Errorf("l should scroll by the configured step, got %d", m.jobLogHScroll)
+	}
+	pressKeys(m, "l", "l", "l", "l")
+	if m.jobLogHScroll != 40 {
+		t.Errorf("scrolling should stop at the end of the longest line, got %d", m.jobLogHScroll)
+	}
+	pressKeys(m, "0", "$")
+	if m.jobLogHScroll != 40 {
+		t.Errorf("$ should show the end of the longest line, got %d", m.jobLogHScroll)
+	}
+}
+
+func TestJobLog_StageFilter(t *testing.T) {
+	m := &MainScreen{
+		keymap:          keymap.DefaultKeyMap(),
+		showJobLogPopup: true,
+		isDemo:          true,
+		width:           120,
+		height:          30,
+	}
+	m.Update(jobsLoadedMsg{jobs: []gitlab.Job{
+		{ID: 1, Name: "build", Stage: "build", Status: "success"},
+		{ID: 2, Name: "unit", Stage: "test", Status: "success"},
+		{ID: 3, Name: "lint", Stage: "test", Status: "failed"},
+		{ID: 4, Name: "deploy", Stage: "deploy", Status: "manual"},
+	}})
+
+	// The first stage keeps the selected build job
+	pressKeys(m, "f")
+	if m.jobStageFilter != "build" || m.selectedJobIdx != 0 {
+		t.Fatalf("expected build stage, got %q job %d", m.jobStageFilter, m.selectedJobIdx)
+	}
+	// The next stage moves the selection into it and j stays within it
+	pressKeys(m, "f")
+	if m.jobStageFilter != "test" || m.selectedJobIdx != 1 {
+		t.Fatalf("expected test stage with unit selected, got %q job %d", m.jobStageFilter, m.selectedJobIdx)
+	}
+	pressKeys(m, "j", "j")
+	if m.selectedJobIdx != 2 {
+		t.Errorf("expected j to stop at the last test job, got %d", m.selectedJobIdx)
+	}
+	view := m.View()
+	if !strings.Contains(view, "Jobs: test (2/4)") || strings.Contains(view, "deploy (manual)") {
+		t.Error("expected the job list narrowed to the test stage")
+	}
+
+	// Past the last stage the filter clears, and a new pipeline resets it
+	pressKeys(m, "f", "f")
+	if m.jobStageFilter != "" {
+		t.Errorf("expected filter cleared after the last stage, got %q", m.jobStageFilter)
+	}
+	pressKeys(m, "f")
+	m.Update(jobsLoadedMsg{jobs: []gitlab.Job{{ID: 5, Stage: "build"}}})
+	if m.jobStageFilter != "" {
+		t.Errorf("expected filter reset for a new pipeline, got %q", m.jobStageFilter)
+	}
+}
+
+func TestLoadCredentials_GlabAPIHost(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv(config.EnvGitLabHost, "")
+	t.Setenv(config.EnvGitLabToken, "")
+	t.Setenv(config.EnvGitLabTokenFile, "")
+
+	glabDir := filepath.Join(tmpDir, ".config", "glab-cli")
+	if err := os.MkdirAll(glabDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	glabConfig := `host: git.corp.example
+hosts:
+  git.corp.example:
+    token: glab-token
+    api_host: api.git.corp.example:8443
+    api_protocol: http
+  gitlab.com:
+    token: other-token
+`
+	if err := os.WriteFile(filepath.Join(glabDir, "config.yml"), []byte(glabConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	token, host := loadCredentials()
+	if token != "glab-token" {
+		t.Errorf("expected token from glab, got %q", token)
+	}
+	if host != "http://api.git.corp.example:8443" {
+		t.Errorf("expected base URL from api_protocol and api_host, got %q", host)
+	}
+
+	// A host chosen elsewhere keeps its URL even when glab supplies the token
+	t.Setenv(config.EnvGitLabHost, "git.corp.example")
+	if token, host := loadCredentials(); token != "glab-token" || host != "https://git.corp.example" {
+		t.Errorf("expected GITLAB_HOST to be kept, got %q, %q", token, host)
+	}
+}
+
+func TestSnapshotScreen_LoadsProjectFromDisk(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	files := map[string]string{
+		"projects.json":   `[{"id": 7, "name": "api", "path_with_namespace": "acme/api", "default_branch": "main"}]`,
+		"tree.json":       `[{"name": "main.go", "type": "blob", "path": "main.go"}, {"name": "README.md", "type": "blob", "path": "README.md"}]`,
+		"files/README.md": "# API\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m, err := NewSnapshotScreen(dir)
+	if err != nil {
+		t.Fatalf("NewSnapshotScreen: %v", err)
+	}
+	project, err := m.client.GetProject("acme/api")
+	if err != nil {
+		t.Fatalf("GetProject: %v", err)
+	}
+	m.selectedProject = project
+
+	msg, ok := m.loadProjectContent()().(projectContentMsg)
+	if !ok {
+		t.Fatalf("expected projectContentMsg, got %#v", msg)
+	}
+	if len(msg.entries) != 2 || msg.readme != "# API\n" {
+		t.Errorf("unexpected content: %d entries, readme %q", len(msg.entries), msg.readme)
+	}
+
+	if _, err := NewSnapshotScreen(filepath.Join(dir, "missing")); err == nil {
+		t.Error("a missing snapshot directory should be an error")
+	}
+}
+
+func TestSnapshotScreen_LeavesFavoritesAlone(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	real := []config.Favorite{{ID: 42, Path: "corp/secret"}}
+	if err := config.SaveLazyLabConfig(&config.LazyLabConfig{Favorites: real}); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "projects.json"), []byte(`[{"id": 7, "name": "api", "path_with_namespace": "acme/api"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := NewSnapshotScreen(dir)
+	if err != nil {
+		t.Fatalf("NewSnapshotScreen: %v", err)
+	}
+	if len(m.favorites) != 0 {
+		t.Errorf("real favorites shouldn't show in a snapshot, got %v", m.favorites)
+	}
+
+	m.toggleFavorite(TreeNode{Type: "project", ID: 7, FullPath: "acme/api"})
+	if !m.isFavorite(7) {
+		t.Error("a snapshot project should still be favorited for the session")
+	}
+	cfg, err := config.LoadLazyLabConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(cfg.Favorites, real) {
+		t.Errorf("snapshot favorites leaked into the config: %v", cfg.Favorites)
+	}
+}
+
+// mockAPI serves groups from memory. Methods it doesn't override panic
+// through the nil embedded API, so a test fails loudly on unexpected calls.
+type mockAPI struct {
+	gitlab.API
+	groups    []gitlab.Group
+	projects  []gitlab.Project
+	tree      []gitlab.TreeEntry
+	pipelines []gitlab.Pipeline
+	jobs      []gitlab.Job
+	jobsErr   error // returned when listing jobs across projects
+	raw       []byte
+	err       error
+	calls     []string
+}
+
+func (a *mockAPI) GetFileRawLimited(projectID, filePath, ref string, maxBytes int64) ([]byte, error) {
+	a.calls = append(a.calls, fmt.Sprintf("GetFileRawLimited %s %d", filePath, maxBytes))
+	if int64(len(a.raw)) > maxBytes {
+		return nil, gitlab.ErrFileTooLarge
+	}
+	return a.raw, a.err
+}
+
+func (a *mockAPI) ListGroups() ([]gitlab.Group, error) {
+	a.calls = append(a.calls, "ListGroups")
+	return a.groups, a.err
+}
+
+func (a *mockAPI) GetGroup(groupID string) (*gitlab.Group, error) {
+	a.calls = append(a.calls, "GetGroup "+groupID)
+	for _, g := range a.groups {
+		if g.FullPath == groupID {
+			return &g, a.err
+		}
+	}
+	return nil, fmt.Errorf("group %s: %w", groupID, gitlab.ErrNotFound)
+}
+
+func (a *mockAPI) ListSubgroups(groupID string) ([]gitlab.Group, error) {
+	a.calls = append(a.calls, "ListSubgroups "+groupID)
+	var subgroups []gitlab.Group
+	for _, g := range a.groups {
+		if strings.HasPrefix(g.FullPath, groupID+"/") {
+			subgroups = append(subgroups, g)
+		}
+	}
+	return subgroups, a.err
+}
+
+func (a *mockAPI) GetProject(projectID string) (*gitlab.Project, error) {
+	a.calls = append(a.calls, "GetProject "+projectID)
+	for _, p := range a.projects {
+		if fmt.Sprint(p.ID) == projectID {
+			return &p, a.err
+		}
+	}
+	return nil, fmt.Errorf("project %s: %w", projectID, gitlab.ErrNotFound)
+}
+
+func (a *mockAPI) GetTree(projectID, ref, treePath string) ([]gitlab.TreeEntry, error) {
+	a.calls = append(a.calls, "GetTree "+ref)
+	return a.tree, a.err
+}
+
+// GetLastCommitForPath is called concurrently, so it doesn't record calls
+func (a *mockAPI) GetLastCommitForPath(projectID, ref, filePath string) (*gitlab.Commit, error) {
+	return nil, nil
+}
+
+func (a *mockAPI) GetPipeline(projectID string, pipelineID int) (*gitlab.Pipeline, error) {
+	a.calls = append(a.calls, fmt.Sprintf("GetPipeline %s %d", projectID, pipelineID))
+	for _, p := range a.pipelines {
+		if p.ID == pipelineID {
+			return &p, a.err
+		}
+	}
+	return nil, fmt.Errorf("pipeline %d: %w", pipelineID, gitlab.ErrNotFound)
+}
+
+func (a *mockAPI) ResolveRefSHA(projectID, ref string) (string, error) {
+	a.calls = append(a.calls, "ResolveRefSHA "+ref)
+	return "c0ffee1234567890", a.err
+}
+
+func (a *mockAPI) ListRunningJobs() ([]gitlab.Job, error) {
+	a.calls = append(a.calls, "ListRunningJobs")
+	return nil, a.jobsErr
+}
+
+func (a *mockAPI) ListPendingJobs() ([]gitlab.Job, error) {
+	a.calls = append(a.calls, "ListPendingJobs")
+	return nil, a.jobsErr
+}
+
+func (a *mockAPI) ListPipelines(projectID string) ([]gitlab.Pipeline, error) {
+	a.calls = append(a.calls, "ListPipelines "+projectID)
+	return a.pipelines, a.err
+}
+
+func (a *mockAPI) ListPipelineJobs(projectID string, pipelineID int) ([]gitlab.Job, error) {
+	a.calls = append(a.calls, fmt.Sprintf("ListPipelineJobs %s %d", projectID, pipelineID))
+	return a.jobs, a.err
+}
+
+func TestRunnersPopup_FallsBackToProject(t *testing.T) {
+	api := &mockAPI{
+		jobsErr:   fmt.Errorf("API error 403: %w", gitlab.ErrUnauthorized),
+		pipelines: []gitlab.Pipeline{{ID: 10, Status: "running"}, {ID: 9, Status: "success"}},
+		jobs: []gitlab.Job{
+			{ID: 1, Name: "build", Status: "running"},
+			{ID: 2, Name: "test", Status: "pending"},
+			{ID: 3, Name: "lint", Status: "success"},
+		},
+	}
+	m := &MainScreen{
+		client:           api,
+		keymap:           keymap.DefaultKeyMap(),
+		selectedProject:  &gitlab.Project{ID: 5, Name: "api"},
+		showRunnersPopup: true,
+		runnersLoading:   true,
+	}
+
+	m.Update(m.loadAllJobs()())
+	if len(m.runningJobs) != 1 || len(m.pendingJobs) != 1 || m.runningJobs[0].Project.Name != "api" {
+		t.Fatalf("expected the project's active jobs, got running=%+v pending=%+v", m.runningJobs, m.pendingJobs)
+	}
+	if m.runnersNote != runnersFallbackNote || m.runnersErr != "" {
+		t.Errorf("the fallback should be explained: note=%q err=%q", m.runnersNote, m.runnersErr)
+	}
+	if want := []string{"ListRunningJobs", "ListPipelines 5", "ListPipelineJobs 5 10"}; !slices.Equal(api.calls, want) {
+		t.Errorf("calls = %v, want %v", api.calls, want)
+	}
+
+	// Other failures are shown instead of an empty list
+	api.jobsErr = errors.New("connection refused")
+	m.Update(m.loadAllJobs()())
+	if m.runnersErr != "connection refused" || len(m.runningJobs) != 1 {
+		t.Errorf("expected the error with the last jobs kept, got err=%q running=%d", m.runnersErr, len(m.runningJobs))
+	}
+}
+
+func TestLoadGroups_WithMockAPI(t *testing.T) {
+	platformID := 1
+	api := &mockAPI{groups: []gitlab.Group{
+		{ID: 1, Name: "platform", FullPath: "platform"},
+		{ID: 2, Name: "team-a", FullPath: "platform/team-a", ParentID: &platformID},
+		{ID: 3, Name: "tools", FullPath: "tools"},
+	}}
+	m := &MainScreen{
+		client:         api,
+		keymap:         keymap.DefaultKeyMap(),
+		expandedGroups: map[int]bool{},
+		groupProjects:  map[int][]gitlab.Project{},
+		loading:        true,
+	}
+
+	msg, ok := m.loadGroups()().(groupsLoadedMsg)
+	if !ok || len(msg.groups) != 3 {
+		t.Fatalf("expected 3 groups, got %#v", msg)
+	}
+	m.Update(msg)
+	if m.loading || len(m.groups) != 3 || len(m.treeNodes) == 0 || m.treeNodes[0].Name != "platform" {
+		t.Errorf("groups not applied: loading=%v groups=%d nodes=%d", m.loading, len(m.groups), len(m.treeNodes))
+	}
+
+	// A group filter loads only that subtree
+	api.calls = nil
+	m.groupFilter = "platform"
+	msg, ok = m.loadGroups()().(groupsLoadedMsg)
+	if !ok || len(msg.groups) != 2 || msg.filter != "platform" {
+		t.Fatalf("expected platform and its subgroup, got %#v", msg)
+	}
+	if want := []string{"GetGroup platform", "ListSubgroups platform"}; !slices.Equal(api.calls, want) {
+		t.Errorf("calls = %q, want %q", api.calls, want)
+	}
+
+	// API errors surface in the status bar
+	m.groupFilter = ""
+	api.err = errors.New("connection refused")
+	m.Update(m.loadGroups()())
+	if m.lastError != "connection refused" {
+		t.Errorf("lastError = %q", m.lastError)
+	}
+}
+
+func TestCoverageBadge(t *testing.T) {
+	tests := []struct {
+		coverage string
+		want     string
+		color    lipgloss.TerminalColor
+	}{
+		{"87.50", "87.5%", styles.ColorSuccess},
+		{"80", "80.0%", styles.ColorSuccess},
+		{"42.1", "42.1%", styles.ColorFailed},
+		{"", "", nil},
+		{"n/a", "", nil},
+	}
+	for _, tt := range tests {
+		got := coverageBadge(tt.coverage, 80)
+		if tt.want == "" {
+			if got != "" {
+				t.Errorf("coverageBadge(%q) = %q, want empty", tt.coverage, got)
+			}
+			continue
+		}
+		want := lipgloss.NewStyle().Foreground(tt.color).Render(tt.want)
+		if got != want {
+			t.Errorf("coverageBadge(%q) = %q, want %q", tt.coverage, got, want)
+		}
+	}
+}
+
+func TestPipelineCoverage_FetchedForSelectedPipeline(t *testing.T) {
+	api := &mockAPI{pipelines: []gitlab.Pipeline{{ID: 7, Status: "success", Coverage: "91.00"}}}
+	m := &MainScreen{
+		client:          api,
+		keymap:          keymap.DefaultKeyMap(),
+		selectedProject: &gitlab.Project{ID: 1},
+		contentTab:      TabPipelines,
+		pipelines:       []gitlab.Pipeline{{ID: 7, Status: "running"}},
+	}
+
+	cmd := m.loadSelectedPipelineDetail()
+	if cmd == nil {
+		t.Fatal("expected the selected pipeline to be fetched")
+	}
+	if m.loadSelectedPipelineDetail() != nil {
+		t.Error("expected no second request while the first is in flight")
+	}
+	m.Update(cmd())
+	if got := m.pipelineCoverage(m.pipelines[0]); got != "91.00" {
+		t.Errorf("expected coverage from the detail, got %q", got)
+	}
+	if m.loadSelectedPipelineDetail() != nil {
+		t.Error("expected no refetch while the status is unchanged")
+	}
+
+	// Once the list reports a new status the detail is fetched again
+	m.pipelines[0].Status = "success"
+	if m.loadSelectedPipelineDetail() == nil {
+		t.Error("expected a refetch after the status changed")
+	}
+	if len(api.calls) != 1 || api.calls[0] != "GetPipeline 1 7" {
+		t.Errorf("unexpected calls %v", api.calls)
+	}
+}
+
+func TestBlobPermalink(t *testing.T) {
+	got := blobPermalink("https://gitlab.com/acme/api", "c0ffee12", "docs/my notes.md")
+	if got != "https://gitlab.com/acme/api/-/blob/c0ffee12/docs/my%20notes.md" {
+		t.Errorf("unexpected permalink %q", got)
+	}
+}
+
+func TestFileViewer_PermalinkResolvesRef(t *testing.T) {
+	api := &mockAPI{}
+	m := &MainScreen{
+		client:          api,
+		selectedProject: &gitlab.Project{ID: 1, WebURL: "https://gitlab.com/acme/api"},
+		currentBranch:   "feature/login",
+		viewingFile:     true,
+		viewingFilePath: "cmd/main.go",
+	}
+
+	// A ref missing from the branch list is resolved through the API
+	cmd := m.copyFilePermalink()
+	if cmd == nil {
+		t.Fatal("expected the ref to be resolved")
+	}
+	msg, ok := cmd().(permalinkResolvedMsg)
+	if !ok || msg.sha != "c0ffee1234567890" || msg.filePath != "cmd/main.go" {
+		t.Errorf("unexpected resolved message %+v", msg)
+	}
+
+	// A loaded branch supplies its commit without a request
+	m.branches = []gitlab.Branch{{Name: "feature/login", Commit: gitlab.Commit{ID: "abc123"}}}
+	if m.copyFilePermalink() != nil {
+		t.Error("expected the branch commit to be reused")
+	}
+	if len(api.calls) != 1 {
+		t.Errorf("unexpected calls %v", api.calls)
+	}
+
+	// Snippets and wiki pages have no repository path
+	m.viewingWiki = true
+	if m.copyFilePermalink() != nil || m.statusMsg != "Not a repository file" {
+		t.Errorf("expected wiki pages to be refused, got %q", m.statusMsg)
+	}
+}
+
+func TestCommitBar(t *testing.T) {
+	tests := []struct {
+		commits, most int
+		want          string
+	}{
+		{100, 100, "██████████"},
+		{50, 100, "█████░░░░░"},
+		{1, 500, "█░░░░░░░░░"}, // a sliver for small contributors
+		{0, 100, "░░░░░░░░░░"},
+		{0, 0, "░░░░░░░░░░"},
+	}
+	for _, tt := range tests {
+		if got := commitBar(tt.commits, tt.most, 10); got != tt.want {
+			t.Errorf("commitBar(%d, %d) = %q, want %q", tt.commits, tt.most, got, tt.want)
+		}
+	}
+}
+
+func TestContributorsPopup_SortedByCommits(t *testing.T) {
+	m := &MainScreen{
+		keymap:                keymap.DefaultKeyMap(),
+		selectedProject:       &gitlab.Project{ID: 1, Name: "api"},
+		showContributorsPopup: true,
+		contributorsLoading:   true,
+		width:                 120,
+		height:                40,
+	}
+	m.Update(contributorsLoadedMsg{contributors: []gitlab.Contributor{
+		{Name: "Bob", Commits: 4},
+		{Name: "Alice", Commits: 40},
+	}})
+	if m.contributorsLoading || len(m.contributors) != 2 || m.contributors[0].Name != "Alice" {
+		t.Fatalf("expected contributors sorted by commits, got %+v", m.contributors)
+	}
+	view := m.View()
+	if !strings.Contains(view, "Contributors - api") || strings.Index(view, "Alice") > strings.Index(view, "Bob") {
+		t.Error("expected Alice listed above Bob")
+	}
+
+	pressKeys(m, "j", "esc")
+	if m.contributorsCursor != 1 || m.showContributorsPopup {
+		t.Errorf("expected j to move and Esc to close, cursor %d open %v", m.contributorsCursor, m.showContributorsPopup)
+	}
+}
+
+func TestEventSummary(t *testing.T) {
+	alice := gitlab.User{Name: "Alice"}
+	tests := []struct {
+		event gitlab.Event
+		want  string
+	}{
+		{gitlab.Event{ActionName: "pushed to", Author: alice, PushData: &gitlab.PushData{RefType: "branch", Ref: "main"}}, "Alice pushed to main"},
+		{gitlab.Event{ActionName: "pushed new", Author: alice, PushData: &gitlab.PushData{RefType: "tag", Ref: "v1.0"}}, "Alice pushed new tag v1.0"},
+		{gitlab.Event{ActionName: "opened", TargetType: "MergeRequest", TargetIID: 23, TargetTitle: "Add OAuth2", Author: alice}, "Alice opened !23 Add OAuth2"},
+		{gitlab.Event{ActionName: "accepted", TargetType: "MergeRequest", TargetIID: 9, TargetTitle: "Fix", Author: alice}, "Alice merged !9 Fix"},
+		{gitlab.Event{ActionName: "closed", TargetType: "Issue", TargetIID: 4, TargetTitle: "Crash", Author: alice}, "Alice closed #4 Crash"},
+		{gitlab.Event{ActionName: "joined", Author: gitlab.User{Username: "bob"}}, "bob joined"},
+	}
+	for _, tt := range tests {
+		if got := eventSummary(tt.event); got != tt.want {
+			t.Errorf("eventSummary() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestActivityPopup_NewestFirst(t *testing.T) {
+	now := time.Now()
+	m := &MainScreen{
+		keymap:            keymap.DefaultKeyMap(),
+		selectedProject:   &gitlab.Project{ID: 1, Name: "api"},
+		showActivityPopup: true,
+		activityLoading:   true,
+		width:             120,
+		height:            40,
+	}
+	m.Update(activityLoadedMsg{events: []gitlab.Event{
+		{ActionName: "opened", TargetType: "MergeRequest", TargetIID: 23, TargetTitle: "Add OAuth2", Author: gitlab.User{Name: "Bob"}, CreatedAt: now.Add(-5 * time.Hour)},
+		{ActionName: "pushed to", Author: gitlab.User{Name: "Alice"}, CreatedAt: now.Add(-2 * time.Hour), PushData: &gitlab.PushData{Ref: "main"}},
+	}})
+	if m.activityLoading || len(m.events) != 2 || m.events[0].Author.Name != "Alice" {
+		t.Fatalf("expected events newest first, got %+v", m.events)
+	}
+	view := m.View()
+	if !strings.Contains(view, "Activity - api") || !strings.Contains(view, "2h ago") || strings.Index(view, "Alice pushed to main") > strings.Index(view, "Bob opened !23") {
+		t.Errorf("expected Alice's push listed above Bob's merge request:\n%s", view)
+	}
+
+	pressKeys(m, "j", "esc")
+	if m.activityCursor != 1 || m.showActivityPopup {
+		t.Errorf("expected j to move and Esc to close, cursor %d open %v", m.activityCursor, m.showActivityPopup)
+	}
+}
+
+func TestCodeSearch(t *testing.T) {
+	m := &MainScreen{
+		keymap:          keymap.DefaultKeyMap(),
+		selectedProject: &gitlab.Project{ID: 1, Name: "api"},
+		contentTab:      TabFiles,
+		focusedPanel:    PanelContent,
+		currentBranch:   "dev",
+		spinning:        true,
+		width:           120,
+		height:          40,
+	}
+
+	// Queries shorter than the minimum aren't sent
+	pressKeys(m, "/", "x", "enter")
+	if !m.codeSearchTyping || m.codeSearchLoading {
+		t.Fatal("expected a one-character query to be refused")
+	}
+	pressKeys(m, "y", "enter")
+	if m.codeSearchTyping || !m.codeSearchLoading || m.codeSearchQuery != "xy" {
+		t.Fatalf("expected a search for xy, typing %v loading %v query %q", m.codeSearchTyping, m.codeSearchLoading, m.codeSearchQuery)
+	}
+
+	// Results for an older query are dropped
+	m.Update(codeSearchMsg{projectID: 1, query: "x", blobs: []gitlab.SearchBlob{{Path: "stale.go"}}})
+	if len(m.codeSearchResults) != 0 {
+		t.Fatal("expected stale results to be ignored")
+	}
+	m.Update(codeSearchMsg{projectID: 1, query: "xy", blobs: []gitlab.SearchBlob{
+		{Path: "a.go", Startline: 3, Data: "package a\n"},
+		{Path: "b/c.go", Startline: 10, Data: "// header\nfunc XY() {}\n"},
+	}})
+	if view := m.View(); !strings.Contains(view, "b/c.go:11") || !strings.Contains(view, "Code search - api@dev") {
+		t.Errorf("expected the match line of b/c.go in the view:\n%s", view)
+	}
+
+	// Enter opens the file at the matching line
+	pressKeys(m, "j", "enter")
+	if m.showCodeSearch || !m.loading || m.retryCmd == nil {
+		t.Errorf("expected the file to load, open %v loading %v", m.showCodeSearch, m.loading)
+	}
+
+	m.showCodeSearch = true
+	m.Update(codeSearchMsg{projectID: 1, query: "xy", err: errors.New("API error 400: {\"error\":\"Scope not supported without Elasticsearch!\"}")})
+	if m.codeSearchErr != "Code search is not enabled on this GitLab instance" {
+		t.Errorf("unexpected error message %q", m.codeSearchErr)
+	}
+}
+
+func TestBlobMatch(t *testing.T) {
+	blob := gitlab.SearchBlob{Startline: 40, Data: "\n  if err := Retry(ctx); err != nil {\n\treturn err\n"}
+	if line, text := blobMatch(blob, "retry"); line != 41 || text != "if err := Retry(ctx); err != nil {" {
+		t.Errorf("blobMatch = %d %q", line, text)
+	}
+	// Search syntax like filename: falls back to the first line
+	if line, _ := blobMatch(blob, "filename:*.go Retry"); line != 40 {
+		t.Errorf("expected the first line for a query with filters, got %d", line)
+	}
+}
+
+func TestLoadProjectContent_FetchesMissingDefaultBranch(t *testing.T) {
+	api := &mockAPI{
+		projects: []gitlab.Project{{ID: 5, DefaultBranch: "master"}},
+		tree:     []gitlab.TreeEntry{{Name: "main.go", Path: "main.go", Type: "blob"}},
+	}
+	// Project lists can leave the default branch out
+	m := &MainScreen{
+		client:          api,
+		keymap:          keymap.DefaultKeyMap(),
+		selectedProject: &gitlab.Project{ID: 5},
+		loading:         true,
+		spinning:        true, // keeps Update from batching a spinner tick
+	}
+
+	msg := m.loadProjectContent()()
+	if _, ok := msg.(defaultBranchMsg); !ok {
+		t.Fatalf("expected the default branch to be fetched first, got %T", msg)
+	}
+	_, cmd := m.Update(msg)
+	if m.selectedProject.DefaultBranch != "master" {
+		t.Errorf("expected the default branch cached on the project, got %q", m.selectedProject.DefaultBranch)
+	}
+	m.Update(cmd())
+	if len(m.files) != 1 || m.currentBranch != "master" {
+		t.Errorf("expected the tree of master, got %d files on %q", len(m.files), m.currentBranch)
+	}
+	if want := []string{"GetProject 5", "GetTree master"}; !slices.Equal(api.calls, want) {
+		t.Errorf("calls = %v, want %v", api.calls, want)
+	}
+
+	// Later loads use the cached branch
+	api.calls = nil
+	m.loadProjectContent()()
+	if want := []string{"GetTree master"}; !slices.Equal(api.calls, want) {
+		t.Errorf("calls = %v, want %v", api.calls, want)
+	}
+}
+
+func TestSelectProject_RestoresLastRef(t *testing.T) {
+	api := &mockAPI{tree: []gitlab.TreeEntry{{Name: "main.go", Path: "main.go", Type: "blob"}}}
+	m := &MainScreen{
+		client:   api,
+		keymap:   keymap.DefaultKeyMap(),
+		spinning: true,
+	}
+	apiProject := &gitlab.Project{ID: 1, DefaultBranch: "main"}
+	web := &gitlab.Project{ID: 2, DefaultBranch: "main"}
+
+	m.selectProject(apiProject)
+	m.switchRef("feature/long-lived")
+	m.selectProject(web)
+	if m.currentBranch != "" {
+		t.Fatalf("another project should start on its default branch, got %q", m.currentBranch)
+	}
+	m.selectProject(apiProject)
+	if m.currentBranch != "feature/long-lived" {
+		t.Fatalf("expected the last ref to be restored, got %q", m.currentBranch)
+	}
+	api.calls = nil
+	m.Update(m.loadProjectContent()())
+	if want := []string{"GetTree feature/long-lived"}; !slices.Equal(api.calls, want) {
+		t.Errorf("calls = %v, want %v", api.calls, want)
+	}
+
+	// A deleted branch falls back to the default
+	api.calls = nil
+	api.err = fmt.Errorf("404 Tree Not Found: %w", gitlab.ErrNotFound)
+	msg := m.loadProjectContent()()
+	if _, ok := msg.(savedRefMissingMsg); !ok {
+		t.Fatalf("expected savedRefMissingMsg, got %T", msg)
+	}
+	api.err = nil
+	_, cmd := m.Update(msg)
+	m.Update(cmd())
+	if m.currentBranch != "main" || m.projectRefs[1] != "" {
+		t.Errorf("expected the default branch with the ref forgotten, got %q (saved %q)", m.currentBranch, m.projectRefs[1])
+	}
+	if want := []string{"GetTree feature/long-lived", "GetTree main"}; !slices.Equal(api.calls, want) {
+		t.Errorf("calls = %v, want %v", api.calls, want)
+	}
+}
+
+func TestRecentProjects_SwitchBack(t *testing.T) {
+	m := &MainScreen{
+		keymap: keymap.DefaultKeyMap(),
+		isDemo: true,
+		width:  100,
+		height: 30,
+		groupProjects: map[int][]gitlab.Project{
+			1: {{ID: 10, PathWithNamespace: "acme/api"}, {ID: 11, PathWithNamespace: "acme/web"}},
+		},
+	}
+	pressKeys(m, "ctrl+r")
+	if m.showRecentPopup || m.statusMsg != "No recent projects" {
+		t.Fatalf("expected no switcher without recent projects, status %q", m.statusMsg)
+	}
+
+	m.selectProject(m.cachedProject(10))
+	m.selectProject(m.cachedProject(11))
+	m.selectProject(m.cachedProject(10))
+	if len(m.recentProjects) != 2 || m.recentProjects[0].ID != 10 {
+		t.Fatalf("expected deduplicated recent projects, got %v", m.recentProjects)
+	}
+
+	// The switcher starts on the previous project
+	pressKeys(m, "ctrl+r")
+	if !m.showRecentPopup || m.recentCursor != 1 {
+		t.Fatalf("expected switcher on the previous project, open %v cursor %d", m.showRecentPopup, m.recentCursor)
+	}
+	if !strings.Contains(m.View(), "acme/api (current)") {
+		t.Error("expected the current project to be marked")
+	}
+	pressKeys(m, "enter")
+	if m.showRecentPopup || m.selectedProject.ID != 11 || m.recentProjects[0].ID != 11 {
+		t.Errorf("expected acme/web reopened and moved to the front, got %+v", m.recentProjects)
+	}
+}
+
+func TestGlobalShortcuts_SkipPrompts(t *testing.T) {
+	prompts := map[string]func(m *MainScreen){
+		"nav filter": func(m *MainScreen) { m.navFilterActive = true },
+		"MR filter":  func(m *MainScreen) { m.mrFilterActive = true },
+		"line jump":  func(m *MainScreen) { m.lineJumpActive = true },
+		"ref jump":   func(m *MainScreen) { m.refJumpActive = true },
+	}
+	for name, open := range prompts {
+		t.Run(name, func(t *testing.T) {
+			m := &MainScreen{
+				keymap:         keymap.DefaultKeyMap(),
+				isDemo:         true,
+				recentProjects: []config.Favorite{{ID: 10}, {ID: 11}},
+			}
+			open(m)
+
+			m.handleKey(tea.KeyMsg{Type: tea.KeyCtrlR})
+			if m.showRecentPopup {
+				t.Error("ctrl+r opened the recent projects while typing")
+			}
+			m.handleKey(tea.KeyMsg{Type: tea.KeyCtrlL})
+			if m.statusMsg != "" {
+				t.Errorf("ctrl+l acted while typing: %q", m.statusMsg)
+			}
+			m.handleKey(tea.KeyMsg{Type: tea.KeyCtrlO})
+			if m.statusMsg != "" {
+				t.Errorf("ctrl+o acted while typing: %q", m.statusMsg)
+			}
+		})
+	}
+}
+
+func TestMRFilter_CtrlCQuits(t *testing.T) {
+	m := &MainScreen{keymap: keymap.DefaultKeyMap(), mrFilterActive: true, mrFilterInput: textinput.New()}
+	m.mrFilterInput.Focus()
+
+	pressKeys(m, "q")
+	if m.mrFilter != "q" {
+		t.Errorf("q should be typed into the filter, got %q", m.mrFilter)
+	}
+	_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyCtrlC})
+	if cmd == nil {
+		t.Fatal("ctrl+c should quit from the MR filter")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("ctrl+c should quit from the MR filter")
+	}
+}
+
+func TestRecentProjects_Persisted(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := &MainScreen{keymap: keymap.DefaultKeyMap(), saveRecent: true}
+	m.selectProject(&gitlab.Project{ID: 7, PathWithNamespace: "acme/api"})
+
+	cfg, err := config.LoadLazyLabConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.RecentProjects) != 1 || cfg.RecentProjects[0] != (config.Favorite{ID: 7, Path: "acme/api"}) {
+		t.Errorf("expected the project saved to the config, got %v", cfg.RecentProjects)
+	}
+}
+
+func TestNearestLabelColor(t *testing.T) {
+	tests := []struct {
+		hex  string
+		want lipgloss.Color
+	}{
+		{"#dc143c", styles.ColorRed},
+		{"#69d100", styles.ColorGreen},
+		{"#428bca", styles.ColorBlue},
+		{"#fff", styles.ColorWhite},
+		{"#7f8c8d", styles.ColorGray},
+		{"crimson", styles.ColorGray}, // unparseable
+	}
+	for _, tt := range tests {
+		if got := nearestLabelColor(tt.hex); got != tt.want {
+			t.Errorf("nearestLabelColor(%q) = %v, want %v", tt.hex, got, tt.want)
+		}
+	}
+
+	// Swatches follow the configured colorscheme
+	styles.Apply("nord")
+	defer styles.Apply(styles.DefaultScheme)
+	if got, want := nearestLabelColor("#dc143c"), styles.Schemes["nord"].Red; got != want {
+		t.Errorf("with nord, nearestLabelColor(#dc143c) = %v, want %v", got, want)
+	}
+}
+
+func TestLabelsPopup_SwitchesToMilestones(t *testing.T) {
+	m := &MainScreen{
+		keymap:          keymap.DefaultKeyMap(),
+		selectedProject: &gitlab.Project{ID: 1, Name: "api"},
+		showLabelsPopup: true,
+		labelsLoading:   true,
+		width:           120,
+		height:          40,
+	}
+	m.Update(labelsLoadedMsg{
+		labels: []gitlab.Label{{Name: "docs", Color: "#428bca"}, {Name: "Bug", Color: "#dc143c"}},
+		milestones: []gitlab.Milestone{
+			{Title: "v1.0", State: "closed", DueDate: "2020-01-01"},
+			{Title: "v2.0", State: "active", DueDate: "2020-06-01"},
+		},
+	})
+	if m.labelsLoading || m.labels[0].Name != "Bug" || m.milestones[0].Title != "v2.0" {
+		t.Fatalf("expected sorted labels and milestones, got %+v %+v", m.labels, m.milestones)
+	}
+	if view := m.View(); !strings.Contains(view, "Labels - api") || !strings.Contains(view, "docs") {
+		t.Error("expected labels listed first")
+	}
+
+	pressKeys(m, "j", "tab")
+	if !m.labelsShowMilestones || m.labelsCursor != 0 {
+		t.Fatalf("expected Tab to switch to milestones and reset the cursor, cursor %d", m.labelsCursor)
+	}
+	view := m.View()
+	if !strings.Contains(view, "Milestones - api") || !strings.Contains(view, "(overdue)") {
+		t.Error("expected milestones with the active one flagged overdue")
+	}
+}
+
+// environmentAPI serves single environments by ID, counting the lookups
+type environmentAPI struct {
+	gitlab.API
+	available    []gitlab.Environment
+	environments map[int]gitlab.Environment
+	fetched      atomic.Int32
+}
+
+func (a *environmentAPI) ListAvailableEnvironments(projectID string) ([]gitlab.Environment, error) {
+	return a.available, nil
+}
+
+func (a *environmentAPI) GetEnvironment(projectID string, environmentID int) (*gitlab.Environment, error) {
+	a.fetched.Add(1)
+	env, ok := a.environments[environmentID]
+	if !ok {
+		return nil, gitlab.ErrNotFound
+	}
+	return &env, nil
+}
+
+func TestFillLastDeployments(t *testing.T) {
+	api := &environmentAPI{environments: map[int]gitlab.Environment{
+		2: {ID: 2, LastDeployment: &gitlab.Deployment{SHA: "def456"}},
+	}}
+	envs := []gitlab.Environment{
+		{ID: 1, LastDeployment: &gitlab.Deployment{SHA: "abc123"}},
+		{ID: 2},
+		{ID: 3}, // never deployed
+	}
+	fillLastDeployments(api, make(chan struct{}, 2), "1", envs)
+
+	if envs[1].LastDeployment == nil || envs[1].LastDeployment.SHA != "def456" {
+		t.Errorf("expected the deployment from the environment itself, got %+v", envs[1].LastDeployment)
+	}
+	if envs[0].LastDeployment.SHA != "abc123" || envs[2].LastDeployment != nil {
+		t.Errorf("unexpected deployments: %+v", envs)
+	}
+	if n := api.fetched.Load(); n != 2 {
+		t.Errorf("only environments without a deployment should be fetched, got %d lookups", n)
+	}
+}
+
+func TestReviewAppURL(t *testing.T) {
+	deployed := func(name, state, ref, url string) gitlab.Environment {
+		return gitlab.Environment{Name: name, State: state, ExternalURL: url, LastDeployment: &gitlab.Deployment{Ref: ref}}
+	}
+	envs := []gitlab.Environment{
+		deployed("staging", "available", "feature/x", "https://staging.example.com"),
+		deployed("review/feature-x", "available", "feature/x", "https://x.review.example.com"),
+		deployed("review/old", "stopped", "feature/y", "https://y.review.example.com"),
+		{Name: "production", State: "available", ExternalURL: "https://example.com"},
+	}
+
+	tests := []struct {
+		branch, want string
+	}{
+		{"feature/x", "https://x.review.example.com"}, // review/ wins over staging
+		{"feature/y", ""}, // stopped
+		{"main", ""},      // never deployed
+	}
+	for _, tt := range tests {
+		if got := reviewAppURL(envs, tt.branch); got != tt.want {
+			t.Errorf("reviewAppURL(%q) = %q, want %q", tt.branch, got, tt.want)
+		}
+	}
+	if got := reviewAppURL(envs[:1], "feature/x"); got != "https://staging.example.com" {
+		t.Errorf("expected a shared environment as fallback, got %q", got)
+	}
+}
+
+func TestLoadReviewApp_FetchesDeployments(t *testing.T) {
+	review := gitlab.Environment{ID: 9, Name: "review/fix-typo", State: "available", ExternalURL: "https://typo.review.example.com"}
+	api := &environmentAPI{
+		// The list leaves out last_deployment
+		available: []gitlab.Environment{{ID: 8, Name: "docs", State: "available"}, review},
+		environments: map[int]gitlab.Environment{
+			9: {ID: 9, LastDeployment: &gitlab.Deployment{Ref: "fix/typo"}},
+		},
+	}
+	m := &MainScreen{client: api, selectedProject: &gitlab.Project{ID: 1}}
+
+	msg, ok := m.loadReviewApp(gitlab.MergeRequest{IID: 7, SourceBranch: "fix/typo"})().(reviewAppLoadedMsg)
+	if !ok || msg.url != review.ExternalURL {
+		t.Errorf("expected the review app from its deployment, got %+v", msg)
+	}
+	if n := api.fetched.Load(); n != 1 {
+		t.Errorf("only environments with a URL should be fetched, got %d lookups", n)
+	}
+}
+
+// releaseAPI serves single releases by tag
+type releaseAPI struct {
+	gitlab.API
+	releases map[string]gitlab.Release
+}
+
+func (a *releaseAPI) GetRelease(projectID, tagName string) (*gitlab.Release, error) {
+	rel, ok := a.releases[tagName]
+	if !ok {
+		return nil, gitlab.ErrNotFound
+	}
+	return &rel, nil
+}
+
+func TestEnterOnTag_FetchesOlderRelease(t *testing.T) {
+	m := &MainScreen{
+		client:          &releaseAPI{releases: map[string]gitlab.Release{"v0.1.0": {TagName: "v0.1.0", Name: "First"}}},
+		keymap:          keymap.DefaultKeyMap(),
+		selectedProject: &gitlab.Project{ID: 1},
+		focusedPanel:    PanelContent,
+		contentTab:      TabReleases,
+		showTags:        true,
+		spinning:        true,
+		releases:        []gitlab.Release{{TagName: "v1.0.0"}}, // only the first page is loaded
+		tags:            []gitlab.Tag{{Name: "v1.0.0"}, {Name: "v0.1.0", Release: &gitlab.TagRelease{}}, {Name: "wip"}},
+		selectedContent: 1,
+	}
+
+	_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil || m.showReleasePopup {
+		t.Fatal("expected the release to be fetched by tag")
+	}
+	m.Update(cmd())
+	if !m.showReleasePopup || m.releases[m.selectedReleaseIdx].Name != "First" {
+		t.Errorf("expected the fetched release in the popup, got %+v", m.releases)
+	}
+
+	// A tag without a release isn't looked up
+	m.showReleasePopup = false
+	m.selectedContent = 2
+	if _, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || m.statusMsg != "No release for tag wip" {
+		t.Errorf("expected no lookup for a plain tag, got %q", m.statusMsg)
+	}
+}
+
+func TestMRDetail_NoReviewApp(t *testing.T) {
+	mr := gitlab.MergeRequest{IID: 7, SourceBranch: "fix/typo"}
+	m := &MainScreen{
+		keymap:            keymap.DefaultKeyMap(),
+		selectedProject:   &gitlab.Project{ID: 1},
+		showMRDetailPopup: true,
+		mrDetail:          &mr,
+	}
+
+	pressKeys(m, "p")
+	if m.statusMsg != "No pipeline for this MR" {
+		t.Errorf("expected no pipeline message, got %q", m.statusMsg)
+	}
+
+	m.Update(reviewAppLoadedMsg{projectID: 1, iid: 7, branch: "fix/typo"})
+	if m.statusMsg != "No review app for fix/typo" {
+		t.Errorf("expected no review app message, got %q", m.statusMsg)
+	}
+
+	// Lookups for another MR are ignored
+	m.statusMsg = ""
+	m.Update(reviewAppLoadedMsg{projectID: 1, iid: 8, branch: "other"})
+	if m.statusMsg != "" {
+		t.Errorf("expected stale lookup to be ignored, got %q", m.statusMsg)
+	}
+}
+
+func TestMRDetail_BrowseSourceBranch(t *testing.T) {
+	mr := gitlab.MergeRequest{IID: 7, SourceBranch: "fix/typo"}
+	api := &mockAPI{err: gitlab.ErrNotFound}
+	m := &MainScreen{
+		client:            api,
+		keymap:            keymap.DefaultKeyMap(),
+		selectedProject:   &gitlab.Project{ID: 1, DefaultBranch: "main"},
+		contentTab:        TabMRs,
+		focusedPanel:      PanelNavigator,
+		showMRDetailPopup: true,
+		mrDetail:          &mr,
+		spinning:          true,
+	}
+
+	_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	m.Update(cmd())
+	if m.statusMsg != "Source branch fix/typo no longer exists" || !m.showMRDetailPopup || m.currentRef() != "main" {
+		t.Fatalf("a deleted branch should only be reported, got status %q", m.statusMsg)
+	}
+
+	api.err = nil
+	_, cmd = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	_, cmd = m.Update(cmd())
+	if m.showMRDetailPopup || m.contentTab != TabFiles || m.focusedPanel != PanelContent {
+		t.Errorf("should switch to the files tab: popup=%v tab=%v panel=%v", m.showMRDetailPopup, m.contentTab, m.focusedPanel)
+	}
+	if m.currentRef() != "fix/typo" || cmd == nil {
+		t.Errorf("should load the source branch, got ref %q", m.currentRef())
+	}
+}
+
+func TestBranchPopup_AheadBehind(t *testing.T) {
+	m := &MainScreen{
+		keymap:          keymap.DefaultKeyMap(),
+		selectedProject: &gitlab.Project{ID: 1, DefaultBranch: "main"},
+		showBranchPopup: true,
+		branches: []gitlab.Branch{
+			{Name: "main", Default: true, Protected: true},
+			{Name: "feature/old", Merged: true},
+		},
+		width:  120,
+		height: 40,
+	}
+
+	// The default branch has nothing to compare against
+	pressKeys(m, "a")
+	if m.divergenceLoading != "" || m.statusMsg != "main is the default branch" {
+		t.Fatalf("expected no lookup for the default branch, status %q", m.statusMsg)
+	}
+
+	pressKeys(m, "j", "a")
+	if m.divergenceLoading != "feature/old" {
+		t.Fatalf("expected a lookup for the selected branch, got %q", m.divergenceLoading)
+	}
+	m.Update(divergenceLoadedMsg{projectID: 1, branch: "feature/old", counts: aheadBehind{ahead: 0, behind: 14}})
+	if m.divergenceLoading != "" {
+		t.Error("expected loading to be cleared")
+	}
+
+	view := m.View()
+	for _, want := range []string{"default", "protected", "merged", "↑0 ↓14"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in the branch popup", want)
+		}
+	}
+}
+
+func TestRenderImageBlocks(t *testing.T) {
+	// 2x4 image: red over blue in the left column, transparent on the right
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 4))
+	for y := 0; y < 4; y++ {
+		c := color.NRGBA{R: 255, A: 255}
+		if y%2 == 1 {
+			c = color.NRGBA{B: 255, A: 255}
+		}
+		img.Set(0, y, c)
+	}
+
+	got := stripANSI(renderImageBlocks(img, 80))
+	if got != "▀ \n▀ " {
+		t.Errorf("expected two rows of half blocks with blank transparent cells, got %q", got)
+	}
+
+	// Wider images are scaled down to fit
+	wide := image.NewNRGBA(image.Rect(0, 0, 100, 10))
+	for _, line := range strings.Split(renderImageBlocks(wide, 20), "\n") {
+		if w := lipgloss.Width(line); w != 20 {
+			t.Fatalf("expected rows scaled to 20 cells, got %d", w)
+		}
+	}
+}
+
+func TestFileViewer_ImagePreview(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 4, 2))); err != nil {
+		t.Fatal(err)
+	}
+	img, err := decodeImagePreview(buf.Bytes())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	m := &MainScreen{}
+	m.Update(fileContentMsg{path: "logo.png", image: img})
+	if m.fileImage == nil || m.fileContent != "[image 4x2]" {
+		t.Errorf("expected image to be shown, got %q", m.fileContent)
+	}
+
+	// Anything that doesn't decode falls back to the binary note
+	if _, err := decodeImagePreview([]byte("not an image")); err == nil {
+		t.Error("expected an error for invalid image data")
+	}
+	m.Update(fileContentMsg{path: "broken.png"})
+	if m.fileImage != nil || m.fileContent != binaryFileNote {
+		t.Errorf("expected binary note, got %q", m.fileContent)
+	}
+}
+
+func TestFileViewer_LargeImageNotRead(t *testing.T) {
+	api := &mockAPI{raw: make([]byte, config.MaxFileViewBytes+1)}
+	m := &MainScreen{client: api, imagePreview: true, selectedProject: &gitlab.Project{ID: 1}}
+
+	msg := m.loadFile("huge.png")()
+	if got, ok := msg.(fileContentMsg); !ok || got.image != nil {
+		t.Fatalf("expected a file without image, got %#v", msg)
+	}
+	if want := fmt.Sprintf("GetFileRawLimited huge.png %d", config.MaxFileViewBytes); !slices.Contains(api.calls, want) {
+		t.Errorf("calls = %v, want %q", api.calls, want)
+	}
+}
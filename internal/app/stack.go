@@ -1,6 +1,9 @@
 package app
 
-import "github.com/EspenTeigen/lazylab/internal/ui/views"
+import (
+	"github.com/EspenTeigen/lazylab/internal/gitlab"
+	"github.com/EspenTeigen/lazylab/internal/ui/views"
+)
 
 // ViewStack manages the navigation history
 type ViewStack struct {
@@ -50,3 +53,48 @@ func (s *ViewStack) Breadcrumbs() []string {
 	}
 	return titles
 }
+
+// viewContext is where MainScreen was before switching projects
+type viewContext struct {
+	project *gitlab.Project
+	tab     ContentTab
+	path    []string // browsed directory in the files tab
+	ref     string   // browsed ref, empty for the default branch
+}
+
+// contextStack is MainScreen's history of project contexts, like ViewStack
+// but holding state rather than views. The oldest are dropped past max.
+type contextStack struct {
+	contexts []viewContext
+	max      int
+}
+
+// Push records a context, dropping the oldest when the stack is full
+func (s *contextStack) Push(c viewContext) {
+	s.contexts = append(s.contexts, c)
+	if s.max > 0 && len(s.contexts) > s.max {
+		s.contexts = s.contexts[len(s.contexts)-s.max:]
+	}
+}
+
+// Pop removes and returns the latest context, false when there is none
+func (s *contextStack) Pop() (viewContext, bool) {
+	c, ok := s.Peek()
+	if ok {
+		s.contexts = s.contexts[:len(s.contexts)-1]
+	}
+	return c, ok
+}
+
+// Peek returns the latest context without removing it
+func (s *contextStack) Peek() (viewContext, bool) {
+	if len(s.contexts) == 0 {
+		return viewContext{}, false
+	}
+	return s.contexts[len(s.contexts)-1], true
+}
+
+// Len returns the number of contexts in the stack
+func (s *contextStack) Len() int {
+	return len(s.contexts)
+}
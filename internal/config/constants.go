@@ -5,6 +5,7 @@ import "time"
 // API configuration
 const (
 	DefaultPerPage  = 50
+	MaxPerPage      = 100 // GitLab ignores larger per_page values
 	DefaultTimeout  = 30 * time.Second
 	MaxRetries      = 3
 	InitialBackoff  = 500 * time.Millisecond
@@ -14,19 +15,37 @@ const (
 	DefaultAPIPath  = "/api/v4"
 	RateLimitStatus = 429
 	ServerErrorMin  = 500
+	// BackoffJitter is the largest random fraction added to a retry backoff
+	BackoffJitter = 0.25
+	// DefaultFetchConcurrency limits parallel per-item requests
+	DefaultFetchConcurrency = 10
+	// FetchStagger is the largest random delay before each parallel request
+	FetchStagger = 50 * time.Millisecond
 )
 
 // Environment variable names
 const (
-	EnvGitLabToken = "GITLAB_TOKEN"
-	EnvGitLabHost  = "GITLAB_HOST"
-	EnvGitLabGroup = "GITLAB_GROUP"
+	EnvGitLabToken     = "GITLAB_TOKEN"
+	EnvGitLabTokenFile = "GITLAB_TOKEN_FILE"
+	EnvGitLabHost      = "GITLAB_HOST"
+	EnvGitLabGroup     = "GITLAB_GROUP"
+	// EnvDebug enables the HTTP debug log when set, like -debug
+	EnvDebug = "LAZYLAB_DEBUG"
 )
 
+// DebugLogFile is the HTTP debug log, in the config directory
+const DebugLogFile = "debug.log"
+
 // UI layout ratios
 const (
 	NavigatorWidthRatio = 0.15
 	ReadmeHeightRatio   = 0.60
+	// Bounds and step for adjusting the ratios above, so no panel collapses
+	MinNavigatorWidthRatio = 0.10
+	MaxNavigatorWidthRatio = 0.50
+	MinReadmeHeightRatio   = 0.20
+	MaxReadmeHeightRatio   = 0.80
+	LayoutRatioStep        = 0.05
 )
 
 // Popup configuration
@@ -49,6 +68,55 @@ const (
 	JobLogRefreshInterval   = 3 * time.Second
 )
 
+// File viewer configuration
+const (
+	// MaxHighlightBytes is the default size above which files are shown without syntax highlighting
+	MaxHighlightBytes = 1 << 20
+	// MaxFileViewBytes is how much of a file the viewer downloads before cutting it off
+	MaxFileViewBytes = 10 << 20
+	// MaxImagePreviewPixels is the largest image, in pixels, decoded for a preview
+	MaxImagePreviewPixels = 4096 * 4096
+)
+
+// Job log configuration
+const (
+	// SidewaysScrollStep is how many columns h/l scroll an unwrapped job log or a wide README
+	SidewaysScrollStep = 20
+
+	// ErrorSummaryLines is how many trailing lines are copied when no error marker matches
+	ErrorSummaryLines = 40
+)
+
+// MaxRecentProjects bounds the recently opened projects kept in the config
+const MaxRecentProjects = 10
+
+// MaxBackStack bounds the project contexts ctrl+o can go back to
+const MaxBackStack = 20
+
+// Pipeline list configuration
+const (
+	// CoverageThreshold is the default coverage percentage shown green rather than red
+	CoverageThreshold = 80.0
+)
+
+// Job log color modes for log_color_mode
+const (
+	LogColorAuto = "auto" // from the terminal's color support
+	LogColor256  = "256"  // as the job printed them
+	LogColor16   = "16"   // 256-color and truecolor codes mapped to basic colors
+	LogColorNone = "none" // escape codes stripped
+)
+
+// DefaultErrorMarkers mark the start of the failing part of a job log
+var DefaultErrorMarkers = []string{"ERROR", "FAILED", "error:"}
+
+// UI feedback timing
+const (
+	LineFlashDuration = 1500 * time.Millisecond
+	// RelativeTimeRefresh is how often "5m ago" style times are redrawn
+	RelativeTimeRefresh = 30 * time.Second
+)
+
 // UI element sizes
 const (
 	BorderSize      = 2
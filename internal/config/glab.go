@@ -1,6 +1,7 @@
 package config
 
 import (
+	"cmp"
 	"os"
 	"path/filepath"
 
@@ -50,6 +51,15 @@ func (c *GlabConfig) GetHostConfig(host string) *GlabHost {
 	return nil
 }
 
+// BaseURL returns the URL of host's API, which glab may reach on a different
+// host (api_host) or protocol (api_protocol) than the host's key
+func (h *GlabHost) BaseURL(host string) string {
+	if h.APIHost == "" && h.APIProtocol == "" {
+		return host
+	}
+	return cmp.Or(h.APIProtocol, "https") + "://" + cmp.Or(h.APIHost, host)
+}
+
 // GetDefaultHost returns the default host from glab config
 func (c *GlabConfig) GetDefaultHost() string {
 	if c.Host != "" {
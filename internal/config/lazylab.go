@@ -1,21 +1,220 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
-// LazyLabConfig represents the lazylab configuration
+// LazyLabConfig represents the lazylab configuration. Its accessors fall back
+// to the defaults in constants.go for unset or invalid values and are safe to
+// call on a nil config.
 type LazyLabConfig struct {
 	DefaultHost string                 `yaml:"default_host,omitempty"`
 	Hosts       map[string]LazyLabHost `yaml:"hosts,omitempty"`
+	// Keys overrides keybindings by action name, e.g. quit: "Q" or search: "ctrl+f".
+	// Multiple keys for one action are comma separated.
+	Keys map[string]string `yaml:"keys,omitempty"`
+	// Favorites are projects pinned to the top of the navigator
+	Favorites []Favorite `yaml:"favorites,omitempty"`
+	// RecentProjects are the last opened projects, most recent first ('ctrl+r')
+	RecentProjects []Favorite `yaml:"recent_projects,omitempty"`
+	// Auto-refresh intervals in seconds; unset or zero uses the defaults
+	PipelineRefreshSeconds int `yaml:"pipeline_refresh_seconds,omitempty"`
+	JobLogRefreshSeconds   int `yaml:"joblog_refresh_seconds,omitempty"`
+	// IdleTimeoutMinutes pauses auto-refresh after this long without input;
+	// unset or zero never pauses
+	IdleTimeoutMinutes int `yaml:"idle_timeout_minutes,omitempty"`
+	// MaxHighlightBytes disables syntax highlighting for larger files; unset uses the default
+	MaxHighlightBytes int `yaml:"max_highlight_bytes,omitempty"`
+	// PerPage is how many items list requests fetch at a time, up to 100;
+	// unset uses the default
+	PerPage int `yaml:"per_page,omitempty"`
+	// ImagePreview renders PNG, JPEG and GIF files as colored blocks in the
+	// file viewer instead of the binary file note
+	ImagePreview bool `yaml:"image_preview,omitempty"`
+	// FetchConcurrency limits parallel per-item requests; unset uses the default
+	FetchConcurrency int `yaml:"fetch_concurrency,omitempty"`
+	// SidewaysScrollStep is how many columns h/l scroll the job log and the
+	// README; unset uses the default
+	SidewaysScrollStep int `yaml:"scroll_step,omitempty"`
+	// JobLogScrollStep is the old name of scroll_step, still read when it's unset
+	JobLogScrollStep int `yaml:"joblog_scroll_step,omitempty"`
+	// Job log error summary: the copied tail starts at the last line containing
+	// one of ErrorMarkers, else the last ErrorSummaryLines lines; unset uses the defaults
+	ErrorMarkers      []string `yaml:"error_markers,omitempty"`
+	ErrorSummaryLines int      `yaml:"error_summary_lines,omitempty"`
+	// CoverageThreshold is the pipeline coverage percentage shown green rather
+	// than red; unset uses the default
+	CoverageThreshold float64 `yaml:"coverage_threshold,omitempty"`
+	// LogColorMode limits job log colors for terminals that can't show them:
+	// auto (the default), 256, 16 or none
+	LogColorMode string `yaml:"log_color_mode,omitempty"`
+	// Tabs lists the content tabs to show, in order, e.g. [files, pipelines, mrs];
+	// unset shows all of them
+	Tabs []string `yaml:"tabs,omitempty"`
+	// ColorScheme names a built-in palette: lazygit (the default), nord or gruvbox
+	ColorScheme string `yaml:"colorscheme,omitempty"`
+	// Layout ratios, also adjusted live with '<'/'>'; unset uses the defaults
+	NavigatorWidthRatio float64 `yaml:"navigator_width_ratio,omitempty"`
+	ReadmeHeightRatio   float64 `yaml:"readme_height_ratio,omitempty"`
+	// RestoreLastProject reopens LastProject on startup; unset means true
+	RestoreLastProject *bool        `yaml:"restore_last_project,omitempty"`
+	LastProject        *LastProject `yaml:"last_project,omitempty"`
+}
+
+// LastProject records the project and tab that were open when lazylab exited
+type LastProject struct {
+	ID   int    `yaml:"id"`
+	Path string `yaml:"path"`
+	Tab  string `yaml:"tab,omitempty"`
+}
+
+// HighlightLimit returns the file size above which highlighting is skipped
+func (c *LazyLabConfig) HighlightLimit() int {
+	if c == nil || c.MaxHighlightBytes <= 0 {
+		return MaxHighlightBytes
+	}
+	return c.MaxHighlightBytes
+}
+
+// PageSize returns the per_page for list requests, capped at GitLab's maximum
+func (c *LazyLabConfig) PageSize() int {
+	if c == nil || c.PerPage <= 0 {
+		return DefaultPerPage
+	}
+	return min(c.PerPage, MaxPerPage)
+}
+
+// Concurrency returns how many parallel per-item requests may be in flight
+func (c *LazyLabConfig) Concurrency() int {
+	if c == nil || c.FetchConcurrency <= 0 {
+		return DefaultFetchConcurrency
+	}
+	return c.FetchConcurrency
+}
+
+// ScrollStep returns how many columns h/l scroll the job log and the README
+func (c *LazyLabConfig) ScrollStep() int {
+	switch {
+	case c == nil:
+		return SidewaysScrollStep
+	case c.SidewaysScrollStep > 0:
+		return c.SidewaysScrollStep
+	case c.JobLogScrollStep > 0:
+		return c.JobLogScrollStep
+	}
+	return SidewaysScrollStep
+}
+
+// CoverageGoal returns the coverage percentage at or above which pipeline
+// coverage counts as passing
+func (c *LazyLabConfig) CoverageGoal() float64 {
+	if c == nil || c.CoverageThreshold <= 0 {
+		return CoverageThreshold
+	}
+	return c.CoverageThreshold
+}
+
+// LogColors returns the job log color mode. ok is false for an unknown mode,
+// which falls back to auto.
+func (c *LazyLabConfig) LogColors() (mode string, ok bool) {
+	if c == nil || c.LogColorMode == "" {
+		return LogColorAuto, true
+	}
+	switch mode := strings.ToLower(c.LogColorMode); mode {
+	case LogColorAuto, LogColor256, LogColor16, LogColorNone:
+		return mode, true
+	}
+	return LogColorAuto, false
+}
+
+// LayoutRatios returns the navigator width and README height ratios
+func (c *LazyLabConfig) LayoutRatios() (navigator, readme float64) {
+	navigator, readme = NavigatorWidthRatio, ReadmeHeightRatio
+	if c == nil {
+		return navigator, readme
+	}
+	if c.NavigatorWidthRatio > 0 {
+		navigator = c.NavigatorWidthRatio
+	}
+	if c.ReadmeHeightRatio > 0 {
+		readme = c.ReadmeHeightRatio
+	}
+	return ClampLayoutRatios(navigator, readme)
+}
+
+// ClampLayoutRatios limits the layout ratios to their bounds
+func ClampLayoutRatios(navigator, readme float64) (float64, float64) {
+	navigator = min(max(navigator, MinNavigatorWidthRatio), MaxNavigatorWidthRatio)
+	readme = min(max(readme, MinReadmeHeightRatio), MaxReadmeHeightRatio)
+	return navigator, readme
+}
+
+// ErrorSummary returns the job log error markers and the fallback line count
+func (c *LazyLabConfig) ErrorSummary() (markers []string, lines int) {
+	markers, lines = DefaultErrorMarkers, ErrorSummaryLines
+	if c == nil {
+		return markers, lines
+	}
+	if len(c.ErrorMarkers) > 0 {
+		markers = c.ErrorMarkers
+	}
+	if c.ErrorSummaryLines > 0 {
+		lines = c.ErrorSummaryLines
+	}
+	return markers, lines
+}
+
+// ShouldRestoreLastProject reports whether the last project should be
+// reopened on startup
+func (c *LazyLabConfig) ShouldRestoreLastProject() bool {
+	return c == nil || c.RestoreLastProject == nil || *c.RestoreLastProject
+}
+
+// RefreshIntervals returns the pipeline and job log auto-refresh intervals
+func (c *LazyLabConfig) RefreshIntervals() (pipeline, jobLog time.Duration) {
+	pipeline, jobLog = PipelineRefreshInterval, JobLogRefreshInterval
+	if c == nil {
+		return pipeline, jobLog
+	}
+	if c.PipelineRefreshSeconds > 0 {
+		pipeline = time.Duration(c.PipelineRefreshSeconds) * time.Second
+	}
+	if c.JobLogRefreshSeconds > 0 {
+		jobLog = time.Duration(c.JobLogRefreshSeconds) * time.Second
+	}
+	return pipeline, jobLog
+}
+
+// IdleTimeout returns how long lazylab waits without input before pausing
+// auto-refresh, zero for never
+func (c *LazyLabConfig) IdleTimeout() time.Duration {
+	if c == nil || c.IdleTimeoutMinutes <= 0 {
+		return 0
+	}
+	return time.Duration(c.IdleTimeoutMinutes) * time.Minute
+}
+
+// Favorite identifies a favorited or recently opened project
+type Favorite struct {
+	ID   int    `yaml:"id"`
+	Path string `yaml:"path"`
 }
 
 // LazyLabHost represents a GitLab host configuration
 type LazyLabHost struct {
 	Token string `yaml:"token"`
+	// TokenFile reads the token from a file instead, e.g. a mounted secret
+	TokenFile string `yaml:"token_file,omitempty"`
+	// DefaultGroup limits the navigator to this group's subtree, e.g. "platform/team-a"
+	DefaultGroup string `yaml:"default_group,omitempty"`
 }
 
 // GetConfigDir returns the lazylab config directory path
@@ -56,6 +255,17 @@ func LoadLazyLabConfig() (*LazyLabConfig, error) {
 	return &config, nil
 }
 
+// LoadLazyLabConfigForUpdate reads the config to change and save back. A
+// missing file gives an empty config; other errors, such as a YAML typo, are
+// returned so the caller doesn't overwrite the user's settings.
+func LoadLazyLabConfigForUpdate() (*LazyLabConfig, error) {
+	cfg, err := LoadLazyLabConfig()
+	if errors.Is(err, fs.ErrNotExist) {
+		return &LazyLabConfig{}, nil
+	}
+	return cfg, err
+}
+
 // SaveLazyLabConfig writes the lazylab configuration
 func SaveLazyLabConfig(cfg *LazyLabConfig) error {
 	configDir, err := GetConfigDir()
@@ -81,7 +291,42 @@ func SaveLazyLabConfig(cfg *LazyLabConfig) error {
 	return os.WriteFile(configPath, data, 0600)
 }
 
-// GetHostConfig returns the configuration for a specific host
+// ReadTokenFile reads a token from a file, trimming surrounding whitespace
+func ReadTokenFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("token file %s is empty", path)
+	}
+	return token, nil
+}
+
+// NormalizeHost turns a host as typed or configured - "gitlab.com",
+// "gitlab.example.com:8443/", "http://internal" - into a base URL. HTTPS is
+// assumed without a scheme, and an empty host means DefaultHost.
+func NormalizeHost(host string) string {
+	host = strings.TrimRight(strings.TrimSpace(host), "/")
+	if host == "" {
+		host = DefaultHost
+	}
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+	return host
+}
+
+// HostKey returns the key a host is stored under in the config: its
+// NormalizeHost URL without the default https scheme, e.g. "gitlab.com" or
+// "http://internal".
+func HostKey(host string) string {
+	return strings.TrimPrefix(NormalizeHost(host), "https://")
+}
+
+// GetHostConfig returns the configuration for a specific host. Keys written
+// by hand, e.g. with a scheme or trailing slash, match their HostKey.
 func (c *LazyLabConfig) GetHostConfig(host string) *LazyLabHost {
 	if c.Hosts == nil {
 		return nil
@@ -89,6 +334,12 @@ func (c *LazyLabConfig) GetHostConfig(host string) *LazyLabHost {
 	if hostConfig, ok := c.Hosts[host]; ok {
 		return &hostConfig
 	}
+	key := HostKey(host)
+	for name, hostConfig := range c.Hosts {
+		if HostKey(name) == key {
+			return &hostConfig
+		}
+	}
 	return nil
 }
 
@@ -97,7 +348,45 @@ func (c *LazyLabConfig) SetHostToken(host, token string) {
 	if c.Hosts == nil {
 		c.Hosts = make(map[string]LazyLabHost)
 	}
-	c.Hosts[host] = LazyLabHost{Token: token}
+	// Keep other per-host settings such as default_group
+	hostConfig := c.Hosts[host]
+	hostConfig.Token = token
+	c.Hosts[host] = hostConfig
+}
+
+// IsFavorite reports whether the project is favorited
+func (c *LazyLabConfig) IsFavorite(id int) bool {
+	for _, f := range c.Favorites {
+		if f.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// ToggleFavorite adds or removes a project from favorites and
+// reports whether it is now a favorite
+func (c *LazyLabConfig) ToggleFavorite(id int, path string) bool {
+	for i, f := range c.Favorites {
+		if f.ID == id {
+			c.Favorites = append(c.Favorites[:i], c.Favorites[i+1:]...)
+			return false
+		}
+	}
+	c.Favorites = append(c.Favorites, Favorite{ID: id, Path: path})
+	return true
+}
+
+// AddRecentProject moves a project to the front of the recent projects,
+// keeping at most MaxRecentProjects
+func (c *LazyLabConfig) AddRecentProject(id int, path string) {
+	recent := []Favorite{{ID: id, Path: path}}
+	for _, r := range c.RecentProjects {
+		if r.ID != id && len(recent) < MaxRecentProjects {
+			recent = append(recent, r)
+		}
+	}
+	c.RecentProjects = recent
 }
 
 // GetDefaultHost returns the default host
@@ -1,9 +1,11 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestLazyLabConfig_SetHostToken(t *testing.T) {
@@ -86,6 +88,15 @@ func TestLazyLabConfig_GetHostConfig(t *testing.T) {
 		t.Error("expected nil for non-existing host")
 	}
 
+	// Hosts match however they're written
+	if host = cfg.GetHostConfig("https://gitlab.com/"); host == nil || host.Token != "token1" {
+		t.Errorf("expected a URL to find gitlab.com, got %v", host)
+	}
+	cfg.Hosts["https://gitlab.example.com:8443/"] = LazyLabHost{Token: "token2"}
+	if host = cfg.GetHostConfig("gitlab.example.com:8443"); host == nil || host.Token != "token2" {
+		t.Errorf("expected a key with a scheme to match, got %v", host)
+	}
+
 	// Nil hosts map
 	cfg2 := &LazyLabConfig{}
 	host = cfg2.GetHostConfig("gitlab.com")
@@ -160,3 +171,276 @@ func TestLoadLazyLabConfig_NotExists(t *testing.T) {
 		t.Error("expected error when config doesn't exist")
 	}
 }
+
+func TestLoadLazyLabConfigForUpdate(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	// A missing config starts empty
+	cfg, err := LoadLazyLabConfigForUpdate()
+	if err != nil || cfg == nil || cfg.DefaultHost != "" {
+		t.Fatalf("missing config: got %+v, %v", cfg, err)
+	}
+
+	// A config that fails to parse must not be replaced
+	path, _ := GetConfigPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("default_host: [oops"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadLazyLabConfigForUpdate(); err == nil {
+		t.Error("expected an error for an unparsable config")
+	}
+}
+
+func TestReadTokenFile(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	path := filepath.Join(tmpDir, "token")
+	if err := os.WriteFile(path, []byte("  glpat-from-file\n"), 0600); err != nil {
+		t.Fatalf("failed to write token file: %v", err)
+	}
+	token, err := ReadTokenFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token != "glpat-from-file" {
+		t.Errorf("expected trimmed token 'glpat-from-file', got '%s'", token)
+	}
+
+	// Missing file
+	if _, err := ReadTokenFile(filepath.Join(tmpDir, "missing")); err == nil {
+		t.Error("expected error for missing token file")
+	}
+
+	// Whitespace-only file
+	empty := filepath.Join(tmpDir, "empty")
+	if err := os.WriteFile(empty, []byte(" \n"), 0600); err != nil {
+		t.Fatalf("failed to write token file: %v", err)
+	}
+	if _, err := ReadTokenFile(empty); err == nil {
+		t.Error("expected error for empty token file")
+	}
+}
+
+func TestNormalizeHost(t *testing.T) {
+	tests := []struct {
+		host string
+		want string
+	}{
+		{"gitlab.com", "https://gitlab.com"},
+		{"http://internal", "http://internal"},
+		{"gitlab.example.com:8443", "https://gitlab.example.com:8443"},
+		{"https://gitlab.example.com/ ", "https://gitlab.example.com"},
+		{"httpbin.local", "https://httpbin.local"},
+		{"", "https://gitlab.com"},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeHost(tt.host); got != tt.want {
+			t.Errorf("NormalizeHost(%q) = %q, want %q", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestHostKey(t *testing.T) {
+	tests := []struct {
+		host string
+		want string
+	}{
+		{"gitlab.com", "gitlab.com"},
+		{"https://gitlab.com/", "gitlab.com"},
+		{"http://internal", "http://internal"},
+		{"gitlab.example.com:8443", "gitlab.example.com:8443"},
+	}
+
+	for _, tt := range tests {
+		if got := HostKey(tt.host); got != tt.want {
+			t.Errorf("HostKey(%q) = %q, want %q", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestLazyLabConfig_ToggleFavorite(t *testing.T) {
+	cfg := &LazyLabConfig{}
+
+	if !cfg.ToggleFavorite(1, "acme/api") {
+		t.Error("expected first toggle to add favorite")
+	}
+	cfg.ToggleFavorite(2, "acme/web")
+	if !cfg.IsFavorite(1) || !cfg.IsFavorite(2) {
+		t.Fatalf("expected both projects favorited, got %v", cfg.Favorites)
+	}
+
+	// Toggling again removes it
+	if cfg.ToggleFavorite(1, "acme/api") {
+		t.Error("expected second toggle to remove favorite")
+	}
+	if cfg.IsFavorite(1) {
+		t.Error("expected project 1 to be removed")
+	}
+	if len(cfg.Favorites) != 1 || cfg.Favorites[0].Path != "acme/web" {
+		t.Errorf("unexpected favorites: %v", cfg.Favorites)
+	}
+}
+
+func TestLazyLabConfig_AddRecentProject(t *testing.T) {
+	cfg := &LazyLabConfig{}
+	cfg.AddRecentProject(1, "acme/api")
+	cfg.AddRecentProject(2, "acme/web")
+	cfg.AddRecentProject(1, "acme/api-renamed")
+	if len(cfg.RecentProjects) != 2 || cfg.RecentProjects[0] != (Favorite{ID: 1, Path: "acme/api-renamed"}) || cfg.RecentProjects[1].ID != 2 {
+		t.Errorf("expected reopened project first and deduplicated, got %v", cfg.RecentProjects)
+	}
+
+	for id := 3; id < 3+MaxRecentProjects; id++ {
+		cfg.AddRecentProject(id, fmt.Sprintf("acme/p%d", id))
+	}
+	if len(cfg.RecentProjects) != MaxRecentProjects || cfg.RecentProjects[0].ID != 2+MaxRecentProjects {
+		t.Errorf("expected the %d most recent projects, got %v", MaxRecentProjects, cfg.RecentProjects)
+	}
+}
+
+func TestLazyLabConfig_Defaults(t *testing.T) {
+	// Every accessor returns its default both without a config and with nothing set
+	tests := []struct {
+		name string
+		get  func(c *LazyLabConfig) any
+		want any
+	}{
+		{"RefreshIntervals", func(c *LazyLabConfig) any { p, j := c.RefreshIntervals(); return [2]time.Duration{p, j} },
+			[2]time.Duration{PipelineRefreshInterval, JobLogRefreshInterval}},
+		{"IdleTimeout", func(c *LazyLabConfig) any { return c.IdleTimeout() }, time.Duration(0)},
+		{"LogColors", func(c *LazyLabConfig) any { mode, ok := c.LogColors(); return fmt.Sprint(mode, ok) }, fmt.Sprint(LogColorAuto, true)},
+		{"HighlightLimit", func(c *LazyLabConfig) any { return c.HighlightLimit() }, MaxHighlightBytes},
+		{"PageSize", func(c *LazyLabConfig) any { return c.PageSize() }, DefaultPerPage},
+		{"Concurrency", func(c *LazyLabConfig) any { return c.Concurrency() }, DefaultFetchConcurrency},
+		{"ScrollStep", func(c *LazyLabConfig) any { return c.ScrollStep() }, SidewaysScrollStep},
+		{"CoverageGoal", func(c *LazyLabConfig) any { return c.CoverageGoal() }, CoverageThreshold},
+		{"LayoutRatios", func(c *LazyLabConfig) any { n, r := c.LayoutRatios(); return [2]float64{n, r} },
+			[2]float64{NavigatorWidthRatio, ReadmeHeightRatio}},
+		{"ErrorSummary", func(c *LazyLabConfig) any { m, l := c.ErrorSummary(); return fmt.Sprint(m, l) },
+			fmt.Sprint(DefaultErrorMarkers, ErrorSummaryLines)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.get(nil); got != tt.want {
+				t.Errorf("nil config: expected %v, got %v", tt.want, got)
+			}
+			if got := tt.get(&LazyLabConfig{}); got != tt.want {
+				t.Errorf("empty config: expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLazyLabConfig_RefreshIntervals(t *testing.T) {
+
+	cfg := &LazyLabConfig{PipelineRefreshSeconds: 30, JobLogRefreshSeconds: -1}
+	p, j := cfg.RefreshIntervals()
+	if p != 30*time.Second {
+		t.Errorf("expected 30s pipeline interval, got %v", p)
+	}
+	if j != JobLogRefreshInterval {
+		t.Errorf("expected default job log interval for invalid value, got %v", j)
+	}
+}
+
+func TestLazyLabConfig_IdleTimeout(t *testing.T) {
+	if d := (&LazyLabConfig{IdleTimeoutMinutes: -5}).IdleTimeout(); d != 0 {
+		t.Errorf("expected no idle timeout for invalid value, got %v", d)
+	}
+	if d := (&LazyLabConfig{IdleTimeoutMinutes: 15}).IdleTimeout(); d != 15*time.Minute {
+		t.Errorf("expected 15m, got %v", d)
+	}
+}
+
+func TestLazyLabConfig_LogColors(t *testing.T) {
+	if mode, ok := (&LazyLabConfig{LogColorMode: "None"}).LogColors(); mode != LogColorNone || !ok {
+		t.Errorf("expected none, got %q, %v", mode, ok)
+	}
+	if mode, ok := (&LazyLabConfig{LogColorMode: "8"}).LogColors(); mode != LogColorAuto || ok {
+		t.Errorf("expected auto for an unknown mode, got %q, %v", mode, ok)
+	}
+}
+
+func TestLazyLabConfig_ShouldRestoreLastProject(t *testing.T) {
+	disabled := false
+	tests := []struct {
+		name     string
+		cfg      *LazyLabConfig
+		expected bool
+	}{
+		{"nil config", nil, true},
+		{"unset defaults to true", &LazyLabConfig{}, true},
+		{"disabled", &LazyLabConfig{RestoreLastProject: &disabled}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.ShouldRestoreLastProject(); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestLazyLabConfig_HighlightLimit(t *testing.T) {
+	if got := (&LazyLabConfig{MaxHighlightBytes: 4096}).HighlightLimit(); got != 4096 {
+		t.Errorf("expected configured limit, got %d", got)
+	}
+}
+
+func TestLazyLabConfig_Concurrency(t *testing.T) {
+	if got := (&LazyLabConfig{FetchConcurrency: -1}).Concurrency(); got != DefaultFetchConcurrency {
+		t.Errorf("expected default for invalid value, got %d", got)
+	}
+	if got := (&LazyLabConfig{FetchConcurrency: 3}).Concurrency(); got != 3 {
+		t.Errorf("expected configured limit, got %d", got)
+	}
+}
+
+func TestLazyLabConfig_ErrorSummary(t *testing.T) {
+	markers, lines := (&LazyLabConfig{ErrorMarkers: []string{"panic:"}, ErrorSummaryLines: 10}).ErrorSummary()
+	if len(markers) != 1 || markers[0] != "panic:" || lines != 10 {
+		t.Errorf("expected configured values, got %v, %d", markers, lines)
+	}
+}
+
+func TestLazyLabConfig_LayoutRatios(t *testing.T) {
+	if nav, readme := (&LazyLabConfig{NavigatorWidthRatio: 0.3, ReadmeHeightRatio: 0.4}).LayoutRatios(); nav != 0.3 || readme != 0.4 {
+		t.Errorf("expected configured ratios, got %v, %v", nav, readme)
+	}
+	if nav, readme := (&LazyLabConfig{NavigatorWidthRatio: 0.9, ReadmeHeightRatio: 0.01}).LayoutRatios(); nav != MaxNavigatorWidthRatio || readme != MinReadmeHeightRatio {
+		t.Errorf("expected clamped ratios, got %v, %v", nav, readme)
+	}
+}
+
+func TestLazyLabConfig_CoverageGoal(t *testing.T) {
+	if got := (&LazyLabConfig{CoverageThreshold: 65.5}).CoverageGoal(); got != 65.5 {
+		t.Errorf("expected configured threshold, got %v", got)
+	}
+}
+
+func TestLazyLabConfig_PageSize(t *testing.T) {
+	if got := (&LazyLabConfig{PerPage: 100}).PageSize(); got != 100 {
+		t.Errorf("expected configured page size, got %d", got)
+	}
+	if got := (&LazyLabConfig{PerPage: 500}).PageSize(); got != MaxPerPage {
+		t.Errorf("expected page size capped at %d, got %d", MaxPerPage, got)
+	}
+}
+
+func TestLazyLabConfig_ScrollStep(t *testing.T) {
+	if got := (&LazyLabConfig{SidewaysScrollStep: 8}).ScrollStep(); got != 8 {
+		t.Errorf("expected configured step, got %d", got)
+	}
+	if got := (&LazyLabConfig{JobLogScrollStep: 6}).ScrollStep(); got != 6 {
+		t.Errorf("expected the old joblog_scroll_step to still apply, got %d", got)
+	}
+	if got := (&LazyLabConfig{SidewaysScrollStep: 8, JobLogScrollStep: 6}).ScrollStep(); got != 8 {
+		t.Errorf("expected scroll_step to win over the old key, got %d", got)
+	}
+}
@@ -0,0 +1,82 @@
+package gitlab
+
+import "context"
+
+// API is the read-only set of GitLab calls the TUI makes. *Client talks to a
+// GitLab instance; SnapshotClient serves captured JSON files instead.
+type API interface {
+	HasToken() bool
+
+	// Users
+	GetCurrentUser() (*User, error)
+	GetTodosCount() (int, error)
+
+	// Groups and projects
+	ListGroups() ([]Group, error)
+	GetGroup(groupID string) (*Group, error)
+	ListSubgroups(groupID string) ([]Group, error)
+	ListGroupProjects(groupID string) ([]Project, error)
+	ListProjects() ([]Project, error)
+	ListAllProjects() ([]Project, error)
+	GetProject(projectID string) (*Project, error)
+	GetProjectStatistics(projectID string) (*ProjectStatistics, error)
+	GetProjectCounts(project Project) (*ProjectCounts, error)
+	GetProjectLanguages(projectID string) (map[string]float64, error)
+
+	// Repository
+	GetTree(projectID, ref, treePath string) ([]TreeEntry, error)
+	GetFileContent(projectID string, filePath string, ref string) (string, error)
+	GetFileContentLimited(projectID string, filePath string, ref string, maxBytes int64) (string, bool, error)
+	GetFileRawLimited(projectID, filePath, ref string, maxBytes int64) ([]byte, error)
+	SaveFileRaw(projectID, filePath, ref, destPath string) (int64, error)
+	GetLastCommitForPath(projectID, ref, filePath string) (*Commit, error)
+	ResolveRefSHA(projectID, ref string) (string, error)
+	GetCommitDiff(projectID, sha string) ([]Diff, error)
+	ListBranches(projectID string) ([]Branch, error)
+	ListTags(projectID string) ([]Tag, error)
+	CompareBranches(projectID, from, to string) (*Comparison, error)
+	ListContributors(projectID string) ([]Contributor, error)
+	SearchBlobs(projectID, query, ref string) ([]SearchBlob, error)
+	ListProjectEvents(projectID string) ([]Event, error)
+
+	// Labels and milestones
+	ListLabels(projectID string) ([]Label, error)
+	ListMilestones(projectID string) ([]Milestone, error)
+
+	// Merge requests
+	ListMergeRequests(projectID string) ([]MergeRequest, error)
+	ListMergeRequestsPage(projectID string, page int) (mrs []MergeRequest, nextPage int, err error)
+	GetMergeRequest(projectID string, iid int) (*MergeRequest, error)
+	GetMergeRequestApprovals(projectID string, iid int) (*MRApprovals, error)
+	ListMyMergeRequests() ([]MergeRequest, error)
+	ListMyReviewRequests() ([]MergeRequest, error)
+
+	// CI/CD
+	ListPipelines(projectID string) ([]Pipeline, error)
+	ListPipelinesPage(projectID string, page int) (pipelines []Pipeline, nextPage int, err error)
+	GetPipeline(projectID string, pipelineID int) (*Pipeline, error)
+	ListPipelineJobs(projectID string, pipelineID int) ([]Job, error)
+	ListRunningJobs() ([]Job, error)
+	ListPendingJobs() ([]Job, error)
+	GetJobLog(projectID string, jobID int) (string, error)
+	GetJobLogFrom(projectID string, jobID int, offset int) (log string, partial bool, err error)
+	ListProjectVariables(projectID string) ([]CIVariable, error)
+	ListEnvironments(projectID string) ([]Environment, error)
+	ListAvailableEnvironments(projectID string) ([]Environment, error)
+	GetEnvironment(projectID string, environmentID int) (*Environment, error)
+	ListDeployments(projectID, environment string) ([]Deployment, error)
+
+	// Releases, snippets and wiki
+	ListReleases(projectID string) ([]Release, error)
+	GetRelease(projectID, tagName string) (*Release, error)
+	DownloadFile(ctx context.Context, downloadURL, destPath string, progress ProgressFunc) (int64, string, error)
+	ListProjectSnippets(projectID string) ([]Snippet, error)
+	GetSnippetContent(projectID string, snippetID int) (string, error)
+	ListWikiPages(projectID string) ([]WikiPage, error)
+	GetWikiPage(projectID, slug string) (*WikiPage, error)
+}
+
+var (
+	_ API = (*Client)(nil)
+	_ API = (*SnapshotClient)(nil)
+)
@@ -1,13 +1,23 @@
 package gitlab
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"math/rand/v2"
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/EspenTeigen/lazylab/internal/config"
@@ -21,6 +31,15 @@ import (
 // ErrWriteNotAllowed is returned when attempting non-GET requests
 var ErrWriteNotAllowed = fmt.Errorf("write operations are not allowed - this client is read-only")
 
+// ErrNotFound is wrapped by errors for 404 responses
+var ErrNotFound = errors.New("not found")
+
+// ErrFileTooLarge is returned by GetFileRawLimited for files over the limit
+var ErrFileTooLarge = errors.New("file too large")
+
+// ErrUnauthorized is wrapped by errors for 401 and 403 responses
+var ErrUnauthorized = errors.New("unauthorized")
+
 // Client is a GitLab API client (READ-ONLY)
 type Client struct {
 	baseURL    string
@@ -39,6 +58,72 @@ func WithPerPage(n int) ClientOption {
 	}
 }
 
+// WithDebugLog logs every request's method, URL, status and duration to w.
+// The token and the query values of non-API URLs are redacted.
+func WithDebugLog(w io.Writer) ClientOption {
+	return func(c *Client) {
+		next := c.httpClient.Transport
+		if next == nil {
+			next = http.DefaultTransport
+		}
+		c.httpClient.Transport = &loggingTransport{next: next, out: w}
+	}
+}
+
+// loggingTransport is a RoundTripper that writes one line per request
+type loggingTransport struct {
+	next http.RoundTripper
+	mu   sync.Mutex // requests run concurrently; keep lines whole
+	out  io.Writer
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+
+	result := "error: " + fmt.Sprint(err)
+	if err == nil {
+		result = strconv.Itoa(resp.StatusCode)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fmt.Fprintf(t.out, "%s %s %s %s %s%s\n",
+		start.Format(time.RFC3339), req.Method, redactedURL(req.URL), result, elapsed, redactedHeaders(req.Header))
+	return resp, err
+}
+
+// redactedURL formats a request URL for the debug log. API queries are kept
+// for debugging; other URLs, such as presigned download links that a release
+// asset redirects to, may carry credentials in the query, so only the
+// parameter names are kept.
+func redactedURL(u *url.URL) string {
+	if u.RawQuery == "" || strings.Contains(u.Path, "/api/v4/") {
+		return u.Redacted()
+	}
+	var params []string
+	for _, key := range slices.Sorted(maps.Keys(u.Query())) {
+		params = append(params, url.QueryEscape(key)+"=[REDACTED]")
+	}
+	redacted := *u
+	redacted.RawQuery = strings.Join(params, "&")
+	return redacted.Redacted()
+}
+
+// redactedHeaders formats request headers for the debug log, hiding the token
+func redactedHeaders(h http.Header) string {
+	var b strings.Builder
+	for _, key := range slices.Sorted(maps.Keys(h)) {
+		value := strings.Join(h[key], ",")
+		if http.CanonicalHeaderKey(key) == "Private-Token" {
+			value = "[REDACTED]"
+		}
+		fmt.Fprintf(&b, " %s=%s", key, value)
+	}
+	return b.String()
+}
+
 // NewClient creates a new GitLab client
 func NewClient(baseURL, token string, opts ...ClientOption) *Client {
 	c := &Client{
@@ -56,8 +141,13 @@ func NewClient(baseURL, token string, opts ...ClientOption) *Client {
 }
 
 // NewPublicClient creates a client for gitlab.com public repos (no auth)
-func NewPublicClient() *Client {
-	return NewClient("https://"+config.DefaultHost, "")
+func NewPublicClient(opts ...ClientOption) *Client {
+	return NewClient("https://"+config.DefaultHost, "", opts...)
+}
+
+// HasToken reports whether the client authenticates its requests
+func (c *Client) HasToken() bool {
+	return c.token != ""
 }
 
 // isRetryableStatus returns true if the status code should trigger a retry
@@ -83,8 +173,9 @@ func (c *Client) doWithRetry(req *http.Request) (*http.Response, error) {
 				return nil, req.Context().Err()
 			default:
 			}
-			// Use a simple sleep - in production you'd want a timer
-			sleepDuration := backoff
+			// Use a simple sleep - in production you'd want a timer.
+			// Jitter keeps concurrent retries from hitting the server in lockstep.
+			sleepDuration := withJitter(backoff)
 			backoff *= 2
 			if backoff > config.MaxBackoff {
 				backoff = config.MaxBackoff
@@ -111,6 +202,15 @@ func (c *Client) doWithRetry(req *http.Request) (*http.Response, error) {
 	return nil, lastErr
 }
 
+// withJitter adds a random delay of up to config.BackoffJitter of d
+func withJitter(d time.Duration) time.Duration {
+	maxJitter := time.Duration(float64(d) * config.BackoffJitter)
+	if maxJitter <= 0 {
+		return d
+	}
+	return d + rand.N(maxJitter+1)
+}
+
 // sleepChan returns a channel that closes after the duration
 func sleepChan(d time.Duration) <-chan time.Time {
 	return time.After(d)
@@ -134,6 +234,14 @@ func (c *Client) get(path string, result interface{}) error {
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API error %d: %s: %w", resp.StatusCode, string(body), ErrNotFound)
+	}
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API error %d: %s: %w", resp.StatusCode, string(body), ErrUnauthorized)
+	}
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
@@ -146,16 +254,94 @@ func (c *Client) get(path string, result interface{}) error {
 	return nil
 }
 
+// errNoTotal is returned by getTotal when GitLab leaves out X-Total, which it
+// does for lists of more than 10,000 items
+var errNoTotal = errors.New("X-Total header missing")
+
+// getTotal performs a GET request and returns the X-Total header value.
+// Callers should request per_page=1 so the response body stays small.
+func (c *Client) getTotal(path string) (int, error) {
+	reqURL := c.baseURL + "/api/v4" + path
+
+	req, err := http.NewRequest("GET", reqURL, nil)
+	if err != nil {
+		return 0, fmt.Errorf("creating request: %w", err)
+	}
+
+	if c.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", c.token)
+	}
+
+	resp, err := c.doWithRetry(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return 0, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	header := resp.Header.Get("X-Total")
+	if header == "" {
+		return 0, errNoTotal
+	}
+	total, err := strconv.Atoi(header)
+	if err != nil {
+		return 0, fmt.Errorf("parsing X-Total header: %w", err)
+	}
+	return total, nil
+}
+
 // GetProject fetches a single project by ID or path
 func (c *Client) GetProject(projectID string) (*Project, error) {
 	var project Project
-	path := fmt.Sprintf("/projects/%s", url.PathEscape(projectID))
+	path := fmt.Sprintf("/projects/%s?statistics=true", url.PathEscape(projectID))
 	if err := c.get(path, &project); err != nil {
 		return nil, err
 	}
 	return &project, nil
 }
 
+// GetProjectStatistics fetches the repository size and commit count of a project.
+// Returns nil without an error when the token's access doesn't include statistics.
+func (c *Client) GetProjectStatistics(projectID string) (*ProjectStatistics, error) {
+	project, err := c.GetProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return project.Statistics, nil
+}
+
+// GetProjectCounts fetches the number of open merge requests of a listed
+// project; its open issue count comes with the listing. The MR count is left
+// at 0 when GitLab doesn't report a total.
+func (c *Client) GetProjectCounts(project Project) (*ProjectCounts, error) {
+	counts := &ProjectCounts{OpenIssues: project.OpenIssuesCount}
+	path := fmt.Sprintf("/projects/%d/merge_requests?state=opened&per_page=1", project.ID)
+	openMRs, err := c.getTotal(path)
+	if errors.Is(err, errNoTotal) {
+		return counts, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	counts.OpenMergeRequests = openMRs
+	return counts, nil
+}
+
+// GetProjectLanguages fetches the repository languages of a project as
+// percentages keyed by language name
+func (c *Client) GetProjectLanguages(projectID string) (map[string]float64, error) {
+	var languages map[string]float64
+	path := fmt.Sprintf("/projects/%s/languages", url.PathEscape(projectID))
+	if err := c.get(path, &languages); err != nil {
+		return nil, err
+	}
+	return languages, nil
+}
+
 // GetTree fetches the repository tree for a project
 func (c *Client) GetTree(projectID, ref, treePath string) ([]TreeEntry, error) {
 	var entries []TreeEntry
@@ -191,8 +377,122 @@ func (c *Client) GetLastCommitForPath(projectID, ref, filePath string) (*Commit,
 	return &commits[0], nil
 }
 
+// ResolveRefSHA returns the full SHA of the commit a branch, tag or SHA points at
+func (c *Client) ResolveRefSHA(projectID, ref string) (string, error) {
+	var commit Commit
+	path := fmt.Sprintf("/projects/%s/repository/commits/%s", url.PathEscape(projectID), url.PathEscape(ref))
+	if err := c.get(path, &commit); err != nil {
+		return "", err
+	}
+	return commit.ID, nil
+}
+
+// maxDiffPages bounds how many pages of changed files GetCommitDiff follows
+const maxDiffPages = 20
+
+// GetCommitDiff fetches the file diffs introduced by a commit, following
+// pagination so files past the first page aren't missed
+func (c *Client) GetCommitDiff(projectID, sha string) ([]Diff, error) {
+	path := fmt.Sprintf("/projects/%s/repository/commits/%s/diff",
+		url.PathEscape(projectID),
+		url.PathEscape(sha))
+	return getAllPages[Diff](c, path, maxDiffPages)
+}
+
 // GetFileContent fetches raw file content
 func (c *Client) GetFileContent(projectID string, filePath string, ref string) (string, error) {
+	resp, err := c.openFileRaw(projectID, filePath, ref)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading response: %w", err)
+	}
+
+	return string(content), nil
+}
+
+// GetFileContentLimited fetches at most maxBytes of a file, reporting whether
+// the rest was cut off, so opening a huge blob doesn't buffer it all in memory
+func (c *Client) GetFileContentLimited(projectID string, filePath string, ref string, maxBytes int64) (string, bool, error) {
+	resp, err := c.openFileRaw(projectID, filePath, ref)
+	if err != nil {
+		return "", false, err
+	}
+	defer resp.Body.Close()
+
+	// One extra byte tells a file of exactly maxBytes from a longer one
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return "", false, fmt.Errorf("reading response: %w", err)
+	}
+	if int64(len(content)) > maxBytes {
+		return string(content[:maxBytes]), true, nil
+	}
+
+	return string(content), false, nil
+}
+
+// SaveFileRaw streams a file's raw bytes to destPath without holding them in
+// memory. Returns the number of bytes written.
+func (c *Client) SaveFileRaw(projectID, filePath, ref, destPath string) (int64, error) {
+	resp, err := c.openFileRaw(projectID, filePath, ref)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	return writeFileAtomic(destPath, resp.Body)
+}
+
+// writeFileAtomic copies r to a temporary file next to destPath and renames it
+// into place once complete, so a failed transfer leaves no partial file
+func writeFileAtomic(destPath string, r io.Reader) (int64, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(destPath), "."+filepath.Base(destPath)+".*")
+	if err != nil {
+		return 0, fmt.Errorf("creating file: %w", err)
+	}
+	written, err := io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), destPath)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return written, fmt.Errorf("writing file: %w", err)
+	}
+	return written, nil
+}
+
+// GetFileRawLimited fetches a file's raw bytes, for binary content such as
+// images, reading at most maxBytes and returning ErrFileTooLarge for longer files
+func (c *Client) GetFileRawLimited(projectID, filePath, ref string, maxBytes int64) ([]byte, error) {
+	resp, err := c.openFileRaw(projectID, filePath, ref)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// One extra byte tells a file of exactly maxBytes from a longer one
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, fmt.Errorf("%s is over %d bytes: %w", filePath, maxBytes, ErrFileTooLarge)
+	}
+	return data, nil
+}
+
+// openFileRaw requests a file's raw content, returning the open response on success
+func (c *Client) openFileRaw(projectID string, filePath string, ref string) (*http.Response, error) {
 	reqURL := fmt.Sprintf("%s/api/v4/projects/%s/repository/files/%s/raw?ref=%s",
 		c.baseURL,
 		url.PathEscape(projectID),
@@ -201,7 +501,7 @@ func (c *Client) GetFileContent(projectID string, filePath string, ref string) (
 
 	req, err := http.NewRequest("GET", reqURL, nil)
 	if err != nil {
-		return "", fmt.Errorf("creating request: %w", err)
+		return nil, fmt.Errorf("creating request: %w", err)
 	}
 
 	if c.token != "" {
@@ -210,21 +510,19 @@ func (c *Client) GetFileContent(projectID string, filePath string, ref string) (
 
 	resp, err := c.doWithRetry(req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
-	}
-
-	content, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("reading response: %w", err)
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("API error %d: %s: %w", resp.StatusCode, string(body), ErrNotFound)
+		}
+		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
 	}
 
-	return string(content), nil
+	return resp, nil
 }
 
 // ListBranches fetches branches for a project
@@ -237,24 +535,135 @@ func (c *Client) ListBranches(projectID string) ([]Branch, error) {
 	return branches, nil
 }
 
-// ListMergeRequests fetches open MRs for a project
+// ListMergeRequests fetches the first page of open MRs for a project
 func (c *Client) ListMergeRequests(projectID string) ([]MergeRequest, error) {
+	mrs, _, err := c.ListMergeRequestsPage(projectID, 1)
+	return mrs, err
+}
+
+// ListMergeRequestsPage fetches one page of open MRs for a project. nextPage
+// is 0 once there are no more.
+func (c *Client) ListMergeRequestsPage(projectID string, page int) (mrs []MergeRequest, nextPage int, err error) {
+	path := fmt.Sprintf("/projects/%s/merge_requests?state=opened&per_page=%d&page=%d", url.PathEscape(projectID), c.perPage, page)
+	if err := c.get(path, &mrs); err != nil {
+		return nil, 0, err
+	}
+	return mrs, c.nextPage(page, len(mrs)), nil
+}
+
+// nextPage returns the page after page, or 0 when a short page showed it was the last
+func (c *Client) nextPage(page, count int) int {
+	if count < c.perPage {
+		return 0
+	}
+	return page + 1
+}
+
+// GetMergeRequest fetches a single merge request, including its head pipeline
+func (c *Client) GetMergeRequest(projectID string, iid int) (*MergeRequest, error) {
+	var mr MergeRequest
+	path := fmt.Sprintf("/projects/%s/merge_requests/%d", url.PathEscape(projectID), iid)
+	if err := c.get(path, &mr); err != nil {
+		return nil, err
+	}
+	return &mr, nil
+}
+
+// GetMergeRequestApprovals fetches the approval state of a merge request
+func (c *Client) GetMergeRequestApprovals(projectID string, iid int) (*MRApprovals, error) {
+	var approvals MRApprovals
+	path := fmt.Sprintf("/projects/%s/merge_requests/%d/approvals", url.PathEscape(projectID), iid)
+	if err := c.get(path, &approvals); err != nil {
+		return nil, err
+	}
+	return &approvals, nil
+}
+
+// GetCurrentUser fetches the user the client is authenticated as
+func (c *Client) GetCurrentUser() (*User, error) {
+	var user User
+	if err := c.get("/user", &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetTodosCount returns the number of pending todos for the current user
+func (c *Client) GetTodosCount() (int, error) {
+	return c.getTotal("/todos?per_page=1")
+}
+
+// ListMyMergeRequests fetches open MRs assigned to the current user across all projects
+func (c *Client) ListMyMergeRequests() ([]MergeRequest, error) {
 	var mrs []MergeRequest
-	path := fmt.Sprintf("/projects/%s/merge_requests?state=opened&per_page=%d", url.PathEscape(projectID), c.perPage)
+	path := fmt.Sprintf("/merge_requests?scope=assigned_to_me&state=opened&per_page=%d", c.perPage)
 	if err := c.get(path, &mrs); err != nil {
 		return nil, err
 	}
 	return mrs, nil
 }
 
-// ListPipelines fetches recent pipelines for a project
+// ListMyReviewRequests fetches open MRs where the current user is a reviewer
+func (c *Client) ListMyReviewRequests() ([]MergeRequest, error) {
+	user, err := c.GetCurrentUser()
+	if err != nil {
+		return nil, err
+	}
+
+	var mrs []MergeRequest
+	path := fmt.Sprintf("/merge_requests?scope=all&state=opened&reviewer_id=%d&per_page=%d", user.ID, c.perPage)
+	if err := c.get(path, &mrs); err != nil {
+		return nil, err
+	}
+	return mrs, nil
+}
+
+// ListPipelines fetches the first page of recent pipelines for a project
 func (c *Client) ListPipelines(projectID string) ([]Pipeline, error) {
-	var pipelines []Pipeline
-	path := fmt.Sprintf("/projects/%s/pipelines?per_page=%d", url.PathEscape(projectID), c.perPage)
+	pipelines, _, err := c.ListPipelinesPage(projectID, 1)
+	return pipelines, err
+}
+
+// ListPipelinesPage fetches one page of pipelines for a project, newest
+// first. nextPage is 0 once there are no more.
+func (c *Client) ListPipelinesPage(projectID string, page int) (pipelines []Pipeline, nextPage int, err error) {
+	path := fmt.Sprintf("/projects/%s/pipelines?per_page=%d&page=%d", url.PathEscape(projectID), c.perPage, page)
 	if err := c.get(path, &pipelines); err != nil {
+		return nil, 0, err
+	}
+	return pipelines, c.nextPage(page, len(pipelines)), nil
+}
+
+// GetPipeline fetches a single pipeline, including its duration and coverage
+func (c *Client) GetPipeline(projectID string, pipelineID int) (*Pipeline, error) {
+	var pipeline Pipeline
+	path := fmt.Sprintf("/projects/%s/pipelines/%d", url.PathEscape(projectID), pipelineID)
+	if err := c.get(path, &pipeline); err != nil {
 		return nil, err
 	}
-	return pipelines, nil
+	return &pipeline, nil
+}
+
+// ListWikiPages fetches the pages of a project wiki, without their content.
+// Projects with the wiki disabled return ErrNotFound.
+func (c *Client) ListWikiPages(projectID string) ([]WikiPage, error) {
+	var pages []WikiPage
+	path := fmt.Sprintf("/projects/%s/wikis", url.PathEscape(projectID))
+	if err := c.get(path, &pages); err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
+// GetWikiPage fetches a wiki page with its content. Slugs of nested pages
+// contain slashes, which are escaped.
+func (c *Client) GetWikiPage(projectID, slug string) (*WikiPage, error) {
+	var page WikiPage
+	path := fmt.Sprintf("/projects/%s/wikis/%s", url.PathEscape(projectID), url.PathEscape(slug))
+	if err := c.get(path, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
 }
 
 // filterActiveProjects removes projects that are marked for deletion
@@ -271,6 +680,26 @@ func filterActiveProjects(projects []Project) []Project {
 	return result
 }
 
+// GetGroup fetches a single group by ID or path, without its projects
+func (c *Client) GetGroup(groupID string) (*Group, error) {
+	var group Group
+	path := fmt.Sprintf("/groups/%s?with_projects=false", url.PathEscape(groupID))
+	if err := c.get(path, &group); err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// ListSubgroups fetches all groups below a group, at any depth
+func (c *Client) ListSubgroups(groupID string) ([]Group, error) {
+	var groups []Group
+	path := fmt.Sprintf("/groups/%s/descendant_groups?per_page=%d&order_by=name", url.PathEscape(groupID), c.perPage)
+	if err := c.get(path, &groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
 // ListGroupProjects fetches projects from a group
 func (c *Client) ListGroupProjects(groupID string) ([]Project, error) {
 	var projects []Project
@@ -291,6 +720,19 @@ func (c *Client) ListProjects() ([]Project, error) {
 	return filterActiveProjects(projects), nil
 }
 
+// maxProjectPages bounds how many pages ListAllProjects follows
+const maxProjectPages = 50
+
+// ListAllProjects fetches every project the user is a member of, following
+// pagination, rather than the first page ListProjects returns
+func (c *Client) ListAllProjects() ([]Project, error) {
+	projects, err := getAllPages[Project](c, "/projects?order_by=last_activity_at&membership=true", maxProjectPages)
+	if err != nil {
+		return nil, err
+	}
+	return filterActiveProjects(projects), nil
+}
+
 // ListGroups fetches all accessible groups
 func (c *Client) ListGroups() ([]Group, error) {
 	var groups []Group
@@ -354,10 +796,15 @@ func (c *Client) ListRunningJobs() ([]Job, error) {
 	}
 
 	var allJobs []Job
+	failed := 0
 	for _, p := range projects {
 		jobs, err := c.ListProjectJobs(fmt.Sprintf("%d", p.ID), "running")
 		if err != nil {
-			continue // Skip projects we can't access
+			// Skip projects we can't access, unless that's all of them
+			if failed++; failed == len(projects) {
+				return nil, err
+			}
+			continue
 		}
 		// Add project info to jobs
 		for i := range jobs {
@@ -378,9 +825,13 @@ func (c *Client) ListPendingJobs() ([]Job, error) {
 	}
 
 	var allJobs []Job
+	failed := 0
 	for _, p := range projects {
 		jobs, err := c.ListProjectJobs(fmt.Sprintf("%d", p.ID), "pending")
 		if err != nil {
+			if failed++; failed == len(projects) {
+				return nil, err
+			}
 			continue
 		}
 		for i := range jobs {
@@ -403,13 +854,235 @@ func (c *Client) ListReleases(projectID string) ([]Release, error) {
 	return releases, nil
 }
 
+// GetRelease fetches the release attached to a tag
+func (c *Client) GetRelease(projectID, tagName string) (*Release, error) {
+	var release Release
+	path := fmt.Sprintf("/projects/%s/releases/%s", url.PathEscape(projectID), url.PathEscape(tagName))
+	if err := c.get(path, &release); err != nil {
+		return nil, err
+	}
+	return &release, nil
+}
+
+// ListTags fetches tags for a project, most recently updated first
+func (c *Client) ListTags(projectID string) ([]Tag, error) {
+	var tags []Tag
+	path := fmt.Sprintf("/projects/%s/repository/tags?order_by=updated&sort=desc&per_page=%d",
+		url.PathEscape(projectID),
+		c.perPage)
+	if err := c.get(path, &tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// maxEnvironmentPages bounds how many pages ListEnvironments follows
+const maxEnvironmentPages = 10
+
+// ListEnvironments fetches the environments of a project, following
+// pagination. The list endpoint may leave out last_deployment; GetEnvironment
+// always includes it.
+func (c *Client) ListEnvironments(projectID string) ([]Environment, error) {
+	return getAllPages[Environment](c, fmt.Sprintf("/projects/%s/environments", url.PathEscape(projectID)), maxEnvironmentPages)
+}
+
+// ListAvailableEnvironments fetches the environments of a project that are
+// up, leaving out stopped ones, following pagination
+func (c *Client) ListAvailableEnvironments(projectID string) ([]Environment, error) {
+	path := fmt.Sprintf("/projects/%s/environments?states=available", url.PathEscape(projectID))
+	return getAllPages[Environment](c, path, maxEnvironmentPages)
+}
+
+// GetEnvironment fetches a single environment with its last deployment
+func (c *Client) GetEnvironment(projectID string, environmentID int) (*Environment, error) {
+	var environment Environment
+	path := fmt.Sprintf("/projects/%s/environments/%d", url.PathEscape(projectID), environmentID)
+	if err := c.get(path, &environment); err != nil {
+		return nil, err
+	}
+	return &environment, nil
+}
+
+// CompareBranches fetches the commits and diffs between two refs
+func (c *Client) CompareBranches(projectID, from, to string) (*Comparison, error) {
+	var comparison Comparison
+	path := fmt.Sprintf("/projects/%s/repository/compare?from=%s&to=%s",
+		url.PathEscape(projectID),
+		url.QueryEscape(from),
+		url.QueryEscape(to))
+	if err := c.get(path, &comparison); err != nil {
+		return nil, err
+	}
+	return &comparison, nil
+}
+
+// ListProjectVariables fetches the CI/CD variables of a project.
+// Requires maintainer access; otherwise the error wraps ErrUnauthorized.
+func (c *Client) ListProjectVariables(projectID string) ([]CIVariable, error) {
+	var variables []CIVariable
+	path := fmt.Sprintf("/projects/%s/variables?per_page=%d", url.PathEscape(projectID), c.perPage)
+	if err := c.get(path, &variables); err != nil {
+		return nil, err
+	}
+	return variables, nil
+}
+
+// getAllPages fetches path page by page until a short page shows it was the
+// last, or maxPages have been read. path must not set per_page or page.
+func getAllPages[T any](c *Client, path string, maxPages int) ([]T, error) {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	var all []T
+	for page := 1; page <= maxPages; page++ {
+		var items []T
+		if err := c.get(fmt.Sprintf("%s%sper_page=%d&page=%d", path, sep, c.perPage, page), &items); err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+		if len(items) < c.perPage {
+			break
+		}
+	}
+	return all, nil
+}
+
+// maxSnippetPages bounds how many pages ListProjectSnippets follows
+const maxSnippetPages = 10
+
+// ListProjectSnippets fetches the snippets of a project, following pagination
+func (c *Client) ListProjectSnippets(projectID string) ([]Snippet, error) {
+	return getAllPages[Snippet](c, fmt.Sprintf("/projects/%s/snippets", url.PathEscape(projectID)), maxSnippetPages)
+}
+
+// maxContributorPages bounds how many pages ListContributors follows
+const maxContributorPages = 10
+
+// ListContributors fetches the contributors of a repository, most commits
+// first, following pagination
+func (c *Client) ListContributors(projectID string) ([]Contributor, error) {
+	path := fmt.Sprintf("/projects/%s/repository/contributors?order_by=commits&sort=desc", url.PathEscape(projectID))
+	return getAllPages[Contributor](c, path, maxContributorPages)
+}
+
+// maxLabelPages bounds how many pages ListLabels and ListMilestones follow
+const maxLabelPages = 10
+
+// ListLabels fetches the labels of a project, following pagination
+func (c *Client) ListLabels(projectID string) ([]Label, error) {
+	return getAllPages[Label](c, fmt.Sprintf("/projects/%s/labels", url.PathEscape(projectID)), maxLabelPages)
+}
+
+// ListMilestones fetches the active and closed milestones of a project,
+// following pagination
+func (c *Client) ListMilestones(projectID string) ([]Milestone, error) {
+	return getAllPages[Milestone](c, fmt.Sprintf("/projects/%s/milestones", url.PathEscape(projectID)), maxLabelPages)
+}
+
+// maxEventPages bounds how many pages ListProjectEvents follows; the feed is
+// for catching up on recent activity, not the full history
+const maxEventPages = 5
+
+// ListProjectEvents fetches the activity feed of a project, newest first,
+// following pagination
+func (c *Client) ListProjectEvents(projectID string) ([]Event, error) {
+	return getAllPages[Event](c, fmt.Sprintf("/projects/%s/events?sort=desc", url.PathEscape(projectID)), maxEventPages)
+}
+
+// maxSearchPages bounds how many pages of matches SearchBlobs follows
+const maxSearchPages = 5
+
+// SearchBlobs searches the code of a project at ref, or at the default branch
+// when ref is empty, following pagination
+func (c *Client) SearchBlobs(projectID, query, ref string) ([]SearchBlob, error) {
+	params := url.Values{"scope": {"blobs"}, "search": {query}}
+	if ref != "" {
+		params.Set("ref", ref)
+	}
+	path := fmt.Sprintf("/projects/%s/search?%s", url.PathEscape(projectID), params.Encode())
+	return getAllPages[SearchBlob](c, path, maxSearchPages)
+}
+
+// GetSnippetContent fetches the raw content of a project snippet
+func (c *Client) GetSnippetContent(projectID string, snippetID int) (string, error) {
+	reqURL := fmt.Sprintf("%s/api/v4/projects/%s/snippets/%d/raw",
+		c.baseURL,
+		url.PathEscape(projectID),
+		snippetID)
+
+	req, err := http.NewRequest("GET", reqURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+
+	if c.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", c.token)
+	}
+
+	resp, err := c.doWithRetry(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading response: %w", err)
+	}
+
+	return string(content), nil
+}
+
+// ListDeployments fetches the most recent deployments to an environment
+func (c *Client) ListDeployments(projectID, environment string) ([]Deployment, error) {
+	var deployments []Deployment
+	path := fmt.Sprintf("/projects/%s/deployments?environment=%s&order_by=created_at&sort=desc&per_page=%d",
+		url.PathEscape(projectID),
+		url.QueryEscape(environment),
+		c.perPage)
+	if err := c.get(path, &deployments); err != nil {
+		return nil, err
+	}
+	return deployments, nil
+}
+
+// ProgressFunc is called as a download advances. total is -1 when the
+// server didn't send a Content-Length.
+type ProgressFunc func(written, total int64)
+
+// progressReader reports the bytes read through it to a ProgressFunc
+type progressReader struct {
+	r        io.Reader
+	written  int64
+	total    int64
+	progress ProgressFunc
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.written += int64(n)
+	if n > 0 {
+		p.progress(p.written, p.total)
+	}
+	return n, err
+}
+
 // DownloadFile downloads a file from the given URL and saves it to the specified path.
-// It uses the client's token for authentication if available.
-// Returns the number of bytes written and any error encountered.
-func (c *Client) DownloadFile(downloadURL, destPath string) (int64, error) {
-	req, err := http.NewRequest("GET", downloadURL, nil)
+// It uses the client's token for authentication if available. progress, if not nil,
+// is called as bytes arrive; cancelling ctx aborts the download and removes the
+// partial file.
+// Returns the number of bytes written, the hex SHA-256 of the saved file and
+// any error encountered.
+func (c *Client) DownloadFile(ctx context.Context, downloadURL, destPath string, progress ProgressFunc) (int64, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", downloadURL, nil)
 	if err != nil {
-		return 0, fmt.Errorf("creating request: %w", err)
+		return 0, "", fmt.Errorf("creating request: %w", err)
 	}
 
 	if c.token != "" {
@@ -418,33 +1091,52 @@ func (c *Client) DownloadFile(downloadURL, destPath string) (int64, error) {
 
 	resp, err := c.doWithRetry(req)
 	if err != nil {
-		return 0, err
+		return 0, "", err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return 0, fmt.Errorf("download error %d: %s", resp.StatusCode, string(body))
+		return 0, "", fmt.Errorf("download error %d: %s", resp.StatusCode, string(body))
 	}
 
 	// Create the destination file
 	out, err := os.Create(destPath)
 	if err != nil {
-		return 0, fmt.Errorf("creating file: %w", err)
+		return 0, "", fmt.Errorf("creating file: %w", err)
 	}
 	defer out.Close()
 
-	// Copy the response body to the file
-	written, err := io.Copy(out, resp.Body)
+	var body io.Reader = resp.Body
+	if progress != nil {
+		body = &progressReader{r: resp.Body, total: resp.ContentLength, progress: progress}
+	}
+
+	// Copy the response body to the file, hashing it on the way
+	hash := sha256.New()
+	written, err := io.Copy(io.MultiWriter(out, hash), body)
 	if err != nil {
-		return written, fmt.Errorf("writing file: %w", err)
+		out.Close()
+		os.Remove(destPath)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return written, "", ctxErr
+		}
+		return written, "", fmt.Errorf("writing file: %w", err)
 	}
 
-	return written, nil
+	return written, hex.EncodeToString(hash.Sum(nil)), nil
 }
 
 // GetJobLog fetches the log/trace for a specific job
 func (c *Client) GetJobLog(projectID string, jobID int) (string, error) {
+	log, _, err := c.GetJobLogFrom(projectID, jobID, 0)
+	return log, err
+}
+
+// GetJobLogFrom fetches the job log starting at byte offset using a Range request.
+// partial is true when only the bytes past offset were returned; servers that
+// ignore Range send the whole log and partial is false.
+func (c *Client) GetJobLogFrom(projectID string, jobID int, offset int) (log string, partial bool, err error) {
 	reqURL := fmt.Sprintf("%s/api/v4/projects/%s/jobs/%d/trace",
 		c.baseURL,
 		url.PathEscape(projectID),
@@ -452,28 +1144,36 @@ func (c *Client) GetJobLog(projectID string, jobID int) (string, error) {
 
 	req, err := http.NewRequest("GET", reqURL, nil)
 	if err != nil {
-		return "", fmt.Errorf("creating request: %w", err)
+		return "", false, fmt.Errorf("creating request: %w", err)
 	}
 
 	if c.token != "" {
 		req.Header.Set("PRIVATE-TOKEN", c.token)
 	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
 
 	resp, err := c.doWithRetry(req)
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusPartialContent:
+	case http.StatusRequestedRangeNotSatisfiable:
+		// Nothing new past offset
+		return "", true, nil
+	default:
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return "", false, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
 	}
 
 	content, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("reading response: %w", err)
+		return "", false, fmt.Errorf("reading response: %w", err)
 	}
 
-	return string(content), nil
+	return string(content), resp.StatusCode == http.StatusPartialContent, nil
 }
@@ -0,0 +1,580 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrSnapshotDownload is returned for downloads, which a snapshot can't serve
+var ErrSnapshotDownload = errors.New("downloads are not available in a snapshot")
+
+// SnapshotClient serves GitLab data captured as JSON files in a directory,
+// for demos and bug reports. Files hold API responses and are named like the
+// ones in testdata:
+//
+//	groups.json, projects.json   groups, subgroups (by parent_id) and their projects
+//	tree.json, tree/<path>.json  repository root and subdirectories
+//	files/<path>                 raw file contents
+//	branches.json, tags.json, merge_requests.json, pipelines.json, jobs.json,
+//	releases.json, environments.json, deployments.json, variables.json,
+//	snippets.json, languages.json, user.json, wikis.json (with content),
+//	contributors.json, labels.json, milestones.json, events.json
+//	diffs/<sha>.json             commit diffs
+//	logs/<job id>.log            job logs
+//	snippets/<id>                raw snippet contents
+//
+// Missing list files read as empty lists and missing items as ErrNotFound.
+// Project data is shared by every project in the snapshot. It never writes,
+// like Client.
+type SnapshotClient struct {
+	dir string
+}
+
+// NewSnapshotClient creates a client reading the snapshot in dir
+func NewSnapshotClient(dir string) (*SnapshotClient, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("snapshot %s is not a directory", dir)
+	}
+	return &SnapshotClient{dir: dir}, nil
+}
+
+// read returns the contents of a snapshot file, wrapping ErrNotFound when it
+// doesn't exist
+func (s *SnapshotClient) read(name string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, filepath.FromSlash(name)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("snapshot %s: %w", name, ErrNotFound)
+	}
+	return data, err
+}
+
+// load decodes a snapshot JSON file into result
+func (s *SnapshotClient) load(name string, result interface{}) error {
+	data, err := s.read(name)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("decoding snapshot %s: %w", name, err)
+	}
+	return nil
+}
+
+// loadList decodes a snapshot JSON list, treating a missing file as empty
+func loadList[T any](s *SnapshotClient, name string) ([]T, error) {
+	var items []T
+	if err := s.load(name, &items); err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	return items, nil
+}
+
+// filter returns the items for which keep is true
+func filter[T any](items []T, keep func(T) bool) []T {
+	var kept []T
+	for _, item := range items {
+		if keep(item) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+// HasToken reports true so the snapshot behaves like a signed-in session
+func (s *SnapshotClient) HasToken() bool {
+	return true
+}
+
+// GetCurrentUser returns the user in user.json
+func (s *SnapshotClient) GetCurrentUser() (*User, error) {
+	var user User
+	if err := s.load("user.json", &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetTodosCount returns zero; snapshots don't capture todos
+func (s *SnapshotClient) GetTodosCount() (int, error) {
+	return 0, nil
+}
+
+// ListGroups returns the groups in groups.json
+func (s *SnapshotClient) ListGroups() ([]Group, error) {
+	return loadList[Group](s, "groups.json")
+}
+
+// GetGroup finds a group by ID or full path
+func (s *SnapshotClient) GetGroup(groupID string) (*Group, error) {
+	groups, err := s.ListGroups()
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range groups {
+		if strconv.Itoa(g.ID) == groupID || g.FullPath == groupID {
+			return &g, nil
+		}
+	}
+	return nil, fmt.Errorf("snapshot group %s: %w", groupID, ErrNotFound)
+}
+
+// ListSubgroups returns the direct subgroups of a group
+func (s *SnapshotClient) ListSubgroups(groupID string) ([]Group, error) {
+	group, err := s.GetGroup(groupID)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := s.ListGroups()
+	if err != nil {
+		return nil, err
+	}
+	return filter(groups, func(g Group) bool {
+		return g.ParentID != nil && *g.ParentID == group.ID
+	}), nil
+}
+
+// ListGroupProjects returns the projects whose namespace is the group
+func (s *SnapshotClient) ListGroupProjects(groupID string) ([]Project, error) {
+	group, err := s.GetGroup(groupID)
+	if err != nil {
+		return nil, err
+	}
+	projects, err := s.ListProjects()
+	if err != nil {
+		return nil, err
+	}
+	return filter(projects, func(p Project) bool {
+		return p.Namespace != nil && p.Namespace.ID == group.ID
+	}), nil
+}
+
+// ListProjects returns the projects in projects.json
+func (s *SnapshotClient) ListProjects() ([]Project, error) {
+	return loadList[Project](s, "projects.json")
+}
+
+// ListAllProjects returns the projects in projects.json, which aren't paged
+func (s *SnapshotClient) ListAllProjects() ([]Project, error) {
+	return s.ListProjects()
+}
+
+// GetProject finds a project by ID or full path
+func (s *SnapshotClient) GetProject(projectID string) (*Project, error) {
+	projects, err := s.ListProjects()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range projects {
+		if strconv.Itoa(p.ID) == projectID || p.PathWithNamespace == projectID {
+			return &p, nil
+		}
+	}
+	return nil, fmt.Errorf("snapshot project %s: %w", projectID, ErrNotFound)
+}
+
+// GetProjectStatistics returns the project's statistics, if captured
+func (s *SnapshotClient) GetProjectStatistics(projectID string) (*ProjectStatistics, error) {
+	project, err := s.GetProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return project.Statistics, nil
+}
+
+// GetProjectCounts counts the opened merge requests and the project's open issues
+func (s *SnapshotClient) GetProjectCounts(project Project) (*ProjectCounts, error) {
+	mrs, err := s.ListMergeRequests(strconv.Itoa(project.ID))
+	if err != nil {
+		return nil, err
+	}
+	opened := filter(mrs, func(mr MergeRequest) bool { return mr.State == "opened" })
+	return &ProjectCounts{
+		OpenMergeRequests: len(opened),
+		OpenIssues:        project.OpenIssuesCount,
+	}, nil
+}
+
+// GetProjectLanguages returns the languages in languages.json, nil if absent
+func (s *SnapshotClient) GetProjectLanguages(projectID string) (map[string]float64, error) {
+	var languages map[string]float64
+	if err := s.load("languages.json", &languages); err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	return languages, nil
+}
+
+// GetTree returns tree.json for the root and tree/<path>.json below it. A
+// missing root reads as an empty repository, like the API's 404.
+func (s *SnapshotClient) GetTree(projectID, ref, treePath string) ([]TreeEntry, error) {
+	if treePath == "" {
+		var entries []TreeEntry
+		if err := s.load("tree.json", &entries); err != nil {
+			return nil, err
+		}
+		return entries, nil
+	}
+	return loadList[TreeEntry](s, "tree/"+treePath+".json")
+}
+
+// GetFileContent returns files/<path>
+func (s *SnapshotClient) GetFileContent(projectID string, filePath string, ref string) (string, error) {
+	data, err := s.read("files/" + filePath)
+	return string(data), err
+}
+
+// GetFileRawLimited returns the bytes of files/<path>, or ErrFileTooLarge
+// when there are more than maxBytes
+func (s *SnapshotClient) GetFileRawLimited(projectID, filePath, ref string, maxBytes int64) ([]byte, error) {
+	data, err := s.read("files/" + filePath)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, fmt.Errorf("%s is over %d bytes: %w", filePath, maxBytes, ErrFileTooLarge)
+	}
+	return data, nil
+}
+
+// SaveFileRaw copies files/<path> to destPath
+func (s *SnapshotClient) SaveFileRaw(projectID, filePath, ref, destPath string) (int64, error) {
+	f, err := os.Open(filepath.Join(s.dir, "files", filepath.FromSlash(filePath)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, fmt.Errorf("snapshot files/%s: %w", filePath, ErrNotFound)
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return writeFileAtomic(destPath, f)
+}
+
+// SearchBlobs returns the lines of files/ containing query, ignoring case
+func (s *SnapshotClient) SearchBlobs(projectID, query, ref string) ([]SearchBlob, error) {
+	root := filepath.Join(s.dir, "files")
+	var blobs []SearchBlob
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(root, path)
+		for i, line := range strings.Split(string(data), "\n") {
+			if strings.Contains(strings.ToLower(line), strings.ToLower(query)) {
+				blobs = append(blobs, SearchBlob{Path: filepath.ToSlash(rel), Startline: i + 1, Data: line})
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return blobs, err
+}
+
+// GetFileContentLimited returns files/<path>, cut off after maxBytes
+func (s *SnapshotClient) GetFileContentLimited(projectID string, filePath string, ref string, maxBytes int64) (string, bool, error) {
+	data, err := s.read("files/" + filePath)
+	if err != nil {
+		return "", false, err
+	}
+	if int64(len(data)) > maxBytes {
+		return string(data[:maxBytes]), true, nil
+	}
+	return string(data), false, nil
+}
+
+// GetLastCommitForPath returns no commit; snapshots don't capture history
+func (s *SnapshotClient) GetLastCommitForPath(projectID, ref, filePath string) (*Commit, error) {
+	return nil, nil
+}
+
+// GetCommitDiff returns diffs/<sha>.json
+func (s *SnapshotClient) GetCommitDiff(projectID, sha string) ([]Diff, error) {
+	var diffs []Diff
+	if err := s.load("diffs/"+sha+".json", &diffs); err != nil {
+		return nil, err
+	}
+	return diffs, nil
+}
+
+// ListBranches returns the branches in branches.json
+func (s *SnapshotClient) ListBranches(projectID string) ([]Branch, error) {
+	return loadList[Branch](s, "branches.json")
+}
+
+// ListTags returns the tags in tags.json
+func (s *SnapshotClient) ListTags(projectID string) ([]Tag, error) {
+	return loadList[Tag](s, "tags.json")
+}
+
+// ResolveRefSHA looks the ref up in branches.json, then tags.json
+func (s *SnapshotClient) ResolveRefSHA(projectID, ref string) (string, error) {
+	branches, err := s.ListBranches(projectID)
+	if err != nil {
+		return "", err
+	}
+	for _, b := range branches {
+		if b.Name == ref {
+			return b.Commit.ID, nil
+		}
+	}
+	tags, err := s.ListTags(projectID)
+	if err != nil {
+		return "", err
+	}
+	for _, t := range tags {
+		if t.Name == ref {
+			return t.Commit.ID, nil
+		}
+	}
+	return "", fmt.Errorf("snapshot ref %s: %w", ref, ErrNotFound)
+}
+
+// ListContributors returns the contributors in contributors.json
+func (s *SnapshotClient) ListContributors(projectID string) ([]Contributor, error) {
+	return loadList[Contributor](s, "contributors.json")
+}
+
+// ListProjectEvents returns the activity feed in events.json
+func (s *SnapshotClient) ListProjectEvents(projectID string) ([]Event, error) {
+	return loadList[Event](s, "events.json")
+}
+
+// ListLabels returns the labels in labels.json
+func (s *SnapshotClient) ListLabels(projectID string) ([]Label, error) {
+	return loadList[Label](s, "labels.json")
+}
+
+// ListMilestones returns the milestones in milestones.json
+func (s *SnapshotClient) ListMilestones(projectID string) ([]Milestone, error) {
+	return loadList[Milestone](s, "milestones.json")
+}
+
+// CompareBranches returns ErrNotFound; snapshots don't capture comparisons
+func (s *SnapshotClient) CompareBranches(projectID, from, to string) (*Comparison, error) {
+	return nil, fmt.Errorf("snapshot comparison %s...%s: %w", from, to, ErrNotFound)
+}
+
+// ListMergeRequests returns the merge requests in merge_requests.json
+func (s *SnapshotClient) ListMergeRequests(projectID string) ([]MergeRequest, error) {
+	return loadList[MergeRequest](s, "merge_requests.json")
+}
+
+// ListMergeRequestsPage returns every merge request as page 1
+func (s *SnapshotClient) ListMergeRequestsPage(projectID string, page int) ([]MergeRequest, int, error) {
+	if page > 1 {
+		return nil, 0, nil
+	}
+	mrs, err := s.ListMergeRequests(projectID)
+	return mrs, 0, err
+}
+
+// GetMergeRequest finds a merge request by IID
+func (s *SnapshotClient) GetMergeRequest(projectID string, iid int) (*MergeRequest, error) {
+	mrs, err := s.ListMergeRequests(projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, mr := range mrs {
+		if mr.IID == iid {
+			return &mr, nil
+		}
+	}
+	return nil, fmt.Errorf("snapshot merge request !%d: %w", iid, ErrNotFound)
+}
+
+// GetMergeRequestApprovals returns ErrNotFound; snapshots don't capture approvals
+func (s *SnapshotClient) GetMergeRequestApprovals(projectID string, iid int) (*MRApprovals, error) {
+	return nil, fmt.Errorf("snapshot approvals of !%d: %w", iid, ErrNotFound)
+}
+
+// ListMyMergeRequests returns the opened merge requests in merge_requests.json
+func (s *SnapshotClient) ListMyMergeRequests() ([]MergeRequest, error) {
+	mrs, err := s.ListMergeRequests("")
+	if err != nil {
+		return nil, err
+	}
+	return filter(mrs, func(mr MergeRequest) bool { return mr.State == "opened" }), nil
+}
+
+// ListMyReviewRequests returns no merge requests
+func (s *SnapshotClient) ListMyReviewRequests() ([]MergeRequest, error) {
+	return nil, nil
+}
+
+// ListPipelines returns the pipelines in pipelines.json
+func (s *SnapshotClient) ListPipelines(projectID string) ([]Pipeline, error) {
+	return loadList[Pipeline](s, "pipelines.json")
+}
+
+// ListPipelinesPage returns every pipeline as page 1
+func (s *SnapshotClient) ListPipelinesPage(projectID string, page int) ([]Pipeline, int, error) {
+	if page > 1 {
+		return nil, 0, nil
+	}
+	pipelines, err := s.ListPipelines(projectID)
+	return pipelines, 0, err
+}
+
+// GetPipeline finds a pipeline by ID
+func (s *SnapshotClient) GetPipeline(projectID string, pipelineID int) (*Pipeline, error) {
+	pipelines, err := s.ListPipelines(projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range pipelines {
+		if p.ID == pipelineID {
+			return &p, nil
+		}
+	}
+	return nil, fmt.Errorf("snapshot pipeline %d: %w", pipelineID, ErrNotFound)
+}
+
+// ListPipelineJobs returns the jobs in jobs.json that belong to the pipeline
+func (s *SnapshotClient) ListPipelineJobs(projectID string, pipelineID int) ([]Job, error) {
+	jobs, err := loadList[Job](s, "jobs.json")
+	if err != nil {
+		return nil, err
+	}
+	return filter(jobs, func(j Job) bool { return j.Pipeline.ID == pipelineID }), nil
+}
+
+// ListRunningJobs returns the running jobs in jobs.json
+func (s *SnapshotClient) ListRunningJobs() ([]Job, error) {
+	return s.jobsWithStatus("running")
+}
+
+// ListPendingJobs returns the pending jobs in jobs.json
+func (s *SnapshotClient) ListPendingJobs() ([]Job, error) {
+	return s.jobsWithStatus("pending")
+}
+
+func (s *SnapshotClient) jobsWithStatus(status string) ([]Job, error) {
+	jobs, err := loadList[Job](s, "jobs.json")
+	if err != nil {
+		return nil, err
+	}
+	return filter(jobs, func(j Job) bool { return j.Status == status }), nil
+}
+
+// GetJobLog returns logs/<job id>.log
+func (s *SnapshotClient) GetJobLog(projectID string, jobID int) (string, error) {
+	data, err := s.read(fmt.Sprintf("logs/%d.log", jobID))
+	return string(data), err
+}
+
+// GetJobLogFrom returns the whole log, like a server that ignores Range
+func (s *SnapshotClient) GetJobLogFrom(projectID string, jobID int, offset int) (log string, partial bool, err error) {
+	log, err = s.GetJobLog(projectID, jobID)
+	return log, false, err
+}
+
+// ListProjectVariables returns the variables in variables.json
+func (s *SnapshotClient) ListProjectVariables(projectID string) ([]CIVariable, error) {
+	return loadList[CIVariable](s, "variables.json")
+}
+
+// ListEnvironments returns the environments in environments.json
+func (s *SnapshotClient) ListEnvironments(projectID string) ([]Environment, error) {
+	return loadList[Environment](s, "environments.json")
+}
+
+// ListAvailableEnvironments returns the available environments in environments.json
+func (s *SnapshotClient) ListAvailableEnvironments(projectID string) ([]Environment, error) {
+	environments, err := s.ListEnvironments(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return filter(environments, func(e Environment) bool { return e.State == "available" }), nil
+}
+
+// GetEnvironment returns the environment with environmentID from environments.json
+func (s *SnapshotClient) GetEnvironment(projectID string, environmentID int) (*Environment, error) {
+	environments, err := s.ListEnvironments(projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range environments {
+		if e.ID == environmentID {
+			return &e, nil
+		}
+	}
+	return nil, fmt.Errorf("snapshot environment %d: %w", environmentID, ErrNotFound)
+}
+
+// ListDeployments returns the deployments in deployments.json to the environment
+func (s *SnapshotClient) ListDeployments(projectID, environment string) ([]Deployment, error) {
+	deployments, err := loadList[Deployment](s, "deployments.json")
+	if err != nil {
+		return nil, err
+	}
+	return filter(deployments, func(d Deployment) bool { return d.Environment.Name == environment }), nil
+}
+
+// ListReleases returns the releases in releases.json
+func (s *SnapshotClient) ListReleases(projectID string) ([]Release, error) {
+	return loadList[Release](s, "releases.json")
+}
+
+// GetRelease returns the release for tagName from releases.json
+func (s *SnapshotClient) GetRelease(projectID, tagName string) (*Release, error) {
+	releases, err := s.ListReleases(projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range releases {
+		if r.TagName == tagName {
+			return &r, nil
+		}
+	}
+	return nil, fmt.Errorf("snapshot release %s: %w", tagName, ErrNotFound)
+}
+
+// DownloadFile returns ErrSnapshotDownload
+func (s *SnapshotClient) DownloadFile(ctx context.Context, downloadURL, destPath string, progress ProgressFunc) (int64, string, error) {
+	return 0, "", ErrSnapshotDownload
+}
+
+// ListProjectSnippets returns the snippets in snippets.json
+func (s *SnapshotClient) ListProjectSnippets(projectID string) ([]Snippet, error) {
+	return loadList[Snippet](s, "snippets.json")
+}
+
+// GetSnippetContent returns snippets/<id>
+func (s *SnapshotClient) GetSnippetContent(projectID string, snippetID int) (string, error) {
+	data, err := s.read(fmt.Sprintf("snippets/%d", snippetID))
+	return string(data), err
+}
+
+// ListWikiPages returns the pages in wikis.json
+func (s *SnapshotClient) ListWikiPages(projectID string) ([]WikiPage, error) {
+	return loadList[WikiPage](s, "wikis.json")
+}
+
+// GetWikiPage finds a page in wikis.json by slug
+func (s *SnapshotClient) GetWikiPage(projectID, slug string) (*WikiPage, error) {
+	pages, err := s.ListWikiPages(projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range pages {
+		if p.Slug == slug {
+			return &p, nil
+		}
+	}
+	return nil, fmt.Errorf("snapshot wiki page %s: %w", slug, ErrNotFound)
+}
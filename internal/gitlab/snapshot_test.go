@@ -0,0 +1,123 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeSnapshot creates a snapshot directory with the given files
+func writeSnapshot(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestSnapshotClient(t *testing.T) {
+	dir := writeSnapshot(t, map[string]string{
+		"groups.json": string(groupsJSON),
+		"projects.json": `[
+			{"id": 7, "name": "api", "path_with_namespace": "acme-corp/backend/api", "namespace": {"id": 1002}, "open_issues_count": 4},
+			{"id": 8, "name": "web", "path_with_namespace": "acme-corp/frontend/web", "namespace": {"id": 1003}}
+		]`,
+		"tree.json":           string(treeJSON),
+		"tree/lib.json":       `[{"name": "util.go", "type": "blob", "path": "lib/util.go"}]`,
+		"files/Makefile":      "all:\n\tgo build\n",
+		"merge_requests.json": string(mergeRequestsJSON),
+		"logs/42.log":         "job output\n",
+		"tags.json":           `[{"name": "v1.0.0", "commit": {"id": "0a1b2c3d4e5f"}}]`,
+	})
+	c, err := NewSnapshotClient(dir)
+	if err != nil {
+		t.Fatalf("NewSnapshotClient: %v", err)
+	}
+
+	subgroups, err := c.ListSubgroups("acme-corp")
+	if err != nil || len(subgroups) != 2 {
+		t.Errorf("expected 2 subgroups of acme-corp, got %d (%v)", len(subgroups), err)
+	}
+	projects, err := c.ListGroupProjects("1002")
+	if err != nil || len(projects) != 1 || projects[0].Name != "api" {
+		t.Errorf("expected api in backend, got %+v (%v)", projects, err)
+	}
+	if p, err := c.GetProject("acme-corp/frontend/web"); err != nil || p.ID != 8 {
+		t.Errorf("GetProject by path = %+v, %v", p, err)
+	}
+	if counts, err := c.GetProjectCounts(Project{ID: 7, OpenIssuesCount: 4}); err != nil || counts.OpenMergeRequests != 3 || counts.OpenIssues != 4 {
+		t.Errorf("GetProjectCounts = %+v, %v", counts, err)
+	}
+
+	if entries, err := c.GetTree("7", "main", ""); err != nil || len(entries) != 7 {
+		t.Errorf("expected 7 root entries, got %d (%v)", len(entries), err)
+	}
+	if entries, err := c.GetTree("7", "main", "lib"); err != nil || len(entries) != 1 {
+		t.Errorf("expected 1 entry in lib, got %d (%v)", len(entries), err)
+	}
+	if content, err := c.GetFileContent("7", "Makefile", "main"); err != nil || content != "all:\n\tgo build\n" {
+		t.Errorf("GetFileContent = %q, %v", content, err)
+	}
+	saved := filepath.Join(t.TempDir(), "Makefile")
+	if n, err := c.SaveFileRaw("7", "Makefile", "main", saved); err != nil || n != 15 {
+		t.Errorf("SaveFileRaw = %d, %v", n, err)
+	} else if data, _ := os.ReadFile(saved); string(data) != "all:\n\tgo build\n" {
+		t.Errorf("saved %q", data)
+	}
+	if blobs, err := c.SearchBlobs("7", "BUILD", "main"); err != nil || len(blobs) != 1 || blobs[0].Path != "Makefile" || blobs[0].Startline != 2 {
+		t.Errorf("SearchBlobs = %+v, %v", blobs, err)
+	}
+	if sha, err := c.ResolveRefSHA("7", "v1.0.0"); err != nil || sha != "0a1b2c3d4e5f" {
+		t.Errorf("ResolveRefSHA = %q, %v", sha, err)
+	}
+	if log, partial, err := c.GetJobLogFrom("7", 42, 5); err != nil || partial || log != "job output\n" {
+		t.Errorf("GetJobLogFrom = %q, %v, %v", log, partial, err)
+	}
+
+	// Missing lists are empty, missing items not found
+	if pipelines, err := c.ListPipelines("7"); err != nil || len(pipelines) != 0 {
+		t.Errorf("missing pipelines.json should be an empty list, got %d (%v)", len(pipelines), err)
+	}
+	if _, err := c.GetFileContent("7", "missing.go", "main"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing file should be ErrNotFound, got %v", err)
+	}
+	if _, err := c.GetProject("99"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("unknown project should be ErrNotFound, got %v", err)
+	}
+	if _, _, err := c.DownloadFile(context.Background(), "https://example.com/a", filepath.Join(dir, "a"), nil); !errors.Is(err, ErrSnapshotDownload) {
+		t.Errorf("downloads should fail with ErrSnapshotDownload, got %v", err)
+	}
+}
+
+func TestSnapshotClient_EmptyRepository(t *testing.T) {
+	c, err := NewSnapshotClient(writeSnapshot(t, map[string]string{"projects.json": "[]"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Without tree.json the root reads as the API's 404 for an empty repository
+	if _, err := c.GetTree("1", "main", ""); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestNewSnapshotClient_NotADirectory(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "groups.json")
+	if err := os.WriteFile(file, []byte("[]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewSnapshotClient(file); err == nil {
+		t.Error("expected an error for a file")
+	}
+	if _, err := NewSnapshotClient(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected an error for a missing directory")
+	}
+}
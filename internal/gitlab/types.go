@@ -44,8 +44,24 @@ type Project struct {
 	StarCount           int        `json:"star_count"`
 	ForksCount          int        `json:"forks_count"`
 	LastActivityAt      time.Time  `json:"last_activity_at"`
+	OpenIssuesCount     int        `json:"open_issues_count"`
+	EmptyRepo           bool       `json:"empty_repo"`
 	Namespace           *Namespace `json:"namespace"`
 	MarkedForDeletionAt *string    `json:"marked_for_deletion_at"`
+	// Statistics is only returned with statistics=true and enough access
+	Statistics *ProjectStatistics `json:"statistics"`
+}
+
+// ProjectStatistics holds repository size figures of a project
+type ProjectStatistics struct {
+	CommitCount    int   `json:"commit_count"`
+	RepositorySize int64 `json:"repository_size"`
+}
+
+// ProjectCounts holds the open merge request and issue counts for a project
+type ProjectCounts struct {
+	OpenMergeRequests int
+	OpenIssues        int
 }
 
 // Pipeline represents a GitLab CI/CD pipeline
@@ -59,9 +75,14 @@ type Pipeline struct {
 	Source    string    `json:"source"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
-	WebURL    string    `json:"web_url"`
-	Name      string    `json:"name"`
-	User      User      `json:"user"`
+	// StartedAt, FinishedAt, Duration and Coverage are only returned by the single pipeline endpoint
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	Duration   int        `json:"duration"`
+	Coverage   string     `json:"coverage"`
+	WebURL     string     `json:"web_url"`
+	Name       string     `json:"name"`
+	User       User       `json:"user"`
 }
 
 // User represents a GitLab user
@@ -76,27 +97,49 @@ type User struct {
 
 // MergeRequest represents a GitLab merge request
 type MergeRequest struct {
-	ID             int       `json:"id"`
-	IID            int       `json:"iid"`
-	ProjectID      int       `json:"project_id"`
-	Title          string    `json:"title"`
-	Description    string    `json:"description"`
-	State          string    `json:"state"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
-	TargetBranch   string    `json:"target_branch"`
-	SourceBranch   string    `json:"source_branch"`
-	UserNotesCount int       `json:"user_notes_count"`
-	Upvotes        int       `json:"upvotes"`
-	Downvotes      int       `json:"downvotes"`
-	Author         User      `json:"author"`
-	Assignees      []User    `json:"assignees"`
-	Reviewers      []User    `json:"reviewers"`
-	Labels         []string  `json:"labels"`
-	Draft          bool      `json:"draft"`
-	WebURL         string    `json:"web_url"`
-	MergeStatus    string    `json:"merge_status"`
-	HasConflicts   bool      `json:"has_conflicts"`
+	ID             int        `json:"id"`
+	IID            int        `json:"iid"`
+	ProjectID      int        `json:"project_id"`
+	Title          string     `json:"title"`
+	Description    string     `json:"description"`
+	State          string     `json:"state"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	TargetBranch   string     `json:"target_branch"`
+	SourceBranch   string     `json:"source_branch"`
+	UserNotesCount int        `json:"user_notes_count"`
+	Upvotes        int        `json:"upvotes"`
+	Downvotes      int        `json:"downvotes"`
+	Author         User       `json:"author"`
+	Assignees      []User     `json:"assignees"`
+	Reviewers      []User     `json:"reviewers"`
+	Labels         []string   `json:"labels"`
+	Draft          bool       `json:"draft"`
+	WebURL         string     `json:"web_url"`
+	MergeStatus    string     `json:"merge_status"`
+	HasConflicts   bool       `json:"has_conflicts"`
+	References     References `json:"references"`
+	// HeadPipeline is only returned by the single merge request endpoint
+	HeadPipeline *Pipeline `json:"head_pipeline"`
+}
+
+// MRApprovals represents the approval state of a merge request
+type MRApprovals struct {
+	ApprovalsRequired int          `json:"approvals_required"`
+	ApprovalsLeft     int          `json:"approvals_left"`
+	ApprovedBy        []MRApprover `json:"approved_by"`
+}
+
+// MRApprover is a user who approved a merge request
+type MRApprover struct {
+	User User `json:"user"`
+}
+
+// References holds the short, relative, and full textual references of an item (e.g. "!12", "group/project!12")
+type References struct {
+	Short    string `json:"short"`
+	Relative string `json:"relative"`
+	Full     string `json:"full"`
 }
 
 // Commit represents a Git commit
@@ -216,3 +259,158 @@ type Job struct {
 		PathWithNamespace string `json:"path_with_namespace"`
 	} `json:"project"`
 }
+
+// Environment represents a deployment environment of a project
+type Environment struct {
+	ID             int         `json:"id"`
+	Name           string      `json:"name"`
+	Slug           string      `json:"slug"`
+	State          string      `json:"state"`
+	Tier           string      `json:"tier"`
+	ExternalURL    string      `json:"external_url"`
+	LastDeployment *Deployment `json:"last_deployment"`
+}
+
+// Deployment represents a deployment of a ref to an environment
+type Deployment struct {
+	ID          int       `json:"id"`
+	IID         int       `json:"iid"`
+	Ref         string    `json:"ref"`
+	SHA         string    `json:"sha"`
+	Status      string    `json:"status"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	User        User      `json:"user"`
+	Deployable  *Job      `json:"deployable"`
+	Environment struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"environment"`
+}
+
+// Diff represents the changes to a single file
+type Diff struct {
+	OldPath     string `json:"old_path"`
+	NewPath     string `json:"new_path"`
+	Diff        string `json:"diff"`
+	NewFile     bool   `json:"new_file"`
+	RenamedFile bool   `json:"renamed_file"`
+	DeletedFile bool   `json:"deleted_file"`
+}
+
+// Comparison represents the difference between two refs
+type Comparison struct {
+	Commit         *Commit  `json:"commit"`
+	Commits        []Commit `json:"commits"`
+	Diffs          []Diff   `json:"diffs"`
+	CompareTimeout bool     `json:"compare_timeout"`
+	CompareSameRef bool     `json:"compare_same_ref"`
+}
+
+// Snippet represents a GitLab project snippet
+type Snippet struct {
+	ID          int       `json:"id"`
+	Title       string    `json:"title"`
+	FileName    string    `json:"file_name"`
+	Description string    `json:"description"`
+	Visibility  string    `json:"visibility"`
+	Author      User      `json:"author"`
+	WebURL      string    `json:"web_url"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Contributor is a commit author of a repository, aggregated by email
+type Contributor struct {
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	Commits   int    `json:"commits"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+}
+
+// Label represents a project label. Color is a hex value like "#dc143c".
+type Label struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description"`
+}
+
+// Milestone represents a project milestone. DueDate is a "2006-01-02" date
+// and empty when the milestone has none.
+type Milestone struct {
+	ID      int    `json:"id"`
+	IID     int    `json:"iid"`
+	Title   string `json:"title"`
+	State   string `json:"state"` // active, closed
+	DueDate string `json:"due_date"`
+	WebURL  string `json:"web_url"`
+}
+
+// SearchBlob is a code search match. Data holds the matching lines and a few
+// around them, the first being line Startline of the file.
+type SearchBlob struct {
+	Path      string `json:"path"`
+	Filename  string `json:"filename"`
+	Ref       string `json:"ref"`
+	Startline int    `json:"startline"`
+	Data      string `json:"data"`
+	ProjectID int    `json:"project_id"`
+}
+
+// Event is an entry in a project's activity feed. Pushes have no target and
+// carry PushData instead.
+type Event struct {
+	ID          int       `json:"id"`
+	ActionName  string    `json:"action_name"` // pushed to, opened, accepted, commented on, ...
+	TargetType  string    `json:"target_type"` // MergeRequest, Issue, Milestone, Note, ...
+	TargetIID   int       `json:"target_iid"`
+	TargetTitle string    `json:"target_title"`
+	Author      User      `json:"author"`
+	CreatedAt   time.Time `json:"created_at"`
+	PushData    *PushData `json:"push_data"`
+}
+
+// PushData describes the ref a push event updated
+type PushData struct {
+	CommitCount int    `json:"commit_count"`
+	RefType     string `json:"ref_type"` // branch, tag
+	Ref         string `json:"ref"`
+	CommitTitle string `json:"commit_title"`
+}
+
+// WikiPage represents a project wiki page. Content is only returned when
+// fetching a single page.
+type WikiPage struct {
+	Slug    string `json:"slug"`
+	Title   string `json:"title"`
+	Format  string `json:"format"`
+	Content string `json:"content"`
+}
+
+// CIVariable represents a project-level CI/CD variable
+type CIVariable struct {
+	Key              string `json:"key"`
+	Value            string `json:"value"`
+	VariableType     string `json:"variable_type"`
+	Protected        bool   `json:"protected"`
+	Masked           bool   `json:"masked"`
+	EnvironmentScope string `json:"environment_scope"`
+}
+
+// Tag represents a Git tag
+type Tag struct {
+	Name      string      `json:"name"`
+	Message   string      `json:"message"`
+	Target    string      `json:"target"`
+	Protected bool        `json:"protected"`
+	Commit    Commit      `json:"commit"`
+	Release   *TagRelease `json:"release"`
+}
+
+// TagRelease is the release attached to a tag, if any
+type TagRelease struct {
+	TagName     string `json:"tag_name"`
+	Description string `json:"description"`
+}
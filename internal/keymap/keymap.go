@@ -1,6 +1,15 @@
 package keymap
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/EspenTeigen/lazylab/internal/config"
+	"github.com/charmbracelet/bubbles/key"
+)
 
 // KeyMap defines all keybindings for the application
 type KeyMap struct {
@@ -18,8 +27,6 @@ type KeyMap struct {
 	PageUp   key.Binding
 	PageDown key.Binding
 	Search   key.Binding
-	Open     key.Binding
-	New      key.Binding
 }
 
 // DefaultKeyMap returns the default vim-style keybindings
@@ -81,17 +88,192 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("/"),
 			key.WithHelp("/", "search"),
 		),
-		Open: key.NewBinding(
-			key.WithKeys("o"),
-			key.WithHelp("o", "open in browser"),
-		),
-		New: key.NewBinding(
-			key.WithKeys("n"),
-			key.WithHelp("n", "new"),
-		),
 	}
 }
 
+// reservedKeys have fixed meanings in the panels, so binding an action to one
+// of them would either never take effect or hide the fixed key. Actions keep
+// the defaults they share with one, such as top on g outside the file list.
+var reservedKeys = map[string]string{
+	"ctrl+p": "pausing auto-refresh",
+	"ctrl+r": "recent projects",
+	"ctrl+o": "going back",
+	"ctrl+y": "copying a markdown link",
+	"ctrl+l": "copying a lazylab link",
+	"S":      "copying the SSH clone URL",
+	"U":      "copying the HTTPS clone URL",
+	"b":      "the branch selector",
+	"R":      "running jobs",
+	"E":      "environments",
+	"A":      "toggling the default group",
+	"<":      "shrinking a panel",
+	">":      "growing a panel",
+	"i":      "project info",
+	"n":      "snippets",
+	"C":      "contributors",
+	"T":      "labels and milestones",
+	"a":      "recent activity",
+	"M":      "my merge requests",
+	"H":      "panel navigation",
+	"J":      "panel navigation",
+	"K":      "panel navigation",
+	"L":      "panel navigation",
+	"1":      "panel navigation",
+	"2":      "panel navigation",
+	"3":      "panel navigation",
+	// Navigator
+	"z": "folding groups",
+	"o": "unfolding all groups after z",
+	"c": "folding all groups after z",
+	"*": "favoriting a project",
+	// Content lists and the file view
+	"g":      "going to a ref",
+	"W":      "the wiki",
+	"space":  "marking a file",
+	" ":      "marking a file",
+	"D":      "downloading",
+	"y":      "copying",
+	"Y":      "copying",
+	"t":      "copying a release tag",
+	"s":      "sorting",
+	"f":      "filtering",
+	"F":      "filtering",
+	"v":      "the diff view",
+	"e":      "opening an editor",
+	"m":      "toggling rendered markdown",
+	":":      "jumping to a line",
+	"ctrl+s": "saving a file",
+	// README
+	"0":           "scrolling to the line start",
+	"$":           "scrolling to the line end",
+	"V":           "selecting lines",
+	"shift+left":  "panel navigation",
+	"shift+right": "panel navigation",
+	"shift+up":    "panel navigation",
+	"shift+down":  "panel navigation",
+}
+
+// LoadKeyMap returns the default keybindings with overrides from the config's
+// keys section applied. If any override is unknown, malformed, or collides with
+// another binding or a reserved key, the defaults are returned together with an error.
+func LoadKeyMap(cfg *config.LazyLabConfig) (KeyMap, error) {
+	km := DefaultKeyMap()
+	if cfg == nil || len(cfg.Keys) == 0 {
+		return km, nil
+	}
+
+	bindings := km.bindingsByAction()
+	defaults := DefaultKeyMap()
+	defaultBindings := defaults.bindingsByAction()
+
+	// Apply overrides in a stable order so errors are deterministic
+	actions := make([]string, 0, len(cfg.Keys))
+	for action := range cfg.Keys {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	for _, action := range actions {
+		binding, ok := bindings[action]
+		if !ok {
+			return DefaultKeyMap(), fmt.Errorf("unknown key action %q", action)
+		}
+		keys, err := parseKeys(cfg.Keys[action])
+		if err != nil {
+			return DefaultKeyMap(), fmt.Errorf("invalid keys for %q: %w", action, err)
+		}
+		binding.SetKeys(keys...)
+		binding.SetHelp(strings.Join(keys, "/"), binding.Help().Desc)
+	}
+
+	// Reject keys bound to more than one action or taken by fixed bindings
+	seen := make(map[string]string)
+	for _, action := range sortedActions(bindings) {
+		for _, k := range bindings[action].Keys() {
+			if use, ok := reservedKeys[k]; ok && !slices.Contains(defaultBindings[action].Keys(), k) {
+				return DefaultKeyMap(), fmt.Errorf("key %q for %q is reserved for %s", k, action, use)
+			}
+			if other, ok := seen[k]; ok {
+				return DefaultKeyMap(), fmt.Errorf("key %q is bound to both %q and %q", k, other, action)
+			}
+			seen[k] = action
+		}
+	}
+
+	return km, nil
+}
+
+// bindingsByAction maps config action names to the bindings in k
+func (k *KeyMap) bindingsByAction() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up":        &k.Up,
+		"down":      &k.Down,
+		"left":      &k.Left,
+		"right":     &k.Right,
+		"select":    &k.Select,
+		"back":      &k.Back,
+		"quit":      &k.Quit,
+		"help":      &k.Help,
+		"refresh":   &k.Refresh,
+		"top":       &k.Top,
+		"bottom":    &k.Bottom,
+		"page_up":   &k.PageUp,
+		"page_down": &k.PageDown,
+		"search":    &k.Search,
+	}
+}
+
+func sortedActions(bindings map[string]*key.Binding) []string {
+	actions := make([]string, 0, len(bindings))
+	for action := range bindings {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	return actions
+}
+
+// namedKeys are the multi-character key names understood by bubbletea
+var namedKeys = map[string]bool{
+	"enter": true, "esc": true, "backspace": true, "tab": true, "space": true,
+	"up": true, "down": true, "left": true, "right": true,
+	"home": true, "end": true, "pgup": true, "pgdown": true, "delete": true, "insert": true,
+	"f1": true, "f2": true, "f3": true, "f4": true, "f5": true, "f6": true,
+	"f7": true, "f8": true, "f9": true, "f10": true, "f11": true, "f12": true,
+}
+
+// parseKeys splits a comma separated key list and validates each key
+func parseKeys(value string) ([]string, error) {
+	var keys []string
+	for _, k := range strings.Split(value, ",") {
+		k = strings.TrimSpace(k)
+		if !validKey(k) {
+			return nil, fmt.Errorf("malformed key %q", k)
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// validKey reports whether k is a single character, a named key, or a
+// modifier (ctrl+, alt+, shift+) applied to one of those
+func validKey(k string) bool {
+	if k == "" {
+		return false
+	}
+	if utf8.RuneCountInString(k) == 1 {
+		return true
+	}
+	if namedKeys[k] {
+		return true
+	}
+	for _, mod := range []string{"ctrl+", "alt+", "shift+"} {
+		if rest, ok := strings.CutPrefix(k, mod); ok {
+			return validKey(rest)
+		}
+	}
+	return false
+}
+
 // ShortHelp returns the minimal help bindings
 func (k KeyMap) ShortHelp() []key.Binding {
 	return []key.Binding{k.Up, k.Down, k.Select, k.Back, k.Quit, k.Help}
@@ -103,6 +285,6 @@ func (k KeyMap) FullHelp() [][]key.Binding {
 		{k.Up, k.Down, k.Left, k.Right},
 		{k.Select, k.Back, k.Quit},
 		{k.Top, k.Bottom, k.PageUp, k.PageDown},
-		{k.Refresh, k.Search, k.Open, k.New},
+		{k.Refresh, k.Search},
 	}
 }
@@ -3,6 +3,7 @@ package keymap
 import (
 	"testing"
 
+	"github.com/EspenTeigen/lazylab/internal/config"
 	"github.com/charmbracelet/bubbles/key"
 )
 
@@ -52,3 +53,72 @@ func TestFullHelp(t *testing.T) {
 		t.Errorf("expected 4 help columns, got %d", len(help))
 	}
 }
+
+func TestLoadKeyMap_Overrides(t *testing.T) {
+	cfg := &config.LazyLabConfig{
+		Keys: map[string]string{
+			"quit":   "Q",
+			"search": "ctrl+f, x",
+		},
+	}
+
+	km, err := LoadKeyMap(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if keys := km.Quit.Keys(); len(keys) != 1 || keys[0] != "Q" {
+		t.Errorf("expected quit keys [Q], got %v", keys)
+	}
+	if keys := km.Search.Keys(); len(keys) != 2 || keys[0] != "ctrl+f" || keys[1] != "x" {
+		t.Errorf("expected search keys [ctrl+f f], got %v", keys)
+	}
+	if help := km.Quit.Help(); help.Key != "Q" || help.Desc != "quit" {
+		t.Errorf("expected quit help 'Q'/'quit', got %q/%q", help.Key, help.Desc)
+	}
+
+	// Untouched bindings keep their defaults
+	if keys := km.Up.Keys(); len(keys) != 2 || keys[0] != "k" {
+		t.Errorf("expected default up keys, got %v", keys)
+	}
+}
+
+func TestLoadKeyMap_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		keys map[string]string
+	}{
+		{"unknown action", map[string]string{"launch": "x"}},
+		{"malformed key", map[string]string{"quit": "ctrl+"}},
+		{"empty key", map[string]string{"quit": "Q,"}},
+		{"collision", map[string]string{"quit": "j"}},
+		{"reserved key", map[string]string{"search": "ctrl+p"}},
+		{"reserved panel key", map[string]string{"search": "D"}},
+		{"reserved copy key", map[string]string{"help": "y"}},
+		{"reserved default of another action", map[string]string{"bottom": "g"}},
+		{"removed action", map[string]string{"open": "o"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			km, err := LoadKeyMap(&config.LazyLabConfig{Keys: tt.keys})
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			// Falls back to defaults
+			if keys := km.Quit.Keys(); len(keys) != 2 || keys[0] != "q" {
+				t.Errorf("expected default quit keys, got %v", keys)
+			}
+		})
+	}
+}
+
+func TestLoadKeyMap_NilConfig(t *testing.T) {
+	km, err := LoadKeyMap(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if keys := km.Quit.Keys(); len(keys) != 2 || keys[0] != "q" {
+		t.Errorf("expected default quit keys, got %v", keys)
+	}
+}
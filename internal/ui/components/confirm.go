@@ -0,0 +1,79 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/EspenTeigen/lazylab/internal/ui/styles"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Confirm is a centered yes/no prompt. The owner routes keys to HandleKey
+// while Active and receives the answer as a ConfirmedMsg.
+type Confirm struct {
+	Active  bool
+	ID      string // Identifies the action being confirmed
+	Message string
+}
+
+// ConfirmedMsg carries the answer to a Confirm prompt
+type ConfirmedMsg struct {
+	ID  string
+	Yes bool
+}
+
+// Ask opens the prompt for the action identified by id
+func (c *Confirm) Ask(id, message string) {
+	c.Active = true
+	c.ID = id
+	c.Message = message
+}
+
+// HandleKey answers the prompt with y/Enter or n/Esc/q, closing it. Other
+// keys are ignored so a stray keypress can't confirm anything.
+func (c *Confirm) HandleKey(msg tea.KeyMsg) tea.Cmd {
+	var yes bool
+	switch msg.String() {
+	case "y", "Y", "enter":
+		yes = true
+	case "n", "N", "esc", "q":
+		yes = false
+	default:
+		return nil
+	}
+	c.Active = false
+	answer := ConfirmedMsg{ID: c.ID, Yes: yes}
+	return func() tea.Msg { return answer }
+}
+
+// View renders the prompt centered in a width x height area, padded so
+// whatever follows starts on the line below it
+func (c *Confirm) View(width, height int) string {
+	hint := styles.StatusBarKey.Render("y/Enter") + styles.StatusBarDesc.Render(" yes") + " │ " +
+		styles.StatusBarKey.Render("n/Esc") + styles.StatusBarDesc.Render(" no")
+
+	// Fit the message and hint, up to 60 columns
+	popupWidth := max(lipgloss.Width(c.Message), lipgloss.Width(hint)) + 4
+	popupWidth = min(popupWidth, 60, width-4)
+	content := lipgloss.NewStyle().Width(popupWidth-2).Padding(0, 1).Render(c.Message + "\n\n" + hint)
+	popupHeight := strings.Count(content, "\n") + 3
+
+	popup := SimpleBorderedPanel("Confirm", content, popupWidth, popupHeight, true)
+
+	// Center the popup
+	popupLines := strings.Split(popup, "\n")
+	topPadding := max((height-len(popupLines))/2, 0)
+	leftPadding := max((width-popupWidth)/2, 0)
+
+	var result strings.Builder
+	for i := 0; i < topPadding; i++ {
+		result.WriteString("\n")
+	}
+	for _, line := range popupLines {
+		result.WriteString(strings.Repeat(" ", leftPadding) + line + "\n")
+	}
+	for i := topPadding + len(popupLines); i < height; i++ {
+		result.WriteString("\n")
+	}
+	return result.String()
+}
@@ -0,0 +1,58 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestConfirmHandleKey(t *testing.T) {
+	tests := []struct {
+		key     tea.KeyMsg
+		answers bool
+		yes     bool
+	}{
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")}, true, true},
+		{tea.KeyMsg{Type: tea.KeyEnter}, true, true},
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")}, true, false},
+		{tea.KeyMsg{Type: tea.KeyEsc}, true, false},
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")}, false, false},
+	}
+	for _, tt := range tests {
+		var c Confirm
+		c.Ask("delete", "Delete it?")
+		cmd := c.HandleKey(tt.key)
+		if !tt.answers {
+			if cmd != nil || !c.Active {
+				t.Errorf("%q should be ignored", tt.key.String())
+			}
+			continue
+		}
+		if cmd == nil || c.Active {
+			t.Fatalf("%q should answer and close the prompt", tt.key.String())
+		}
+		if got := cmd().(ConfirmedMsg); got.ID != "delete" || got.Yes != tt.yes {
+			t.Errorf("%q answered %+v, want yes=%v", tt.key.String(), got, tt.yes)
+		}
+	}
+}
+
+func TestConfirmView(t *testing.T) {
+	c := Confirm{Message: "Overwrite /tmp/app.tar.gz?"}
+	view := c.View(80, 20)
+
+	lines := strings.Split(view, "\n")
+	if len(lines) != 21 {
+		t.Errorf("view should fill the height, got %d lines", len(lines))
+	}
+	if !strings.Contains(view, "Overwrite /tmp/app.tar.gz?") {
+		t.Error("view should contain the message")
+	}
+	for _, line := range lines {
+		if w := lipgloss.Width(line); w > 80 {
+			t.Errorf("line is %d wide, wider than the screen", w)
+		}
+	}
+}
@@ -2,90 +2,192 @@ package styles
 
 import "github.com/charmbracelet/lipgloss"
 
-// Lazygit-inspired color palette
+// Scheme is a color palette. Every color and style below is derived from it,
+// so Apply recolors the whole UI.
+type Scheme struct {
+	Cyan, Green, Yellow, Red, Magenta, Blue lipgloss.Color
+	White, Gray, DimGray                    lipgloss.Color
+	StatusBarBackground                     lipgloss.Color
+}
+
+// DefaultScheme is used when no colorscheme is configured or it is unknown
+const DefaultScheme = "lazygit"
+
+// Schemes are the built-in palettes, selected with the colorscheme config key
+var Schemes = map[string]Scheme{
+	// Lazygit-inspired
+	"lazygit": {
+		Cyan:                "#00ffff",
+		Green:               "#00ff00",
+		Yellow:              "#ffff00",
+		Red:                 "#ff0000",
+		Magenta:             "#ff00ff",
+		Blue:                "#5f87ff",
+		White:               "#ffffff",
+		Gray:                "#808080",
+		DimGray:             "#4a4a4a",
+		StatusBarBackground: "#1a1a1a",
+	},
+	"nord": {
+		Cyan:                "#88c0d0",
+		Green:               "#a3be8c",
+		Yellow:              "#ebcb8b",
+		Red:                 "#bf616a",
+		Magenta:             "#b48ead",
+		Blue:                "#81a1c1",
+		White:               "#eceff4",
+		Gray:                "#7b88a1",
+		DimGray:             "#4c566a",
+		StatusBarBackground: "#2e3440",
+	},
+	"gruvbox": {
+		Cyan:                "#8ec07c",
+		Green:               "#b8bb26",
+		Yellow:              "#fabd2f",
+		Red:                 "#fb4934",
+		Magenta:             "#d3869b",
+		Blue:                "#83a598",
+		White:               "#ebdbb2",
+		Gray:                "#928374",
+		DimGray:             "#504945",
+		StatusBarBackground: "#282828",
+	},
+}
+
+// Colors of the active scheme
 var (
 	// Core colors
-	ColorCyan    = lipgloss.Color("#00ffff")
-	ColorGreen   = lipgloss.Color("#00ff00")
-	ColorYellow  = lipgloss.Color("#ffff00")
-	ColorRed     = lipgloss.Color("#ff0000")
-	ColorMagenta = lipgloss.Color("#ff00ff")
-	ColorBlue    = lipgloss.Color("#5f87ff")
-	ColorWhite   = lipgloss.Color("#ffffff")
-	ColorGray    = lipgloss.Color("#808080")
-	ColorDimGray = lipgloss.Color("#4a4a4a")
+	ColorCyan    lipgloss.Color
+	ColorGreen   lipgloss.Color
+	ColorYellow  lipgloss.Color
+	ColorRed     lipgloss.Color
+	ColorMagenta lipgloss.Color
+	ColorBlue    lipgloss.Color
+	ColorWhite   lipgloss.Color
+	ColorGray    lipgloss.Color
+	ColorDimGray lipgloss.Color
 
 	// Panel colors
-	ColorActiveBorder   = ColorCyan
-	ColorInactiveBorder = ColorDimGray
-	ColorActiveTitle    = ColorCyan
-	ColorInactiveTitle  = ColorGray
+	ColorActiveBorder   lipgloss.Color
+	ColorInactiveBorder lipgloss.Color
+	ColorActiveTitle    lipgloss.Color
+	ColorInactiveTitle  lipgloss.Color
 
 	// Status colors
-	ColorSuccess = ColorGreen
-	ColorRunning = ColorYellow
-	ColorFailed  = ColorRed
-	ColorPending = ColorGray
+	ColorSuccess lipgloss.Color
+	ColorRunning lipgloss.Color
+	ColorFailed  lipgloss.Color
+	ColorPending lipgloss.Color
 
 	// MR status
-	ColorMROpen   = ColorGreen
-	ColorMRMerged = ColorMagenta
-	ColorMRClosed = ColorRed
-	ColorMRDraft  = ColorGray
+	ColorMROpen   lipgloss.Color
+	ColorMRMerged lipgloss.Color
+	ColorMRClosed lipgloss.Color
+	ColorMRDraft  lipgloss.Color
 )
 
 // Panel styles
 var (
 	// Active panel border
-	ActivePanelBorder = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(ColorActiveBorder)
+	ActivePanelBorder lipgloss.Style
 
 	// Inactive panel border
-	InactivePanelBorder = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(ColorInactiveBorder)
+	InactivePanelBorder lipgloss.Style
 
 	// Panel title (active)
-	ActivePanelTitle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(ColorActiveBorder).
-				Padding(0, 1)
+	ActivePanelTitle lipgloss.Style
 
 	// Panel title (inactive)
-	InactivePanelTitle = lipgloss.NewStyle().
-				Foreground(ColorInactiveTitle).
-				Padding(0, 1)
+	InactivePanelTitle lipgloss.Style
 
 	// Selected item in list
-	SelectedItem = lipgloss.NewStyle().
-			Foreground(ColorCyan).
-			Bold(true)
+	SelectedItem lipgloss.Style
 
 	// Normal item
-	NormalItem = lipgloss.NewStyle().
-			Foreground(ColorWhite)
+	NormalItem lipgloss.Style
 
 	// Dimmed/secondary text
-	DimmedText = lipgloss.NewStyle().
-			Foreground(ColorGray)
+	DimmedText lipgloss.Style
 
 	// Status bar at bottom
-	StatusBar = lipgloss.NewStyle().
-			Foreground(ColorGray).
-			Background(lipgloss.Color("#1a1a1a")).
-			Padding(0, 1)
+	StatusBar lipgloss.Style
 
 	// Status bar keys
-	StatusBarKey = lipgloss.NewStyle().
-			Foreground(ColorCyan).
-			Bold(true)
+	StatusBarKey lipgloss.Style
 
 	// Status bar description
-	StatusBarDesc = lipgloss.NewStyle().
-			Foreground(ColorGray)
+	StatusBarDesc lipgloss.Style
 )
 
+func init() {
+	Apply(DefaultScheme)
+}
+
+// Apply recolors the UI with the named scheme. Unknown names fall back to
+// DefaultScheme and report false.
+func Apply(name string) bool {
+	scheme, ok := Schemes[name]
+	if !ok {
+		scheme = Schemes[DefaultScheme]
+	}
+
+	ColorCyan = scheme.Cyan
+	ColorGreen = scheme.Green
+	ColorYellow = scheme.Yellow
+	ColorRed = scheme.Red
+	ColorMagenta = scheme.Magenta
+	ColorBlue = scheme.Blue
+	ColorWhite = scheme.White
+	ColorGray = scheme.Gray
+	ColorDimGray = scheme.DimGray
+
+	ColorActiveBorder = ColorCyan
+	ColorInactiveBorder = ColorDimGray
+	ColorActiveTitle = ColorCyan
+	ColorInactiveTitle = ColorGray
+
+	ColorSuccess = ColorGreen
+	ColorRunning = ColorYellow
+	ColorFailed = ColorRed
+	ColorPending = ColorGray
+
+	ColorMROpen = ColorGreen
+	ColorMRMerged = ColorMagenta
+	ColorMRClosed = ColorRed
+	ColorMRDraft = ColorGray
+
+	ActivePanelBorder = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ColorActiveBorder)
+	InactivePanelBorder = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ColorInactiveBorder)
+	ActivePanelTitle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(ColorActiveBorder).
+		Padding(0, 1)
+	InactivePanelTitle = lipgloss.NewStyle().
+		Foreground(ColorInactiveTitle).
+		Padding(0, 1)
+	SelectedItem = lipgloss.NewStyle().
+		Foreground(ColorCyan).
+		Bold(true)
+	NormalItem = lipgloss.NewStyle().
+		Foreground(ColorWhite)
+	DimmedText = lipgloss.NewStyle().
+		Foreground(ColorGray)
+	StatusBar = lipgloss.NewStyle().
+		Foreground(ColorGray).
+		Background(scheme.StatusBarBackground).
+		Padding(0, 1)
+	StatusBarKey = lipgloss.NewStyle().
+		Foreground(ColorCyan).
+		Bold(true)
+	StatusBarDesc = lipgloss.NewStyle().
+		Foreground(ColorGray)
+	return ok
+}
+
 // Pipeline status styles
 func PipelineStatus(status string) lipgloss.Style {
 	switch status {
@@ -0,0 +1,24 @@
+package styles
+
+import "testing"
+
+func TestApply(t *testing.T) {
+	defer Apply(DefaultScheme)
+
+	if !Apply("nord") {
+		t.Fatal("nord should be a known scheme")
+	}
+	if ColorActiveBorder != Schemes["nord"].Cyan {
+		t.Errorf("ColorActiveBorder = %q, want nord's %q", ColorActiveBorder, Schemes["nord"].Cyan)
+	}
+	if ActivePanelBorder.GetBorderTopForeground() != ColorActiveBorder {
+		t.Error("styles should be rebuilt from the new colors")
+	}
+
+	if Apply("solarized") {
+		t.Error("unknown schemes should report false")
+	}
+	if ColorActiveBorder != Schemes[DefaultScheme].Cyan {
+		t.Errorf("unknown schemes should fall back to %s, got %q", DefaultScheme, ColorActiveBorder)
+	}
+}